@@ -0,0 +1,15 @@
+# On the consumer cluster
+data "redshift_datashare" "sales" {
+  name       = "sales_share"
+  share_type = "INBOUND"
+}
+
+resource "redshift_database" "sales" {
+  name = "sales_consumer"
+
+  datashare_source {
+    share_name = data.redshift_datashare.sales.name
+    account_id = data.redshift_datashare.sales.producer_account
+    namespace  = data.redshift_datashare.sales.producer_namespace
+  }
+}
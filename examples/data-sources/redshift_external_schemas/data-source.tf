@@ -0,0 +1,12 @@
+data "redshift_external_schemas" "spectrum" {
+  source_type = "data_catalog"
+}
+
+resource "redshift_grant" "spectrum_usage" {
+  for_each = toset([for s in data.redshift_external_schemas.spectrum.external_schemas : s.name])
+
+  group       = "analysts"
+  schema      = each.value
+  object_type = "schema"
+  privileges  = ["usage"]
+}
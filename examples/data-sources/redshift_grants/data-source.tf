@@ -0,0 +1,12 @@
+data "redshift_grants" "analysts" {
+  group = "analysts"
+}
+
+check "analysts_cannot_delete" {
+  assert {
+    condition = length([
+      for g in data.redshift_grants.analysts.grants : g if g.privilege == "delete"
+    ]) == 0
+    error_message = "The analysts group must not have the delete privilege on any table."
+  }
+}
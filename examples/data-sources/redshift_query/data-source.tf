@@ -0,0 +1,8 @@
+data "redshift_query" "active_users" {
+  query     = "SELECT usename, usesysid FROM pg_user WHERE usename LIKE 'app_%'"
+  row_limit = 50
+}
+
+output "active_user_names" {
+  value = [for row in data.redshift_query.active_users.rows : row.usename]
+}
@@ -1,3 +1,11 @@
 data "redshift_schema" "schema" {
   name = "my_schema"
 }
+
+data "redshift_schema" "by_oid" {
+  oid = 100234
+}
+
+data "redshift_schema" "by_external_database" {
+  external_database_name = "spectrum_db"
+}
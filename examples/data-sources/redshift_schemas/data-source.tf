@@ -0,0 +1,12 @@
+data "redshift_schemas" "analytics" {
+  pattern = "analytics_%"
+}
+
+resource "redshift_grant" "analytics_usage" {
+  for_each = toset([for s in data.redshift_schemas.analytics.schemas : s.name])
+
+  group       = "analysts"
+  schema      = each.value
+  object_type = "schema"
+  privileges  = ["usage"]
+}
@@ -0,0 +1,8 @@
+data "redshift_session" "session" {
+  lifecycle {
+    postcondition {
+      condition     = self.username == "terraform" && !self.temporary_credentials
+      error_message = "The configuration must be applied by the terraform user with a password."
+    }
+  }
+}
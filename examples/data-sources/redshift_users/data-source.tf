@@ -0,0 +1,15 @@
+data "redshift_users" "superusers" {
+  superuser = true
+}
+
+output "superuser_names" {
+  value = [for u in data.redshift_users.superusers.users : u.name]
+}
+
+data "redshift_users" "service_accounts" {
+  name_regex = "^svc_"
+}
+
+output "service_accounts_without_connection_limit" {
+  value = [for u in data.redshift_users.service_accounts.users : u.name if u.connection_limit == -1]
+}
@@ -0,0 +1,14 @@
+resource "aws_redshift_cluster" "analytics" {
+  cluster_identifier = "analytics"
+  # ...
+}
+
+provider "redshift" {
+  # The endpoint is discovered with DescribeClusters on the first connection,
+  # so the cluster doesn't need to exist when planning its creation.
+  # Temporary credentials would be obtained when the provider is configured, which requires the cluster.
+  cluster_identifier = "analytics"
+  region             = "eu-west-1"
+  username           = "admin"
+  password           = var.redshift_password
+}
@@ -0,0 +1,7 @@
+provider "redshift" {
+  host = var.redshift_serverless_endpoint
+  temporary_credentials {
+    workgroup_name = "my-workgroup"
+    region         = "eu-west-1"
+  }
+}
@@ -0,0 +1,2 @@
+# The ID is the object type and the object name, e.g. column:<schema>.<table>.<column>
+terraform import redshift_comment.column "column:analytics.events.created_at"
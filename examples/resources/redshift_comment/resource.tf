@@ -0,0 +1,11 @@
+resource "redshift_comment" "schema" {
+  object_type = "schema"
+  object_name = "analytics"
+  comment     = "Reporting tables, owned by the data team"
+}
+
+resource "redshift_comment" "column" {
+  object_type = "column"
+  object_name = "analytics.events.created_at"
+  comment     = "Time of the event, in UTC"
+}
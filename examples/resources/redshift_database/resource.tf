@@ -23,3 +23,17 @@ resource "redshift_database" "datashare_db" {
     namespace = "00000000-0000-0000-0000-000000000000" # producer cluster namespace (uuid)
   }
 }
+
+# Example resource declaration of a database
+# created from a database of the AWS Glue Data Catalog,
+# e.g. to query Apache Iceberg tables
+resource "redshift_database" "lakehouse_db" {
+  name = "my_lakehouse_db"
+  owner = "my_user"
+
+  data_catalog_source {
+    arn = "arn:aws:glue:us-east-1:123456789012:database/lakehouse"
+    data_catalog_schema = "lakehouse" # optional, defaults to the name of the Data Catalog database
+    iam_role_arn = "arn:aws:iam::123456789012:role/lakehouse-access" # optional, defaults to the default IAM role of the cluster
+  }
+}
@@ -0,0 +1,3 @@
+# Import the settings of the user with usesysid: SELECT usesysid FROM pg_user_info WHERE usename = 'analyst'
+
+terraform import redshift_database_user_mapping.analyst 123
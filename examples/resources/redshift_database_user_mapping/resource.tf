@@ -0,0 +1,4 @@
+resource "redshift_database_user_mapping" "analyst" {
+  user        = "analyst"
+  search_path = ["analytics", "spectrum", "public"]
+}
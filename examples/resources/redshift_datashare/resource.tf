@@ -8,4 +8,9 @@ resource "redshift_datashare" "my_datashare" {
     "public",
     "other",
   ]
+
+  # Optional. Consumer clusters in the same account granted usage of the datashare.
+  consumer_namespaces = [
+    "1c2e1c5f-2a5d-4a3b-9e2f-0f8e6a1b2c3d",
+  ]
 }
@@ -0,0 +1,15 @@
+resource "redshift_schema" "sales" {
+  name = "sales"
+  external_schema {
+    database_name = "sales_share_db"
+    redshift_source {
+      schema = "public"
+    }
+  }
+}
+
+resource "redshift_datashare_consumer_access" "analysts" {
+  database = "sales_share_db"
+  group    = "analysts"
+  schemas  = [redshift_schema.sales.name]
+}
@@ -11,3 +11,20 @@ resource "redshift_default_privileges" "user" {
   object_type = "table"
   privileges  = ["select", "update", "insert", "delete", "drop", "references"]
 }
+
+# Make functions created later by etl executable by analysts
+resource "redshift_default_privileges" "analysts_functions" {
+  group       = "analysts"
+  owner       = "etl"
+  object_type = "function"
+  privileges  = ["execute"]
+}
+
+# Make tables created later by any of the ETL users readable by analysts
+resource "redshift_default_privileges" "analysts_etl_tables" {
+  group       = "analysts"
+  schema      = "staging"
+  owners      = ["etl_batch", "etl_streaming", "etl_backfill"]
+  object_type = "table"
+  privileges  = ["select"]
+}
@@ -0,0 +1,5 @@
+# Import the grant with an ID in the <user|group|role>/<name>/<object_type>[/<schema>[/<objects>]] format
+# where <objects> is a comma separated list. Without objects, the grant applies to all objects of the type in the schema.
+
+terraform import redshift_grant.analysts_tables group/analysts/table/myschema/mytable,othertable
+terraform import redshift_grant.analyst_schema user/analyst/schema/myschema
@@ -12,6 +12,14 @@ resource "redshift_grant" "group" {
   privileges  = ["usage"]
 }
 
+# Granting usage on several schemas at once
+resource "redshift_grant" "group_reporting_schemas" {
+  group       = "analysts"
+  object_type = "schema"
+  objects     = ["reporting", "reporting_archive", "marketing"]
+  privileges  = ["usage"]
+}
+
 # Granting permissions to execute functions or procedures requires providing their arguments' types
 resource "redshift_grant" "user" {
   user        = "john"
@@ -28,3 +36,26 @@ resource "redshift_grant" "public" {
   object_type = "schema"
   privileges  = ["usage"]
 }
+
+# Granting system permissions to a role
+resource "redshift_grant" "ml_engineers" {
+  role        = "ml_engineers"
+  object_type = "system"
+  privileges  = ["create model", "access catalog"]
+}
+
+# Granting usage on a database created from a datashare
+resource "redshift_grant" "analysts_sales_share" {
+  group       = "analysts"
+  database    = "sales_share_db"
+  object_type = "database"
+  privileges  = ["usage"]
+}
+
+# Granting the same privileges to several groups with one resource
+resource "redshift_grant" "readers" {
+  groups      = ["analysts", "data_scientists", "bi_tools"]
+  schema      = "my_schema"
+  object_type = "table"
+  privileges  = ["select"]
+}
@@ -0,0 +1,5 @@
+resource "redshift_grant_all_schemas" "analysts" {
+  group           = "analysts"
+  privileges      = ["usage"]
+  exclude_schemas = ["finance"]
+}
@@ -0,0 +1,16 @@
+resource "redshift_group" "analysts" {
+  name = "analysts"
+
+  # The members are added by the teams with redshift_group_membership.
+  lifecycle {
+    ignore_changes = [users]
+  }
+}
+
+resource "redshift_group_membership" "team_a" {
+  group = redshift_group.analysts.name
+  users = [
+    redshift_user.alice.name,
+    redshift_user.bob.name,
+  ]
+}
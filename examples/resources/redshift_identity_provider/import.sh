@@ -0,0 +1,2 @@
+# The ID is the name of the identity provider
+terraform import redshift_identity_provider.azure_ad "azure_ad"
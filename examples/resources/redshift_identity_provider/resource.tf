@@ -0,0 +1,11 @@
+resource "redshift_identity_provider" "azure_ad" {
+  name      = "azure_ad"
+  type      = "azure"
+  namespace = "aad"
+  parameters = jsonencode({
+    issuer        = "https://sts.windows.net/${var.azure_tenant_id}/"
+    client_id     = var.azure_client_id
+    client_secret = var.azure_client_secret
+    audience      = ["api://${var.azure_client_id}"]
+  })
+}
@@ -0,0 +1,2 @@
+# The ID is the name of the policy
+terraform import redshift_masking_policy.partial_card "partial_card"
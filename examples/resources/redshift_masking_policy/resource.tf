@@ -0,0 +1,10 @@
+# Only the last 4 digits of the credit card numbers are visible to the users the policy is attached to.
+resource "redshift_masking_policy" "partial_card" {
+  name  = "partial_card"
+  using = ["'XXXXXXXXXXXX' || SUBSTRING(credit_card, 13, 4)::varchar(16)"]
+
+  with {
+    name = "credit_card"
+    type = "varchar(16)"
+  }
+}
@@ -0,0 +1,2 @@
+# The ID is the name of the policy, the table and the grantee, <policy>:<schema>.<table>:<user|role>:<name> or <policy>:<schema>.<table>:public
+terraform import redshift_masking_policy_attachment.fraud "unmasked:payments.transactions:role:fraud"
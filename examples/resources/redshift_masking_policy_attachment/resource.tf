@@ -0,0 +1,17 @@
+resource "redshift_masking_policy_attachment" "everyone" {
+  policy  = redshift_masking_policy.partial_card.name
+  schema  = "payments"
+  table   = "transactions"
+  columns = ["credit_card"]
+  public  = true
+}
+
+# The fraud team can read the full credit card numbers, with a policy of higher priority.
+resource "redshift_masking_policy_attachment" "fraud" {
+  policy   = redshift_masking_policy.unmasked.name
+  schema   = "payments"
+  table    = "transactions"
+  columns  = ["credit_card"]
+  role     = redshift_role.fraud.name
+  priority = 10
+}
@@ -0,0 +1,11 @@
+resource "redshift_prepared_onboarding" "analyst" {
+  name     = "analyst"
+  password = "Secret password 1"
+  groups   = ["analysts"]
+
+  schema_access {
+    schema           = "reporting"
+    privileges       = ["usage"]
+    table_privileges = ["select"]
+  }
+}
@@ -0,0 +1,25 @@
+resource "redshift_procedure" "refresh_sales" {
+  name     = "refresh_sales"
+  schema   = "reporting"
+  security = "DEFINER"
+
+  argument {
+    name = "since"
+    type = "date"
+  }
+
+  body = <<-EOT
+  BEGIN
+    DELETE FROM reporting.sales WHERE sale_date >= since;
+    INSERT INTO reporting.sales SELECT * FROM staging.sales WHERE sale_date >= since;
+  END;
+  EOT
+}
+
+resource "redshift_grant" "refresh_sales" {
+  group       = "reporting"
+  schema      = redshift_procedure.refresh_sales.schema
+  object_type = "procedure"
+  objects     = [redshift_procedure.refresh_sales.signature]
+  privileges  = ["execute"]
+}
@@ -0,0 +1,2 @@
+# The ID is the name of the policy
+terraform import redshift_rls_policy.own_region "own_region"
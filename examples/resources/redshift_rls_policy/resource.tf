@@ -0,0 +1,11 @@
+# Only the sales of their own region are visible to the users the policy is attached to.
+resource "redshift_rls_policy" "own_region" {
+  name  = "own_region"
+  alias = "s"
+  using = "s.region = current_user"
+
+  with {
+    name = "region"
+    type = "varchar(64)"
+  }
+}
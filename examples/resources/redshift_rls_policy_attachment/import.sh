@@ -0,0 +1,2 @@
+# The ID is the name of the policy and the table, <policy>:<schema>.<table>
+terraform import redshift_rls_policy_attachment.sales "own_region:analytics.sales"
@@ -0,0 +1,13 @@
+resource "redshift_table_attributes" "sales" {
+  schema             = "analytics"
+  name               = "sales"
+  row_level_security = true
+}
+
+resource "redshift_rls_policy_attachment" "sales" {
+  policy = redshift_rls_policy.own_region.name
+  schema = redshift_table_attributes.sales.schema
+  table  = redshift_table_attributes.sales.name
+  roles  = [redshift_role.sales.name]
+  users  = ["eu", "us"]
+}
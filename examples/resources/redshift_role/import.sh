@@ -0,0 +1,3 @@
+# Import the role with its role_id: SELECT role_id FROM svv_roles WHERE role_name = 'developers'
+
+terraform import redshift_role.developers 123456
@@ -0,0 +1,5 @@
+resource "redshift_role" "developers" {
+  name          = "developers"
+  create_schema = true
+  create_table  = true
+}
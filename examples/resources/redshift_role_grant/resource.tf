@@ -0,0 +1,17 @@
+resource "redshift_role" "analysts" {
+  name = "analysts"
+}
+
+resource "redshift_role" "developers" {
+  name = "developers"
+}
+
+resource "redshift_role_grant" "john" {
+  role = redshift_role.analysts.name
+  user = "john"
+}
+
+resource "redshift_role_grant" "developers" {
+  role         = redshift_role.analysts.name
+  grantee_role = redshift_role.developers.name
+}
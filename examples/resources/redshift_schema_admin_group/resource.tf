@@ -0,0 +1,17 @@
+resource "redshift_user" "etl" {
+  name = "etl_service"
+}
+
+resource "redshift_group" "data_engineers" {
+  name = "data_engineers"
+}
+
+resource "redshift_schema" "staging" {
+  name = "staging"
+}
+
+resource "redshift_schema_admin_group" "staging" {
+  schema = redshift_schema.staging.name
+  group  = redshift_group.data_engineers.name
+  owner  = redshift_user.etl.name
+}
@@ -0,0 +1,3 @@
+# Import the table with its oid: SELECT oid FROM pg_class WHERE relname = 'events'
+
+terraform import redshift_table.events 123456
@@ -0,0 +1,32 @@
+resource "redshift_table" "events" {
+  name     = "events"
+  schema   = redshift_schema.analytics.name
+  comment  = "Raw events"
+  distkey  = "user_id"
+  sortkeys = ["created_at"]
+
+  column {
+    name     = "id"
+    type     = "bigint"
+    nullable = false
+  }
+
+  column {
+    name     = "user_id"
+    type     = "bigint"
+    nullable = false
+  }
+
+  column {
+    name     = "name"
+    type     = "varchar(256)"
+    encoding = "zstd"
+    comment  = "Name of the event"
+  }
+
+  column {
+    name     = "created_at"
+    type     = "timestamp"
+    encoding = "az64"
+  }
+}
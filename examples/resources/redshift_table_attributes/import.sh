@@ -0,0 +1,2 @@
+# The ID is the schema and the name of the table
+terraform import redshift_table_attributes.events "analytics.events"
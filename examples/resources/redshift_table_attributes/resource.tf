@@ -0,0 +1,9 @@
+# The table is created by dbt, only its owner and distribution are managed by Terraform.
+resource "redshift_table_attributes" "events" {
+  schema    = "analytics"
+  name      = "events"
+  owner     = "dbt"
+  diststyle = "KEY"
+  distkey   = "user_id"
+  sortkeys  = ["created_at"]
+}
@@ -0,0 +1,2 @@
+# The ID is the comma separated list of the sorted user names
+terraform import redshift_user_limits.analysts "alice,bob"
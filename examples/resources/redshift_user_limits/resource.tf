@@ -0,0 +1,10 @@
+resource "redshift_user_limits" "analysts" {
+  users = [
+    redshift_user.alice.name,
+    redshift_user.bob.name,
+  ]
+
+  # 30 minutes
+  statement_timeout = 1800000
+  session_timeout   = 3600
+}
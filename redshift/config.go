@@ -2,14 +2,38 @@ package redshift
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	_ "embed"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"net"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+const (
+	defaultConnectTimeout = 180 * time.Second
+	defaultRetryAttempts  = 10
+	defaultRetryBackoff   = time.Second
+
+	// pqErrorCodeCannotConnectNow is reported while the cluster is starting up or shutting down.
+	pqErrorCodeCannotConnectNow = "57P03"
+)
+
+// awsCABundle holds the certificate authorities signing the Amazon Redshift server certificates,
+// as published by AWS in https://s3.amazonaws.com/redshift-downloads/amazon-trust-ca-bundle.crt.
+//
+//go:embed redshift-ca-bundle.crt
+var awsCABundle string
+
 var (
 	dbRegistryLock sync.Mutex
 	dbRegistry     map[string]*DBConnection = make(map[string]*DBConnection, 1)
@@ -17,6 +41,8 @@ var (
 
 // Config - provider config
 type Config struct {
+	// Host is the address of the cluster. Several comma separated addresses can be given,
+	// which are tried in order when connecting.
 	Host     string
 	Username string
 	Password string
@@ -24,6 +50,53 @@ type Config struct {
 	Database string
 	SSLMode  string
 	MaxConns int
+
+	// SSLServerName overrides the host name used to verify the server certificate.
+	SSLServerName string
+
+	// UseAWSCABundle verifies the server certificate against the bundled Amazon Redshift certificate authorities.
+	UseAWSCABundle bool
+
+	// StatementTimeout limits the duration of the statements run in transactions, in seconds. 0 means no limit.
+	StatementTimeout int
+
+	// WaitForClusterAvailable delays statements modifying the database while the cluster is being resized or restored.
+	WaitForClusterAvailable bool
+
+	// ReadOnly makes the operations creating, updating or deleting resources fail, see RedshiftResourceFunc.
+	ReadOnly bool
+
+	// CaseSensitiveIdentifiers reflects the enable_case_sensitive_identifier setting of the cluster.
+	// When false, identifiers are folded to lower case like Redshift does.
+	CaseSensitiveIdentifiers bool
+
+	// WarnOnUnquotedIdentifiers logs a warning for every name of a modified resource which requires quoting.
+	WarnOnUnquotedIdentifiers bool
+
+	// ConnectTimeout limits the time to establish a connection. 0 means defaultConnectTimeout.
+	ConnectTimeout time.Duration
+
+	// MaxConnectionLifetime closes the connections after they have been open for that long. 0 means no limit.
+	MaxConnectionLifetime time.Duration
+
+	// RetryAttempts and RetryBackoff control how often the connection and the statements failing with
+	// transient errors are tried, see RedshiftResourceRetryOnPQErrors. The delay grows linearly with
+	// RetryBackoff after each attempt. 0 means defaultRetryAttempts and defaultRetryBackoff.
+	RetryAttempts int
+	RetryBackoff  time.Duration
+
+	// ReadOnlyUsername and ReadOnlyPassword are used instead of Username and Password
+	// for operations which do not modify the database (refresh, plan, data sources).
+	ReadOnlyUsername string
+	ReadOnlyPassword string
+
+	// endpoint discovers Host and Port on the first connection when cluster_identifier or workgroup_name is set.
+	// It is kept in the configuration so that the endpoint is discovered once for all the clients.
+	endpoint *endpointDiscovery
+
+	// stats count the statements run by the clients of the resource operation, see RedshiftResourceFunc.
+	// It is kept in the configuration so that the clients for other databases keep counting in the same stats.
+	stats *statementStats
 }
 
 // Client struct holding connection string
@@ -48,43 +121,173 @@ func (c *Config) NewClient(database string) *Client {
 	}
 }
 
+// ReadOnly returns a client connecting with the read-only credentials.
+// If no read-only credentials are configured, the client itself is returned.
+func (c *Client) ReadOnly() *Client {
+	if c.config.ReadOnlyUsername == "" {
+		return c
+	}
+
+	config := c.config
+	config.Username = config.ReadOnlyUsername
+	config.Password = config.ReadOnlyPassword
+	config.ReadOnlyUsername = ""
+	config.ReadOnlyPassword = ""
+
+	return config.NewClient(c.databaseName)
+}
+
+// normalizeIdentifier returns the identifier the way Redshift stores it in the catalog.
+func (c *Client) normalizeIdentifier(name string) string {
+	if c.config.CaseSensitiveIdentifiers {
+		return name
+	}
+	return strings.ToLower(name)
+}
+
+// unquotedIdentifierPattern matches the identifiers which are kept as is by Redshift without quoting.
+var unquotedIdentifierPattern = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+
+// identifierRequiresQuoting reports whether the identifier would be changed or rejected by Redshift unless quoted,
+// including reserved words.
+func identifierRequiresQuoting(name string) bool {
+	return !unquotedIdentifierPattern.MatchString(name) || sliceContainsStr(reservedWords, name)
+}
+
 // Connect returns a copy to an sql.Open()'ed database connection wrapped in a DBConnection struct.
 // Callers must return their database resources. Use of QueryRow() or Exec() is encouraged.
 // Query() must have their rows.Close()'ed.
 func (c *Client) Connect() (*DBConnection, error) {
+	config, err := c.config.withDiscoveredEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	dsn := config.connStr(c.databaseName)
+
+	dbRegistryLock.Lock()
+	conn, found := dbRegistry[dsn]
+	dbRegistryLock.Unlock()
+	if found {
+		return &DBConnection{conn.DB, c}, nil
+	}
+
+	db, err := config.open(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("Error connecting to PostgreSQL server %s: %w", config.Host, err)
+	}
+
+	// We don't want to retain connection
+	// So when we connect on a specific database which might be managed by terraform,
+	// we don't keep opened connection in case of the db has to be dopped in the plan.
+	db.SetMaxIdleConns(0)
+	db.SetMaxOpenConns(config.MaxConns)
+	db.SetConnMaxLifetime(config.MaxConnectionLifetime)
+
+	// The ping may be retried for a while, so it is done without holding the lock,
+	// which would block the connections to the other databases.
+	if err := config.ping(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Error connecting to PostgreSQL server %s: %w", config.Host, err)
+	}
+
 	dbRegistryLock.Lock()
 	defer dbRegistryLock.Unlock()
 
-	dsn := c.config.connStr(c.databaseName)
-	conn, found := dbRegistry[dsn]
-	if !found {
-		db, err := sql.Open(proxyDriverName, dsn)
-		if err != nil {
-			return nil, fmt.Errorf("Error connecting to PostgreSQL server %s: %w", c.config.Host, err)
-		}
+	// Another resource may have connected to the same database in the meantime.
+	if conn, found := dbRegistry[dsn]; found {
+		db.Close()
+		return &DBConnection{conn.DB, c}, nil
+	}
+	dbRegistry[dsn] = &DBConnection{db, c}
 
-		// We don't want to retain connection
-		// So when we connect on a specific database which might be managed by terraform,
-		// we don't keep opened connection in case of the db has to be dopped in the plan.
-		db.SetMaxIdleConns(0)
-		db.SetMaxOpenConns(c.config.MaxConns)
+	return &DBConnection{db, c}, nil
+}
 
-		conn = &DBConnection{
-			db,
-			c,
+// ping checks that the database can be reached, retrying on connection errors,
+// e.g. while a paused Redshift Serverless workgroup resumes or during a maintenance window.
+func (c *Config) ping(db *sql.DB) error {
+	var err error
+	for attempt := 1; attempt <= c.retryAttempts(); attempt++ {
+		if err = db.Ping(); err == nil || !isRetryableConnectionError(err) {
+			return err
+		}
+		if attempt < c.retryAttempts() {
+			delay := c.retryDelay(attempt)
+			log.Printf("[WARN] could not connect to %s (attempt %d of %d), retrying in %s: %v", c.Host, attempt, c.retryAttempts(), delay, err)
+			time.Sleep(delay)
 		}
-		dbRegistry[dsn] = conn
 	}
+	return err
+}
 
-	return conn, nil
+func (c *Config) retryAttempts() int {
+	if c.RetryAttempts > 0 {
+		return c.RetryAttempts
+	}
+	return defaultRetryAttempts
+}
+
+// retryDelay returns the delay before retrying after the failed attempt, starting at 1.
+func (c *Config) retryDelay(attempt int) time.Duration {
+	backoff := c.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	return time.Duration(attempt) * backoff
+}
+
+// isRetryableConnectionError reports whether the error is a failure to reach the database,
+// rather than an error reported by the database, e.g. because of invalid credentials.
+func isRetryableConnectionError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code.Class() == "08" || pqErr.Code == pqErrorCodeCannotConnectNow
+	}
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr)
+}
+
+// open opens the database handle. When SSLServerName is set, the connection string
+// refers to SSLServerName (which is then used for TLS verification),
+// but the connection itself is established to Host.
+// When several hosts are configured, they are used for failover.
+func (c *Config) open(dsn string) (*sql.DB, error) {
+	hosts := c.hosts()
+	if c.SSLServerName == "" && len(hosts) <= 1 {
+		return sql.Open(proxyDriverName, dsn)
+	}
+
+	return sql.OpenDB(&proxyConnector{
+		dsn:   dsn,
+		hosts: hosts,
+	}), nil
+}
+
+// hosts returns the addresses listed in Host.
+func (c *Config) hosts() []string {
+	hosts := []string{}
+	for _, host := range strings.Split(c.Host, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
 }
 
 func (c *Config) connStr(database string) string {
+	host := ""
+	if hosts := c.hosts(); len(hosts) > 0 {
+		host = hosts[0]
+	}
+	if c.SSLServerName != "" {
+		host = c.SSLServerName
+	}
+
 	connStr := fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s?%s",
 		url.QueryEscape(c.Username),
 		url.QueryEscape(c.Password),
-		c.Host,
+		host,
 		c.Port,
 		database,
 		strings.Join(c.connParams(), "&"),
@@ -97,7 +300,15 @@ func (c *Config) connParams() []string {
 	params := map[string]string{}
 
 	params["sslmode"] = c.SSLMode
-	params["connect_timeout"] = "180"
+	connectTimeout := c.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = defaultConnectTimeout
+	}
+	params["connect_timeout"] = strconv.Itoa(int(connectTimeout.Seconds()))
+	if c.UseAWSCABundle {
+		params["sslrootcert"] = awsCABundle
+		params["sslinline"] = "true"
+	}
 
 	paramsArray := []string{}
 	for key, value := range params {
@@ -0,0 +1,181 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestClientReadOnly(t *testing.T) {
+	config := Config{
+		Host:     "localhost",
+		Username: "admin",
+		Password: "secret",
+		Database: "redshift",
+	}
+
+	client := config.NewClient("db")
+	if client.ReadOnly() != client {
+		t.Fatal("expected the same client when read-only credentials are not configured")
+	}
+
+	config.ReadOnlyUsername = "reader"
+	config.ReadOnlyPassword = "reader-secret"
+	client = config.NewClient("db")
+	readOnly := client.ReadOnly()
+
+	if readOnly.config.Username != "reader" || readOnly.config.Password != "reader-secret" {
+		t.Errorf("expected read-only credentials, got %q", readOnly.config.Username)
+	}
+	if readOnly.databaseName != "db" {
+		t.Errorf("expected database %q, got %q", "db", readOnly.databaseName)
+	}
+	if readOnly.ReadOnly() != readOnly {
+		t.Error("expected read-only client to not switch credentials again")
+	}
+	if client.config.Username != "admin" {
+		t.Errorf("expected privileged client to keep its credentials, got %q", client.config.Username)
+	}
+}
+
+func TestConfigConnStrSSLServerName(t *testing.T) {
+	config := Config{
+		Host:          "nlb.example.com",
+		Username:      "admin",
+		Port:          5439,
+		SSLMode:       "verify-full",
+		SSLServerName: "cluster.example.com",
+	}
+
+	dsn := config.connStr("db")
+	expectedPrefix := "postgres://admin:@cluster.example.com:5439/db?"
+	if !strings.HasPrefix(dsn, expectedPrefix) {
+		t.Errorf("expected connection string to start with %q, got %q", expectedPrefix, dsn)
+	}
+}
+
+func TestConfigConnStrIPv6(t *testing.T) {
+	settings := connectionSettings{Port: 5439}
+	if err := settings.parseURL("postgres://admin@[::1]:5439/db"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config := Config{
+		Host:     settings.Host,
+		Username: settings.Username,
+		Port:     settings.Port,
+	}
+
+	dsn := config.connStr("db")
+	expectedPrefix := "postgres://admin:@[::1]:5439/db?"
+	if !strings.HasPrefix(dsn, expectedPrefix) {
+		t.Errorf("expected connection string to start with %q, got %q", expectedPrefix, dsn)
+	}
+}
+
+func TestClientNormalizeIdentifier(t *testing.T) {
+	config := Config{}
+	if result := config.NewClient("db").normalizeIdentifier("MyTable"); result != "mytable" {
+		t.Errorf("expected identifier to be folded to lower case, got %s", result)
+	}
+
+	config.CaseSensitiveIdentifiers = true
+	if result := config.NewClient("db").normalizeIdentifier("MyTable"); result != "MyTable" {
+		t.Errorf("expected identifier to keep its case, got %s", result)
+	}
+}
+
+func TestConfigHosts(t *testing.T) {
+	config := Config{
+		Host:     "primary.example.com, standby.example.com,",
+		Username: "admin",
+		Port:     5439,
+	}
+
+	hosts := config.hosts()
+	if len(hosts) != 2 || hosts[0] != "primary.example.com" || hosts[1] != "standby.example.com" {
+		t.Errorf("expected primary and standby hosts, got %v", hosts)
+	}
+
+	dsn := config.connStr("db")
+	expectedPrefix := "postgres://admin:@primary.example.com:5439/db?"
+	if !strings.HasPrefix(dsn, expectedPrefix) {
+		t.Errorf("expected connection string to start with %q, got %q", expectedPrefix, dsn)
+	}
+}
+
+func TestConfigConnParamsAWSCABundle(t *testing.T) {
+	config := Config{
+		Host:     "cluster.example.com",
+		Username: "admin",
+		Port:     5439,
+		SSLMode:  "verify-full",
+	}
+
+	if dsn := config.connStr("db"); strings.Contains(dsn, "sslrootcert") {
+		t.Errorf("expected no root certificate by default, got %q", dsn)
+	}
+
+	config.UseAWSCABundle = true
+	params := strings.Join(config.connParams(), "&")
+	if !strings.Contains(params, "sslinline=true") || !strings.Contains(params, "sslrootcert=") {
+		t.Errorf("expected the inline CA bundle in the connection parameters, got %q", params)
+	}
+	if strings.Count(awsCABundle, "BEGIN CERTIFICATE") == 0 {
+		t.Error("expected the embedded CA bundle to contain certificates")
+	}
+}
+
+func TestConfigConnParamsConnectTimeout(t *testing.T) {
+	config := Config{}
+	if params := strings.Join(config.connParams(), "&"); !strings.Contains(params, "connect_timeout=180") {
+		t.Errorf("expected the default connect timeout, got %q", params)
+	}
+
+	config.ConnectTimeout = 30 * time.Second
+	if params := strings.Join(config.connParams(), "&"); !strings.Contains(params, "connect_timeout=30") {
+		t.Errorf("expected the configured connect timeout, got %q", params)
+	}
+}
+
+func TestIsRetryableConnectionError(t *testing.T) {
+	cases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"dial failure":         {&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		"bad connection":       {driver.ErrBadConn, true},
+		"unexpected EOF":       {fmt.Errorf("reading: %w", io.ErrUnexpectedEOF), true},
+		"connection exception": {&pq.Error{Code: "08006"}, true},
+		"cannot connect now":   {&pq.Error{Code: pqErrorCodeCannotConnectNow}, true},
+		"invalid password":     {&pq.Error{Code: "28P01"}, false},
+		"concurrent update":    {&pq.Error{Code: pqErrorCodeConcurrent}, false},
+		"other":                {errors.New("syntax error"), false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := isRetryableConnectionError(c.err); actual != c.expected {
+				t.Errorf("expected %t, got %t", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestConfigRetryDelay(t *testing.T) {
+	config := Config{}
+	if config.retryAttempts() != defaultRetryAttempts || config.retryDelay(2) != 2*defaultRetryBackoff {
+		t.Errorf("unexpected default retries: %d attempts, %s delay", config.retryAttempts(), config.retryDelay(2))
+	}
+
+	config = Config{RetryAttempts: 3, RetryBackoff: 5 * time.Second}
+	if config.retryAttempts() != 3 || config.retryDelay(1) != 5*time.Second || config.retryDelay(3) != 15*time.Second {
+		t.Errorf("unexpected retries: %d attempts, %s delay", config.retryAttempts(), config.retryDelay(3))
+	}
+}
@@ -2,6 +2,7 @@ package redshift
 
 import (
 	"context"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -14,3 +15,43 @@ func forceNewIfListSizeChanged(key string) schema.CustomizeDiffFunc {
 func listSizeChanged(ctx context.Context, old, new, meta interface{}) bool {
 	return len(old.([]interface{})) != len(new.([]interface{}))
 }
+
+// forceNewIfSetEmptied forces a new resource when the set attribute becomes empty or stops being empty,
+// while changes of its elements are applied in place.
+func forceNewIfSetEmptied(key string) schema.CustomizeDiffFunc {
+	return customdiff.ForceNewIfChange(key, func(ctx context.Context, old, new, meta interface{}) bool {
+		return (old.(*schema.Set).Len() == 0) != (new.(*schema.Set).Len() == 0)
+	})
+}
+
+// computedIfAnyChanged marks the computed attribute as unknown in the plan
+// when any of the given attributes is about to change.
+func computedIfAnyChanged(computed string, keys ...string) schema.CustomizeDiffFunc {
+	return customdiff.ComputedIf(computed, func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+		if d.Id() == "" {
+			return false
+		}
+		for _, key := range keys {
+			if d.HasChange(key) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// normalizedNameDiff plans the normalized_name attribute from the name attribute, so that it is known before applying.
+func normalizedNameDiff(nameAttr string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		client, ok := meta.(*Client)
+		if !ok || !d.NewValueKnown(nameAttr) || (d.Id() != "" && !d.HasChange(nameAttr)) {
+			return nil
+		}
+		return d.SetNew(normalizedNameAttr, normalizeName(client, d.Get(nameAttr).(string)))
+	}
+}
+
+// normalizeName returns the name as normalized by the provider, see normalizedNameAttr.
+func normalizeName(client *Client, name string) string {
+	return client.normalizeIdentifier(strings.TrimSpace(name))
+}
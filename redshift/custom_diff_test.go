@@ -0,0 +1,37 @@
+package redshift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestNormalizedNameDiff(t *testing.T) {
+	tests := map[string]struct {
+		caseSensitive bool
+		expected      string
+	}{
+		"case insensitive": {caseSensitive: false, expected: "analysts"},
+		"case sensitive":   {caseSensitive: true, expected: "Analysts"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := &Client{config: Config{CaseSensitiveIdentifiers: tt.caseSensitive}}
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{"name": " Analysts "})
+
+			diff, err := redshiftUser().Diff(context.Background(), nil, config, client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			attr, ok := diff.Attributes[normalizedNameAttr]
+			if !ok {
+				t.Fatalf("expected %s to be planned", normalizedNameAttr)
+			}
+			if attr.NewComputed || attr.New != tt.expected {
+				t.Errorf("expected %s to be planned as %q, got %q (computed: %t)", normalizedNameAttr, tt.expected, attr.New, attr.NewComputed)
+			}
+		})
+	}
+}
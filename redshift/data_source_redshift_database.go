@@ -10,7 +10,7 @@ import (
 func dataSourceRedshiftDatabase() *schema.Resource {
 	return &schema.Resource{
 		Description: `Fetches information about a Redshift database.`,
-		Read:        RedshiftResourceFunc(dataSourceRedshiftDatabaseRead),
+		Read:        RedshiftResourceReadFunc(dataSourceRedshiftDatabaseRead),
 		Schema: map[string]*schema.Schema{
 			databaseNameAttr: {
 				Type:        schema.TypeString,
@@ -30,6 +30,8 @@ func dataSourceRedshiftDatabase() *schema.Resource {
 				Computed:    true,
 				Description: "The maximum number of concurrent connections that can be made to this database. A value of -1 means no limit.",
 			},
+			databaseIsolationLevelAttr: databaseIsolationLevelSchema(),
+			databaseCollationAttr:      databaseCollationSchema(),
 			databaseDatashareSourceAttr: {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -69,13 +71,14 @@ func dataSourceRedshiftDatabase() *schema.Resource {
 }
 
 func dataSourceRedshiftDatabaseRead(db *DBConnection, d *schema.ResourceData) error {
-	var id, owner, connLimit, databaseType, shareName, producerAccount, producerNamespace string
+	var id, owner, connLimit, databaseType, isolationLevel, shareName, producerAccount, producerNamespace string
 
 	err := db.QueryRow(`SELECT
   pg_database_info.datid,
   trim(pg_user_info.usename),
   COALESCE(pg_database_info.datconnlimit::text, 'UNLIMITED'),
 	svv_redshift_databases.database_type,
+  trim(COALESCE(svv_redshift_databases.database_isolation_level, '')),
   trim(COALESCE(svv_datashares.share_name, '')),
   trim(COALESCE(svv_datashares.producer_account, '')),
   trim(COALESCE(svv_datashares.producer_namespace, ''))
@@ -88,12 +91,14 @@ LEFT JOIN pg_user_info
 LEFT JOIN svv_datashares
 	ON (svv_redshift_databases.database_name = svv_datashares.consumer_database AND svv_redshift_databases.database_type = 'shared' AND svv_datashares.share_type = 'INBOUND')
 WHERE svv_redshift_databases.database_name = $1
-	`, d.Get(databaseNameAttr).(string)).Scan(&id, &owner, &connLimit, &databaseType, &shareName, &producerAccount, &producerNamespace)
+	`, d.Get(databaseNameAttr).(string)).Scan(&id, &owner, &connLimit, &databaseType, &isolationLevel, &shareName, &producerAccount, &producerNamespace)
 
 	if err != nil {
 		return err
 	}
 
+	collation := readDatabaseCollation(db, d.Get(databaseNameAttr).(string), databaseType)
+
 	connLimitNumber := -1
 	if connLimit != "UNLIMITED" {
 		if connLimitNumber, err = strconv.Atoi(connLimit); err != nil {
@@ -104,6 +109,8 @@ WHERE svv_redshift_databases.database_name = $1
 	d.SetId(id)
 	d.Set(databaseOwnerAttr, owner)
 	d.Set(databaseConnLimitAttr, connLimitNumber)
+	d.Set(databaseIsolationLevelAttr, isolationLevel)
+	d.Set(databaseCollationAttr, collation)
 
 	dataShareConfiguration := make([]map[string]interface{}, 0, 1)
 	if databaseType == "shared" {
@@ -22,6 +22,8 @@ func TestAccDataSourceRedshiftDatabase_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("data.redshift_database.db", databaseNameAttr, dbName),
 					resource.TestCheckResourceAttrSet("data.redshift_database.db", databaseOwnerAttr),
 					resource.TestCheckResourceAttrSet("data.redshift_database.db", databaseConnLimitAttr),
+					resource.TestCheckResourceAttrSet("data.redshift_database.db", databaseIsolationLevelAttr),
+					resource.TestCheckResourceAttr("data.redshift_database.db", databaseCollationAttr, "case_sensitive"),
 					resource.TestCheckResourceAttr("data.redshift_database.db", fmt.Sprintf("%s.#", databaseDatashareSourceAttr), "0"),
 				),
 			},
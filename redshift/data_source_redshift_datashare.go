@@ -0,0 +1,200 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	dataShareShareTypeAttr        = "share_type"
+	dataShareObjectsAttr          = "objects"
+	dataShareObjectTypeAttr       = "type"
+	dataShareObjectNameAttr       = "name"
+	dataShareObjectIncludeNewAttr = "include_new"
+)
+
+func dataSourceRedshiftDatashare() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Fetches information about a datashare of the producer cluster (` + "`OUTBOUND`" + `) or shared with the consumer cluster (` + "`INBOUND`" + `), e.g. to create a database from an inbound datashare without hardcoding the namespace of the producer.
+`,
+		Read: RedshiftResourceReadFunc(dataSourceRedshiftDatashareRead),
+		Schema: map[string]*schema.Schema{
+			dataShareNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the datashare.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			dataShareShareTypeAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "OUTBOUND",
+				ValidateFunc: validation.StringInSlice([]string{"INBOUND", "OUTBOUND"}, false),
+				Description:  "The type of the datashare, `OUTBOUND` (default) for datashares of the cluster, or `INBOUND` for datashares shared with the cluster by a producer.",
+			},
+			dataShareProducerNamespaceAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The namespace (guid) of the producer cluster. Can be set to choose between inbound datashares of the same name from several producers.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			dataShareProducerAccountAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The AWS account ID of the producer cluster.",
+			},
+			dataShareOwnerAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The user who owns the datashare. Empty for inbound datashares.",
+			},
+			dataSharePublicAccessibleAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the datashare can be shared to clusters that are publicly accessible.",
+			},
+			dataShareCreatedAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date when datashare was created. Empty for inbound datashares.",
+			},
+			dataShareObjectsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The objects included in the datashare, ordered by type and name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						dataShareObjectTypeAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the object, e.g. `schema`, `table`, `view` or `function`.",
+						},
+						dataShareObjectNameAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the object, qualified by its schema for objects other than schemas.",
+						},
+						dataShareObjectIncludeNewAttr: {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the new tables, views and functions of a schema are added to the datashare automatically.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftDatashareRead(db *DBConnection, d *schema.ResourceData) error {
+	var shareName, owner, producerAccount, producerNamespace, created string
+	var publicAccessible bool
+
+	name := strings.ToLower(d.Get(dataShareNameAttr).(string))
+	shareType := d.Get(dataShareShareTypeAttr).(string)
+	namespace := strings.ToLower(d.Get(dataShareProducerNamespaceAttr).(string))
+
+	query := `
+	SELECT
+		trim(svv_datashares.share_name),
+		trim(COALESCE(pg_user.usename, '')),
+		COALESCE(svv_datashares.is_publicaccessible, false),
+		TRIM(COALESCE(svv_datashares.producer_account, '')),
+		TRIM(COALESCE(svv_datashares.producer_namespace, '')),
+		COALESCE(REPLACE(TO_CHAR(svv_datashares.createdate, 'YYYY-MM-DD HH24:MI:SS'), ' ', 'T') || 'Z', '')
+	FROM svv_datashares
+	LEFT JOIN pg_user ON svv_datashares.share_owner = pg_user.usesysid
+	WHERE share_type = $1
+	AND lower(share_name) = $2`
+	args := []interface{}{shareType, name}
+	if namespace != "" {
+		query += `
+	AND lower(producer_namespace) = $3`
+		args = append(args, namespace)
+	}
+	log.Printf("[DEBUG] %s, %v\n", query, args)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	found := 0
+	for rows.Next() {
+		if err := rows.Scan(&shareName, &owner, &publicAccessible, &producerAccount, &producerNamespace, &created); err != nil {
+			return err
+		}
+		found++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	switch {
+	case found == 0:
+		return fmt.Errorf("%s datashare %s not found: %w", shareType, name, sql.ErrNoRows)
+	case found > 1:
+		return fmt.Errorf("found %d %s datashares named %s, set %s to choose one of them", found, shareType, name, dataShareProducerNamespaceAttr)
+	}
+
+	objects, err := readDatashareObjects(db, shareType, shareName, producerNamespace)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s:%s", strings.ToLower(shareType), producerNamespace, shareName))
+	d.Set(dataShareNameAttr, shareName)
+	d.Set(dataShareOwnerAttr, owner)
+	d.Set(dataSharePublicAccessibleAttr, publicAccessible)
+	d.Set(dataShareProducerAccountAttr, producerAccount)
+	d.Set(dataShareProducerNamespaceAttr, producerNamespace)
+	d.Set(dataShareCreatedAttr, created)
+	d.Set(dataShareObjectsAttr, objects)
+
+	return nil
+}
+
+// readDatashareObjects lists the objects of the datashare from svv_datashare_objects.
+func readDatashareObjects(db *DBConnection, shareType, shareName, producerNamespace string) ([]map[string]interface{}, error) {
+	query := `
+	SELECT
+		trim(object_type),
+		trim(object_name),
+		COALESCE(include_new, false)
+	FROM svv_datashare_objects
+	WHERE share_type = $1
+	AND share_name = $2
+	AND TRIM(COALESCE(producer_namespace, '')) = $3
+	ORDER BY 1, 2`
+	log.Printf("[DEBUG] %s, $1=%s, $2=%s, $3=%s\n", query, shareType, shareName, producerNamespace)
+	rows, err := db.Query(query, shareType, shareName, producerNamespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	objects := []map[string]interface{}{}
+	for rows.Next() {
+		var objectType, objectName string
+		var includeNew bool
+		if err := rows.Scan(&objectType, &objectName, &includeNew); err != nil {
+			return nil, err
+		}
+		objects = append(objects, map[string]interface{}{
+			dataShareObjectTypeAttr:       objectType,
+			dataShareObjectNameAttr:       objectName,
+			dataShareObjectIncludeNewAttr: includeNew,
+		})
+	}
+	return objects, rows.Err()
+}
@@ -0,0 +1,57 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRedshiftDatashare_Outbound(t *testing.T) {
+	_ = getEnvOrSkip("REDSHIFT_DATASHARE_SUPPORTED", t)
+	shareName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_data_datashare"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_schema" "schema" {
+	%[1]s = %[2]q
+	%[3]s = true
+}
+
+resource "redshift_datashare" "share" {
+	%[4]s = %[2]q
+	%[5]s = true
+	%[6]s = [
+		redshift_schema.schema.%[1]s,
+	]
+}
+
+data "redshift_datashare" "share" {
+	%[4]s = redshift_datashare.share.%[4]s
+	%[7]s = "OUTBOUND"
+
+	depends_on = [redshift_datashare.share]
+}
+`, schemaNameAttr, shareName, schemaCascadeOnDeleteAttr, dataShareNameAttr, dataSharePublicAccessibleAttr, dataShareSchemasAttr, dataShareShareTypeAttr)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftDatashareDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redshift_datashare.share", dataShareNameAttr, shareName),
+					resource.TestCheckResourceAttr("data.redshift_datashare.share", dataSharePublicAccessibleAttr, "true"),
+					resource.TestCheckResourceAttrPair("data.redshift_datashare.share", dataShareProducerNamespaceAttr, "redshift_datashare.share", dataShareProducerNamespaceAttr),
+					resource.TestCheckResourceAttrPair("data.redshift_datashare.share", dataShareProducerAccountAttr, "redshift_datashare.share", dataShareProducerAccountAttr),
+					resource.TestCheckResourceAttrPair("data.redshift_datashare.share", dataShareOwnerAttr, "redshift_datashare.share", dataShareOwnerAttr),
+					resource.TestCheckTypeSetElemNestedAttrs("data.redshift_datashare.share", fmt.Sprintf("%s.*", dataShareObjectsAttr), map[string]string{
+						dataShareObjectTypeAttr: "schema",
+						dataShareObjectNameAttr: shareName,
+					}),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,195 @@
+package redshift
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	externalSchemasSourceTypeAttr      = "source_type"
+	externalSchemasAttr                = "external_schemas"
+	externalSchemasNameAttr            = "name"
+	externalSchemasOwnerAttr           = "owner"
+	externalSchemasDatabaseNameAttr    = "database_name"
+	externalSchemasIAMRoleARNsAttr     = "iam_role_arns"
+	externalSchemasCatalogRoleARNsAttr = "catalog_role_arns"
+	externalSchemasRegionAttr          = "region"
+	externalSchemasURIAttr             = "uri"
+	externalSchemasPortAttr            = "port"
+	externalSchemasSourceSchemaAttr    = "source_schema"
+	externalSchemasSecretARNAttr       = "secret_arn"
+)
+
+func dataSourceRedshiftExternalSchemas() *schema.Resource {
+	sourceTypes := []string{}
+	for _, source := range externalSchemaSources {
+		sourceTypes = append(sourceTypes, strings.TrimSuffix(source, "_source"))
+	}
+
+	return &schema.Resource{
+		Description: `
+Lists the external schemas of the database the provider connects to, with the details of their source (from ` + "`svv_external_schemas`" + `), e.g. to inventory the Redshift Spectrum schemas or to grant privileges on all of them.
+`,
+		Read: RedshiftResourceReadFunc(dataSourceRedshiftExternalSchemasRead),
+		Schema: map[string]*schema.Schema{
+			externalSchemasSourceTypeAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(sourceTypes, false),
+				Description:  "Only list the external schemas with this source, one of `" + strings.Join(sourceTypes, "`, `") + "`. All external schemas are listed by default.",
+			},
+			externalSchemasAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The external schemas, ordered by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						externalSchemasNameAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the external schema.",
+						},
+						externalSchemasOwnerAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the owner of the external schema.",
+						},
+						externalSchemasSourceTypeAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The source of the external schema, one of `" + strings.Join(sourceTypes, "`, `") + "`, or `unknown` for sources not supported by the provider.",
+						},
+						externalSchemasDatabaseNameAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the external database.",
+						},
+						externalSchemasIAMRoleARNsAttr: {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The ARNs of the IAM roles used to access the source.",
+						},
+						externalSchemasCatalogRoleARNsAttr: {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The ARNs of the IAM roles used to access the data catalog. Only set for the `data_catalog` source.",
+						},
+						externalSchemasRegionAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The AWS region of the data catalog. Only set for the `data_catalog` source.",
+						},
+						externalSchemasURIAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The hostname of the Hive metastore or of the RDS database.",
+						},
+						externalSchemasPortAttr: {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The port of the Hive metastore or of the RDS database, `0` when not set.",
+						},
+						externalSchemasSourceSchemaAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the schema in the source database, for the `rds_postgres` and `redshift` sources.",
+						},
+						externalSchemasSecretARNAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ARN of the secret holding the credentials of the RDS database.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftExternalSchemasRead(db *DBConnection, d *schema.ResourceData) error {
+	query := `
+	SELECT
+		trim(es.schemaname),
+		trim(COALESCE(u.usename, '')),
+		CASE
+			WHEN es.eskind = 1 THEN 'data_catalog'
+			WHEN es.eskind = 2 THEN 'hive_metastore'
+			WHEN es.eskind = 3 THEN 'rds_postgres'
+			WHEN es.eskind = 4 THEN 'redshift'
+			WHEN es.eskind = 7 THEN 'rds_mysql'
+			ELSE 'unknown'
+		END,
+		trim(es.databasename),
+		COALESCE(CASE WHEN is_valid_json(es.esoptions) THEN json_extract_path_text(es.esoptions, 'IAM_ROLE') END, ''),
+		COALESCE(CASE WHEN is_valid_json(es.esoptions) THEN json_extract_path_text(es.esoptions, 'CATALOG_ROLE') END, ''),
+		COALESCE(CASE WHEN is_valid_json(es.esoptions) THEN json_extract_path_text(es.esoptions, 'REGION') END, ''),
+		COALESCE(CASE WHEN is_valid_json(es.esoptions) THEN json_extract_path_text(es.esoptions, 'SCHEMA') END, ''),
+		COALESCE(CASE WHEN is_valid_json(es.esoptions) THEN json_extract_path_text(es.esoptions, 'URI') END, ''),
+		COALESCE(CASE WHEN is_valid_json(es.esoptions) THEN json_extract_path_text(es.esoptions, 'PORT') END, ''),
+		COALESCE(CASE WHEN is_valid_json(es.esoptions) THEN json_extract_path_text(es.esoptions, 'SECRET_ARN') END, '')
+	FROM svv_external_schemas es
+	LEFT JOIN pg_user_info u ON u.usesysid = es.esowner
+	ORDER BY 1`
+	log.Printf("[DEBUG] %s\n", query)
+	rows, err := db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	sourceTypeFilter := d.Get(externalSchemasSourceTypeAttr).(string)
+	externalSchemas := []map[string]interface{}{}
+	for rows.Next() {
+		var name, owner, sourceType, databaseName, iamRole, catalogRole, region, sourceSchema, uri, port, secretARN string
+		if err := rows.Scan(&name, &owner, &sourceType, &databaseName, &iamRole, &catalogRole, &region, &sourceSchema, &uri, &port, &secretARN); err != nil {
+			return err
+		}
+		if sourceTypeFilter != "" && sourceType != sourceTypeFilter {
+			continue
+		}
+
+		iamRoleARNs, err := splitCsvAndTrim(iamRole)
+		if err != nil {
+			return fmt.Errorf("Error parsing the IAM roles of external schema %s: %w", name, err)
+		}
+		catalogRoleARNs, err := splitCsvAndTrim(catalogRole)
+		if err != nil {
+			return fmt.Errorf("Error parsing the catalog roles of external schema %s: %w", name, err)
+		}
+		portNum := 0
+		if port != "" {
+			if portNum, err = strconv.Atoi(port); err != nil {
+				return fmt.Errorf("port of external schema %s was not an integer", name)
+			}
+		}
+
+		externalSchemas = append(externalSchemas, map[string]interface{}{
+			externalSchemasNameAttr:            name,
+			externalSchemasOwnerAttr:           owner,
+			externalSchemasSourceTypeAttr:      sourceType,
+			externalSchemasDatabaseNameAttr:    databaseName,
+			externalSchemasIAMRoleARNsAttr:     iamRoleARNs,
+			externalSchemasCatalogRoleARNsAttr: catalogRoleARNs,
+			externalSchemasRegionAttr:          region,
+			externalSchemasURIAttr:             uri,
+			externalSchemasPortAttr:            portNum,
+			externalSchemasSourceSchemaAttr:    sourceSchema,
+			externalSchemasSecretARNAttr:       secretARN,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.SetId(db.client.databaseName)
+	d.Set(externalSchemasAttr, externalSchemas)
+
+	return nil
+}
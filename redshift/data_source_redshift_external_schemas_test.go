@@ -0,0 +1,84 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Acceptance test for listing external schemas using AWS Glue Data Catalog
+// The following environment variables must be set, otherwise the test will be skipped:
+//
+//	REDSHIFT_EXTERNAL_SCHEMA_DATA_CATALOG_DATABASE - source database name
+//	REDSHIFT_EXTERNAL_SCHEMA_DATA_CATALOG_IAM_ROLE_ARNS - comma-separated list of ARNs to use
+func TestAccDataSourceRedshiftExternalSchemas_DataCatalog(t *testing.T) {
+	dbName := getEnvOrSkip("REDSHIFT_EXTERNAL_SCHEMA_DATA_CATALOG_DATABASE", t)
+	iamRoleArnsRaw := getEnvOrSkip("REDSHIFT_EXTERNAL_SCHEMA_DATA_CATALOG_IAM_ROLE_ARNS", t)
+	iamRoleArns := strings.Split(iamRoleArnsRaw, ",")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_external_schemas_data_catalog"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_schema" "spectrum" {
+	%[1]s = %[2]q
+	%[3]s {
+		database_name = %[4]q
+		data_catalog_source {
+			iam_role_arns = %[5]s
+		}
+	}
+}
+
+data "redshift_external_schemas" "data_catalog" {
+	source_type = "data_catalog"
+
+	depends_on = [redshift_schema.spectrum]
+}
+
+data "redshift_external_schemas" "redshift" {
+	source_type = "redshift"
+
+	depends_on = [redshift_schema.spectrum]
+}
+`,
+		schemaNameAttr, schemaName, schemaExternalSchemaAttr, dbName, tfArray(iamRoleArns))
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftSchemaExists(schemaName),
+					resource.TestCheckTypeSetElemNestedAttrs("data.redshift_external_schemas.data_catalog", fmt.Sprintf("%s.*", externalSchemasAttr), map[string]string{
+						externalSchemasNameAttr:                             schemaName,
+						externalSchemasSourceTypeAttr:                       "data_catalog",
+						externalSchemasDatabaseNameAttr:                     dbName,
+						externalSchemasPortAttr:                             "0",
+						fmt.Sprintf("%s.#", externalSchemasIAMRoleARNsAttr): fmt.Sprintf("%d", len(iamRoleArns)),
+						fmt.Sprintf("%s.0", externalSchemasIAMRoleARNsAttr): iamRoleArns[0],
+					}),
+					testAccCheckRedshiftExternalSchemasMissing("data.redshift_external_schemas.redshift", schemaName),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftExternalSchemasMissing(dataSource, schemaName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[dataSource]
+		if !ok {
+			return fmt.Errorf("Not found: %s", dataSource)
+		}
+		for key, value := range rs.Primary.Attributes {
+			if strings.HasPrefix(key, externalSchemasAttr+".") && strings.HasSuffix(key, "."+externalSchemasNameAttr) && value == schemaName {
+				return fmt.Errorf("External schema %s should have been filtered out of %s", schemaName, dataSource)
+			}
+		}
+		return nil
+	}
+}
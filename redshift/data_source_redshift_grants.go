@@ -0,0 +1,257 @@
+package redshift
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	grantsAttr                = "grants"
+	grantsObjectTypeAttr      = "object_type"
+	grantsDatabaseAttr        = "database"
+	grantsSchemaAttr          = "schema"
+	grantsObjectAttr          = "object"
+	grantsPrivilegeAttr       = "privilege"
+	grantsWithGrantOptionAttr = "with_grant_option"
+	grantsGranteeTypeAttr     = "grantee_type"
+	grantsGranteeNameAttr     = "grantee_name"
+)
+
+// grantsObjectTypes are the object types listed by the redshift_grants data source, in order.
+var grantsObjectTypes = []string{"database", "schema", "table", "function"}
+
+// grantsQueries list the privileges granted to the identities ($1, as "type:name") and to PUBLIC for each object type,
+// as database, schema, object, privilege, grant option, grantee type and grantee name.
+// Objects other than databases are listed in the current database ($2).
+var grantsQueries = map[string]string{
+	"database": `
+	SELECT trim(database_name), '', '', lower(privilege_type), admin_option, identity_type, trim(identity_name)
+	FROM svv_database_privileges
+	WHERE identity_type || ':' || identity_name = ANY($1) OR identity_type = 'public'
+	ORDER BY 1, 4
+`,
+	"schema": `
+	SELECT $2, trim(namespace_name), '', lower(privilege_type), admin_option, identity_type, trim(identity_name)
+	FROM svv_schema_privileges
+	WHERE identity_type || ':' || identity_name = ANY($1) OR identity_type = 'public'
+	ORDER BY 2, 4
+`,
+	"table": `
+	SELECT $2, trim(namespace_name), trim(relation_name), lower(privilege_type), admin_option, identity_type, trim(identity_name)
+	FROM svv_relation_privileges
+	WHERE identity_type || ':' || identity_name = ANY($1) OR identity_type = 'public'
+	ORDER BY 2, 3, 4
+`,
+	"function": `
+	SELECT $2, trim(namespace_name), trim(function_name), lower(privilege_type), admin_option, identity_type, trim(identity_name)
+	FROM svv_function_privileges
+	WHERE identity_type || ':' || identity_name = ANY($1) OR identity_type = 'public'
+	ORDER BY 2, 3, 4
+`,
+}
+
+func dataSourceRedshiftGrants() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Lists the effective privileges of a user, group or role: those granted to it directly, through its groups and roles (including nested roles) and to PUBLIC.
+Privileges on databases are listed for all databases, while privileges on schemas, tables and views, functions and procedures are listed for the database the provider connects to.
+Superusers have all privileges, which are not listed. This is useful to audit privileges, e.g. with ` + "`check`" + ` blocks asserting that a group never has the ` + "`delete`" + ` privilege.
+`,
+		Read: RedshiftResourceReadFunc(dataSourceRedshiftGrantsRead),
+		Schema: map[string]*schema.Schema{
+			grantUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{grantUserAttr, grantGroupAttr, grantRoleAttr},
+				Description:  "The name of the user to list the privileges of.",
+			},
+			grantGroupAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{grantUserAttr, grantGroupAttr, grantRoleAttr},
+				Description:  "The name of the group to list the privileges of.",
+			},
+			grantRoleAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{grantUserAttr, grantGroupAttr, grantRoleAttr},
+				Description:  "The name of the role to list the privileges of.",
+			},
+			grantsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The privileges, ordered by object type (`database`, `schema`, `table`, `function`) and object.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						grantsObjectTypeAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the object, one of `database`, `schema`, `table` (also for views) or `function` (also for procedures).",
+						},
+						grantsDatabaseAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the database of the object.",
+						},
+						grantsSchemaAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the schema of the object. Empty for databases.",
+						},
+						grantsObjectAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the table or function. Empty for databases and schemas.",
+						},
+						grantsPrivilegeAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The privilege, in lower case, e.g. `select` or `delete`.",
+						},
+						grantsWithGrantOptionAttr: {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the privilege was granted with the grant option.",
+						},
+						grantsGranteeTypeAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the identity the privilege was granted to, one of `user`, `group`, `role` or `public`.",
+						},
+						grantsGranteeNameAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the identity the privilege was granted to.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftGrantsRead(db *DBConnection, d *schema.ResourceData) error {
+	var granteeType, granteeName string
+	for _, attr := range []string{grantUserAttr, grantGroupAttr, grantRoleAttr} {
+		if name, ok := d.GetOk(attr); ok {
+			granteeType, granteeName = attr, db.client.normalizeIdentifier(name.(string))
+		}
+	}
+
+	identities, err := readGranteeIdentities(db, granteeType, granteeName)
+	if err != nil {
+		return err
+	}
+
+	grants := []map[string]interface{}{}
+	for _, objectType := range grantsObjectTypes {
+		query := grantsQueries[objectType]
+		queryArgs := []interface{}{pq.Array(identities)}
+		if objectType != "database" {
+			queryArgs = append(queryArgs, db.client.databaseName)
+		}
+		log.Printf("[DEBUG] %s\n", query)
+		rows, err := db.Query(query, queryArgs...)
+		if err != nil {
+			return fmt.Errorf("Error reading %s privileges: %w", objectType, err)
+		}
+		for rows.Next() {
+			var database, schemaName, object, privilege, identityType, identityName string
+			var withGrantOption bool
+			if err := rows.Scan(&database, &schemaName, &object, &privilege, &withGrantOption, &identityType, &identityName); err != nil {
+				rows.Close()
+				return err
+			}
+			if privilege == "temp" {
+				privilege = "temporary"
+			}
+			grants = append(grants, map[string]interface{}{
+				grantsObjectTypeAttr:      objectType,
+				grantsDatabaseAttr:        database,
+				grantsSchemaAttr:          schemaName,
+				grantsObjectAttr:          object,
+				grantsPrivilegeAttr:       privilege,
+				grantsWithGrantOptionAttr: withGrantOption,
+				grantsGranteeTypeAttr:     identityType,
+				grantsGranteeNameAttr:     identityName,
+			})
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", granteeType, granteeName))
+	d.Set(grantsAttr, grants)
+
+	return nil
+}
+
+// readGranteeIdentities returns the identities whose privileges apply to the grantee, as "type:name":
+// the grantee itself, the groups of a user and the roles granted to a user or role, recursively.
+func readGranteeIdentities(db *DBConnection, granteeType, granteeName string) ([]string, error) {
+	identities := []string{granteeType + ":" + granteeName}
+
+	roles := []string{}
+	switch granteeType {
+	case grantUserAttr:
+		groups, err := queryNames(db, "SELECT trim(g.groname) FROM pg_group g, pg_user_info u WHERE u.usename = $1 AND u.usesysid = ANY(g.grolist)", granteeName)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading the groups of user %s: %w", granteeName, err)
+		}
+		for _, group := range groups {
+			identities = append(identities, grantGroupAttr+":"+group)
+		}
+		if roles, err = queryNames(db, "SELECT trim(role_name) FROM svv_user_grants WHERE user_name = $1", granteeName); err != nil {
+			return nil, fmt.Errorf("Error reading the roles of user %s: %w", granteeName, err)
+		}
+	case grantRoleAttr:
+		roles = []string{granteeName}
+	}
+
+	seen := map[string]bool{}
+	for len(roles) > 0 {
+		role := roles[0]
+		roles = roles[1:]
+		if seen[role] {
+			continue
+		}
+		seen[role] = true
+		if identity := grantRoleAttr + ":" + role; !sliceContainsStr(identities, identity) {
+			identities = append(identities, identity)
+		}
+
+		nested, err := queryNames(db, "SELECT trim(granted_role_name) FROM svv_role_grants WHERE role_name = $1", role)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading the roles granted to role %s: %w", role, err)
+		}
+		roles = append(roles, nested...)
+	}
+
+	return identities, nil
+}
+
+// queryNames returns the values of the single column query.
+func queryNames(db *DBConnection, query string, args ...interface{}) ([]string, error) {
+	log.Printf("[DEBUG] %s\n", query)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
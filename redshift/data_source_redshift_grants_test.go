@@ -0,0 +1,95 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRedshiftGrants_Effective(t *testing.T) {
+	prefix := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_data_grants"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name = "%[1]s_user"
+}
+
+resource "redshift_group" "group" {
+  name  = "%[1]s_group"
+  users = [redshift_user.user.name]
+}
+
+resource "redshift_role" "role" {
+  name = "%[1]s_role"
+}
+
+resource "redshift_role_grant" "role" {
+  role = redshift_role.role.name
+  user = redshift_user.user.name
+}
+
+resource "redshift_schema" "schema" {
+  name = "%[1]s_schema"
+}
+
+resource "redshift_grant" "group" {
+  group       = redshift_group.group.name
+  schema      = redshift_schema.schema.name
+  object_type = "schema"
+  privileges  = ["usage"]
+}
+
+resource "redshift_grant" "role" {
+  role        = redshift_role.role.name
+  schema      = redshift_schema.schema.name
+  object_type = "schema"
+  privileges  = ["create"]
+}
+
+data "redshift_grants" "user" {
+  user = redshift_user.user.name
+
+  depends_on = [redshift_grant.group, redshift_grant.role, redshift_role_grant.role]
+}
+
+data "redshift_grants" "group" {
+  group = redshift_group.group.name
+
+  depends_on = [redshift_grant.group, redshift_grant.role]
+}
+`, prefix)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.redshift_grants.user", fmt.Sprintf("%s.*", grantsAttr), map[string]string{
+						grantsObjectTypeAttr:  "schema",
+						grantsSchemaAttr:      prefix + "_schema",
+						grantsPrivilegeAttr:   "usage",
+						grantsGranteeTypeAttr: "group",
+						grantsGranteeNameAttr: prefix + "_group",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.redshift_grants.user", fmt.Sprintf("%s.*", grantsAttr), map[string]string{
+						grantsObjectTypeAttr:  "schema",
+						grantsSchemaAttr:      prefix + "_schema",
+						grantsPrivilegeAttr:   "create",
+						grantsGranteeTypeAttr: "role",
+						grantsGranteeNameAttr: prefix + "_role",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.redshift_grants.group", fmt.Sprintf("%s.*", grantsAttr), map[string]string{
+						grantsObjectTypeAttr:  "schema",
+						grantsSchemaAttr:      prefix + "_schema",
+						grantsPrivilegeAttr:   "usage",
+						grantsGranteeTypeAttr: "group",
+					}),
+				),
+			},
+		},
+	})
+}
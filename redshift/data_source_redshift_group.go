@@ -1,12 +1,15 @@
 package redshift
 
 import (
+	"database/sql"
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/brainly/terraform-provider-redshift/redshift/internal/catalog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/lib/pq"
 )
 
 func dataSourceRedshiftGroup() *schema.Resource {
@@ -14,7 +17,7 @@ func dataSourceRedshiftGroup() *schema.Resource {
 		Description: `
 Groups are collections of users who are all granted whatever privileges are associated with the group. You can use groups to assign privileges by role. For example, you can create different groups for sales, administration, and support and give the users in each group the appropriate access to the data they require for their work. You can grant or revoke privileges at the group level, and those changes will apply to all members of the group, except for superusers.
 		`,
-		Read: RedshiftResourceFunc(dataSourceRedshiftGroupRead),
+		Read: RedshiftResourceReadFunc(dataSourceRedshiftGroupRead),
 		Schema: map[string]*schema.Schema{
 			groupNameAttr: {
 				Type:         schema.TypeString,
@@ -38,17 +41,21 @@ Groups are collections of users who are all granted whatever privileges are asso
 }
 
 func dataSourceRedshiftGroupRead(db *DBConnection, d *schema.ResourceData) error {
-	var (
-		groupId    string
-		groupUsers []string
-	)
+	groupName := d.Get(groupNameAttr).(string)
+	groupID, err := catalog.NewResolver(db).GroupID(db.client.normalizeIdentifier(groupName))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("group %q does not exist", groupName)
+		}
+		return fmt.Errorf("failed to get group ID for group '%s': %w", groupName, err)
+	}
 
-	sql := `SELECT ARRAY(SELECT u.usename FROM pg_user_info u, pg_group g WHERE g.groname = $1 AND u.usesysid = ANY(g.grolist)) AS members, grosysid FROM pg_group WHERE groname = $1`
-	if err := db.QueryRow(sql, d.Get(groupNameAttr).(string)).Scan(pq.Array(&groupUsers), &groupId); err != nil {
+	groupUsers, err := groupMembers(db, strconv.Itoa(groupID))
+	if err != nil {
 		return err
 	}
 
-	d.SetId(groupId)
+	d.SetId(strconv.Itoa(groupID))
 	d.Set(groupUsersAttr, groupUsers)
 	return nil
 }
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
 func TestAccDataSourceRedshiftGroup_basic(t *testing.T) {
@@ -44,3 +47,92 @@ data "redshift_group" "group" {
 }
 `, userNameAttr, userName, groupNameAttr, groupName, groupUsersAttr)
 }
+
+func TestAccDataSourceRedshiftGroup_ManyMembers(t *testing.T) {
+	const memberCount = 5000
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_data_many"), "-", "_")
+
+	userNames := make([]string, memberCount)
+	for i := range userNames {
+		userNames[i] = fmt.Sprintf("%s_user_%d", groupName, i)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				// The members are created outside of Terraform, listing them in the configuration would dominate the test.
+				PreConfig: func() {
+					conn, err := testAccProvider.Meta().(*Client).Connect()
+					if err != nil {
+						t.Fatalf("couldn't start redshift connection: %s", err)
+					}
+					t.Cleanup(func() {
+						conn.Exec(fmt.Sprintf("DROP GROUP %s", pq.QuoteIdentifier(groupName)))
+						for _, userName := range userNames {
+							conn.Exec(fmt.Sprintf("DROP USER IF EXISTS %s", pq.QuoteIdentifier(userName)))
+						}
+					})
+
+					if _, err := conn.Exec(fmt.Sprintf("CREATE GROUP %s", pq.QuoteIdentifier(groupName))); err != nil {
+						t.Fatalf("couldn't create group: %s", err)
+					}
+					quoted := []string{}
+					for i, userName := range userNames {
+						if _, err := conn.Exec(fmt.Sprintf("CREATE USER %s PASSWORD DISABLE", pq.QuoteIdentifier(userName))); err != nil {
+							t.Fatalf("couldn't create user %s: %s", userName, err)
+						}
+						quoted = append(quoted, pq.QuoteIdentifier(userName))
+						if len(quoted) == maxObjectsPerStatement || i == len(userNames)-1 {
+							if _, err := conn.Exec(fmt.Sprintf("ALTER GROUP %s ADD USER %s", pq.QuoteIdentifier(groupName), strings.Join(quoted, ", "))); err != nil {
+								t.Fatalf("couldn't add users to group: %s", err)
+							}
+							quoted = []string{}
+						}
+					}
+				},
+				Config: fmt.Sprintf(`
+data "redshift_group" "group" {
+  name = %q
+}
+`, groupName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redshift_group.group", fmt.Sprintf("%s.#", groupUsersAttr), fmt.Sprintf("%d", memberCount)),
+					resource.TestCheckTypeSetElemAttr("data.redshift_group.group", fmt.Sprintf("%s.*", groupUsersAttr), userNames[0]),
+					resource.TestCheckTypeSetElemAttr("data.redshift_group.group", fmt.Sprintf("%s.*", groupUsersAttr), userNames[memberCount-1]),
+					testAccCheckRedshiftGroupMembersReadTime("data.redshift_group.group", memberCount, time.Minute),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckRedshiftGroupMembersReadTime checks that reading the members of the group returns all of them within the limit.
+func testAccCheckRedshiftGroupMembersReadTime(resourceName string, memberCount int, limit time.Duration) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+
+		conn, err := testAccProvider.Meta().(*Client).Connect()
+		if err != nil {
+			return err
+		}
+
+		start := time.Now()
+		members, err := groupMembers(conn, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if elapsed := time.Since(start); elapsed > limit {
+			return fmt.Errorf("reading %d group members took %s, expected less than %s", len(members), elapsed, limit)
+		}
+		if len(members) != memberCount {
+			return fmt.Errorf("expected %d group members, got %d", memberCount, len(members))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,182 @@
+package redshift
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	queryStatementAttr     = "query"
+	queryRowLimitAttr      = "row_limit"
+	queryTimeoutAttr       = "statement_timeout"
+	querySensitiveAttr     = "sensitive"
+	queryColumnsAttr       = "columns"
+	queryRowsAttr          = "rows"
+	querySensitiveRowsAttr = "sensitive_rows"
+
+	defaultQueryRowLimit = 100
+	defaultQueryTimeout  = 30
+)
+
+var readOnlyQueryPrefix = regexp.MustCompile(`^(?i)(SELECT|SHOW)\s`)
+
+func dataSourceRedshiftQuery() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Runs an arbitrary read-only query and exposes the returned rows. This data source is meant as an escape hatch for lookups which are not (yet) covered by any other data source.
+Only statements starting with ` + "`SELECT`" + ` or ` + "`SHOW`" + ` are accepted and they are always executed inside a read-only transaction.
+`,
+		Read: RedshiftResourceReadFunc(dataSourceRedshiftQueryRead),
+		Schema: map[string]*schema.Schema{
+			queryStatementAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The read-only query to run. It must start with `SELECT` or `SHOW` and must consist of a single statement.",
+				ValidateFunc: validateReadOnlyQuery,
+			},
+			queryRowLimitAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultQueryRowLimit,
+				Description:  "The maximum number of rows the query is allowed to return. Reading fails if the query returns more rows.",
+				ValidateFunc: validation.IntBetween(1, 10000),
+			},
+			queryTimeoutAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultQueryTimeout,
+				Description:  "The maximum time in seconds the query is allowed to run.",
+				ValidateFunc: validation.IntBetween(1, 3600),
+			},
+			querySensitiveAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When set to `true`, the results are exposed in the `sensitive_rows` attribute, which is marked as sensitive, instead of `rows`.",
+			},
+			queryColumnsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the columns returned by the query, in order.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			queryRowsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Rows returned by the query, as maps of column name to value. `NULL` values are omitted. Empty when `sensitive` is set.",
+				Elem: &schema.Schema{
+					Type: schema.TypeMap,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+			querySensitiveRowsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Rows returned by the query when `sensitive` is set, as maps of column name to value. `NULL` values are omitted.",
+				Elem: &schema.Schema{
+					Type: schema.TypeMap,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+		},
+	}
+}
+
+func validateReadOnlyQuery(i interface{}, k string) ([]string, []error) {
+	query, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+	if !readOnlyQueryPrefix.MatchString(query + " ") {
+		return nil, []error{fmt.Errorf("%s must start with SELECT or SHOW", k)}
+	}
+	if strings.Contains(query, ";") {
+		return nil, []error{fmt.Errorf("%s must contain a single statement", k)}
+	}
+
+	return nil, nil
+}
+
+func dataSourceRedshiftQueryRead(db *DBConnection, d *schema.ResourceData) error {
+	query := strings.TrimSuffix(strings.TrimSpace(d.Get(queryStatementAttr).(string)), ";")
+	rowLimit := d.Get(queryRowLimitAttr).(int)
+	timeout := d.Get(queryTimeoutAttr).(int)
+
+	txn, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(fmt.Sprintf("SET statement_timeout TO %d", timeout*1000)); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] running read-only query: %s\n", query)
+	rows, err := txn.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	results := []map[string]interface{}{}
+	for rows.Next() {
+		if len(results) >= rowLimit {
+			return fmt.Errorf("query returned more than %d rows, increase `%s` or narrow down the query", rowLimit, queryRowLimitAttr)
+		}
+
+		values := make([]sql.NullString, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if values[i].Valid {
+				row[column] = values[i].String
+			}
+		}
+		results = append(results, row)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(query))))
+	d.Set(queryColumnsAttr, columns)
+	if d.Get(querySensitiveAttr).(bool) {
+		d.Set(queryRowsAttr, []map[string]interface{}{})
+		d.Set(querySensitiveRowsAttr, results)
+	} else {
+		d.Set(queryRowsAttr, results)
+		d.Set(querySensitiveRowsAttr, []map[string]interface{}{})
+	}
+
+	return nil
+}
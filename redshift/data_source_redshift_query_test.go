@@ -0,0 +1,68 @@
+package redshift
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRedshiftQuery_basic(t *testing.T) {
+	config := `
+data "redshift_query" "query" {
+	query = "SELECT 1 AS one, NULL AS nothing, 'foo' AS foo"
+}
+
+data "redshift_query" "sensitive" {
+	query     = "SELECT 'secret' AS value"
+	sensitive = true
+}
+`
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redshift_query.query", fmt.Sprintf("%s.#", queryColumnsAttr), "3"),
+					resource.TestCheckResourceAttr("data.redshift_query.query", fmt.Sprintf("%s.#", queryRowsAttr), "1"),
+					resource.TestCheckResourceAttr("data.redshift_query.query", fmt.Sprintf("%s.0.one", queryRowsAttr), "1"),
+					resource.TestCheckResourceAttr("data.redshift_query.query", fmt.Sprintf("%s.0.foo", queryRowsAttr), "foo"),
+					resource.TestCheckNoResourceAttr("data.redshift_query.query", fmt.Sprintf("%s.0.nothing", queryRowsAttr)),
+					resource.TestCheckResourceAttr("data.redshift_query.sensitive", fmt.Sprintf("%s.#", queryRowsAttr), "0"),
+					resource.TestCheckResourceAttr("data.redshift_query.sensitive", fmt.Sprintf("%s.0.value", querySensitiveRowsAttr), "secret"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateReadOnlyQuery(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		valid bool
+	}{
+		"select":               {query: "SELECT 1", valid: true},
+		"lowercase select":     {query: "select usename from pg_user", valid: true},
+		"show":                 {query: "SHOW search_path", valid: true},
+		"trailing semicolon":   {query: "SELECT 1;", valid: true},
+		"leading whitespace":   {query: "\n  SELECT 1", valid: true},
+		"insert":               {query: "INSERT INTO foo VALUES (1)", valid: false},
+		"multiple statements":  {query: "SELECT 1; DROP TABLE foo", valid: false},
+		"select prefixed word": {query: "SELECTED", valid: false},
+		"empty":                {query: "", valid: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, errors := validateReadOnlyQuery(tt.query, queryStatementAttr)
+			if tt.valid && len(errors) > 0 {
+				t.Errorf("Expected %q to be valid but got %v", tt.query, errors)
+			}
+			if !tt.valid && len(errors) == 0 {
+				t.Errorf("Expected %q to be invalid", tt.query)
+			}
+		})
+	}
+}
@@ -1,27 +1,60 @@
 package redshift
 
 import (
+	"database/sql"
 	"fmt"
+	"log"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+const (
+	schemaTypeAttr                 = "type"
+	schemaSourceTypeAttr           = "source_type"
+	schemaOIDAttr                  = "oid"
+	schemaExternalDatabaseNameAttr = "external_database_name"
+)
+
+// externalSchemaSources lists the source blocks of the external_schema attribute.
+var externalSchemaSources = []string{
+	"data_catalog_source",
+	"hive_metastore_source",
+	"rds_postgres_source",
+	"rds_mysql_source",
+	"redshift_source",
+}
+
 func dataSourceRedshiftSchema() *schema.Resource {
 	return &schema.Resource{
 		Description: `
 A database contains one or more named schemas. Each schema in a database contains tables and other kinds of named objects. By default, a database has a single schema, which is named PUBLIC. You can use schemas to group database objects under a common name. Schemas are similar to file system directories, except that schemas cannot be nested.
 `,
-		Read: RedshiftResourceFunc(dataSourceRedshiftSchemaRead),
+		Read: RedshiftResourceReadFunc(dataSourceRedshiftSchemaRead),
 		Schema: map[string]*schema.Schema{
 			schemaNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Name of the schema.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				AtLeastOneOf: []string{schemaNameAttr, schemaOIDAttr, schemaExternalDatabaseNameAttr},
+				Description:  "Name of the schema.",
 				StateFunc: func(val interface{}) string {
 					return strings.ToLower(val.(string))
 				},
 			},
+			schemaOIDAttr: {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{schemaNameAttr, schemaExternalDatabaseNameAttr},
+				Description:   "The OID of the schema. Can be set instead of `name` to look the schema up by its OID, e.g. the `relnamespace` of a table.",
+			},
+			schemaExternalDatabaseNameAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only look up external schemas referencing this database of their source (`external_schema.database_name`). Can be set without `name` when a single external schema references the database.",
+			},
 			schemaOwnerAttr: {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -32,6 +65,16 @@ A database contains one or more named schemas. Each schema in a database contain
 				Computed:    true,
 				Description: "The maximum amount of disk space that the specified schema can use. GB is the default unit of measurement.",
 			},
+			schemaTypeAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the schema, either `local` or `external`.",
+			},
+			schemaSourceTypeAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The source of an external schema, one of `data_catalog`, `hive_metastore`, `rds_postgres`, `rds_mysql` or `redshift`. Empty for local schemas.",
+			},
 			schemaExternalSchemaAttr: {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -263,32 +306,93 @@ A database contains one or more named schemas. Each schema in a database contain
 }
 
 func dataSourceRedshiftSchemaRead(db *DBConnection, d *schema.ResourceData) error {
-	var schemaOwner, schemaId, schemaType string
+	var schemaName, schemaOwner, schemaType string
+	var schemaOID int
 
 	// Step 1: get basic schema info
-	err := db.QueryRow(`
+	query := `
 			SELECT
 				pg_namespace.oid,
+				trim(svv_all_schemas.schema_name),
 				trim(pg_user_info.usename),
 				trim(svv_all_schemas.schema_type)
 			FROM svv_all_schemas
 			INNER JOIN pg_namespace ON (svv_all_schemas.database_name = $1 and svv_all_schemas.schema_name = pg_namespace.nspname)
 	LEFT JOIN pg_user_info
 		ON (svv_all_schemas.database_name = $1 and pg_user_info.usesysid = svv_all_schemas.schema_owner)
-	where svv_all_schemas.database_name = $1
-	AND svv_all_schemas.schema_name = $2`, db.client.databaseName, d.Get(schemaNameAttr).(string)).Scan(&schemaId, &schemaOwner, &schemaType)
+	where svv_all_schemas.database_name = $1`
+	args := []interface{}{db.client.databaseName}
+	filters := []string{}
+	if name, ok := d.GetOk(schemaNameAttr); ok {
+		args = append(args, strings.ToLower(name.(string)))
+		query += fmt.Sprintf(`
+	AND svv_all_schemas.schema_name = $%d`, len(args))
+		filters = append(filters, fmt.Sprintf("%s = %s", schemaNameAttr, name))
+	}
+	if oid, ok := d.GetOk(schemaOIDAttr); ok {
+		args = append(args, oid.(int))
+		query += fmt.Sprintf(`
+	AND pg_namespace.oid = $%d`, len(args))
+		filters = append(filters, fmt.Sprintf("%s = %d", schemaOIDAttr, oid))
+	}
+	if databaseName, ok := d.GetOk(schemaExternalDatabaseNameAttr); ok {
+		args = append(args, databaseName.(string))
+		query += fmt.Sprintf(`
+	AND pg_namespace.oid IN (SELECT esoid FROM svv_external_schemas WHERE trim(databasename) = $%d)`, len(args))
+		filters = append(filters, fmt.Sprintf("%s = %s", schemaExternalDatabaseNameAttr, databaseName))
+	}
+	log.Printf("[DEBUG] %s, %v\n", query, args)
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return err
 	}
-	d.SetId(schemaId)
+	defer rows.Close()
+
+	found := 0
+	for rows.Next() {
+		if err := rows.Scan(&schemaOID, &schemaName, &schemaOwner, &schemaType); err != nil {
+			return err
+		}
+		found++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	switch {
+	case found == 0:
+		return fmt.Errorf("schema with %s not found: %w", strings.Join(filters, ", "), sql.ErrNoRows)
+	case found > 1:
+		return fmt.Errorf("found %d schemas with %s, set %s to choose one of them", found, strings.Join(filters, ", "), schemaNameAttr)
+	}
+
+	d.SetId(strconv.Itoa(schemaOID))
+	d.Set(schemaNameAttr, schemaName)
+	d.Set(schemaOIDAttr, schemaOID)
 	d.Set(schemaOwnerAttr, schemaOwner)
+	d.Set(schemaTypeAttr, schemaType)
 
 	switch {
 	case schemaType == "local":
+		d.Set(schemaSourceTypeAttr, "")
 		return resourceRedshiftSchemaReadLocal(db, d)
 	case schemaType == "external":
-		return resourceRedshiftSchemaReadExternal(db, d)
+		if err := resourceRedshiftSchemaReadExternal(db, d); err != nil {
+			return err
+		}
+		d.Set(schemaSourceTypeAttr, externalSchemaSourceType(d))
+		return nil
 	default:
 		return fmt.Errorf(`Unsupported schema type "%s". Supported types are "local" and "external".`, schemaType)
 	}
 }
+
+// externalSchemaSourceType returns the name of the configured source block of the external schema
+// without the "_source" suffix, e.g. "data_catalog".
+func externalSchemaSourceType(d *schema.ResourceData) string {
+	for _, source := range externalSchemaSources {
+		if len(d.Get(fmt.Sprintf("%s.0.%s", schemaExternalSchemaAttr, source)).([]interface{})) > 0 {
+			return strings.TrimSuffix(source, "_source")
+		}
+	}
+	return ""
+}
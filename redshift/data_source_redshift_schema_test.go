@@ -23,6 +23,11 @@ func TestAccDataSourceRedshiftSchema_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("data.redshift_schema.schema", schemaNameAttr, schemaName),
 					resource.TestCheckResourceAttrSet("data.redshift_schema.schema", schemaOwnerAttr),
 					resource.TestCheckResourceAttrSet("data.redshift_schema.schema", schemaQuotaAttr),
+					resource.TestCheckResourceAttr("data.redshift_schema.schema", schemaTypeAttr, "local"),
+					resource.TestCheckResourceAttr("data.redshift_schema.schema", schemaSourceTypeAttr, ""),
+					resource.TestCheckResourceAttrPair("data.redshift_schema.schema", schemaOIDAttr, "data.redshift_schema.schema", "id"),
+					resource.TestCheckResourceAttr("data.redshift_schema.by_oid", schemaNameAttr, schemaName),
+					resource.TestCheckResourceAttrPair("data.redshift_schema.by_oid", "id", "data.redshift_schema.schema", "id"),
 				),
 			},
 		},
@@ -38,7 +43,11 @@ resource "redshift_schema" "schema" {
 data "redshift_schema" "schema" {
 	%[1]s = redshift_schema.schema.%[1]s
 }
-`, schemaNameAttr, schemaName)
+
+data "redshift_schema" "by_oid" {
+	%[3]s = data.redshift_schema.schema.%[3]s
+}
+`, schemaNameAttr, schemaName, schemaOIDAttr)
 }
 
 // Acceptance test for external redshift schema using AWS Glue Data Catalog
@@ -65,8 +74,13 @@ resource "redshift_schema" "spectrum" {
 data "redshift_schema" "spectrum" {
 	%[1]s = redshift_schema.spectrum.%[1]s
 }
+
+data "redshift_schema" "by_database" {
+	%[1]s = redshift_schema.spectrum.%[1]s
+	%[6]s = %[4]q
+}
 `,
-		schemaNameAttr, schemaName, schemaExternalSchemaAttr, dbName, tfArray(iamRoleArns))
+		schemaNameAttr, schemaName, schemaExternalSchemaAttr, dbName, tfArray(iamRoleArns), schemaExternalDatabaseNameAttr)
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
@@ -77,9 +91,12 @@ data "redshift_schema" "spectrum" {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckRedshiftSchemaExists(schemaName),
 					resource.TestCheckResourceAttr("data.redshift_schema.spectrum", "name", schemaName),
+					resource.TestCheckResourceAttr("data.redshift_schema.spectrum", schemaTypeAttr, "external"),
+					resource.TestCheckResourceAttr("data.redshift_schema.spectrum", schemaSourceTypeAttr, "data_catalog"),
 					resource.TestCheckResourceAttr("data.redshift_schema.spectrum", fmt.Sprintf("%s.#", schemaExternalSchemaAttr), "1"),
 					resource.TestCheckResourceAttr("data.redshift_schema.spectrum", fmt.Sprintf("%s.0.database_name", schemaExternalSchemaAttr), dbName),
 					resource.TestCheckResourceAttr("data.redshift_schema.spectrum", fmt.Sprintf("%s.0.data_catalog_source.#", schemaExternalSchemaAttr), "1"),
+					resource.TestCheckResourceAttrPair("data.redshift_schema.by_database", "id", "data.redshift_schema.spectrum", "id"),
 					resource.TestCheckResourceAttr("data.redshift_schema.spectrum", fmt.Sprintf("%s.0.data_catalog_source.0.iam_role_arns.#", schemaExternalSchemaAttr), fmt.Sprintf("%d", len(iamRoleArns))),
 					resource.ComposeTestCheckFunc(func() []resource.TestCheckFunc {
 						results := []resource.TestCheckFunc{}
@@ -142,6 +159,8 @@ data "redshift_schema" "hive" {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckRedshiftSchemaExists(schemaName),
 					resource.TestCheckResourceAttr("data.redshift_schema.hive", "name", schemaName),
+					resource.TestCheckResourceAttr("data.redshift_schema.hive", schemaTypeAttr, "external"),
+					resource.TestCheckResourceAttr("data.redshift_schema.hive", schemaSourceTypeAttr, "hive_metastore"),
 					resource.TestCheckResourceAttr("data.redshift_schema.hive", fmt.Sprintf("%s.#", schemaExternalSchemaAttr), "1"),
 					resource.TestCheckResourceAttr("data.redshift_schema.hive", fmt.Sprintf("%s.0.database_name", schemaExternalSchemaAttr), dbName),
 					resource.TestCheckResourceAttr("data.redshift_schema.hive", fmt.Sprintf("%s.0.hive_metastore_source.#", schemaExternalSchemaAttr), "1"),
@@ -218,6 +237,8 @@ data "redshift_schema" "postgres" {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckRedshiftSchemaExists(schemaName),
 					resource.TestCheckResourceAttr("data.redshift_schema.postgres", "name", schemaName),
+					resource.TestCheckResourceAttr("data.redshift_schema.postgres", schemaTypeAttr, "external"),
+					resource.TestCheckResourceAttr("data.redshift_schema.postgres", schemaSourceTypeAttr, "rds_postgres"),
 					resource.TestCheckResourceAttr("data.redshift_schema.postgres", fmt.Sprintf("%s.#", schemaExternalSchemaAttr), "1"),
 					resource.TestCheckResourceAttr("data.redshift_schema.postgres", fmt.Sprintf("%s.0.database_name", schemaExternalSchemaAttr), dbName),
 					resource.TestCheckResourceAttr("data.redshift_schema.postgres", fmt.Sprintf("%s.0.rds_postgres_source.#", schemaExternalSchemaAttr), "1"),
@@ -290,6 +311,8 @@ data "redshift_schema" "mysql" {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckRedshiftSchemaExists(schemaName),
 					resource.TestCheckResourceAttr("data.redshift_schema.mysql", "name", schemaName),
+					resource.TestCheckResourceAttr("data.redshift_schema.mysql", schemaTypeAttr, "external"),
+					resource.TestCheckResourceAttr("data.redshift_schema.mysql", schemaSourceTypeAttr, "rds_mysql"),
 					resource.TestCheckResourceAttr("data.redshift_schema.mysql", fmt.Sprintf("%s.#", schemaExternalSchemaAttr), "1"),
 					resource.TestCheckResourceAttr("data.redshift_schema.mysql", fmt.Sprintf("%s.0.database_name", schemaExternalSchemaAttr), dbName),
 					resource.TestCheckResourceAttr("data.redshift_schema.mysql", fmt.Sprintf("%s.0.rds_mysql_source.#", schemaExternalSchemaAttr), "1"),
@@ -351,6 +374,8 @@ data "redshift_schema" "redshift" {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckRedshiftSchemaExists(schemaName),
 					resource.TestCheckResourceAttr("data.redshift_schema.redshift", "name", schemaName),
+					resource.TestCheckResourceAttr("data.redshift_schema.redshift", schemaTypeAttr, "external"),
+					resource.TestCheckResourceAttr("data.redshift_schema.redshift", schemaSourceTypeAttr, "redshift"),
 					resource.TestCheckResourceAttr("data.redshift_schema.redshift", fmt.Sprintf("%s.#", schemaExternalSchemaAttr), "1"),
 					resource.TestCheckResourceAttr("data.redshift_schema.redshift", fmt.Sprintf("%s.0.database_name", schemaExternalSchemaAttr), dbName),
 					resource.TestCheckResourceAttr("data.redshift_schema.redshift", fmt.Sprintf("%s.0.redshift_source.#", schemaExternalSchemaAttr), "1"),
@@ -0,0 +1,134 @@
+package redshift
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	schemasPatternAttr = "pattern"
+	schemasAttr        = "schemas"
+)
+
+func dataSourceRedshiftSchemas() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Lists the local and external schemas of the database the provider connects to (from ` + "`svv_all_schemas`" + `), e.g. to create grants on every schema matching a pattern.
+`,
+		Read: RedshiftResourceReadFunc(dataSourceRedshiftSchemasRead),
+		Schema: map[string]*schema.Schema{
+			schemasPatternAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only list the schemas whose name matches this `LIKE` pattern, e.g. `analytics_%`. All schemas, including the system ones like `pg_catalog`, are listed by default.",
+			},
+			schemasAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The schemas, ordered by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						schemaNameAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the schema.",
+						},
+						schemaOwnerAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the schema owner.",
+						},
+						schemaTypeAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the schema, e.g. `local` or `external`.",
+						},
+						schemaQuotaAttr: {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The maximum amount of disk space that the schema can use, in GB. `0` when the schema has no quota.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftSchemasRead(db *DBConnection, d *schema.ResourceData) error {
+	quotas, err := readSchemaQuotas(db)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	SELECT
+		trim(svv_all_schemas.schema_name),
+		trim(COALESCE(pg_user_info.usename, '')),
+		trim(svv_all_schemas.schema_type)
+	FROM svv_all_schemas
+	LEFT JOIN pg_user_info ON pg_user_info.usesysid = svv_all_schemas.schema_owner
+	WHERE svv_all_schemas.database_name = $1`
+	args := []interface{}{db.client.databaseName}
+	if pattern, ok := d.GetOk(schemasPatternAttr); ok {
+		query += `
+	AND svv_all_schemas.schema_name LIKE $2`
+		args = append(args, pattern.(string))
+	}
+	query += `
+	ORDER BY 1`
+	log.Printf("[DEBUG] %s\n", query)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	schemas := []map[string]interface{}{}
+	for rows.Next() {
+		var name, owner, schemaType string
+		if err := rows.Scan(&name, &owner, &schemaType); err != nil {
+			return err
+		}
+		schemas = append(schemas, map[string]interface{}{
+			schemaNameAttr:  name,
+			schemaOwnerAttr: owner,
+			schemaTypeAttr:  schemaType,
+			schemaQuotaAttr: quotas[name],
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.SetId(db.client.databaseName)
+	d.Set(schemasAttr, schemas)
+
+	return nil
+}
+
+// readSchemaQuotas returns the quotas of the schemas of the current database by schema name.
+// svv_schema_quota_state is queried on its own as it can't be joined with the leader node only svv_all_schemas.
+func readSchemaQuotas(db *DBConnection) (map[string]int, error) {
+	query := "SELECT trim(schema_name), COALESCE(quota, 0) FROM svv_schema_quota_state"
+	log.Printf("[DEBUG] %s\n", query)
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quotas := map[string]int{}
+	for rows.Next() {
+		var name string
+		var quota int
+		if err := rows.Scan(&name, &quota); err != nil {
+			return nil, err
+		}
+		quotas[name] = quota
+	}
+	return quotas, rows.Err()
+}
@@ -0,0 +1,64 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRedshiftSchemas_Pattern(t *testing.T) {
+	prefix := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_data_schemas"), "-", "_")
+	schemaName := prefix + "_quota"
+	otherSchemaName := prefix + "_other"
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceRedshiftSchemasConfig_Pattern(prefix, schemaName, otherSchemaName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftSchemaExists(schemaName),
+					testAccCheckRedshiftSchemaExists(otherSchemaName),
+					resource.TestCheckResourceAttr("data.redshift_schemas.all", fmt.Sprintf("%s.#", schemasAttr), "2"),
+					resource.TestCheckResourceAttr("data.redshift_schemas.all", fmt.Sprintf("%s.0.%s", schemasAttr, schemaNameAttr), otherSchemaName),
+					resource.TestCheckResourceAttr("data.redshift_schemas.all", fmt.Sprintf("%s.0.%s", schemasAttr, schemaQuotaAttr), "0"),
+					resource.TestCheckResourceAttr("data.redshift_schemas.all", fmt.Sprintf("%s.1.%s", schemasAttr, schemaNameAttr), schemaName),
+					resource.TestCheckResourceAttr("data.redshift_schemas.all", fmt.Sprintf("%s.1.%s", schemasAttr, schemaTypeAttr), "local"),
+					resource.TestCheckResourceAttr("data.redshift_schemas.all", fmt.Sprintf("%s.1.%s", schemasAttr, schemaQuotaAttr), "150"),
+					resource.TestCheckResourceAttrSet("data.redshift_schemas.all", fmt.Sprintf("%s.1.%s", schemasAttr, schemaOwnerAttr)),
+					resource.TestCheckResourceAttr("data.redshift_schemas.quota", fmt.Sprintf("%s.#", schemasAttr), "1"),
+					resource.TestCheckResourceAttr("data.redshift_schemas.quota", fmt.Sprintf("%s.0.%s", schemasAttr, schemaNameAttr), schemaName),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceRedshiftSchemasConfig_Pattern(prefix, schemaName, otherSchemaName string) string {
+	return fmt.Sprintf(`
+resource "redshift_schema" "quota" {
+  name  = %[2]q
+  quota = 150
+}
+
+resource "redshift_schema" "other" {
+  name = %[3]q
+}
+
+data "redshift_schemas" "all" {
+  pattern = "%[1]s_%%"
+
+  depends_on = [redshift_schema.quota, redshift_schema.other]
+}
+
+data "redshift_schemas" "quota" {
+  pattern = %[2]q
+
+  depends_on = [redshift_schema.quota, redshift_schema.other]
+}
+`, prefix, schemaName, otherSchemaName)
+}
@@ -0,0 +1,88 @@
+package redshift
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	sessionCurrentUserAttr          = "current_user"
+	sessionSessionUserAttr          = "session_user"
+	sessionUsernameAttr             = "username"
+	sessionDatabaseAttr             = "database"
+	sessionVersionAttr              = "version"
+	sessionIsolationLevelAttr       = "isolation_level"
+	sessionTemporaryCredentialsAttr = "temporary_credentials"
+)
+
+func dataSourceRedshiftSession() *schema.Resource {
+	return &schema.Resource{
+		Description: `Gets the identity and the settings of the connection of the provider, e.g. to check with a postcondition that a configuration is applied by the expected user.`,
+		Read:        RedshiftResourceReadFunc(dataSourceRedshiftSessionRead),
+		Schema: map[string]*schema.Schema{
+			sessionCurrentUserAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The current user, as returned by `current_user`. It has an `IAM:`, `IAMA:` or `IAMR:` prefix when connected with temporary credentials.",
+			},
+			sessionSessionUserAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The user who initiated the session, as returned by `session_user`.",
+			},
+			sessionUsernameAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The current user without the prefix of temporary credentials, i.e. the name of the database user.",
+			},
+			sessionDatabaseAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The database the provider is connected to.",
+			},
+			sessionVersionAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the cluster, as returned by `version()`.",
+			},
+			sessionIsolationLevelAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The isolation level of the database, `serializable` or `snapshot isolation`.",
+			},
+			sessionTemporaryCredentialsAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the provider is connected with IAM temporary credentials, e.g. obtained by `GetClusterCredentials`.",
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftSessionRead(db *DBConnection, d *schema.ResourceData) error {
+	var currentUser, sessionUser, database, version, isolationLevel string
+	err := db.QueryRow(`
+	SELECT
+	  current_user,
+	  session_user,
+	  current_database(),
+	  version(),
+	  COALESCE((SELECT trim(isolation_level) FROM stv_db_isolation_level WHERE trim(db_name) = current_database()), '')`).Scan(&currentUser, &sessionUser, &database, &version, &isolationLevel)
+	if err != nil {
+		return fmt.Errorf("Error reading session: %w", err)
+	}
+
+	username := permanentUsername(currentUser)
+
+	d.SetId(fmt.Sprintf("%s@%s", sessionUser, database))
+	d.Set(sessionCurrentUserAttr, currentUser)
+	d.Set(sessionSessionUserAttr, sessionUser)
+	d.Set(sessionUsernameAttr, username)
+	d.Set(sessionDatabaseAttr, database)
+	d.Set(sessionVersionAttr, version)
+	d.Set(sessionIsolationLevelAttr, isolationLevel)
+	d.Set(sessionTemporaryCredentialsAttr, username != currentUser)
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package redshift
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRedshiftSession(t *testing.T) {
+	config := `
+data "redshift_session" "session" {
+
+}
+`
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redshift_session.session", "username", permanentUsername(os.Getenv("REDSHIFT_USER"))),
+					resource.TestCheckResourceAttr("data.redshift_session.session", "temporary_credentials", "false"),
+					resource.TestMatchResourceAttr("data.redshift_session.session", "version", regexp.MustCompile("Redshift")),
+					resource.TestCheckResourceAttrSet("data.redshift_session.session", "database"),
+					resource.TestCheckResourceAttrSet("data.redshift_session.session", "isolation_level"),
+				),
+			},
+		},
+	})
+}
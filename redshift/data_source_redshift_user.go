@@ -14,7 +14,7 @@ func dataSourceRedshiftUser() *schema.Resource {
 		Description: `
 This data source can be used to fetch information about a specific database user. Users are authenticated when they login to Amazon Redshift. They can own databases and database objects (for example, tables) and can grant privileges on those objects to users, groups, and schemas to control who has access to which object. Users with CREATE DATABASE rights can create databases and grant privileges to those databases. Superusers have database ownership privileges for all databases.
 `,
-		Read: RedshiftResourceFunc(dataSourceRedshiftUserRead),
+		Read: RedshiftResourceReadFunc(dataSourceRedshiftUserRead),
 		Schema: map[string]*schema.Schema{
 			userNameAttr: {
 				Type:        schema.TypeString,
@@ -57,12 +57,17 @@ This data source can be used to fetch information about a specific database user
 				Computed:    true,
 				Description: "The maximum time in seconds that a session remains inactive or idle. The range is 60 seconds (one minute) to 1,728,000 seconds (20 days). If no session timeout is set for the user, the cluster setting applies.",
 			},
+			userExternalIDAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The identifier of the user in the identity provider, for users authenticating through identity federation. Empty for other users.",
+			},
 		},
 	}
 }
 
 func dataSourceRedshiftUserRead(db *DBConnection, d *schema.ResourceData) error {
-	var useSysID, userValidUntil, userConnLimit, userSyslogAccess, userSessionTimeout string
+	var useSysID, userValidUntil, userConnLimit, userSyslogAccess, userSessionTimeout, userExternalID string
 	var userSuperuser, userCreateDB bool
 
 	columns := []string{
@@ -72,6 +77,7 @@ func dataSourceRedshiftUserRead(db *DBConnection, d *schema.ResourceData) error
 		"syslogaccess",
 		`COALESCE(useconnlimit::TEXT, 'UNLIMITED')`,
 		"sessiontimeout",
+		"COALESCE(trim(external_user_id), '')",
 	}
 
 	values := []interface{}{
@@ -81,6 +87,7 @@ func dataSourceRedshiftUserRead(db *DBConnection, d *schema.ResourceData) error
 		&userSyslogAccess,
 		&userConnLimit,
 		&userSessionTimeout,
+		&userExternalID,
 	}
 
 	userName := d.Get(userNameAttr).(string)
@@ -115,6 +122,7 @@ func dataSourceRedshiftUserRead(db *DBConnection, d *schema.ResourceData) error
 	d.Set(userConnLimitAttr, userConnLimitNumber)
 	d.Set(userValidUntilAttr, userValidUntil)
 	d.Set(userSessionTimeoutAttr, userSessionTimeoutNumber)
+	d.Set(userExternalIDAttr, userExternalID)
 
 	return nil
 }
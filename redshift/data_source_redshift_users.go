@@ -0,0 +1,148 @@
+package redshift
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	usersNameRegexAttr = "name_regex"
+	usersSuperuserAttr = "superuser"
+	usersAttr          = "users"
+	usersIDAttr        = "id"
+)
+
+func dataSourceRedshiftUsers() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Lists the database users, optionally filtered by name or by superuser flag, e.g. to audit the users of a cluster with ` + "`for_each`" + `.
+`,
+		Read: RedshiftResourceReadFunc(dataSourceRedshiftUsersRead),
+		Schema: map[string]*schema.Schema{
+			usersNameRegexAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only list the users whose name matches this regular expression. All users are listed by default.",
+			},
+			usersSuperuserAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only list the superusers when `true`, or the regular users when `false`. All users are listed by default.",
+			},
+			usersAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The users, ordered by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						usersIDAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the user.",
+						},
+						userNameAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the user.",
+						},
+						userSuperuserAttr: {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Indicates whether the user is a superuser.",
+						},
+						userCreateDBAttr: {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Indicates whether the user is allowed to create new databases.",
+						},
+						userConnLimitAttr: {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The maximum number of database connections the user is permitted to have open concurrently, `-1` when unlimited.",
+						},
+						userValidUntilAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Date and time after which the user's password is no longer valid, `infinity` when it has no time limit.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftUsersRead(db *DBConnection, d *schema.ResourceData) error {
+	var nameRegexp *regexp.Regexp
+	if nameRegex := d.Get(usersNameRegexAttr).(string); nameRegex != "" {
+		var err error
+		if nameRegexp, err = regexp.Compile(nameRegex); err != nil {
+			return fmt.Errorf("Error compiling %s: %w", usersNameRegexAttr, err)
+		}
+	}
+	// GetOk can't tell an unset superuser filter from false.
+	superuserFilter, filterSuperuser := d.GetOkExists(usersSuperuserAttr)
+
+	query := `
+	SELECT
+		u.usesysid,
+		trim(u.usename),
+		u.usesuper,
+		u.usecreatedb,
+		COALESCE(u.useconnlimit::TEXT, 'UNLIMITED'),
+		COALESCE(p.valuntil, 'infinity')
+	FROM svl_user_info u
+	JOIN pg_user_info p ON p.usesysid = u.usesysid
+	ORDER BY 2`
+	log.Printf("[DEBUG] %s\n", query)
+	rows, err := db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	users := []map[string]interface{}{}
+	for rows.Next() {
+		var id, name, connLimit, validUntil string
+		var superuser, createDB bool
+		if err := rows.Scan(&id, &name, &superuser, &createDB, &connLimit, &validUntil); err != nil {
+			return err
+		}
+		if nameRegexp != nil && !nameRegexp.MatchString(name) {
+			continue
+		}
+		if filterSuperuser && superuser != superuserFilter.(bool) {
+			continue
+		}
+
+		connLimitNumber := -1
+		if connLimit != "UNLIMITED" {
+			if connLimitNumber, err = strconv.Atoi(connLimit); err != nil {
+				return fmt.Errorf("connection limit of user %s was not an integer", name)
+			}
+		}
+
+		users = append(users, map[string]interface{}{
+			usersIDAttr:        id,
+			userNameAttr:       name,
+			userSuperuserAttr:  superuser,
+			userCreateDBAttr:   createDB,
+			userConnLimitAttr:  connLimitNumber,
+			userValidUntilAttr: validUntil,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.SetId(db.client.databaseName)
+	d.Set(usersAttr, users)
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRedshiftUsers_Basic(t *testing.T) {
+	prefix := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_data_users"), "-", "_")
+	userName := prefix + "_user"
+	superuserName := prefix + "_superuser"
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceRedshiftUsersConfig_Basic(prefix, userName, superuserName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftUserExists(userName),
+					testAccCheckRedshiftUserExists(superuserName),
+					resource.TestCheckResourceAttr("data.redshift_users.all", fmt.Sprintf("%s.#", usersAttr), "2"),
+					resource.TestCheckResourceAttr("data.redshift_users.all", fmt.Sprintf("%s.0.%s", usersAttr, userNameAttr), superuserName),
+					resource.TestCheckResourceAttr("data.redshift_users.all", fmt.Sprintf("%s.1.%s", usersAttr, userNameAttr), userName),
+					resource.TestCheckResourceAttr("data.redshift_users.superusers", fmt.Sprintf("%s.#", usersAttr), "1"),
+					resource.TestCheckResourceAttrPair("data.redshift_users.superusers", fmt.Sprintf("%s.0.%s", usersAttr, usersIDAttr), "redshift_user.superuser", "id"),
+					resource.TestCheckResourceAttr("data.redshift_users.superusers", fmt.Sprintf("%s.0.%s", usersAttr, userSuperuserAttr), "true"),
+					resource.TestCheckResourceAttr("data.redshift_users.regular", fmt.Sprintf("%s.#", usersAttr), "1"),
+					resource.TestCheckResourceAttr("data.redshift_users.regular", fmt.Sprintf("%s.0.%s", usersAttr, userNameAttr), userName),
+					resource.TestCheckResourceAttr("data.redshift_users.regular", fmt.Sprintf("%s.0.%s", usersAttr, userSuperuserAttr), "false"),
+					resource.TestCheckResourceAttr("data.redshift_users.regular", fmt.Sprintf("%s.0.%s", usersAttr, userCreateDBAttr), "true"),
+					resource.TestCheckResourceAttr("data.redshift_users.regular", fmt.Sprintf("%s.0.%s", usersAttr, userConnLimitAttr), "5"),
+					resource.TestCheckResourceAttr("data.redshift_users.regular", fmt.Sprintf("%s.0.%s", usersAttr, userValidUntilAttr), "infinity"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceRedshiftUsersConfig_Basic(prefix, userName, superuserName string) string {
+	return fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name             = %[2]q
+  create_database  = true
+  connection_limit = 5
+}
+
+resource "redshift_user" "superuser" {
+  name      = %[3]q
+  password  = "Foobarbaz1"
+  superuser = true
+}
+
+data "redshift_users" "all" {
+  name_regex = "^%[1]s_"
+
+  depends_on = [redshift_user.user, redshift_user.superuser]
+}
+
+data "redshift_users" "superusers" {
+  name_regex = "^%[1]s_"
+  superuser  = true
+
+  depends_on = [redshift_user.user, redshift_user.superuser]
+}
+
+data "redshift_users" "regular" {
+  name_regex = "^%[1]s_"
+  superuser  = false
+
+  depends_on = [redshift_user.user, redshift_user.superuser]
+}
+`, prefix, userName, superuserName)
+}
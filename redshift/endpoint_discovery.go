@@ -0,0 +1,132 @@
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// describeClustersAPI is the part of the Redshift client used to discover the endpoint of a cluster.
+type describeClustersAPI interface {
+	DescribeClusters(ctx context.Context, params *redshift.DescribeClustersInput, optFns ...func(*redshift.Options)) (*redshift.DescribeClustersOutput, error)
+}
+
+type getServerlessWorkgroupInput struct {
+	WorkgroupName string `json:"workgroupName"`
+}
+
+type getServerlessWorkgroupOutput struct {
+	Workgroup struct {
+		Status   string `json:"status"`
+		Endpoint *struct {
+			Address string `json:"address"`
+			Port    int    `json:"port"`
+		} `json:"endpoint"`
+	} `json:"workgroup"`
+}
+
+// endpointDiscovery discovers the endpoint of the cluster_identifier cluster or the workgroup_name
+// Redshift Serverless workgroup on the first connection, so that it doesn't have to exist when the provider is configured.
+type endpointDiscovery struct {
+	discover func(ctx context.Context) (string, int, error)
+
+	lock sync.Mutex
+	host string
+	port int
+}
+
+// newEndpointDiscovery returns the discovery of the endpoint of the cluster_identifier cluster
+// or the workgroup_name workgroup, or nil when none of them is configured.
+func newEndpointDiscovery(d *schema.ResourceData) (*endpointDiscovery, error) {
+	clusterIdentifier := d.Get("cluster_identifier").(string)
+	workgroupName := d.Get("workgroup_name").(string)
+	if clusterIdentifier == "" && workgroupName == "" {
+		return nil, nil
+	}
+
+	cfg, err := awsConfig(d)
+	if err != nil {
+		return nil, err
+	}
+
+	if clusterIdentifier != "" {
+		return &endpointDiscovery{discover: func(ctx context.Context) (string, int, error) {
+			return clusterEndpoint(ctx, redshift.NewFromConfig(cfg), clusterIdentifier)
+		}}, nil
+	}
+	return &endpointDiscovery{discover: func(ctx context.Context) (string, int, error) {
+		return workgroupEndpoint(ctx, cfg, serverlessEndpoint(cfg.Region), workgroupName)
+	}}, nil
+}
+
+// endpoint returns the host and port, discovering them on the first call.
+// Failures are not kept, so the next connection discovers the endpoint again.
+func (e *endpointDiscovery) endpoint() (string, int, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.host == "" {
+		host, port, err := e.discover(context.TODO())
+		if err != nil {
+			return "", 0, err
+		}
+		log.Printf("[DEBUG] discovered endpoint %s:%d", host, port)
+		e.host, e.port = host, port
+	}
+	return e.host, e.port, nil
+}
+
+// withDiscoveredEndpoint returns a copy of the configuration with the Host and Port discovered by endpoint, if any.
+func (c *Config) withDiscoveredEndpoint() (*Config, error) {
+	config := *c
+	if config.endpoint == nil {
+		return &config, nil
+	}
+
+	host, port, err := config.endpoint.endpoint()
+	if err != nil {
+		return nil, err
+	}
+	config.Host = host
+	config.Port = port
+	return &config, nil
+}
+
+// clusterEndpoint returns the address and port of the cluster, as reported by DescribeClusters.
+func clusterEndpoint(ctx context.Context, client describeClustersAPI, clusterIdentifier string) (string, int, error) {
+	log.Printf("[DEBUG] making DescribeClusters request for cluster %s", clusterIdentifier)
+	response, err := client.DescribeClusters(ctx, &redshift.DescribeClustersInput{
+		ClusterIdentifier: aws.String(clusterIdentifier),
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("could not describe cluster %s: %w", clusterIdentifier, err)
+	}
+	if len(response.Clusters) == 0 {
+		return "", 0, fmt.Errorf("cluster %s not found", clusterIdentifier)
+	}
+
+	cluster := response.Clusters[0]
+	if cluster.Endpoint == nil || aws.ToString(cluster.Endpoint.Address) == "" {
+		return "", 0, fmt.Errorf("cluster %s has no endpoint yet, its status is %q", clusterIdentifier, aws.ToString(cluster.ClusterStatus))
+	}
+	return aws.ToString(cluster.Endpoint.Address), int(cluster.Endpoint.Port), nil
+}
+
+// workgroupEndpoint returns the address and port of the Redshift Serverless workgroup, as reported by GetWorkgroup.
+func workgroupEndpoint(ctx context.Context, cfg aws.Config, endpoint, workgroupName string) (string, int, error) {
+	output := &getServerlessWorkgroupOutput{}
+	if err := callServerlessAPI(ctx, cfg, endpoint, serverlessWorkgroupAPI, getServerlessWorkgroupInput{WorkgroupName: workgroupName}, output); err != nil {
+		return "", 0, fmt.Errorf("could not get workgroup %s: %w", workgroupName, err)
+	}
+
+	workgroup := output.Workgroup
+	if workgroup.Endpoint == nil || workgroup.Endpoint.Address == "" {
+		return "", 0, fmt.Errorf("workgroup %s has no endpoint yet, its status is %q", workgroupName, workgroup.Status)
+	}
+	return workgroup.Endpoint.Address, workgroup.Endpoint.Port, nil
+}
@@ -0,0 +1,135 @@
+package redshift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	"github.com/aws/aws-sdk-go-v2/service/redshift/types"
+)
+
+type fakeDescribeClusters struct {
+	clusters []types.Cluster
+}
+
+func (f fakeDescribeClusters) DescribeClusters(ctx context.Context, params *redshift.DescribeClustersInput, optFns ...func(*redshift.Options)) (*redshift.DescribeClustersOutput, error) {
+	for _, cluster := range f.clusters {
+		if aws.ToString(cluster.ClusterIdentifier) == aws.ToString(params.ClusterIdentifier) {
+			return &redshift.DescribeClustersOutput{Clusters: []types.Cluster{cluster}}, nil
+		}
+	}
+	return &redshift.DescribeClustersOutput{}, nil
+}
+
+func TestClusterEndpoint(t *testing.T) {
+	client := fakeDescribeClusters{clusters: []types.Cluster{
+		{
+			ClusterIdentifier: aws.String("analytics"),
+			ClusterStatus:     aws.String("available"),
+			Endpoint:          &types.Endpoint{Address: aws.String("analytics.abc123.eu-west-1.redshift.amazonaws.com"), Port: 5439},
+		},
+		{
+			ClusterIdentifier: aws.String("creating"),
+			ClusterStatus:     aws.String("creating"),
+		},
+	}}
+
+	host, port, err := clusterEndpoint(context.Background(), client, "analytics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host != "analytics.abc123.eu-west-1.redshift.amazonaws.com" || port != 5439 {
+		t.Errorf("unexpected endpoint %s:%d", host, port)
+	}
+
+	if _, _, err := clusterEndpoint(context.Background(), client, "creating"); err == nil || !strings.Contains(err.Error(), `"creating"`) {
+		t.Errorf("expected an error reporting the cluster status, got %v", err)
+	}
+	if _, _, err := clusterEndpoint(context.Background(), client, "missing"); err == nil {
+		t.Error("expected an error for a missing cluster")
+	}
+}
+
+func TestWorkgroupEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if target := r.Header.Get("X-Amz-Target"); target != serverlessWorkgroupAPI {
+			t.Errorf("unexpected X-Amz-Target %q", target)
+		}
+
+		input := getServerlessWorkgroupInput{}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Fatal(err)
+		}
+		switch input.WorkgroupName {
+		case "default":
+			w.Write([]byte(`{"workgroup":{"status":"AVAILABLE","endpoint":{"address":"default.123456789012.eu-west-1.redshift-serverless.amazonaws.com","port":5439}}}`))
+		case "creating":
+			w.Write([]byte(`{"workgroup":{"status":"CREATING"}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Workgroup missing not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	cfg := aws.Config{
+		Region:      "eu-west-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	}
+
+	host, port, err := workgroupEndpoint(context.Background(), cfg, server.URL, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host != "default.123456789012.eu-west-1.redshift-serverless.amazonaws.com" || port != 5439 {
+		t.Errorf("unexpected endpoint %s:%d", host, port)
+	}
+
+	if _, _, err := workgroupEndpoint(context.Background(), cfg, server.URL, "creating"); err == nil || !strings.Contains(err.Error(), `"CREATING"`) {
+		t.Errorf("expected an error reporting the workgroup status, got %v", err)
+	}
+	if _, _, err := workgroupEndpoint(context.Background(), cfg, server.URL, "missing"); err == nil || !strings.Contains(err.Error(), "Workgroup missing not found") {
+		t.Errorf("expected the API error, got %v", err)
+	}
+}
+
+func TestEndpointDiscovery(t *testing.T) {
+	calls := 0
+	available := false
+	endpoint := &endpointDiscovery{discover: func(ctx context.Context) (string, int, error) {
+		calls++
+		if !available {
+			return "", 0, fmt.Errorf("cluster analytics has no endpoint yet")
+		}
+		return "analytics.abc123.eu-west-1.redshift.amazonaws.com", 5439, nil
+	}}
+	config := &Config{endpoint: endpoint}
+
+	if _, err := config.withDiscoveredEndpoint(); err == nil {
+		t.Fatal("expected an error while the cluster has no endpoint")
+	}
+
+	available = true
+	for i := 0; i < 2; i++ {
+		discovered, err := config.withDiscoveredEndpoint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if discovered.Host != "analytics.abc123.eu-west-1.redshift.amazonaws.com" || discovered.Port != 5439 {
+			t.Errorf("unexpected endpoint %s:%d", discovered.Host, discovered.Port)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected the endpoint to be discovered again after a failure and then cached, got %d calls", calls)
+	}
+	if config.Host != "" {
+		t.Errorf("expected the configuration to be left unchanged, got host %s", config.Host)
+	}
+}
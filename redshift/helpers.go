@@ -1,14 +1,20 @@
 package redshift
 
 import (
+	"crypto/sha256"
 	"database/sql"
 	"encoding/csv"
+	"encoding/hex"
 	"fmt"
 	"log"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/brainly/terraform-provider-redshift/redshift/internal/catalog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/lib/pq"
 )
 
@@ -18,6 +24,16 @@ const (
 	pqErrorCodeDeadlock          = "40P01"
 	pqErrorCodeFailedTransaction = "25P02"
 	pqErrorCodeDuplicateSchema   = "42P06"
+
+	clusterAvailableTimeout      = 60 * time.Minute
+	clusterAvailablePollInterval = 30 * time.Second
+
+	granteeWaitTimeout      = 5 * time.Minute
+	granteeWaitPollInterval = 5 * time.Second
+
+	// maxObjectsPerStatement limits the objects listed in a single GRANT or REVOKE statement,
+	// as statements on thousands of objects exceed the maximum statement length.
+	maxObjectsPerStatement = 500
 )
 
 // startTransaction starts a new DB transaction on the specified database.
@@ -37,6 +53,14 @@ func startTransaction(client *Client, database string) (*sql.Tx, error) {
 		return nil, fmt.Errorf("could not start transaction: %w", err)
 	}
 
+	// SET LOCAL only lasts until the end of the transaction, so pooled connections keep the default timeout.
+	if timeout := client.config.StatementTimeout; timeout > 0 {
+		if _, err := txn.Exec(fmt.Sprintf("SET LOCAL statement_timeout TO %d", timeout*1000)); err != nil {
+			txn.Rollback()
+			return nil, fmt.Errorf("could not set statement timeout: %w", err)
+		}
+	}
+
 	return txn, nil
 }
 
@@ -53,6 +77,27 @@ func deferredRollback(txn *sql.Tx) {
 	}
 }
 
+// resourceGetter reads the attributes of a resource from its state (*schema.ResourceData) or from its plan (*schema.ResourceDiff),
+// so that validations can run both at plan time in CustomizeDiff and at apply time.
+type resourceGetter interface {
+	Get(key string) interface{}
+	GetOk(key string) (interface{}, bool)
+}
+
+// fingerprint returns a hash of the parts, used to detect changes of catalog rows cheaply.
+func fingerprint(parts []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// likePatternReplacer escapes the wildcards of LIKE patterns, using the default escape character.
+var likePatternReplacer = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLikePattern returns the value escaped to be matched literally within a LIKE pattern.
+func escapeLikePattern(value string) string {
+	return likePatternReplacer.Replace(value)
+}
+
 // pqQuoteLiteral returns a string literal safe for inclusion in a PostgreSQL
 // query as a parameter.  The resulting string still needs to be wrapped in
 // single quotes in SQL (i.e. fmt.Sprintf(`'%s'`, pqQuoteLiteral("str"))).  See
@@ -63,24 +108,109 @@ func pqQuoteLiteral(in string) string {
 	return in
 }
 
-func getGroupIDFromName(tx *sql.Tx, group string) (groupID int, err error) {
-	err = tx.QueryRow("SELECT grosysid FROM pg_group WHERE groname = $1", group).Scan(&groupID)
-	return
+// commentLiteral returns the comment as a literal for COMMENT ON statements, NULL removing the comment when empty.
+func commentLiteral(comment string) string {
+	if comment == "" {
+		return "NULL"
+	}
+	return fmt.Sprintf("'%s'", pqQuoteLiteral(comment))
+}
+
+func RedshiftResourceFunc(fn func(*DBConnection, *schema.ResourceData) error) func(*schema.ResourceData, interface{}) error {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		client, stats := meta.(*Client).withStatementStats()
+		defer logStatementStats(d, stats, time.Now())
+
+		if client.config.ReadOnly {
+			return errReadOnly(d)
+		}
+
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		if client.config.WarnOnUnquotedIdentifiers {
+			warnOnUnquotedIdentifiers(d)
+		}
+
+		if client.config.WaitForClusterAvailable {
+			if err := waitForClusterAvailable(db); err != nil {
+				return err
+			}
+		}
+
+		return fn(db, d)
+	}
+}
+
+// errReadOnly returns the error of the operations modifying the resource when the provider is read only.
+func errReadOnly(d *schema.ResourceData) error {
+	if d.Id() == "" {
+		return fmt.Errorf("the provider is configured with read_only = true, resources can't be created")
+	}
+	return fmt.Errorf("the provider is configured with read_only = true, resource %q can't be updated or deleted", d.Id())
 }
 
-func getUserIDFromName(tx *sql.Tx, user string) (userID int, err error) {
-	err = tx.QueryRow("SELECT usesysid FROM pg_user WHERE usename = $1", user).Scan(&userID)
-	return
+// identifierAttrs are the attributes holding the names of objects, as used across resources.
+var identifierAttrs = []string{"name", "schema", "owner", "user", "group", "role", "grantee_role"}
+
+// warnOnUnquotedIdentifiers logs a warning for the names of the resource which require quoting.
+func warnOnUnquotedIdentifiers(d *schema.ResourceData) {
+	for _, attr := range identifierAttrs {
+		name, ok := d.Get(attr).(string)
+		if !ok || name == "" || strings.ToLower(name) == grantToPublicName {
+			continue
+		}
+		if identifierRequiresQuoting(name) {
+			log.Printf("[WARN] %s %q requires quoting, consider renaming it to %q", attr, name, suggestedIdentifier(name))
+		}
+	}
 }
 
-func getSchemaIDFromName(tx *sql.Tx, schema string) (schemaID int, err error) {
-	err = tx.QueryRow("SELECT oid FROM pg_namespace WHERE nspname = $1", schema).Scan(&schemaID)
-	return
+var quotedIdentifierCharsPattern = regexp.MustCompile(`[^a-z0-9_$]+`)
+
+// suggestedIdentifier returns a name which doesn't require quoting, similar to the given one.
+func suggestedIdentifier(name string) string {
+	suggested := quotedIdentifierCharsPattern.ReplaceAllString(strings.ToLower(name), "_")
+	if suggested == "" || (suggested[0] >= '0' && suggested[0] <= '9') || suggested[0] == '$' {
+		suggested = "_" + suggested
+	}
+	if sliceContainsStr(reservedWords, suggested) {
+		suggested += "_"
+	}
+	return suggested
 }
 
-func RedshiftResourceFunc(fn func(*DBConnection, *schema.ResourceData) error) func(*schema.ResourceData, interface{}) error {
+// waitForClusterAvailable blocks while the cluster is being resized or restored,
+// that is while some tables are still waiting to be restored.
+func waitForClusterAvailable(db *DBConnection) error {
+	deadline := time.Now().Add(clusterAvailableTimeout)
+	for {
+		var pendingTables int
+		err := db.QueryRow("SELECT COUNT(*) FROM stv_xrestore_alter_queue_state WHERE status <> 'Finished'").Scan(&pendingTables)
+		if err != nil {
+			log.Printf("[WARN] could not check if the cluster is being resized or restored, not waiting: %v", err)
+			return nil
+		}
+		if pendingTables == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("cluster is still being resized or restored after %s, %d tables are not restored yet", clusterAvailableTimeout, pendingTables)
+		}
+
+		log.Printf("[INFO] cluster is being resized or restored, %d tables are not restored yet, waiting %s", pendingTables, clusterAvailablePollInterval)
+		time.Sleep(clusterAvailablePollInterval)
+	}
+}
+
+// RedshiftResourceReadFunc works like RedshiftResourceFunc, but connects with the
+// read-only credentials when they are configured in the provider.
+func RedshiftResourceReadFunc(fn func(*DBConnection, *schema.ResourceData) error) func(*schema.ResourceData, interface{}) error {
 	return func(d *schema.ResourceData, meta interface{}) error {
-		client := meta.(*Client)
+		client, stats := meta.(*Client).ReadOnly().withStatementStats()
+		defer logStatementStats(d, stats, time.Now())
 
 		db, err := client.Connect()
 		if err != nil {
@@ -91,10 +221,16 @@ func RedshiftResourceFunc(fn func(*DBConnection, *schema.ResourceData) error) fu
 	}
 }
 
+// RedshiftResourceRetryOnPQErrors retries fn when it fails with a transient error, e.g. a concurrent update,
+// as many times as configured with retry_attempts of the provider. Connection errors are only retried
+// when establishing the connection, as fn may have already applied some of its statements.
+// Only errors returned as is by fn are retried, so the errors of statements which should be retried must not be wrapped.
 func RedshiftResourceRetryOnPQErrors(fn func(*DBConnection, *schema.ResourceData) error) func(*DBConnection, *schema.ResourceData) error {
 	return func(db *DBConnection, d *schema.ResourceData) error {
-		for i := 0; i < 10; i++ {
-			err := fn(db, d)
+		config := db.client.config
+		var err error
+		for attempt := 1; attempt <= config.retryAttempts(); attempt++ {
+			err = fn(db, d)
 			if err == nil {
 				return nil
 			}
@@ -103,15 +239,185 @@ func RedshiftResourceRetryOnPQErrors(fn func(*DBConnection, *schema.ResourceData
 				return err
 			}
 
-			time.Sleep(time.Duration(i+1) * time.Second)
+			if attempt < config.retryAttempts() {
+				time.Sleep(config.retryDelay(attempt))
+			}
 		}
-		return nil
+		return err
+	}
+}
+
+// resourceDatabaseAttr is the attribute of the resources which can be managed in another database
+// than the one the provider connects to.
+const resourceDatabaseAttr = "database"
+
+// resourceDatabaseSchema returns the schema of the database attribute, see RedshiftResourceInDatabase.
+func resourceDatabaseSchema(objectName string) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.StringIsNotWhiteSpace,
+		Description:  fmt.Sprintf("The database to manage the %s in. Defaults to the database the provider connects to. Connections to other databases use the credentials of the provider and are shared by all resources in the same database. Resources in other databases can't be imported.", objectName),
+	}
+}
+
+// normalizedNameAttr is the attribute of the users, groups and schemas holding their name as normalized by the provider.
+const normalizedNameAttr = "normalized_name"
+
+// normalizedNameSchema returns the schema of the normalized_name attribute, see normalizedNameDiff.
+func normalizedNameSchema(objectName string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: fmt.Sprintf("The name of the %s as normalized by the provider, trimmed and lowercased unless `case_sensitive_identifiers` is set. It is known when planning, so it can be used to compose IDs and grants without case mismatches.", objectName),
+	}
+}
+
+// connectToResourceDatabase returns a connection to the database set in the database attribute of the resource,
+// with the same credentials as db.
+func connectToResourceDatabase(db *DBConnection, d *schema.ResourceData) (*DBConnection, error) {
+	database, ok := d.GetOk(resourceDatabaseAttr)
+	if !ok || database.(string) == db.client.databaseName {
+		return db, nil
+	}
+	return db.client.config.NewClient(database.(string)).Connect()
+}
+
+// RedshiftResourceInDatabase runs fn connected to the database set in the database attribute of the resource,
+// so that the statements of fn, including those run in transactions started with db.client, apply to that database.
+func RedshiftResourceInDatabase(fn func(*DBConnection, *schema.ResourceData) error) func(*DBConnection, *schema.ResourceData) error {
+	return func(db *DBConnection, d *schema.ResourceData) error {
+		db, err := connectToResourceDatabase(db, d)
+		if err != nil {
+			return err
+		}
+		return fn(db, d)
+	}
+}
+
+// RedshiftResourceExistsInDatabase works like RedshiftResourceInDatabase for Exists functions.
+func RedshiftResourceExistsInDatabase(fn func(*DBConnection, *schema.ResourceData) (bool, error)) func(*DBConnection, *schema.ResourceData) (bool, error) {
+	return func(db *DBConnection, d *schema.ResourceData) (bool, error) {
+		db, err := connectToResourceDatabase(db, d)
+		if err != nil {
+			return false, err
+		}
+		return fn(db, d)
+	}
+}
+
+// resourceStatementTimeoutAttr is the attribute of the resources which can override the statement timeout of the provider.
+const resourceStatementTimeoutAttr = "statement_timeout"
+
+// resourceStatementTimeoutSchema returns the schema of the statement_timeout attribute, see RedshiftResourceWithStatementTimeout.
+func resourceStatementTimeoutSchema(operation string) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntAtLeast(0),
+		Description:  fmt.Sprintf("The timeout of the statements, in seconds, overriding the `statement_timeout` of the provider for this resource only, e.g. for slow %s. `0` (the default) uses the timeout of the provider.", operation),
+	}
+}
+
+// RedshiftResourceWithStatementTimeout runs fn with the statement timeout set in the statement_timeout attribute of the resource,
+// which applies to the transactions started with db.client.
+func RedshiftResourceWithStatementTimeout(fn func(*DBConnection, *schema.ResourceData) error) func(*DBConnection, *schema.ResourceData) error {
+	return func(db *DBConnection, d *schema.ResourceData) error {
+		timeout, ok := d.GetOk(resourceStatementTimeoutAttr)
+		if !ok {
+			return fn(db, d)
+		}
+		config := db.client.config
+		config.StatementTimeout = timeout.(int)
+		return fn(&DBConnection{db.DB, config.NewClient(db.client.databaseName)}, d)
+	}
+}
+
+// resourceWaitForGranteeAttr is the attribute of the grant resources which waits for the grantee to exist, see RedshiftResourceWaitForGrantee.
+const resourceWaitForGranteeAttr = "wait_for_grantee"
+
+// resourceWaitForGranteeSchema returns the schema of the wait_for_grantee attribute.
+func resourceWaitForGranteeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: fmt.Sprintf("When set to `true`, creating the resource waits up to %d minutes for the grantee to exist, e.g. when it is referenced by name and created by another terraform state or team, instead of failing right away.", int(granteeWaitTimeout.Minutes())),
+	}
+}
+
+// granteeAttrKinds maps the grantee attributes of the grant resources to the kind of catalog object they name.
+var granteeAttrKinds = map[string]catalog.Kind{
+	"user":   catalog.User,
+	"group":  catalog.Group,
+	"role":   catalog.Role,
+	"users":  catalog.User,
+	"groups": catalog.Group,
+	"roles":  catalog.Role,
+}
+
+// resourceGrantees returns the kind and the names of the grantees of the resource, set in one of the attrs
+// either as a single name or as a set of names. No names are returned when there is no grantee to wait for,
+// e.g. for grants to public.
+func resourceGrantees(d *schema.ResourceData, attrs ...string) (kind catalog.Kind, names []string) {
+	for _, attr := range attrs {
+		value, set := d.GetOk(attr)
+		if !set {
+			continue
+		}
+		if values, isSet := value.(*schema.Set); isSet {
+			for _, name := range values.List() {
+				names = append(names, name.(string))
+			}
+			sort.Strings(names)
+			return granteeAttrKinds[attr], names
+		}
+		name := value.(string)
+		if attr == "group" && strings.ToLower(name) == grantToPublicName {
+			return "", nil
+		}
+		return granteeAttrKinds[attr], []string{name}
+	}
+	return "", nil
+}
+
+// RedshiftResourceWaitForGrantee runs fn once the grantees set in one of the attrs exist,
+// when the wait_for_grantee attribute of the resource is set.
+func RedshiftResourceWaitForGrantee(fn func(*DBConnection, *schema.ResourceData) error, attrs ...string) func(*DBConnection, *schema.ResourceData) error {
+	return func(db *DBConnection, d *schema.ResourceData) error {
+		if !d.Get(resourceWaitForGranteeAttr).(bool) {
+			return fn(db, d)
+		}
+		kind, names := resourceGrantees(d, attrs...)
+
+		deadline := time.Now().Add(granteeWaitTimeout)
+		for _, name := range names {
+			for {
+				_, err := catalog.NewResolver(db).ID(kind, name)
+				if err == nil {
+					break
+				}
+				if err != sql.ErrNoRows {
+					return fmt.Errorf("could not check if %s %s exists: %w", kind, name, err)
+				}
+				if time.Now().After(deadline) {
+					return fmt.Errorf("%s %s does not exist after waiting %s", kind, name, granteeWaitTimeout)
+				}
+
+				log.Printf("[INFO] %s %s does not exist yet, waiting %s", kind, name, granteeWaitPollInterval)
+				time.Sleep(granteeWaitPollInterval)
+			}
+		}
+
+		return fn(db, d)
 	}
 }
 
 func RedshiftResourceExistsFunc(fn func(*DBConnection, *schema.ResourceData) (bool, error)) func(*schema.ResourceData, interface{}) (bool, error) {
 	return func(d *schema.ResourceData, meta interface{}) (bool, error) {
-		client := meta.(*Client)
+		client, stats := meta.(*Client).ReadOnly().withStatementStats()
+		defer logStatementStats(d, stats, time.Now())
 
 		db, err := client.Connect()
 		if err != nil {
@@ -153,48 +459,58 @@ func splitCsvAndTrim(raw string) ([]string, error) {
 	return result, nil
 }
 
+// hashCaseInsensitiveString hashes strings ignoring their case.
+// It is meant for sets of identifiers which Redshift folds to lower case.
+func hashCaseInsensitiveString(v interface{}) int {
+	return schema.HashString(strings.ToLower(v.(string)))
+}
+
+// privilegeAll grants all privileges available for the object type.
+const privilegeAll = "all"
+
+// allowedPrivileges lists the privileges which can be granted per object type,
+// as reported when reading the privileges back.
+var allowedPrivileges = map[string][]string{
+	"schema":    {"create", "usage"},
+	"table":     {"select", "update", "insert", "delete", "drop", "references", "rule", "trigger", "truncate"},
+	"database":  {"create", "temporary", "usage"}, // usage only applies to databases created from datashares
+	"procedure": {"execute"},
+	"function":  {"execute"},
+	"language":  {"usage", "create"}, // create delegates the creation of UDFs in the language, where Redshift supports it
+	// System permissions, which can only be granted to roles.
+	"system": {
+		"create user", "drop user", "alter user",
+		"create schema", "drop schema",
+		"alter default privileges",
+		"access catalog", "access system table",
+		"create table", "drop table", "alter table", "truncate table",
+		"create or replace function", "create or replace external function", "drop function",
+		"create or replace procedure", "drop procedure",
+		"create or replace view", "drop view",
+		"create model", "drop model",
+		"create datashare", "alter datashare", "drop datashare",
+		"create library", "drop library",
+		"create role", "drop role",
+		"alter system",
+		"vacuum", "analyze", "cancel",
+		"ignore rls", "explain rls",
+	},
+}
+
 func validatePrivileges(privileges []string, objectType string) bool {
 	if objectType == "language" && len(privileges) == 0 {
 		return false
 	}
 	for _, p := range privileges {
-		switch strings.ToUpper(objectType) {
-		case "SCHEMA":
-			switch strings.ToUpper(p) {
-			case "CREATE", "USAGE":
-				continue
-			default:
-				return false
-			}
-		case "TABLE":
-			switch strings.ToUpper(p) {
-			case "SELECT", "UPDATE", "INSERT", "DELETE", "DROP", "REFERENCES", "RULE", "TRIGGER":
-				continue
-			default:
-				return false
-			}
-		case "DATABASE":
-			switch strings.ToUpper(p) {
-			case "CREATE", "TEMPORARY":
-				continue
-			default:
-				return false
-			}
-		case "PROCEDURE", "FUNCTION":
-			switch strings.ToUpper(p) {
-			case "EXECUTE":
-				continue
-			default:
-				return false
-			}
-		case "LANGUAGE":
-			switch strings.ToUpper(p) {
-			case "USAGE":
-				continue
-			default:
-				return false
-			}
-		default:
+		allowed, ok := allowedPrivileges[strings.ToLower(objectType)]
+		if !ok {
+			return false
+		}
+		// ALL can't be combined with other privileges.
+		if strings.ToLower(p) == privilegeAll && len(privileges) == 1 {
+			continue
+		}
+		if !sliceContainsStr(allowed, strings.ToLower(p)) {
 			return false
 		}
 	}
@@ -202,6 +518,107 @@ func validatePrivileges(privileges []string, objectType string) bool {
 	return true
 }
 
+// privilegesImpliedByAll overrides allowedPrivileges for the object types where ALL doesn't grant every privilege.
+var privilegesImpliedByAll = map[string][]string{
+	"database": {"create", "temporary"},
+	"language": {"usage"},
+}
+
+// expandPrivileges replaces "all" with the privileges it implies for the object type.
+func expandPrivileges(privileges *schema.Set, objectType string) *schema.Set {
+	if !privileges.Contains(privilegeAll) {
+		return privileges
+	}
+	implied, ok := privilegesImpliedByAll[strings.ToLower(objectType)]
+	if !ok {
+		implied = allowedPrivileges[strings.ToLower(objectType)]
+	}
+	expanded := schema.NewSet(schema.HashString, nil)
+	for _, p := range implied {
+		expanded.Add(p)
+	}
+	return expanded
+}
+
+// privilegesMatch reports whether the observed privileges match the declared ones.
+// Unless strict is set, privileges implied by the declared ones (e.g. by "all") are considered equal.
+// Privileges unknown to the provider, e.g. introduced by newer Redshift releases, are considered implied by "all".
+func privilegesMatch(declared, observed *schema.Set, objectType string, strict bool) bool {
+	if declared.Equal(observed) {
+		return true
+	}
+	if strict {
+		return false
+	}
+	if declared.Contains(privilegeAll) {
+		observed = knownPrivileges(observed, objectType)
+	}
+	return expandPrivileges(declared, objectType).Equal(observed)
+}
+
+// combineObjectsPrivileges combines the privileges observed on each object into the privileges to store in the state:
+// the configured privileges all the objects have, plus any privilege beyond the configured ones that one of the objects has.
+// A single update granting and revoking the difference to the configured privileges then repairs every object.
+// Extra privileges implied by the configured ones are ignored by privilegesMatch unless strict is set.
+func combineObjectsPrivileges(declared *schema.Set, objectType string, observed []*schema.Set) *schema.Set {
+	expected := expandPrivileges(declared, objectType)
+	combined := schema.NewSet(schema.HashString, nil)
+	for _, p := range expected.List() {
+		onAll := len(observed) > 0
+		for _, privileges := range observed {
+			if !privileges.Contains(p) {
+				onAll = false
+				break
+			}
+		}
+		if onAll {
+			combined.Add(p)
+		}
+	}
+	for _, privileges := range observed {
+		for _, p := range privileges.List() {
+			if !expected.Contains(p) {
+				combined.Add(p)
+			}
+		}
+	}
+	return combined
+}
+
+// isKnownPrivilege reports whether the privilege can be managed by the provider for the object type.
+func isKnownPrivilege(privilege, objectType string) bool {
+	return sliceContainsStr(allowedPrivileges[strings.ToLower(objectType)], privilege)
+}
+
+// knownPrivileges returns the privileges which can be managed by the provider for the object type.
+func knownPrivileges(privileges *schema.Set, objectType string) *schema.Set {
+	known := schema.NewSet(schema.HashString, nil)
+	for _, p := range privileges.List() {
+		if isKnownPrivilege(p.(string), objectType) {
+			known.Add(p)
+		}
+	}
+	return known
+}
+
+func sliceContainsStr(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
+// stringsToInterfaces converts the values, e.g. to initialize a schema.Set.
+func stringsToInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
 func appendIfTrue(condition bool, item string, list *[]string) {
 	if condition {
 		*list = append(*list, item)
@@ -221,26 +638,148 @@ func setToPgIdentList(identifiers *schema.Set, prefix string) string {
 	return strings.Join(quoted, ",")
 }
 
-// Quoted identifiers somehow does not work for grants/revokes on functions and procedures
+// privilegesList lists the privileges for GRANT, REVOKE and ALTER DEFAULT PRIVILEGES statements, sorted for stable queries.
+func privilegesList(privileges *schema.Set) []string {
+	list := []string{}
+	for _, p := range privileges.List() {
+		list = append(list, strings.ToUpper(p.(string)))
+	}
+	sort.Strings(list)
+	return list
+}
+
+// chunkIdentifiers splits the identifiers, in sorted order, into sets of at most size identifiers.
+func chunkIdentifiers(identifiers *schema.Set, size int) []*schema.Set {
+	sorted := []string{}
+	for _, identifier := range identifiers.List() {
+		sorted = append(sorted, identifier.(string))
+	}
+	sort.Strings(sorted)
+
+	chunks := []*schema.Set{}
+	for start := 0; start < len(sorted); start += size {
+		end := start + size
+		if end > len(sorted) {
+			end = len(sorted)
+		}
+		chunk := schema.NewSet(schema.HashString, nil)
+		for _, identifier := range sorted[start:end] {
+			chunk.Add(identifier)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// quoteIdentifierIfNeeded quotes the identifier when it requires quoting, e.g. when it contains dots or quotes
+// or is a reserved word, see identifierRequiresQuoting. Upper case letters alone don't require quoting,
+// as Redshift folds them to lower case in both cases unless case_sensitive_identifiers is enabled.
+func quoteIdentifierIfNeeded(identifier string) string {
+	if identifierRequiresQuoting(strings.ToLower(identifier)) {
+		return pq.QuoteIdentifier(identifier)
+	}
+	return identifier
+}
+
+// Quoted identifiers somehow does not work for grants/revokes on functions and procedures,
+// so only the names which can't be used without quotes are quoted, leaving the argument lists untouched.
 func setToPgIdentListNotQuoted(identifiers *schema.Set, prefix string) string {
 	quoted := make([]string, identifiers.Len())
 	for i, identifier := range identifiers.List() {
-		if prefix == "" {
-			quoted[i] = identifier.(string)
-		} else {
-			quoted[i] = fmt.Sprintf("%s.%s", prefix, identifier.(string))
+		name, args := identifier.(string), ""
+		if idx := strings.Index(name, "("); idx >= 0 {
+			name, args = name[:idx], name[idx:]
+		}
+		quoted[i] = quoteIdentifierIfNeeded(name) + args
+		if prefix != "" {
+			quoted[i] = fmt.Sprintf("%s.%s", quoteIdentifierIfNeeded(prefix), quoted[i])
 		}
 	}
 
 	return strings.Join(quoted, ",")
 }
 
+// setToPgCallableIdentList quotes the schema and the names of the callables,
+// leaving the argument lists untouched, e.g. "schema"."Func"(int).
+func setToPgCallableIdentList(identifiers *schema.Set, prefix string) string {
+	quoted := make([]string, identifiers.Len())
+	for i, identifier := range identifiers.List() {
+		name, args := identifier.(string), ""
+		if idx := strings.Index(name, "("); idx >= 0 {
+			name, args = name[:idx], name[idx:]
+		}
+		quoted[i] = pq.QuoteIdentifier(name) + args
+		if prefix != "" {
+			quoted[i] = fmt.Sprintf("%s.%s", pq.QuoteIdentifier(prefix), quoted[i])
+		}
+	}
+
+	return strings.Join(quoted, ",")
+}
+
+// callableSignatures returns the signatures of the callables definitions, see callableSignature.
+func callableSignatures(defs *schema.Set) []string {
+	signatures := make([]string, 0, defs.Len())
+	for _, def := range defs.List() {
+		signatures = append(signatures, callableSignature(def.(string)))
+	}
+	return signatures
+}
+
+// callableSignature returns the signature of the callable definition the way it is built from the catalog,
+// with proname || '(' || oidvectortypes(proargtypes) || ')', e.g. "my_function(integer, character varying)"
+// for "my_function(int, varchar(20))". Argument types are normalized and their modifiers dropped,
+// as they are not part of the signature.
+func callableSignature(def string) string {
+	open := strings.Index(def, "(")
+	if open < 0 || !strings.HasSuffix(strings.TrimSpace(def), ")") {
+		return strings.TrimSpace(def) + "()"
+	}
+	name := strings.TrimSpace(def[:open])
+	arguments := strings.TrimSpace(def[open+1:])
+	arguments = strings.TrimSpace(arguments[:len(arguments)-1])
+
+	types := []string{}
+	for _, argument := range splitCallableArguments(arguments) {
+		typ := normalizeColumnType(argument)
+		if i := strings.Index(typ, "("); i >= 0 {
+			typ = typ[:i]
+		}
+		types = append(types, typ)
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(types, ", "))
+}
+
+// splitCallableArguments splits the arguments of a callable definition on the commas which are not within parentheses,
+// e.g. of numeric(18,2).
+func splitCallableArguments(arguments string) []string {
+	parts := []string{}
+	depth, start := 0, 0
+	for i, c := range arguments {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(arguments[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(arguments[start:]); last != "" || len(parts) > 0 {
+		parts = append(parts, last)
+	}
+	return parts
+}
+
 func stripArgumentsFromCallablesDefinitions(defs *schema.Set) []string {
 	parser := func(name string) string {
 		return strings.Split(name, "(")[0]
 	}
 
-	names := make([]string, defs.Len())
+	names := make([]string, 0, defs.Len())
 	for _, def := range defs.List() {
 		names = append(names, parser(def.(string)))
 	}
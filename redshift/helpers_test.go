@@ -1,7 +1,16 @@
 package redshift
 
 import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/brainly/terraform-provider-redshift/redshift/internal/catalog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
 )
 
 func TestValidatePrivileges(t *testing.T) {
@@ -31,7 +40,7 @@ func TestValidatePrivileges(t *testing.T) {
 			expected:   true,
 		},
 		"valid list for table": {
-			privileges: []string{"insert", "update", "delete", "select", "drop", "references", "rule", "trigger"},
+			privileges: []string{"insert", "update", "delete", "select", "drop", "references", "rule", "trigger", "truncate"},
 			objectType: "table",
 			expected:   true,
 		},
@@ -50,6 +59,16 @@ func TestValidatePrivileges(t *testing.T) {
 			objectType: "table",
 			expected:   true,
 		},
+		"valid list for system": {
+			privileges: []string{"create model", "access catalog", "alter system"},
+			objectType: "system",
+			expected:   true,
+		},
+		"invalid list for system": {
+			privileges: []string{"select"},
+			objectType: "system",
+			expected:   false,
+		},
 		"valid list for function": {
 			privileges: []string{"execute"},
 			objectType: "function",
@@ -90,11 +109,26 @@ func TestValidatePrivileges(t *testing.T) {
 			objectType: "language",
 			expected:   false,
 		},
+		"create for language": {
+			privileges: []string{"usage", "create"},
+			objectType: "language",
+			expected:   true,
+		},
 		"extended invalid list for language": {
 			privileges: []string{"usage", "foo"},
 			objectType: "language",
 			expected:   false,
 		},
+		"all for table": {
+			privileges: []string{"ALL"},
+			objectType: "table",
+			expected:   true,
+		},
+		"all combined with other privileges": {
+			privileges: []string{"all", "select"},
+			objectType: "table",
+			expected:   false,
+		},
 		"empty list for language": {
 			privileges: []string{},
 			objectType: "language",
@@ -112,3 +146,378 @@ func TestValidatePrivileges(t *testing.T) {
 		})
 	}
 }
+
+func TestPrivilegesMatch(t *testing.T) {
+	set := func(privileges ...interface{}) *schema.Set {
+		return schema.NewSet(schema.HashString, privileges)
+	}
+
+	cases := map[string]struct {
+		declared   *schema.Set
+		observed   *schema.Set
+		objectType string
+		strict     bool
+		expected   bool
+	}{
+		"equal":                   {set("create", "usage"), set("usage", "create"), "schema", false, true},
+		"different":               {set("usage"), set("usage", "create"), "schema", false, false},
+		"all implies everything":  {set("all"), set("create", "usage"), "schema", false, true},
+		"all does not match less": {set("all"), set("usage"), "schema", false, false},
+		"strict":                  {set("all"), set("create", "usage"), "schema", true, false},
+		"all on database":         {set("all"), set("create", "temporary"), "database", false, true},
+		"all implies no usage":    {set("all"), set("create", "temporary", "usage"), "database", false, false},
+		"all on language":         {set("all"), set("usage"), "language", false, true},
+		"all implies no create":   {set("all"), set("usage", "create"), "language", false, false},
+		"all implies unknown":     {set("all"), set("create", "usage", "alter"), "schema", false, true},
+		"unknown is a difference": {set("create", "usage"), set("create", "usage", "alter"), "schema", false, false},
+		"strict unknown":          {set("all"), set("create", "usage", "alter"), "schema", true, false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := privilegesMatch(c.declared, c.observed, c.objectType, c.strict); actual != c.expected {
+				t.Errorf("expected %t, got %t", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCombineObjectsPrivileges(t *testing.T) {
+	set := func(privileges ...interface{}) *schema.Set {
+		return schema.NewSet(schema.HashString, privileges)
+	}
+
+	cases := map[string]struct {
+		declared   *schema.Set
+		observed   []*schema.Set
+		objectType string
+		expected   *schema.Set
+	}{
+		"no objects":           {set("select"), nil, "table", set()},
+		"all match":            {set("select", "insert"), []*schema.Set{set("select", "insert"), set("select", "insert")}, "table", set("select", "insert")},
+		"different missing":    {set("select", "insert"), []*schema.Set{set("select", "insert"), set("select"), set("insert")}, "table", set()},
+		"one missing":          {set("select", "insert"), []*schema.Set{set("select", "insert"), set("select")}, "table", set("select")},
+		"extra on one":         {set("select"), []*schema.Set{set("select"), set("select", "delete")}, "table", set("select", "delete")},
+		"missing and extra":    {set("select", "insert"), []*schema.Set{set("select", "delete"), set("insert")}, "table", set("delete")},
+		"all expanded":         {set("all"), []*schema.Set{set("create", "usage"), set("usage")}, "schema", set("usage")},
+		"all with unknown":     {set("all"), []*schema.Set{set("create", "usage", "alter"), set("create", "usage")}, "schema", set("create", "usage", "alter")},
+		"missing object empty": {set("usage"), []*schema.Set{set("usage"), set()}, "language", set()},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := combineObjectsPrivileges(c.declared, c.objectType, c.observed); !actual.Equal(c.expected) {
+				t.Errorf("expected %v, got %v", c.expected.List(), actual.List())
+			}
+		})
+	}
+}
+
+func TestHashCaseInsensitiveString(t *testing.T) {
+	if hashCaseInsensitiveString("wOoOT_I22_@tH15") != hashCaseInsensitiveString("wooot_i22_@th15") {
+		t.Error("expected identifiers differing only in case to have the same hash")
+	}
+	if hashCaseInsensitiveString("schema_a") == hashCaseInsensitiveString("schema_b") {
+		t.Error("expected different identifiers to have different hashes")
+	}
+}
+
+func TestStripArgumentsFromCallablesDefinitions(t *testing.T) {
+	defs := schema.NewSet(schema.HashString, []interface{}{"my_function(float)", "my_procedure(int, varchar)"})
+
+	names := stripArgumentsFromCallablesDefinitions(defs)
+	if len(names) != 2 {
+		t.Fatalf("expected 2 names, got %d: %v", len(names), names)
+	}
+	for _, name := range names {
+		if name != "my_function" && name != "my_procedure" {
+			t.Errorf("unexpected name %q", name)
+		}
+	}
+}
+
+func TestSetToPgCallableIdentList(t *testing.T) {
+	defs := schema.NewSet(schema.HashString, []interface{}{"MyFunction(float)"})
+
+	expected := `"MySchema"."MyFunction"(float)`
+	if result := setToPgCallableIdentList(defs, "MySchema"); result != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+
+	expected = `"MyFunction"(float)`
+	if result := setToPgCallableIdentList(defs, ""); result != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestSetToPgIdentListHostileNames(t *testing.T) {
+	cases := map[string]struct {
+		identifier string
+		prefix     string
+		expected   string
+	}{
+		"dots":           {"my.table", "", `"my.table"`},
+		"quotes":         {`my"table`, "", `"my""table"`},
+		"quoted schema":  {"my_table", `my"schema`, `"my""schema"."my_table"`},
+		"dotted schema":  {"my.table", "my.schema", `"my.schema"."my.table"`},
+		"injection":      {`t"; DROP TABLE x; --`, "s", `"s"."t""; DROP TABLE x; --"`},
+		"trailing quote": {`table"`, "", `"table"""`},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			identifiers := schema.NewSet(schema.HashString, []interface{}{c.identifier})
+			if result := setToPgIdentList(identifiers, c.prefix); result != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, result)
+			}
+		})
+	}
+}
+
+func TestSetToPgIdentListNotQuoted(t *testing.T) {
+	cases := map[string]struct {
+		identifier string
+		prefix     string
+		expected   string
+	}{
+		"plain":         {"my_function(float)", "my_schema", "my_schema.my_function(float)"},
+		"upper case":    {"MyFunction(float)", "MySchema", "MySchema.MyFunction(float)"},
+		"dots":          {"my.function(float)", "my_schema", `my_schema."my.function"(float)`},
+		"quotes":        {`my"function(float)`, "my_schema", `my_schema."my""function"(float)`},
+		"dotted schema": {"my_function", "my.schema", `"my.schema".my_function`},
+		"reserved word": {"select(int)", "table", `"table"."select"(int)`},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			identifiers := schema.NewSet(schema.HashString, []interface{}{c.identifier})
+			if result := setToPgIdentListNotQuoted(identifiers, c.prefix); result != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, result)
+			}
+		})
+	}
+}
+
+func TestChunkIdentifiers(t *testing.T) {
+	identifiers := schema.NewSet(schema.HashString, []interface{}{"e", "c", "a", "d", "b"})
+
+	chunks := chunkIdentifiers(identifiers, 2)
+	expected := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+	if len(chunks) != len(expected) {
+		t.Fatalf("expected %d chunks, got %d", len(expected), len(chunks))
+	}
+	for i, chunk := range chunks {
+		if !chunk.Equal(schema.NewSet(schema.HashString, stringsToInterfaces(expected[i]))) {
+			t.Errorf("expected chunk %d to be %v, got %v", i, expected[i], chunk.List())
+		}
+	}
+
+	if chunks := chunkIdentifiers(schema.NewSet(schema.HashString, nil), 2); len(chunks) != 0 {
+		t.Errorf("expected no chunks, got %d", len(chunks))
+	}
+}
+
+func TestEscapeLikePattern(t *testing.T) {
+	cases := map[string]string{
+		"analysts":   "analysts",
+		"data_team":  `data\_team`,
+		"100%":       `100\%`,
+		`back\slash`: `back\\slash`,
+		"_%_":        `\_\%\_`,
+	}
+
+	for value, expected := range cases {
+		if actual := escapeLikePattern(value); actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := fingerprint([]string{"group", "tbl=group analysts=r/owner"})
+	if a != fingerprint([]string{"group", "tbl=group analysts=r/owner"}) {
+		t.Errorf("expected fingerprints of equal parts to be equal")
+	}
+	if a == fingerprint([]string{"group", "tbl=group analysts=rw/owner"}) {
+		t.Errorf("expected fingerprints of different parts to differ")
+	}
+	if fingerprint([]string{"ab"}) == fingerprint([]string{"a", "b"}) {
+		t.Errorf("expected fingerprints to depend on the parts boundaries")
+	}
+}
+
+func TestSuggestedIdentifier(t *testing.T) {
+	cases := map[string]struct {
+		name            string
+		requiresQuoting bool
+		suggested       string
+	}{
+		"lower case":    {"my_schema", false, "my_schema"},
+		"dollar":        {"tmp$1", false, "tmp$1"},
+		"mixed case":    {"MySchema", true, "myschema"},
+		"email":         {"john.doe@example.com", true, "john_doe_example_com"},
+		"leading digit": {"1st_schema", true, "_1st_schema"},
+		"spaces":        {"my  table", true, "my_table"},
+		"reserved word": {"user", true, "user_"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := identifierRequiresQuoting(c.name); actual != c.requiresQuoting {
+				t.Errorf("expected quoting to be required: %t, got %t", c.requiresQuoting, actual)
+			}
+			if actual := suggestedIdentifier(c.name); actual != c.suggested {
+				t.Errorf("expected %q, got %q", c.suggested, actual)
+			}
+		})
+	}
+}
+
+func TestRedshiftResourceWithStatementTimeout(t *testing.T) {
+	config := Config{StatementTimeout: 60}
+	db := &DBConnection{nil, config.NewClient("db")}
+
+	var observed *Client
+	fn := RedshiftResourceWithStatementTimeout(func(db *DBConnection, d *schema.ResourceData) error {
+		observed = db.client
+		return nil
+	})
+
+	d := schema.TestResourceDataRaw(t, redshiftSchema().Schema, map[string]interface{}{"name": "schema"})
+	if err := fn(db, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if observed.config.StatementTimeout != 60 {
+		t.Errorf("expected the provider timeout without override, got %d", observed.config.StatementTimeout)
+	}
+
+	d = schema.TestResourceDataRaw(t, redshiftSchema().Schema, map[string]interface{}{"name": "schema", resourceStatementTimeoutAttr: 3600})
+	if err := fn(db, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if observed.config.StatementTimeout != 3600 || observed.databaseName != "db" {
+		t.Errorf("expected the resource timeout in database db, got %d in %s", observed.config.StatementTimeout, observed.databaseName)
+	}
+	if db.client.config.StatementTimeout != 60 {
+		t.Errorf("expected the provider timeout to be left unchanged, got %d", db.client.config.StatementTimeout)
+	}
+}
+
+func TestRedshiftResourceRetryOnPQErrors(t *testing.T) {
+	config := Config{RetryAttempts: 3, RetryBackoff: time.Millisecond}
+	db := &DBConnection{nil, config.NewClient("db")}
+	d := schema.TestResourceDataRaw(t, redshiftSchema().Schema, map[string]interface{}{"name": "schema"})
+
+	concurrent := &pq.Error{Code: pqErrorCodeConcurrent}
+	cases := map[string]struct {
+		errs             []error
+		expectedAttempts int
+		expectedErr      bool
+	}{
+		"success":                {nil, 1, false},
+		"concurrent update":      {[]error{&pq.Error{Code: pqErrorCodeConcurrent}}, 2, false},
+		"lost connection":        {[]error{driver.ErrBadConn}, 1, true},
+		"attempts exhausted":     {[]error{concurrent, concurrent, concurrent, concurrent}, 3, true},
+		"not retryable":          {[]error{&pq.Error{Code: "42601"}}, 1, true},
+		"wrapped is not retried": {[]error{fmt.Errorf("wrapped: %w", &pq.Error{Code: pqErrorCodeConcurrent})}, 1, true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			attempts := 0
+			fn := RedshiftResourceRetryOnPQErrors(func(db *DBConnection, d *schema.ResourceData) error {
+				attempts++
+				if attempts <= len(c.errs) {
+					return c.errs[attempts-1]
+				}
+				return nil
+			})
+
+			err := fn(db, d)
+			if (err != nil) != c.expectedErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if attempts != c.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", c.expectedAttempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRedshiftResourceFuncReadOnly(t *testing.T) {
+	client := (&Config{ReadOnly: true}).NewClient("db")
+	fn := RedshiftResourceFunc(func(db *DBConnection, d *schema.ResourceData) error {
+		t.Fatal("expected the operation not to run with a read only provider")
+		return nil
+	})
+
+	// Without a host, connecting would fail, so the error has to be returned before connecting.
+	d := schema.TestResourceDataRaw(t, redshiftGroup().Schema, map[string]interface{}{groupNameAttr: "analysts"})
+	if err := fn(d, client); err == nil || !strings.Contains(err.Error(), "can't be created") {
+		t.Errorf("expected the creation to fail, got %v", err)
+	}
+
+	d.SetId("123")
+	if err := fn(d, client); err == nil || !strings.Contains(err.Error(), `resource "123" can't be updated or deleted`) {
+		t.Errorf("expected the update to fail, got %v", err)
+	}
+}
+
+func TestResourceGrantees(t *testing.T) {
+	cases := map[string]struct {
+		raw           map[string]interface{}
+		expectedKind  catalog.Kind
+		expectedNames []string
+	}{
+		"user": {
+			raw:           map[string]interface{}{grantUserAttr: "john", grantObjectTypeAttr: "schema"},
+			expectedKind:  catalog.User,
+			expectedNames: []string{"john"},
+		},
+		"group": {
+			raw:           map[string]interface{}{grantGroupAttr: "analysts", grantObjectTypeAttr: "schema"},
+			expectedKind:  catalog.Group,
+			expectedNames: []string{"analysts"},
+		},
+		"role": {
+			raw:           map[string]interface{}{grantRoleAttr: "etl", grantObjectTypeAttr: "schema"},
+			expectedKind:  catalog.Role,
+			expectedNames: []string{"etl"},
+		},
+		"users": {
+			raw:           map[string]interface{}{grantUsersAttr: []interface{}{"john", "jane"}, grantObjectTypeAttr: "schema"},
+			expectedKind:  catalog.User,
+			expectedNames: []string{"jane", "john"},
+		},
+		"public": {
+			raw: map[string]interface{}{grantGroupAttr: "PUBLIC", grantObjectTypeAttr: "schema"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, redshiftGrant().Schema, c.raw)
+			kind, names := resourceGrantees(d, grantGranteeAttrs...)
+			if kind != c.expectedKind || !reflect.DeepEqual(names, c.expectedNames) {
+				t.Errorf("expected (%q, %v), got (%q, %v)", c.expectedKind, c.expectedNames, kind, names)
+			}
+		})
+	}
+}
+
+func TestRedshiftResourceWaitForGranteeDisabled(t *testing.T) {
+	called := false
+	fn := RedshiftResourceWaitForGrantee(func(db *DBConnection, d *schema.ResourceData) error {
+		called = true
+		return nil
+	}, grantUserAttr, grantGroupAttr, grantRoleAttr)
+
+	// Without wait_for_grantee the grantee is not looked up, so no connection is needed.
+	d := schema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{grantUserAttr: "john", grantObjectTypeAttr: "schema"})
+	if err := fn(&DBConnection{nil, nil}, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Errorf("expected the wrapped function to be called")
+	}
+}
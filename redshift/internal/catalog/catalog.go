@@ -0,0 +1,111 @@
+// Package catalog resolves names of Redshift catalog objects (schemas, users, groups, roles) to their IDs.
+package catalog
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Kind is a kind of catalog object.
+type Kind string
+
+const (
+	Schema Kind = "schema"
+	User   Kind = "user"
+	Group  Kind = "group"
+	Role   Kind = "role"
+)
+
+var idQueries = map[Kind]string{
+	Schema: "SELECT oid FROM pg_namespace WHERE nspname = $1",
+	User:   "SELECT usesysid FROM pg_user WHERE usename = $1",
+	Group:  "SELECT grosysid FROM pg_group WHERE groname = $1",
+	Role:   "SELECT role_id FROM svv_roles WHERE role_name = $1",
+}
+
+// Querier is implemented by *sql.DB and *sql.Tx.
+type Querier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Resolver resolves names to IDs and caches the results.
+// As the cache reflects what the transaction sees, a Resolver should not outlive the transaction it queries.
+// Objects which are renamed or dropped within the transaction have to be forgotten by the caller.
+type Resolver struct {
+	lookup func(query, name string) (int, error)
+	ids    map[Kind]map[string]int
+}
+
+// NewResolver returns a Resolver querying q.
+func NewResolver(q Querier) *Resolver {
+	return newResolver(func(query, name string) (id int, err error) {
+		err = q.QueryRow(query, name).Scan(&id)
+		return
+	})
+}
+
+func newResolver(lookup func(query, name string) (int, error)) *Resolver {
+	return &Resolver{
+		lookup: lookup,
+		ids:    map[Kind]map[string]int{},
+	}
+}
+
+// ID returns the ID of the object of the given kind.
+// sql.ErrNoRows is returned when the object does not exist. Missing objects are not cached.
+func (r *Resolver) ID(kind Kind, name string) (int, error) {
+	query, ok := idQueries[kind]
+	if !ok {
+		return 0, fmt.Errorf("unsupported catalog object kind %q", kind)
+	}
+
+	if id, ok := r.ids[kind][name]; ok {
+		return id, nil
+	}
+
+	id, err := r.lookup(query, name)
+	if err != nil {
+		return 0, err
+	}
+
+	if r.ids[kind] == nil {
+		r.ids[kind] = map[string]int{}
+	}
+	r.ids[kind][name] = id
+	return id, nil
+}
+
+// SchemaID returns the OID of the schema.
+func (r *Resolver) SchemaID(name string) (int, error) {
+	return r.ID(Schema, name)
+}
+
+// UserID returns the usesysid of the user.
+func (r *Resolver) UserID(name string) (int, error) {
+	return r.ID(User, name)
+}
+
+// GroupID returns the grosysid of the group.
+func (r *Resolver) GroupID(name string) (int, error) {
+	return r.ID(Group, name)
+}
+
+// RoleID returns the role_id of the role.
+func (r *Resolver) RoleID(name string) (int, error) {
+	return r.ID(Role, name)
+}
+
+// Forget removes the object from the cache, e.g. after it has been dropped.
+func (r *Resolver) Forget(kind Kind, name string) {
+	delete(r.ids[kind], name)
+}
+
+// Rename updates the cache after the object has been renamed.
+func (r *Resolver) Rename(kind Kind, oldName, newName string) {
+	id, ok := r.ids[kind][oldName]
+	r.Forget(kind, oldName)
+	r.Forget(kind, newName)
+	if ok {
+		r.ids[kind][newName] = id
+	}
+}
@@ -0,0 +1,103 @@
+package catalog
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func fakeResolver(ids map[string]int, lookups *int) *Resolver {
+	return newResolver(func(query, name string) (int, error) {
+		*lookups++
+		id, ok := ids[name]
+		if !ok {
+			return 0, sql.ErrNoRows
+		}
+		return id, nil
+	})
+}
+
+func TestResolverCachesIDs(t *testing.T) {
+	lookups := 0
+	r := fakeResolver(map[string]int{"alice": 100}, &lookups)
+
+	for i := 0; i < 2; i++ {
+		id, err := r.UserID("alice")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != 100 {
+			t.Errorf("expected ID 100, got %d", id)
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("expected a single lookup, got %d", lookups)
+	}
+
+	// Kinds have separate namespaces.
+	if _, err := r.GroupID("alice"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lookups != 2 {
+		t.Errorf("expected a lookup per kind, got %d", lookups)
+	}
+}
+
+func TestResolverDoesNotCacheMissingObjects(t *testing.T) {
+	lookups := 0
+	ids := map[string]int{}
+	r := fakeResolver(ids, &lookups)
+
+	if _, err := r.SchemaID("analytics"); err != sql.ErrNoRows {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+
+	ids["analytics"] = 200
+	id, err := r.SchemaID("analytics")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != 200 {
+		t.Errorf("expected ID 200, got %d", id)
+	}
+}
+
+func TestResolverRename(t *testing.T) {
+	lookups := 0
+	ids := map[string]int{"old": 100, "new": 300}
+	r := fakeResolver(ids, &lookups)
+
+	if _, err := r.RoleID("old"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.RoleID("new"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// "new" was dropped and "old" renamed to it.
+	delete(ids, "old")
+	ids["new"] = 100
+	r.Rename(Role, "old", "new")
+
+	id, err := r.RoleID("new")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != 100 {
+		t.Errorf("expected renamed role to keep ID 100, got %d", id)
+	}
+	if _, err := r.RoleID("old"); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows for the old name, got %v", err)
+	}
+}
+
+func TestResolverUnsupportedKind(t *testing.T) {
+	lookups := 0
+	r := fakeResolver(map[string]int{}, &lookups)
+
+	if _, err := r.ID(Kind("table"), "t"); err == nil {
+		t.Error("expected an error for an unsupported kind")
+	}
+	if lookups != 0 {
+		t.Errorf("expected no lookups, got %d", lookups)
+	}
+}
@@ -0,0 +1,24 @@
+// Command schemametadata writes the manifest of the resources and data sources of the provider,
+// with the SQL verbs the resources may run, for policy tooling such as OPA or Sentinel.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/brainly/terraform-provider-redshift/redshift"
+)
+
+func main() {
+	output := flag.String("output", "schema_metadata.json", "path of the generated manifest")
+	flag.Parse()
+
+	metadata, err := redshift.GenerateSchemaMetadata()
+	if err != nil {
+		log.Fatalf("could not generate the schema metadata: %v", err)
+	}
+	if err := os.WriteFile(*output, metadata, 0644); err != nil {
+		log.Fatalf("could not write %s: %v", *output, err)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"log"
 	"net"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/lib/pq"
@@ -13,21 +16,72 @@ import (
 
 const proxyDriverName = "postgresql-proxy"
 
-type proxyDriver struct{}
+type proxyDriver struct {
+	// dialHost, when set, replaces the host of every dialed address.
+	// It allows to keep the host name expected by TLS verification in the connection string
+	// while connecting to a different endpoint.
+	dialHost string
+}
 
 func (d proxyDriver) Open(name string) (driver.Conn, error) {
-	return pq.DialOpen(d, name)
+	conn, err := pq.DialOpen(d, name)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn}, nil
 }
 
 func (d proxyDriver) Dial(network, address string) (net.Conn, error) {
 	dialer := proxy.FromEnvironment()
-	return dialer.Dial(network, address)
+	return dialer.Dial(network, d.address(address))
 }
 
 func (d proxyDriver) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
 	ctx, cancel := context.WithTimeout(context.TODO(), timeout)
 	defer cancel()
-	return proxy.Dial(ctx, network, address)
+	return proxy.Dial(ctx, network, d.address(address))
+}
+
+func (d proxyDriver) address(address string) string {
+	if d.dialHost == "" {
+		return address
+	}
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return net.JoinHostPort(strings.Trim(d.dialHost, "[]"), port)
+}
+
+// proxyConnector opens connections to one of the hosts, which are dialed in turn
+// until a connection succeeds. The host that connected last is tried first next time,
+// so that after a failover new connections don't have to wait for the unavailable host.
+type proxyConnector struct {
+	dsn   string
+	hosts []string
+
+	// active is the index of the host that connected last.
+	active int32
+}
+
+func (c *proxyConnector) Connect(context.Context) (driver.Conn, error) {
+	var err error
+	active := int(atomic.LoadInt32(&c.active))
+	for i := range c.hosts {
+		index := (active + i) % len(c.hosts)
+		var conn driver.Conn
+		conn, err = proxyDriver{dialHost: c.hosts[index]}.Open(c.dsn)
+		if err == nil {
+			atomic.StoreInt32(&c.active, int32(index))
+			return conn, nil
+		}
+		log.Printf("[WARN] could not connect to %s: %v", c.hosts[index], err)
+	}
+	return nil, err
+}
+
+func (c *proxyConnector) Driver() driver.Driver {
+	return proxyDriver{}
 }
 
 func init() {
@@ -0,0 +1,40 @@
+package redshift
+
+import "testing"
+
+func TestProxyDriverAddress(t *testing.T) {
+	cases := map[string]struct {
+		dialHost string
+		address  string
+		expected string
+	}{
+		"no override": {
+			address:  "cluster.example.com:5439",
+			expected: "cluster.example.com:5439",
+		},
+		"override": {
+			dialHost: "nlb.example.com",
+			address:  "cluster.example.com:5439",
+			expected: "nlb.example.com:5439",
+		},
+		"override with IPv6": {
+			dialHost: "::1",
+			address:  "cluster.example.com:5439",
+			expected: "[::1]:5439",
+		},
+		"override with bracketed IPv6": {
+			dialHost: "[::1]",
+			address:  "cluster.example.com:5439",
+			expected: "[::1]:5439",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := proxyDriver{dialHost: c.dialHost}.address(c.address)
+			if actual != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	commentObjectTypeAttr = "object_type"
+	commentObjectNameAttr = "object_name"
+	commentCommentAttr    = "comment"
+)
+
+// commentObjectNameParts is the number of dot separated parts of the object_name of each object type.
+var commentObjectNameParts = map[string]int{
+	"database": 1,
+	"schema":   1,
+	"table":    2,
+	"view":     2,
+	"column":   3,
+}
+
+// commentReadQueries read the comment of each object type from pg_description, with the parts of the object name as arguments.
+// They return no rows when the object doesn't exist, and an empty comment when the object has no comment.
+var commentReadQueries = map[string]string{
+	"database": `
+	SELECT COALESCE(ds.description, '')
+	FROM pg_database d
+	LEFT JOIN pg_description ds ON ds.objoid = d.oid AND ds.objsubid = 0
+	WHERE d.datname = $1`,
+	"schema": `
+	SELECT COALESCE(ds.description, '')
+	FROM pg_namespace n
+	LEFT JOIN pg_description ds ON ds.objoid = n.oid AND ds.objsubid = 0
+	WHERE n.nspname = $1`,
+	"table": `
+	SELECT COALESCE(ds.description, '')
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_description ds ON ds.objoid = c.oid AND ds.objsubid = 0
+	WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind = 'r'`,
+	"view": `
+	SELECT COALESCE(ds.description, '')
+	FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_description ds ON ds.objoid = c.oid AND ds.objsubid = 0
+	WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind = 'v'`,
+	"column": `
+	SELECT COALESCE(ds.description, '')
+	FROM pg_attribute a
+	JOIN pg_class c ON c.oid = a.attrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_description ds ON ds.objoid = a.attrelid AND ds.objsubid = a.attnum
+	WHERE n.nspname = $1 AND c.relname = $2 AND a.attname = $3 AND NOT a.attisdropped`,
+}
+
+func redshiftComment() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Manages the comment of a database, schema, table, view or column with ` + "`COMMENT ON`" + `, e.g. to document objects which are not managed by Terraform. The comment is removed when the resource is destroyed.
+
+Do not manage the comment of a table or of its columns managed by ` + "`redshift_table`" + ` with this resource as well, as they would override each other.
+`,
+		Create: RedshiftResourceFunc(resourceRedshiftCommentCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftCommentRead),
+		Update: RedshiftResourceFunc(resourceRedshiftCommentUpdate),
+		Delete: RedshiftResourceFunc(resourceRedshiftCommentDelete),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			commentObjectTypeAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"database", "schema", "table", "view", "column"}, false),
+				Description:  "The type of the object, one of `database`, `schema`, `table`, `view` or `column`.",
+			},
+			commentObjectNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "The name of the object, qualified by its schema for tables and views (`schema.table`) and by its schema and table for columns (`schema.table.column`). The names of the object and its parents can't contain dots.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			commentCommentAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The comment of the object.",
+			},
+		},
+	}
+}
+
+func resourceRedshiftCommentCreate(db *DBConnection, d *schema.ResourceData) error {
+	objectType := d.Get(commentObjectTypeAttr).(string)
+	objectName := d.Get(commentObjectNameAttr).(string)
+	if _, err := commentObjectNameSplit(objectType, objectName); err != nil {
+		return err
+	}
+
+	if err := setObjectComment(db, objectType, objectName, d.Get(commentCommentAttr).(string)); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", objectType, strings.ToLower(objectName)))
+
+	return resourceRedshiftCommentRead(db, d)
+}
+
+func resourceRedshiftCommentRead(db *DBConnection, d *schema.ResourceData) error {
+	objectType, objectName, err := parseCommentID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	comment, err := readObjectComment(db, objectType, objectName)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift %s %s of the comment not found", objectType, objectName)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading the comment of %s %s: %w", objectType, objectName, err)
+	}
+
+	d.Set(commentObjectTypeAttr, objectType)
+	d.Set(commentObjectNameAttr, objectName)
+	d.Set(commentCommentAttr, comment)
+
+	return nil
+}
+
+func resourceRedshiftCommentUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := setObjectComment(db, d.Get(commentObjectTypeAttr).(string), d.Get(commentObjectNameAttr).(string), d.Get(commentCommentAttr).(string)); err != nil {
+		return err
+	}
+
+	return resourceRedshiftCommentRead(db, d)
+}
+
+func resourceRedshiftCommentDelete(db *DBConnection, d *schema.ResourceData) error {
+	objectType := d.Get(commentObjectTypeAttr).(string)
+	objectName := d.Get(commentObjectNameAttr).(string)
+
+	if _, err := readObjectComment(db, objectType, objectName); err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("[WARN] Redshift %s %s was already dropped", objectType, objectName)
+			return nil
+		}
+		return err
+	}
+
+	return setObjectComment(db, objectType, objectName, "")
+}
+
+// setObjectComment sets the comment of the object, or removes it when the comment is empty.
+func setObjectComment(db *DBConnection, objectType, objectName, comment string) error {
+	parts, err := commentObjectNameSplit(objectType, objectName)
+	if err != nil {
+		return err
+	}
+
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := commentQuery(objectType, parts, comment)
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Error updating the comment of %s %s: %w", objectType, objectName, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+	return nil
+}
+
+// readObjectComment returns the comment of the object, or sql.ErrNoRows when the object doesn't exist.
+func readObjectComment(db *DBConnection, objectType, objectName string) (string, error) {
+	parts, err := commentObjectNameSplit(objectType, objectName)
+	if err != nil {
+		return "", err
+	}
+
+	args := []interface{}{}
+	for _, part := range parts {
+		args = append(args, db.client.normalizeIdentifier(part))
+	}
+
+	var comment string
+	query := commentReadQueries[objectType]
+	log.Printf("[DEBUG] %s, %v\n", query, args)
+	err = db.QueryRow(query, args...).Scan(&comment)
+	return comment, err
+}
+
+func commentQuery(objectType string, parts []string, comment string) string {
+	quoted := []string{}
+	for _, part := range parts {
+		quoted = append(quoted, pq.QuoteIdentifier(part))
+	}
+	return fmt.Sprintf("COMMENT ON %s %s IS %s", strings.ToUpper(objectType), strings.Join(quoted, "."), commentLiteral(comment))
+}
+
+// commentObjectNameSplit splits the object name into its parts, e.g. the schema, table and column names of a column.
+func commentObjectNameSplit(objectType, objectName string) ([]string, error) {
+	parts := strings.Split(objectName, ".")
+	if len(parts) != commentObjectNameParts[objectType] {
+		return nil, fmt.Errorf("the name of a %s must have %d dot separated parts, got %q", objectType, commentObjectNameParts[objectType], objectName)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("the name of a %s can't have empty parts, got %q", objectType, objectName)
+		}
+	}
+	return parts, nil
+}
+
+// parseCommentID returns the object type and name of the comment ID, e.g. "table:public.events".
+func parseCommentID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid comment ID %q, expected <object_type>:<object_name>", id)
+	}
+	if _, ok := commentReadQueries[parts[0]]; !ok {
+		return "", "", fmt.Errorf("invalid comment ID %q, unsupported object type %q", id, parts[0])
+	}
+	return parts[0], parts[1], nil
+}
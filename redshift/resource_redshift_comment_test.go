@@ -0,0 +1,173 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftComment_Schema(t *testing.T) {
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	config := func(comment string) string {
+		return fmt.Sprintf(`
+resource "redshift_schema" "schema" {
+	name = %[1]q
+}
+
+resource "redshift_comment" "comment" {
+	object_type = "schema"
+	object_name = redshift_schema.schema.name
+	comment     = %[2]q
+}
+`, schemaName, comment)
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftCommentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("Raw events, owned by the data team"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_comment.comment", "id", "schema:"+schemaName),
+					resource.TestCheckResourceAttr("redshift_comment.comment", "comment", "Raw events, owned by the data team"),
+				),
+			},
+			{
+				Config: config("Don't query, it's 'deprecated'"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_comment.comment", "comment", "Don't query, it's 'deprecated'"),
+				),
+			},
+			{
+				ResourceName:      "redshift_comment.comment",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftCommentDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.Connect()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_comment" {
+			continue
+		}
+
+		objectType, objectName, err := parseCommentID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		comment, err := readObjectComment(db, objectType, objectName)
+		if err != nil {
+			// The object was dropped with its comment.
+			continue
+		}
+		if comment != "" {
+			return fmt.Errorf("Comment of %s %s still exists after destroy", objectType, objectName)
+		}
+	}
+
+	return nil
+}
+
+func TestCommentQuery(t *testing.T) {
+	cases := map[string]struct {
+		objectType string
+		objectName string
+		comment    string
+		expected   string
+	}{
+		"database": {
+			objectType: "database",
+			objectName: "analytics",
+			comment:    "Analytics",
+			expected:   `COMMENT ON DATABASE "analytics" IS 'Analytics'`,
+		},
+		"table": {
+			objectType: "table",
+			objectName: "public.events",
+			comment:    "It's raw",
+			expected:   `COMMENT ON TABLE "public"."events" IS 'It''s raw'`,
+		},
+		"column": {
+			objectType: "column",
+			objectName: "public.events.created_at",
+			comment:    `C:\time`,
+			expected:   `COMMENT ON COLUMN "public"."events"."created_at" IS 'C:\\time'`,
+		},
+		"removed": {
+			objectType: "view",
+			objectName: "public.events_view",
+			comment:    "",
+			expected:   `COMMENT ON VIEW "public"."events_view" IS NULL`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			parts, err := commentObjectNameSplit(c.objectType, c.objectName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := commentQuery(c.objectType, parts, c.comment); actual != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCommentObjectNameSplit(t *testing.T) {
+	cases := map[string]struct {
+		objectType string
+		objectName string
+		valid      bool
+	}{
+		"schema":               {"schema", "public", true},
+		"qualified schema":     {"schema", "analytics.public", false},
+		"table":                {"table", "public.events", true},
+		"unqualified table":    {"table", "events", false},
+		"column":               {"column", "public.events.id", true},
+		"column without table": {"column", "public.id", false},
+		"empty part":           {"view", "public.", false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := commentObjectNameSplit(c.objectType, c.objectName)
+			if c.valid && err != nil {
+				t.Errorf("expected %q to be valid, got %s", c.objectName, err)
+			}
+			if !c.valid && err == nil {
+				t.Errorf("expected %q to be invalid", c.objectName)
+			}
+		})
+	}
+}
+
+func TestParseCommentID(t *testing.T) {
+	objectType, objectName, err := parseCommentID("column:public.events.id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objectType != "column" || objectName != "public.events.id" {
+		t.Errorf("expected column public.events.id, got %s %s", objectType, objectName)
+	}
+
+	for _, id := range []string{"public.events", "function:public.f"} {
+		if _, _, err := parseCommentID(id); err == nil {
+			t.Errorf("expected %q to be an invalid ID", id)
+		}
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/lib/pq"
@@ -19,33 +21,44 @@ const databaseDatashareSourceAttr = "datashare_source"
 const databaseDatashareSourceShareNameAttr = "share_name"
 const databaseDatashareSourceNamespaceAttr = "namespace"
 const databaseDatashareSourceAccountAttr = "account_id"
+const databaseDataCatalogSourceAttr = "data_catalog_source"
+const databaseDataCatalogSourceARNAttr = "arn"
+const databaseDataCatalogSourceSchemaAttr = "data_catalog_schema"
+const databaseDataCatalogSourceIAMRoleAttr = "iam_role_arn"
+const databaseIsolationLevelAttr = "isolation_level"
+const databaseCollationAttr = "collation"
 
 func redshiftDatabase() *schema.Resource {
 	return &schema.Resource{
 		Description: `Defines a local database.`,
 		Exists:      RedshiftResourceExistsFunc(resourceRedshiftDatabaseExists),
 		Create:      RedshiftResourceFunc(resourceRedshiftDatabaseCreate),
-		Read:        RedshiftResourceFunc(resourceRedshiftDatabaseRead),
+		Read:        RedshiftResourceReadFunc(resourceRedshiftDatabaseRead),
 		Update:      RedshiftResourceFunc(resourceRedshiftDatabaseUpdate),
 		Delete:      RedshiftResourceFunc(resourceRedshiftDatabaseDelete),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
-		CustomizeDiff: forceNewIfListSizeChanged(databaseDatashareSourceAttr),
+		CustomizeDiff: customdiff.All(
+			forceNewIfListSizeChanged(databaseDatashareSourceAttr),
+			forceNewIfListSizeChanged(databaseDataCatalogSourceAttr),
+		),
 		Schema: map[string]*schema.Schema{
 			databaseNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Name of the database",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "Name of the database. Changing the name renames the database in place, also for databases created from a datashare.",
 				StateFunc: func(val interface{}) string {
 					return strings.ToLower(val.(string))
 				},
 			},
 			databaseOwnerAttr: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				Description: "Owner of the database, usually the user who created it",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "Owner of the database, usually the user who created it",
 			},
 			databaseConnLimitAttr: {
 				Type:         schema.TypeInt,
@@ -54,18 +67,22 @@ func redshiftDatabase() *schema.Resource {
 				Default:      -1,
 				ValidateFunc: validation.IntAtLeast(-1),
 			},
+			databaseIsolationLevelAttr: databaseIsolationLevelSchema(),
+			databaseCollationAttr:      databaseCollationSchema(),
 			databaseDatashareSourceAttr: {
-				Type:        schema.TypeList,
-				Optional:    true,
-				MaxItems:    1,
-				Description: "Configuration for creating a database from a redshift datashare.",
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				Description:   "Configuration for creating a database from a redshift datashare.",
+				ConflictsWith: []string{databaseDataCatalogSourceAttr},
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						databaseDatashareSourceShareNameAttr: {
-							Type:        schema.TypeString,
-							Required:    true,
-							ForceNew:    true,
-							Description: "The name of the datashare on the producer cluster",
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validateIdentifier,
+							Description:  "The name of the datashare on the producer cluster",
 							StateFunc: func(val interface{}) string {
 								return strings.ToLower(val.(string))
 							},
@@ -90,6 +107,38 @@ func redshiftDatabase() *schema.Resource {
 					},
 				},
 			},
+			databaseDataCatalogSourceAttr: {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				Description:   "Configuration for creating a database from a database of the AWS Glue Data Catalog, e.g. to query Apache Iceberg tables registered in the catalog. Redshift doesn't expose the source of such databases, so it is kept as configured and can't be imported. See https://docs.aws.amazon.com/redshift/latest/dg/r_CREATE_DATABASE.html",
+				ConflictsWith: []string{databaseDatashareSourceAttr},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						databaseDataCatalogSourceARNAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.StringMatch(regexp.MustCompile(`^arn:aws[\w-]*:glue:`), "must be the ARN of an AWS Glue Data Catalog database"),
+							Description:  "The ARN of the AWS Glue Data Catalog database, e.g. `arn:aws:glue:us-east-1:123456789012:database/lakehouse`.",
+						},
+						databaseDataCatalogSourceSchemaAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							ValidateFunc: validateIdentifier,
+							Description:  "The name of the schema of the database the Data Catalog tables are exposed in. Defaults to the name of the Data Catalog database.",
+						},
+						databaseDataCatalogSourceIAMRoleAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+							Description:  "The IAM role used to access the Data Catalog: `default` for the default IAM role of the cluster, `SESSION` for the identity of the connected user, or the ARN of an IAM role. Defaults to the default IAM role of the cluster.",
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -114,6 +163,9 @@ func resourceRedshiftDatabaseCreate(db *DBConnection, d *schema.ResourceData) er
 	if _, isDataShare := d.GetOk(fmt.Sprintf("%s.0.%s", databaseDatashareSourceAttr, databaseDatashareSourceShareNameAttr)); isDataShare {
 		return resourceRedshiftDatabaseCreateFromDatashare(db, d)
 	}
+	if _, isDataCatalog := d.GetOk(fmt.Sprintf("%s.0.%s", databaseDataCatalogSourceAttr, databaseDataCatalogSourceARNAttr)); isDataCatalog {
+		return resourceRedshiftDatabaseCreateFromDataCatalog(db, d)
+	}
 	return resourceRedshiftDatabaseCreateInternal(db, d)
 }
 
@@ -131,9 +183,40 @@ func resourceRedshiftDatabaseCreateFromDatashare(db *DBConnection, d *schema.Res
 		return err
 	}
 
+	return setCreatedDatabaseOptions(db, d)
+}
+
+func resourceRedshiftDatabaseCreateFromDataCatalog(db *DBConnection, d *schema.ResourceData) error {
+	dbName := d.Get(databaseNameAttr).(string)
+	arn := d.Get(fmt.Sprintf("%s.0.%s", databaseDataCatalogSourceAttr, databaseDataCatalogSourceARNAttr)).(string)
+	query := fmt.Sprintf("CREATE DATABASE %s FROM ARN '%s'", pq.QuoteIdentifier(dbName), pqQuoteLiteral(arn))
+	if schemaName, ok := d.GetOk(fmt.Sprintf("%s.0.%s", databaseDataCatalogSourceAttr, databaseDataCatalogSourceSchemaAttr)); ok {
+		query = fmt.Sprintf("%s WITH DATA CATALOG SCHEMA '%s'", query, pqQuoteLiteral(schemaName.(string)))
+	}
+	if iamRole, ok := d.GetOk(fmt.Sprintf("%s.0.%s", databaseDataCatalogSourceAttr, databaseDataCatalogSourceIAMRoleAttr)); ok {
+		if strings.EqualFold(iamRole.(string), "default") {
+			query = fmt.Sprintf("%s IAM_ROLE default", query)
+		} else {
+			query = fmt.Sprintf("%s IAM_ROLE '%s'", query, pqQuoteLiteral(iamRole.(string)))
+		}
+	}
+
+	log.Printf("[DEBUG] create database %s: %s\n", dbName, query)
+	if _, err := db.Exec(query); err != nil {
+		return err
+	}
+
+	return setCreatedDatabaseOptions(db, d)
+}
+
+// setCreatedDatabaseOptions sets the ID, the owner and the connection limit of a database created from a datashare
+// or from a data catalog, as CREATE DATABASE ... FROM doesn't allow to specify them.
+func setCreatedDatabaseOptions(db *DBConnection, d *schema.ResourceData) error {
+	dbName := d.Get(databaseNameAttr).(string)
+
 	// eagerly get the resource ID in case the below statements fail for some reason
 	var oid string
-	query = "SELECT oid FROM pg_database WHERE datname = $1"
+	query := "SELECT oid FROM pg_database WHERE datname = $1"
 	log.Printf("[DEBUG] get oid from database: %s\n", query)
 	if err := db.QueryRow(query, strings.ToLower(dbName)).Scan(&oid); err != nil {
 		return err
@@ -148,8 +231,6 @@ func resourceRedshiftDatabaseCreateFromDatashare(db *DBConnection, d *schema.Res
 	}
 	defer deferredRollback(tx)
 
-	// CREATE DATABASE FROM DATASHARE... doesn't allow you to specify an owner in the create statement,
-	// so we need to set the owner after creation using ALTER DATABASE...
 	owner, ownerIsSet := d.GetOk(databaseOwnerAttr)
 	if ownerIsSet {
 		if _, err = tx.Exec(fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", pq.QuoteIdentifier(dbName), pq.QuoteIdentifier(owner.(string)))); err != nil {
@@ -157,8 +238,6 @@ func resourceRedshiftDatabaseCreateFromDatashare(db *DBConnection, d *schema.Res
 		}
 	}
 
-	// CREATE DATABASE FROM DATASHARE... doesn't allow you to specify the connection limit in the create statement,
-	// so we need to set the owner after creation using ALTER DATABASE...
 	connLimit, connLimitIsSet := d.GetOk(databaseConnLimitAttr)
 	if connLimitIsSet {
 		if _, err = tx.Exec(fmt.Sprintf("ALTER DATABASE %s CONNECTION LIMIT %d", pq.QuoteIdentifier(dbName), connLimit.(int))); err != nil {
@@ -200,13 +279,14 @@ func resourceRedshiftDatabaseCreateInternal(db *DBConnection, d *schema.Resource
 }
 
 func resourceRedshiftDatabaseRead(db *DBConnection, d *schema.ResourceData) error {
-	var name, owner, connLimit, databaseType, shareName, producerAccount, producerNamespace string
+	var name, owner, connLimit, databaseType, isolationLevel, shareName, producerAccount, producerNamespace string
 
 	query := `SELECT
   trim(svv_redshift_databases.database_name),
   trim(pg_user_info.usename),
   COALESCE(pg_database_info.datconnlimit::text, 'UNLIMITED'),
 	svv_redshift_databases.database_type,
+  trim(COALESCE(svv_redshift_databases.database_isolation_level, '')),
   trim(COALESCE(svv_datashares.share_name, '')),
   trim(COALESCE(svv_datashares.producer_account, '')),
   trim(COALESCE(svv_datashares.producer_namespace, ''))
@@ -221,12 +301,14 @@ LEFT JOIN svv_datashares
 WHERE pg_database_info.datid = $1
 `
 	log.Printf("[DEBUG] read database: %s\n", query)
-	err := db.QueryRow(query, d.Id()).Scan(&name, &owner, &connLimit, &databaseType, &shareName, &producerAccount, &producerNamespace)
+	err := db.QueryRow(query, d.Id()).Scan(&name, &owner, &connLimit, &databaseType, &isolationLevel, &shareName, &producerAccount, &producerNamespace)
 
 	if err != nil {
 		return err
 	}
 
+	collation := readDatabaseCollation(db, name, databaseType)
+
 	connLimitNumber := -1
 	if connLimit != "UNLIMITED" {
 		if connLimitNumber, err = strconv.Atoi(connLimit); err != nil {
@@ -237,9 +319,13 @@ WHERE pg_database_info.datid = $1
 	d.Set(databaseNameAttr, name)
 	d.Set(databaseOwnerAttr, owner)
 	d.Set(databaseConnLimitAttr, connLimitNumber)
+	d.Set(databaseIsolationLevelAttr, isolationLevel)
+	d.Set(databaseCollationAttr, collation)
 
 	dataShareConfiguration := make([]map[string]interface{}, 0, 1)
-	if databaseType == "shared" {
+	// A shared database whose datashare was revoked or dropped by the producer has no inbound datashare anymore,
+	// which is reported as a change of the datashare source.
+	if databaseType == "shared" && shareName != "" {
 		config := make(map[string]interface{})
 		config[databaseDatashareSourceShareNameAttr] = &shareName
 		config[databaseDatashareSourceAccountAttr] = &producerAccount
@@ -247,10 +333,51 @@ WHERE pg_database_info.datid = $1
 		dataShareConfiguration = append(dataShareConfiguration, config)
 	}
 	d.Set(databaseDatashareSourceAttr, dataShareConfiguration)
+	// The data catalog source isn't exposed by Redshift, so it is kept from the state.
 
 	return nil
 }
 
+func databaseIsolationLevelSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The isolation level of the database, either `Snapshot Isolation` or `Serializable`.",
+	}
+}
+
+func databaseCollationSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The collation of the database, either `case_sensitive` or `case_insensitive`. Empty for databases created from a datashare or a data catalog, as they can't be connected to, and when the collation can't be read, e.g. without the privilege to connect to the database.",
+	}
+}
+
+// readDatabaseCollation reads the collation of the database, which is only available when connected to it.
+// The collation is informational, so failing to read it, e.g. when the user may not connect to the database,
+// leaves it empty instead of failing the read.
+func readDatabaseCollation(db *DBConnection, name, databaseType string) string {
+	if databaseType != "local" {
+		return ""
+	}
+
+	if name != db.client.databaseName {
+		var err error
+		if db, err = db.client.config.NewClient(name).Connect(); err != nil {
+			log.Printf("[WARN] could not connect to database %s to read its collation: %v", name, err)
+			return ""
+		}
+	}
+
+	var collation string
+	if err := db.QueryRow("SELECT db_collation()").Scan(&collation); err != nil {
+		log.Printf("[WARN] could not read the collation of database %s: %v", name, err)
+		return ""
+	}
+	return strings.ToLower(collation)
+}
+
 func resourceRedshiftDatabaseUpdate(db *DBConnection, d *schema.ResourceData) error {
 	tx, err := startTransaction(db.client, "")
 	if err != nil {
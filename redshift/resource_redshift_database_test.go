@@ -25,6 +25,8 @@ func TestAccResourceRedshiftDatabase_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("redshift_database.db", databaseNameAttr, dbName),
 					resource.TestCheckResourceAttrSet("redshift_database.db", databaseOwnerAttr),
 					resource.TestCheckResourceAttrSet("redshift_database.db", databaseConnLimitAttr),
+					resource.TestCheckResourceAttrSet("redshift_database.db", databaseIsolationLevelAttr),
+					resource.TestCheckResourceAttr("redshift_database.db", databaseCollationAttr, "case_sensitive"),
 				),
 			},
 			{
@@ -95,6 +97,102 @@ resource "redshift_user" "user" {
 	})
 }
 
+func TestAccResourceRedshiftDatabase_DatashareRename(t *testing.T) {
+	producerNamespace := getEnvOrSkip("REDSHIFT_DATASHARE_PRODUCER_NAMESPACE", t)
+	shareName := getEnvOrSkip("REDSHIFT_DATASHARE_PRODUCER_SHARE_NAME", t)
+	dbNameOriginal := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_datashare_original"), "-", "_")
+	dbNameNew := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_datashare_renamed"), "-", "_")
+
+	config := func(dbName string) string {
+		return fmt.Sprintf(`
+resource "redshift_database" "db" {
+	%[1]s = %[2]q
+	%[3]s {
+		%[4]s = %[5]q
+		%[6]s = %[7]q
+	}
+}
+`, databaseNameAttr, dbName, databaseDatashareSourceAttr, databaseDatashareSourceShareNameAttr, shareName, databaseDatashareSourceNamespaceAttr, producerNamespace)
+	}
+
+	var originalID string
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(dbNameOriginal),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabaseExists(dbNameOriginal),
+					resource.TestCheckResourceAttr("redshift_database.db", fmt.Sprintf("%s.0.%s", databaseDatashareSourceAttr, databaseDatashareSourceShareNameAttr), shareName),
+					func(s *terraform.State) error {
+						originalID = s.RootModule().Resources["redshift_database.db"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config: config(dbNameNew),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabaseExists(dbNameNew),
+					resource.TestCheckResourceAttr("redshift_database.db", databaseNameAttr, dbNameNew),
+					resource.TestCheckResourceAttr("redshift_database.db", fmt.Sprintf("%s.0.%s", databaseDatashareSourceAttr, databaseDatashareSourceShareNameAttr), shareName),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["redshift_database.db"].Primary.ID; id != originalID {
+							return fmt.Errorf("Expected database to be renamed in place, but it was recreated (ID %s -> %s)", originalID, id)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// Acceptance test for a database created from an AWS Glue Data Catalog database
+// The following environment variables must be set, otherwise the test will be skipped:
+//
+//	REDSHIFT_DATA_CATALOG_DATABASE_ARN - ARN of the Data Catalog database
+//	REDSHIFT_DATA_CATALOG_IAM_ROLE_ARN - ARN of the IAM role allowed to access the Data Catalog
+func TestAccResourceRedshiftDatabase_DataCatalog(t *testing.T) {
+	arn := getEnvOrSkip("REDSHIFT_DATA_CATALOG_DATABASE_ARN", t)
+	iamRoleArn := getEnvOrSkip("REDSHIFT_DATA_CATALOG_IAM_ROLE_ARN", t)
+	dbName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_data_catalog"), "-", "_")
+
+	config := fmt.Sprintf(`
+resource "redshift_database" "db" {
+	%[1]s = %[2]q
+	%[3]s {
+		%[4]s = %[5]q
+		%[6]s = %[7]q
+	}
+}
+`, databaseNameAttr, dbName, databaseDataCatalogSourceAttr, databaseDataCatalogSourceARNAttr, arn, databaseDataCatalogSourceIAMRoleAttr, iamRoleArn)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabaseExists(dbName),
+					resource.TestCheckResourceAttr("redshift_database.db", databaseNameAttr, dbName),
+					resource.TestCheckResourceAttr("redshift_database.db", fmt.Sprintf("%s.0.%s", databaseDataCatalogSourceAttr, databaseDataCatalogSourceARNAttr), arn),
+					resource.TestCheckResourceAttr("redshift_database.db", fmt.Sprintf("%s.#", databaseDatashareSourceAttr), "0"),
+					resource.TestCheckResourceAttr("redshift_database.db", databaseCollationAttr, ""),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 func testAccResourceRedshiftDatabaseConfig_basic(dbName string) string {
 	return fmt.Sprintf(`
 resource "redshift_database" "db" {
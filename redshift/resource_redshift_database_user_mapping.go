@@ -0,0 +1,199 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/brainly/terraform-provider-redshift/redshift/internal/catalog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	databaseUserMappingUserAttr       = "user"
+	databaseUserMappingSearchPathAttr = "search_path"
+
+	searchPathConfigPrefix = "search_path="
+)
+
+func redshiftDatabaseUserMapping() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Manages the default database context of a user, which is applied to every new session of the user. Currently this is the schema search path, which may list local as well as external schemas, so that objects can be referenced without qualifying them with the schema name.
+
+Note: Redshift stores these settings per user, so they apply to sessions in all databases of the cluster. Objects in other databases still have to be referenced using the three-part ` + "`database.schema.object`" + ` notation.
+`,
+		Exists: RedshiftResourceExistsFunc(resourceRedshiftDatabaseUserMappingExists),
+		Create: RedshiftResourceFunc(resourceRedshiftDatabaseUserMappingCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftDatabaseUserMappingRead),
+		Update: RedshiftResourceFunc(resourceRedshiftDatabaseUserMappingUpdate),
+		Delete: RedshiftResourceFunc(resourceRedshiftDatabaseUserMappingDelete),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			databaseUserMappingUserAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the user the settings are applied to.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			databaseUserMappingSearchPathAttr: {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The schemas to look up unqualified object names in, in order. Use `$user` to refer to the schema with the same name as the user.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotWhiteSpace,
+				},
+			},
+		},
+	}
+}
+
+func resourceRedshiftDatabaseUserMappingExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	var name string
+	err := db.QueryRow("SELECT usename FROM pg_user_info WHERE usesysid = $1", d.Id()).Scan(&name)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
+
+func resourceRedshiftDatabaseUserMappingCreate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	userName := d.Get(databaseUserMappingUserAttr).(string)
+	userID, err := catalog.NewResolver(tx).UserID(strings.ToLower(userName))
+	if err != nil {
+		return fmt.Errorf("failed to get user ID for user '%s': %w", userName, err)
+	}
+
+	if err := setUserSearchPath(tx, d); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(strconv.Itoa(userID))
+
+	return resourceRedshiftDatabaseUserMappingRead(db, d)
+}
+
+func resourceRedshiftDatabaseUserMappingRead(db *DBConnection, d *schema.ResourceData) error {
+	var userName string
+	var userConfig sql.NullString
+
+	query := "SELECT usename, array_to_string(useconfig, '|') FROM pg_user WHERE usesysid = $1"
+	log.Printf("[DEBUG] %s, $1=%s\n", query, d.Id())
+	err := db.QueryRow(query, d.Id()).Scan(&userName, &userConfig)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift User (%s) not found", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading User: %w", err)
+	}
+
+	d.Set(databaseUserMappingUserAttr, userName)
+	d.Set(databaseUserMappingSearchPathAttr, parseUserSearchPath(userConfig.String))
+
+	return nil
+}
+
+func resourceRedshiftDatabaseUserMappingUpdate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if d.HasChange(databaseUserMappingSearchPathAttr) {
+		if err := setUserSearchPath(tx, d); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourceRedshiftDatabaseUserMappingRead(db, d)
+}
+
+func resourceRedshiftDatabaseUserMappingDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	var userName string
+	if err := tx.QueryRow("SELECT usename FROM pg_user WHERE usesysid = $1", d.Id()).Scan(&userName); err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("[WARN] Redshift User (%s) not found", d.Id())
+			return nil
+		}
+		return err
+	}
+
+	query := fmt.Sprintf("ALTER USER %s RESET search_path", pq.QuoteIdentifier(userName))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setUserSearchPath(tx *sql.Tx, d *schema.ResourceData) error {
+	schemas := []string{}
+	for _, s := range d.Get(databaseUserMappingSearchPathAttr).([]interface{}) {
+		schemas = append(schemas, pq.QuoteIdentifier(s.(string)))
+	}
+
+	userName := strings.ToLower(d.Get(databaseUserMappingUserAttr).(string))
+	query := fmt.Sprintf("ALTER USER %s SET search_path TO %s", pq.QuoteIdentifier(userName), strings.Join(schemas, ", "))
+	log.Printf("[DEBUG] %s\n", query)
+	_, err := tx.Exec(query)
+	return err
+}
+
+// parseUserSearchPath extracts the search path from the user config,
+// stored as `|` separated "name=value" entries, e.g. `search_path="$user", public`.
+func parseUserSearchPath(userConfig string) []string {
+	schemas := []string{}
+	for _, entry := range strings.Split(userConfig, "|") {
+		if !strings.HasPrefix(entry, searchPathConfigPrefix) {
+			continue
+		}
+		for _, s := range strings.Split(strings.TrimPrefix(entry, searchPathConfigPrefix), ",") {
+			s = strings.Trim(strings.TrimSpace(s), `"`)
+			if s != "" {
+				schemas = append(schemas, s)
+			}
+		}
+	}
+	return schemas
+}
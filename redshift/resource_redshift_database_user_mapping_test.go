@@ -0,0 +1,92 @@
+package redshift
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftDatabaseUserMapping_Basic(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	config := func(searchPath string) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "user" {
+	name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+	name = %[2]q
+}
+
+resource "redshift_database_user_mapping" "mapping" {
+	user        = redshift_user.user.name
+	search_path = %[3]s
+}
+`, userName, schemaName, searchPath)
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config(`[redshift_schema.schema.name, "public"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_database_user_mapping.mapping", "user", userName),
+					resource.TestCheckResourceAttr("redshift_database_user_mapping.mapping", "search_path.#", "2"),
+					resource.TestCheckResourceAttr("redshift_database_user_mapping.mapping", "search_path.0", schemaName),
+					resource.TestCheckResourceAttr("redshift_database_user_mapping.mapping", "search_path.1", "public"),
+				),
+			},
+			{
+				Config: config(`["$user", "public"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_database_user_mapping.mapping", "search_path.#", "2"),
+					resource.TestCheckResourceAttr("redshift_database_user_mapping.mapping", "search_path.0", "$user"),
+					resource.TestCheckResourceAttr("redshift_database_user_mapping.mapping", "search_path.1", "public"),
+				),
+			},
+			{
+				ResourceName:      "redshift_database_user_mapping.mapping",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestParseUserSearchPath(t *testing.T) {
+	cases := map[string]struct {
+		config   string
+		expected []string
+	}{
+		"empty": {
+			config:   "",
+			expected: []string{},
+		},
+		"search path only": {
+			config:   `search_path="$user", public`,
+			expected: []string{"$user", "public"},
+		},
+		"other settings": {
+			config:   `datestyle=ISO, MDY|search_path=analytics, spectrum|statement_timeout=1000`,
+			expected: []string{"analytics", "spectrum"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := parseUserSearchPath(c.config)
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
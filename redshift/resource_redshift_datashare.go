@@ -4,20 +4,25 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/lib/pq"
 )
 
 const (
-	dataShareNameAttr              = "name"
-	dataShareOwnerAttr             = "owner"
-	dataSharePublicAccessibleAttr  = "publicly_accessible"
-	dataShareProducerAccountAttr   = "producer_account"
-	dataShareProducerNamespaceAttr = "producer_namespace"
-	dataShareCreatedAttr           = "created"
-	dataShareSchemasAttr           = "schemas"
+	dataShareNameAttr               = "name"
+	dataShareOwnerAttr              = "owner"
+	dataSharePublicAccessibleAttr   = "publicly_accessible"
+	dataShareProducerAccountAttr    = "producer_account"
+	dataShareProducerNamespaceAttr  = "producer_namespace"
+	dataShareCreatedAttr            = "created"
+	dataShareSchemasAttr            = "schemas"
+	dataShareFunctionsAttr          = "functions"
+	dataShareConsumerNamespacesAttr = "consumer_namespaces"
 )
 
 func redshiftDatashare() *schema.Resource {
@@ -28,13 +33,15 @@ read data stored in another Redshift cluster (the "producer"). For more informat
 https://docs.aws.amazon.com/redshift/latest/dg/datashare-overview.html
 
 The redshift_datashare resource should be defined on the producer cluster.
+Consumers are granted usage of the datashare with the redshift_datashare_privilege resource,
+or with consumer_namespaces for consumer clusters in the same account.
 
 Note: Data sharing is only supported on certain Redshift instance families,
 such as RA3.
 `,
 		Exists: RedshiftResourceExistsFunc(resourceRedshiftDatashareExists),
 		Create: RedshiftResourceFunc(resourceRedshiftDatashareCreate),
-		Read:   RedshiftResourceFunc(resourceRedshiftDatashareRead),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftDatashareRead),
 		Update: RedshiftResourceFunc(resourceRedshiftDatashareUpdate),
 		Delete: RedshiftResourceFunc(resourceRedshiftDatashareDelete),
 		Importer: &schema.ResourceImporter{
@@ -42,19 +49,21 @@ such as RA3.
 		},
 		Schema: map[string]*schema.Schema{
 			dataShareNameAttr: {
-				Type:        schema.TypeString,
-				Description: "The name of the datashare.",
-				Required:    true,
-				ForceNew:    true,
+				Type:         schema.TypeString,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the datashare.",
+				Required:     true,
+				ForceNew:     true,
 				StateFunc: func(val interface{}) string {
 					return strings.ToLower(val.(string))
 				},
 			},
 			dataShareOwnerAttr: {
-				Type:        schema.TypeString,
-				Description: "The user who owns the datashare.",
-				Optional:    true,
-				Computed:    true,
+				Type:         schema.TypeString,
+				ValidateFunc: validateIdentifier,
+				Description:  "The user who owns the datashare.",
+				Optional:     true,
+				Computed:     true,
 				StateFunc: func(val interface{}) string {
 					return strings.ToLower(val.(string))
 				},
@@ -83,13 +92,40 @@ such as RA3.
 			dataShareSchemasAttr: {
 				Type:        schema.TypeSet,
 				Optional:    true,
-				Description: "Defines which schemas are exposed to the data share.",
-				Set:         schema.HashString,
+				Description: "Defines which schemas are exposed to the data share. The schemas have to exist, and unless the provider connects as a superuser, the user needs the `USAGE` privilege on them and `SELECT` on their tables. This is checked before the datashare is changed.",
+				Set:         hashCaseInsensitiveString,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIdentifier,
+					StateFunc: func(val interface{}) string {
+						return strings.ToLower(val.(string))
+					},
+				},
+			},
+			dataShareFunctionsAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Defines which functions are exposed to the data share, in the form of `schema.function(argument types)`. By default all functions of the `schemas` are exposed. When functions of a schema are listed here, only those are exposed, and tables and functions created later in the schema are no longer added to the data share automatically. The schema of each function has to be listed in `schemas` as well.",
+				Set:         hashCaseInsensitiveString,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 					StateFunc: func(val interface{}) string {
 						return strings.ToLower(val.(string))
 					},
+					ValidateFunc: validation.StringMatch(datashareFunctionPattern, "must be in the form of schema.function(argument types), with only letters, digits, underscores, spaces and commas in the argument types"),
+				},
+			},
+			dataShareConsumerNamespacesAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The namespaces (guids) of the consumer clusters in the same account granted usage of the datashare. Consumers not listed here, e.g. granted with `redshift_datashare_privilege`, are left as they are.",
+				Set:         hashCaseInsensitiveString,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(uuidRegex, "Consumer namespace must be a guid"),
+					StateFunc: func(val interface{}) string {
+						return strings.ToLower(val.(string))
+					},
 				},
 			},
 		},
@@ -119,7 +155,7 @@ func resourceRedshiftDatashareCreate(db *DBConnection, d *schema.ResourceData) e
 	}
 	defer deferredRollback(tx)
 
-	shareName := d.Get(dataShareNameAttr).(string)
+	shareName := strings.ToLower(d.Get(dataShareNameAttr).(string))
 
 	query := fmt.Sprintf("CREATE DATASHARE %s SET PUBLICACCESSIBLE = %t", pq.QuoteIdentifier(shareName), d.Get(dataSharePublicAccessibleAttr).(bool))
 	log.Printf("[DEBUG] %s\n", query)
@@ -129,15 +165,15 @@ func resourceRedshiftDatashareCreate(db *DBConnection, d *schema.ResourceData) e
 
 	var shareId string
 	query = "SELECT share_id FROM SVV_DATASHARES WHERE share_type = 'OUTBOUND' AND share_name = $1"
-	log.Printf("[DEBUG] %s, $1=%s\n", query, strings.ToLower(shareName))
-	if err := tx.QueryRow(query, strings.ToLower(shareName)).Scan(&shareId); err != nil {
+	log.Printf("[DEBUG] %s, $1=%s\n", query, shareName)
+	if err := tx.QueryRow(query, shareName).Scan(&shareId); err != nil {
 		return err
 	}
 
 	d.SetId(shareId)
 
 	if owner, ownerIsSet := d.GetOk(dataShareOwnerAttr); ownerIsSet {
-		query = fmt.Sprintf("ALTER DATASHARE %s OWNER TO %s", pq.QuoteIdentifier(strings.ToLower(shareName)), pq.QuoteIdentifier(strings.ToLower(owner.(string))))
+		query = fmt.Sprintf("ALTER DATASHARE %s OWNER TO %s", pq.QuoteIdentifier(shareName), pq.QuoteIdentifier(strings.ToLower(owner.(string))))
 		log.Printf("[DEBUG] %s\n", query)
 		_, err = tx.Exec(query)
 		if err != nil {
@@ -145,11 +181,32 @@ func resourceRedshiftDatashareCreate(db *DBConnection, d *schema.ResourceData) e
 		}
 	}
 
-	for _, schema := range d.Get(dataShareSchemasAttr).(*schema.Set).List() {
-		err = addSchemaToDatashare(tx, shareName, schema.(string))
+	if err := validateDatashareFunctions(d); err != nil {
+		return err
+	}
+
+	if err := validateDatashareSchemasAccess(tx, shareName, d.Get(dataShareSchemasAttr).(*schema.Set)); err != nil {
+		return err
+	}
+
+	functionsBySchema := datashareFunctionsBySchema(d.Get(dataShareFunctionsAttr).(*schema.Set))
+	for _, s := range d.Get(dataShareSchemasAttr).(*schema.Set).List() {
+		schemaName := strings.ToLower(s.(string))
+		err = addSchemaToDatashare(tx, shareName, schemaName, len(functionsBySchema[schemaName]) == 0)
 		if err != nil {
 			return err
 		}
+		for _, function := range functionsBySchema[schemaName] {
+			if err := addFunctionToDatashare(tx, shareName, function); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, namespace := range d.Get(dataShareConsumerNamespacesAttr).(*schema.Set).List() {
+		if err := grantDatashareToNamespace(tx, shareName, namespace.(string)); err != nil {
+			return err
+		}
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -159,20 +216,78 @@ func resourceRedshiftDatashareCreate(db *DBConnection, d *schema.ResourceData) e
 	return resourceRedshiftDatashareRead(db, d)
 }
 
-func addSchemaToDatashare(tx *sql.Tx, shareName string, schemaName string) error {
-	err := resourceRedshiftDatashareAddSchema(tx, shareName, schemaName)
+// validateDatashareSchemasAccess checks that the schemas can be added to the datashare by the current user,
+// which otherwise fails with a generic error after some of the statements were run.
+// The schemas have to exist and, unless the user is a superuser, adding a schema with all its tables
+// requires the USAGE privilege on the schema and SELECT on its tables.
+func validateDatashareSchemasAccess(tx *sql.Tx, shareName string, schemas *schema.Set) error {
+	if schemas.Len() == 0 {
+		return nil
+	}
+
+	var userName string
+	var superuser bool
+	if err := tx.QueryRow("SELECT usename, usesuper FROM pg_user WHERE usename = current_user").Scan(&userName, &superuser); err != nil {
+		return fmt.Errorf("could not read the privileges of the current user: %w", err)
+	}
+
+	for _, s := range schemas.List() {
+		schemaName := strings.ToLower(s.(string))
+
+		var usage bool
+		err := tx.QueryRow("SELECT has_schema_privilege(nspname, 'USAGE') FROM pg_namespace WHERE nspname = $1", schemaName).Scan(&usage)
+		switch {
+		case err == sql.ErrNoRows:
+			return fmt.Errorf("cannot add schema %s to datashare %s: the schema does not exist", schemaName, shareName)
+		case err != nil:
+			return err
+		case superuser:
+			continue
+		case !usage:
+			return fmt.Errorf("cannot add schema %s to datashare %s: user %s lacks the USAGE privilege on the schema, grant it with GRANT USAGE ON SCHEMA %s TO %s",
+				schemaName, shareName, userName, pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(userName))
+		}
+
+		var tableName string
+		err = tx.QueryRow(`
+	SELECT cl.relname
+	FROM pg_class cl
+	JOIN pg_namespace nsp ON nsp.oid = cl.relnamespace
+	WHERE nsp.nspname = $1 AND cl.relkind = 'r' AND NOT has_table_privilege(cl.oid, 'SELECT')
+	LIMIT 1`, schemaName).Scan(&tableName)
+		switch {
+		case err == sql.ErrNoRows:
+		case err != nil:
+			return err
+		default:
+			return fmt.Errorf("cannot add schema %s to datashare %s: user %s lacks the SELECT privilege on table %s, grant it with GRANT SELECT ON ALL TABLES IN SCHEMA %s TO %s",
+				schemaName, shareName, userName, tableName, pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(userName))
+		}
+	}
+
+	return nil
+}
+
+// addSchemaToDatashare adds the schema with all its tables and, if allFunctions is set, all its functions to the datashare.
+// Objects created later in the schema are only added automatically when allFunctions is set,
+// otherwise functions which are not listed would be shared as well.
+// Redshift folds identifiers to lower case, so both names are normalized before quoting
+// to match the values stored in the state and returned by svv_datashare_objects.
+func addSchemaToDatashare(tx *sql.Tx, shareName string, schemaName string, allFunctions bool) error {
+	shareName, schemaName = strings.ToLower(shareName), strings.ToLower(schemaName)
+	err := resourceRedshiftDatashareAddSchema(tx, shareName, schemaName, allFunctions)
 	if err != nil {
 		return err
 	}
 	err = resourceRedshiftDatashareAddAllTables(tx, shareName, schemaName)
-	if err != nil {
+	if err != nil || !allFunctions {
 		return err
 	}
 	err = resourceRedshiftDatashareAddAllFunctions(tx, shareName, schemaName)
 	return err
 }
 
-func resourceRedshiftDatashareAddSchema(tx *sql.Tx, shareName string, schemaName string) error {
+func resourceRedshiftDatashareAddSchema(tx *sql.Tx, shareName string, schemaName string, includeNew bool) error {
 	query := fmt.Sprintf("ALTER DATASHARE %s ADD SCHEMA %s", pq.QuoteIdentifier(shareName), pq.QuoteIdentifier(schemaName))
 	log.Printf("[DEBUG] %s\n", query)
 	_, err := tx.Exec(query)
@@ -188,9 +303,14 @@ func resourceRedshiftDatashareAddSchema(tx *sql.Tx, shareName string, schemaName
 			return err
 		}
 	}
-	query = fmt.Sprintf("ALTER DATASHARE %s SET INCLUDENEW = TRUE FOR SCHEMA %s", pq.QuoteIdentifier(shareName), pq.QuoteIdentifier(schemaName))
+	return setDatashareSchemaIncludeNew(tx, shareName, schemaName, includeNew)
+}
+
+// setDatashareSchemaIncludeNew sets whether the tables and functions created later in the schema are added to the datashare.
+func setDatashareSchemaIncludeNew(tx *sql.Tx, shareName string, schemaName string, includeNew bool) error {
+	query := fmt.Sprintf("ALTER DATASHARE %s SET INCLUDENEW = %s FOR SCHEMA %s", pq.QuoteIdentifier(shareName), strings.ToUpper(strconv.FormatBool(includeNew)), pq.QuoteIdentifier(schemaName))
 	log.Printf("[DEBUG] %s\n", query)
-	_, err = tx.Exec(query)
+	_, err := tx.Exec(query)
 	return err
 }
 
@@ -201,6 +321,43 @@ func resourceRedshiftDatashareAddAllFunctions(tx *sql.Tx, shareName string, sche
 	return err
 }
 
+// datashareFunctionPattern matches schema.function(argument types). The argument types are put in the statements as they are,
+// so they are limited to the characters of type names.
+var datashareFunctionPattern = regexp.MustCompile(`^([^.(]+)\.([^.(]+)\(([A-Za-z0-9_ ,]*)\)$`)
+
+// datashareFunctionSQL returns the function, given as schema.function(argument types), the way it is put in ALTER DATASHARE statements.
+// As for grants, the schema and function names are only quoted when needed and the argument types are not quoted.
+func datashareFunctionSQL(function string) (string, error) {
+	parts := datashareFunctionPattern.FindStringSubmatch(strings.ToLower(function))
+	if parts == nil {
+		return "", fmt.Errorf("function %s must be in the form of schema.function(argument types)", function)
+	}
+	return fmt.Sprintf("%s.%s(%s)", quoteIdentifierIfNeeded(parts[1]), quoteIdentifierIfNeeded(parts[2]), parts[3]), nil
+}
+
+// addFunctionToDatashare adds a single function, given as schema.function(argument types), to the datashare.
+func addFunctionToDatashare(tx *sql.Tx, shareName string, function string) error {
+	functionSQL, err := datashareFunctionSQL(function)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("ALTER DATASHARE %s ADD FUNCTION %s", pq.QuoteIdentifier(strings.ToLower(shareName)), functionSQL)
+	log.Printf("[DEBUG] %s\n", query)
+	_, err = tx.Exec(query)
+	return err
+}
+
+func removeFunctionFromDatashare(tx *sql.Tx, shareName string, function string) error {
+	functionSQL, err := datashareFunctionSQL(function)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("ALTER DATASHARE %s REMOVE FUNCTION %s", pq.QuoteIdentifier(strings.ToLower(shareName)), functionSQL)
+	log.Printf("[DEBUG] %s\n", query)
+	_, err = tx.Exec(query)
+	return err
+}
+
 func resourceRedshiftDatashareAddAllTables(tx *sql.Tx, shareName string, schemaName string) error {
 	query := fmt.Sprintf("ALTER DATASHARE %s ADD ALL TABLES IN SCHEMA %s", pq.QuoteIdentifier(shareName), pq.QuoteIdentifier(schemaName))
 	log.Printf("[DEBUG] %s\n", query)
@@ -208,7 +365,10 @@ func resourceRedshiftDatashareAddAllTables(tx *sql.Tx, shareName string, schemaN
 	return err
 }
 
+// removeSchemaFromDatashare removes the schema with all its tables and functions from the datashare.
+// Names are normalized the same way as in addSchemaToDatashare.
 func removeSchemaFromDatashare(tx *sql.Tx, shareName string, schemaName string) error {
+	shareName, schemaName = strings.ToLower(shareName), strings.ToLower(schemaName)
 	err := resourceRedshiftDatashareRemoveAllFunctions(tx, shareName, schemaName)
 	if err != nil {
 		return err
@@ -293,6 +453,14 @@ func resourceRedshiftDatashareRead(db *DBConnection, d *schema.ResourceData) err
 		return err
 	}
 
+	if err = readDatashareFunctions(tx, shareName, d); err != nil {
+		return err
+	}
+
+	if err = readDatashareConsumerNamespaces(tx, shareName, d); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return err
 	}
@@ -316,7 +484,7 @@ func readDatashareSchemas(tx *sql.Tx, shareName string, d *schema.ResourceData)
 	}
 	defer rows.Close()
 
-	schemas := schema.NewSet(schema.HashString, nil)
+	schemas := schema.NewSet(hashCaseInsensitiveString, nil)
 	for rows.Next() {
 		var schemaName string
 		if err = rows.Scan(&schemaName); err != nil {
@@ -328,6 +496,105 @@ func readDatashareSchemas(tx *sql.Tx, shareName string, d *schema.ResourceData)
 	return nil
 }
 
+// readDatashareFunctions reads the functions of the schemas whose functions are listed individually.
+func readDatashareFunctions(tx *sql.Tx, shareName string, d *schema.ResourceData) error {
+	configured := d.Get(dataShareFunctionsAttr).(*schema.Set)
+	functionsBySchema := datashareFunctionsBySchema(configured)
+	functions := schema.NewSet(hashCaseInsensitiveString, nil)
+	if len(functionsBySchema) == 0 {
+		d.Set(dataShareFunctionsAttr, functions)
+		return nil
+	}
+
+	query := `
+	SELECT
+		object_name
+	FROM svv_datashare_objects
+	WHERE share_type = 'OUTBOUND'
+	AND object_type = 'function'
+	AND share_name = $1
+`
+	log.Printf("[DEBUG] %s, $1=%s\n", query, shareName)
+	rows, err := tx.Query(query, shareName)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// Shared functions of the selective schemas.
+	shared := []string{}
+	for rows.Next() {
+		var objectName string
+		if err = rows.Scan(&objectName); err != nil {
+			return err
+		}
+		objectName = strings.ToLower(objectName)
+		schemaName, _ := splitDatashareFunction(objectName)
+		if _, ok := functionsBySchema[schemaName]; !ok {
+			continue
+		}
+		shared = append(shared, objectName)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set(dataShareFunctionsAttr, matchDatashareFunctions(configured, shared))
+	return nil
+}
+
+// matchDatashareFunctions returns the shared functions, keeping them as configured when their signatures match,
+// see callableSignature, as svv_datashare_objects does not report the argument types the way they were configured.
+func matchDatashareFunctions(configured *schema.Set, shared []string) *schema.Set {
+	sharedBySignature := map[string]string{}
+	for _, objectName := range shared {
+		sharedBySignature[callableSignature(objectName)] = objectName
+	}
+
+	functions := schema.NewSet(hashCaseInsensitiveString, nil)
+	for _, f := range configured.List() {
+		signature := callableSignature(strings.ToLower(f.(string)))
+		if _, ok := sharedBySignature[signature]; ok {
+			functions.Add(f.(string))
+			delete(sharedBySignature, signature)
+		}
+	}
+	for _, objectName := range sharedBySignature {
+		functions.Add(objectName)
+	}
+	return functions
+}
+
+// splitDatashareFunction splits schema.function(argument types) into the schema name and the rest.
+func splitDatashareFunction(function string) (string, string) {
+	parts := strings.SplitN(function, ".", 2)
+	if len(parts) < 2 {
+		return "", function
+	}
+	return parts[0], parts[1]
+}
+
+// datashareFunctionsBySchema groups the lower cased functions by their schema.
+func datashareFunctionsBySchema(functions *schema.Set) map[string][]string {
+	bySchema := map[string][]string{}
+	for _, f := range functions.List() {
+		function := strings.ToLower(f.(string))
+		schemaName, _ := splitDatashareFunction(function)
+		bySchema[schemaName] = append(bySchema[schemaName], function)
+	}
+	return bySchema
+}
+
+func validateDatashareFunctions(d *schema.ResourceData) error {
+	schemas := d.Get(dataShareSchemasAttr).(*schema.Set)
+	for schemaName, functions := range datashareFunctionsBySchema(d.Get(dataShareFunctionsAttr).(*schema.Set)) {
+		if !schemas.Contains(schemaName) {
+			return fmt.Errorf("schema of function %s has to be listed in `%s`", functions[0], dataShareSchemasAttr)
+		}
+	}
+	return nil
+}
+
 func resourceRedshiftDatashareUpdate(db *DBConnection, d *schema.ResourceData) error {
 	tx, err := startTransaction(db.client, "")
 	if err != nil {
@@ -347,6 +614,10 @@ func resourceRedshiftDatashareUpdate(db *DBConnection, d *schema.ResourceData) e
 		return err
 	}
 
+	if err := setDatashareConsumerNamespaces(tx, d); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
 	}
@@ -358,13 +629,13 @@ func setDatashareOwner(tx *sql.Tx, d *schema.ResourceData) error {
 	if !d.HasChange(dataShareOwnerAttr) {
 		return nil
 	}
-	shareName := d.Get(dataShareNameAttr).(string)
+	shareName := strings.ToLower(d.Get(dataShareNameAttr).(string))
 	_, newRaw := d.GetChange(dataShareOwnerAttr)
 	newValue := newRaw.(string)
 	if newValue == "" {
 		newValue = "CURRENT_USER"
 	} else {
-		newValue = pq.QuoteIdentifier(newValue)
+		newValue = pq.QuoteIdentifier(strings.ToLower(newValue))
 	}
 
 	query := fmt.Sprintf("ALTER DATASHARE %s OWNER TO %s", pq.QuoteIdentifier(shareName), newValue)
@@ -380,7 +651,7 @@ func setDatasharePubliclyAccessble(tx *sql.Tx, d *schema.ResourceData) error {
 		return nil
 	}
 
-	shareName := d.Get(dataShareNameAttr).(string)
+	shareName := strings.ToLower(d.Get(dataShareNameAttr).(string))
 	newValue := d.Get(dataSharePublicAccessibleAttr).(bool)
 	query := fmt.Sprintf("ALTER DATASHARE %s SET PUBLICACCESSIBLE %t", pq.QuoteIdentifier(shareName), newValue)
 	log.Printf("[DEBUG] %s\n", query)
@@ -390,33 +661,170 @@ func setDatasharePubliclyAccessble(tx *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
+// setDatashareConsumerNamespaces grants usage of the datashare to the added consumer namespaces and revokes it from the removed ones.
+func setDatashareConsumerNamespaces(tx *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(dataShareConsumerNamespacesAttr) {
+		return nil
+	}
+	shareName := strings.ToLower(d.Get(dataShareNameAttr).(string))
+	oldRaw, newRaw := d.GetChange(dataShareConsumerNamespacesAttr)
+	oldSet, newSet := oldRaw.(*schema.Set), newRaw.(*schema.Set)
+
+	for _, namespace := range oldSet.Difference(newSet).List() {
+		query := fmt.Sprintf("REVOKE USAGE ON DATASHARE %s FROM NAMESPACE '%s'", pq.QuoteIdentifier(shareName), pqQuoteLiteral(strings.ToLower(namespace.(string))))
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("Error revoking usage of datashare %s from namespace %s: %w", shareName, namespace, err)
+		}
+	}
+
+	for _, namespace := range newSet.Difference(oldSet).List() {
+		if err := grantDatashareToNamespace(tx, shareName, namespace.(string)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func grantDatashareToNamespace(tx *sql.Tx, shareName string, namespace string) error {
+	query := fmt.Sprintf("GRANT USAGE ON DATASHARE %s TO NAMESPACE '%s'", pq.QuoteIdentifier(shareName), pqQuoteLiteral(strings.ToLower(namespace)))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Error granting usage of datashare %s to namespace %s: %w", shareName, namespace, err)
+	}
+	return nil
+}
+
+// readDatashareConsumerNamespaces reads which of the configured consumer namespaces are granted usage of the datashare,
+// ignoring the consumers granted outside of the resource.
+func readDatashareConsumerNamespaces(tx *sql.Tx, shareName string, d *schema.ResourceData) error {
+	configured := d.Get(dataShareConsumerNamespacesAttr).(*schema.Set)
+	if configured.Len() == 0 {
+		return nil
+	}
+
+	query := "SELECT lower(trim(consumer_namespace)) FROM svv_datashare_consumers WHERE share_name = $1 AND consumer_namespace IS NOT NULL"
+	log.Printf("[DEBUG] %s, $1=%s\n", query, shareName)
+	rows, err := tx.Query(query, shareName)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	granted := schema.NewSet(hashCaseInsensitiveString, nil)
+	for rows.Next() {
+		var namespace string
+		if err := rows.Scan(&namespace); err != nil {
+			return err
+		}
+		granted.Add(namespace)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set(dataShareConsumerNamespacesAttr, configured.Intersection(granted))
+	return nil
+}
+
 func setDatashareSchemas(tx *sql.Tx, d *schema.ResourceData) error {
-	if !d.HasChange(dataShareSchemasAttr) {
+	if !d.HasChanges(dataShareSchemasAttr, dataShareFunctionsAttr) {
 		return nil
 	}
+	if err := validateDatashareFunctions(d); err != nil {
+		return err
+	}
 	before, after := d.GetChange(dataShareSchemasAttr)
 	if before == nil {
-		before = schema.NewSet(schema.HashString, nil)
+		before = schema.NewSet(hashCaseInsensitiveString, nil)
 	}
 	if after == nil {
-		after = schema.NewSet(schema.HashString, nil)
+		after = schema.NewSet(hashCaseInsensitiveString, nil)
 	}
 
 	add := after.(*schema.Set).Difference(before.(*schema.Set))
 	remove := before.(*schema.Set).Difference(after.(*schema.Set))
 
+	beforeFunctions, afterFunctions := d.GetChange(dataShareFunctionsAttr)
+	beforeBySchema := datashareFunctionsBySchema(beforeFunctions.(*schema.Set))
+	afterBySchema := datashareFunctionsBySchema(afterFunctions.(*schema.Set))
+
 	shareName := d.Get(dataShareNameAttr).(string)
+	if err := validateDatashareSchemasAccess(tx, shareName, add); err != nil {
+		return err
+	}
 	for _, s := range add.List() {
-		if err := addSchemaToDatashare(tx, shareName, s.(string)); err != nil {
+		schemaName := strings.ToLower(s.(string))
+		if err := addSchemaToDatashare(tx, shareName, schemaName, len(afterBySchema[schemaName]) == 0); err != nil {
 			return err
 		}
+		for _, function := range afterBySchema[schemaName] {
+			if err := addFunctionToDatashare(tx, shareName, function); err != nil {
+				return err
+			}
+		}
 	}
 	for _, s := range remove.List() {
 		if err := removeSchemaFromDatashare(tx, shareName, s.(string)); err != nil {
 			return err
 		}
 	}
+	for _, s := range after.(*schema.Set).Intersection(before.(*schema.Set)).List() {
+		schemaName := strings.ToLower(s.(string))
+		if err := setDatashareSchemaFunctions(tx, shareName, schemaName, beforeBySchema[schemaName], afterBySchema[schemaName]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setDatashareSchemaFunctions updates the functions of a schema which stays in the datashare,
+// switching between sharing all functions (no functions listed) and sharing the listed ones.
+// Objects created later in the schema are only added automatically when all functions are shared.
+func setDatashareSchemaFunctions(tx *sql.Tx, shareName, schemaName string, before, after []string) error {
+	switch {
+	case len(before) == 0 && len(after) == 0:
+		return nil
+	case len(before) == 0:
+		if err := setDatashareSchemaIncludeNew(tx, strings.ToLower(shareName), schemaName, false); err != nil {
+			return err
+		}
+		if err := resourceRedshiftDatashareRemoveAllFunctions(tx, strings.ToLower(shareName), schemaName); err != nil {
+			return err
+		}
+	case len(after) == 0:
+		for _, function := range before {
+			if err := removeFunctionFromDatashare(tx, shareName, function); err != nil {
+				return err
+			}
+		}
+		if err := resourceRedshiftDatashareAddAllFunctions(tx, strings.ToLower(shareName), schemaName); err != nil {
+			return err
+		}
+		return setDatashareSchemaIncludeNew(tx, strings.ToLower(shareName), schemaName, true)
+	}
 
+	beforeSet := map[string]bool{}
+	for _, function := range before {
+		beforeSet[function] = true
+	}
+	afterSet := map[string]bool{}
+	for _, function := range after {
+		afterSet[function] = true
+		if !beforeSet[function] {
+			if err := addFunctionToDatashare(tx, shareName, function); err != nil {
+				return err
+			}
+		}
+	}
+	for _, function := range before {
+		if !afterSet[function] {
+			if err := removeFunctionFromDatashare(tx, shareName, function); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,259 @@
+package redshift
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	datashareConsumerAccessDatabaseAttr = "database"
+	datashareConsumerAccessSchemasAttr  = "schemas"
+	datashareConsumerAccessUserAttr     = "user"
+	datashareConsumerAccessGroupAttr    = "group"
+	datashareConsumerAccessRoleAttr     = "role"
+)
+
+var datashareConsumerAccessGranteeAttrs = []string{
+	datashareConsumerAccessUserAttr,
+	datashareConsumerAccessGroupAttr,
+	datashareConsumerAccessRoleAttr,
+}
+
+func redshiftDatashareConsumerAccess() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Gives a user, group or role access to a database created from a datashare on the consumer side: ` + "`USAGE`" + ` on the datashare database and ` + "`USAGE`" + ` on the schemas through which it is queried, e.g. external schemas referencing the datashare database. The privileges are granted and revoked in a single transaction.
+
+Note: Do not manage the same privileges in ` + "`redshift_grant`" + ` resources as well, as they would override each other.
+`,
+		Create: RedshiftResourceFunc(resourceRedshiftDatashareConsumerAccessCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftDatashareConsumerAccessRead),
+		Update: RedshiftResourceFunc(resourceRedshiftDatashareConsumerAccessUpdate),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftDatashareConsumerAccessDelete),
+		),
+		Schema: map[string]*schema.Schema{
+			datashareConsumerAccessDatabaseAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the database created from the datashare.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			datashareConsumerAccessSchemasAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateIdentifier},
+				Set:         schema.HashString,
+				Description: "The schemas in the database the provider connects to, to grant `USAGE` on, e.g. external schemas referencing the datashare database.",
+			},
+			datashareConsumerAccessUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: datashareConsumerAccessGranteeAttrs,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the user to give access to. Exactly one of `user`, `group` or `role` must be set.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			datashareConsumerAccessGroupAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: datashareConsumerAccessGranteeAttrs,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the group to give access to. Exactly one of `user`, `group` or `role` must be set.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			datashareConsumerAccessRoleAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: datashareConsumerAccessGranteeAttrs,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the role to give access to. Exactly one of `user`, `group` or `role` must be set.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+		},
+	}
+}
+
+func resourceRedshiftDatashareConsumerAccessCreate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	grantee := datashareConsumerAccessGrantee(d)
+	databaseName := strings.ToLower(d.Get(datashareConsumerAccessDatabaseAttr).(string))
+
+	queries := []string{fmt.Sprintf("GRANT USAGE ON DATABASE %s TO %s", pq.QuoteIdentifier(databaseName), grantee)}
+	for _, schemaName := range d.Get(datashareConsumerAccessSchemasAttr).(*schema.Set).List() {
+		queries = append(queries, fmt.Sprintf("GRANT USAGE ON SCHEMA %s TO %s", pq.QuoteIdentifier(strings.ToLower(schemaName.(string))), grantee))
+	}
+	for _, query := range queries {
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("could not give access to datashare database %s: %w", databaseName, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateDatashareConsumerAccessID(d))
+
+	return resourceRedshiftDatashareConsumerAccessRead(db, d)
+}
+
+func resourceRedshiftDatashareConsumerAccessRead(db *DBConnection, d *schema.ResourceData) error {
+	identityType, identityName := datashareConsumerAccessIdentity(d)
+	databaseName := strings.ToLower(d.Get(datashareConsumerAccessDatabaseAttr).(string))
+
+	var databaseUsage int
+	err := db.QueryRow(`
+	SELECT COUNT(*)
+	FROM svv_database_privileges
+	WHERE database_name = $1 AND identity_type = $2 AND identity_name = $3 AND privilege_type = 'USAGE'
+`, databaseName, identityType, identityName).Scan(&databaseUsage)
+	if err != nil {
+		return fmt.Errorf("Error reading datashare consumer access: %w", err)
+	}
+	if databaseUsage == 0 {
+		log.Printf("[WARN] %s %s has no usage on database %s", identityType, identityName, databaseName)
+		d.SetId("")
+		return nil
+	}
+
+	rows, err := db.Query(`
+	SELECT namespace_name
+	FROM svv_schema_privileges
+	WHERE identity_type = $1 AND identity_name = $2 AND privilege_type = 'USAGE'
+`, identityType, identityName)
+	if err != nil {
+		return fmt.Errorf("Error reading datashare consumer access: %w", err)
+	}
+	defer rows.Close()
+
+	granted := map[string]bool{}
+	for rows.Next() {
+		var schemaName string
+		if err := rows.Scan(&schemaName); err != nil {
+			return err
+		}
+		granted[schemaName] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Only the configured schemas are read back, as the grantee may have usage on other schemas as well.
+	schemas := schema.NewSet(schema.HashString, nil)
+	for _, schemaName := range d.Get(datashareConsumerAccessSchemasAttr).(*schema.Set).List() {
+		if granted[strings.ToLower(schemaName.(string))] {
+			schemas.Add(schemaName)
+		}
+	}
+	d.Set(datashareConsumerAccessSchemasAttr, schemas)
+
+	return nil
+}
+
+func resourceRedshiftDatashareConsumerAccessUpdate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	grantee := datashareConsumerAccessGrantee(d)
+	oldRaw, newRaw := d.GetChange(datashareConsumerAccessSchemasAttr)
+	oldSchemas, newSchemas := oldRaw.(*schema.Set), newRaw.(*schema.Set)
+
+	queries := []string{}
+	for _, schemaName := range oldSchemas.Difference(newSchemas).List() {
+		queries = append(queries, fmt.Sprintf("REVOKE USAGE ON SCHEMA %s FROM %s", pq.QuoteIdentifier(strings.ToLower(schemaName.(string))), grantee))
+	}
+	for _, schemaName := range newSchemas.Difference(oldSchemas).List() {
+		queries = append(queries, fmt.Sprintf("GRANT USAGE ON SCHEMA %s TO %s", pq.QuoteIdentifier(strings.ToLower(schemaName.(string))), grantee))
+	}
+	for _, query := range queries {
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("could not update schema usage: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourceRedshiftDatashareConsumerAccessRead(db, d)
+}
+
+func resourceRedshiftDatashareConsumerAccessDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	grantee := datashareConsumerAccessGrantee(d)
+	databaseName := strings.ToLower(d.Get(datashareConsumerAccessDatabaseAttr).(string))
+
+	queries := []string{}
+	for _, schemaName := range d.Get(datashareConsumerAccessSchemasAttr).(*schema.Set).List() {
+		queries = append(queries, fmt.Sprintf("REVOKE USAGE ON SCHEMA %s FROM %s", pq.QuoteIdentifier(strings.ToLower(schemaName.(string))), grantee))
+	}
+	queries = append(queries, fmt.Sprintf("REVOKE USAGE ON DATABASE %s FROM %s", pq.QuoteIdentifier(databaseName), grantee))
+	for _, query := range queries {
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// datashareConsumerAccessIdentity returns the identity type and name of the grantee, as listed in the svv_*_privileges views.
+func datashareConsumerAccessIdentity(d *schema.ResourceData) (string, string) {
+	if groupName, ok := d.GetOk(datashareConsumerAccessGroupAttr); ok {
+		return "group", strings.ToLower(groupName.(string))
+	}
+	if roleName, ok := d.GetOk(datashareConsumerAccessRoleAttr); ok {
+		return "role", strings.ToLower(roleName.(string))
+	}
+	return "user", strings.ToLower(d.Get(datashareConsumerAccessUserAttr).(string))
+}
+
+// datashareConsumerAccessGrantee returns the grantee for GRANT and REVOKE statements.
+func datashareConsumerAccessGrantee(d *schema.ResourceData) string {
+	identityType, identityName := datashareConsumerAccessIdentity(d)
+	if identityType == "user" {
+		return pq.QuoteIdentifier(identityName)
+	}
+	return fmt.Sprintf("%s %s", strings.ToUpper(identityType), pq.QuoteIdentifier(identityName))
+}
+
+func generateDatashareConsumerAccessID(d *schema.ResourceData) string {
+	prefixes := map[string]string{"user": "un", "group": "gn", "role": "rn"}
+	identityType, identityName := datashareConsumerAccessIdentity(d)
+	return fmt.Sprintf("%s:%s_db:%s", prefixes[identityType], identityName, strings.ToLower(d.Get(datashareConsumerAccessDatabaseAttr).(string)))
+}
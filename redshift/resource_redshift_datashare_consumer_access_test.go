@@ -0,0 +1,61 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRedshiftDatashareConsumerAccess_Basic(t *testing.T) {
+	shareDatabase := getEnvOrSkip("REDSHIFT_DATASHARE_CONSUMER_DATABASE", t)
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_share_schema"), "-", "_")
+
+	config := func(schemas string) string {
+		return fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "share" {
+  name = %[2]q
+  external_schema {
+    database_name = %[3]q
+    redshift_source {
+      schema = "public"
+    }
+  }
+}
+
+resource "redshift_datashare_consumer_access" "access" {
+  database = %[3]q
+  group    = redshift_group.group.name
+  schemas  = %[4]s
+}
+`, groupName, schemaName, shareDatabase, schemas)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config("[redshift_schema.share.name]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_datashare_consumer_access.access", "id", fmt.Sprintf("gn:%s_db:%s", groupName, strings.ToLower(shareDatabase))),
+					resource.TestCheckResourceAttr("redshift_datashare_consumer_access.access", "schemas.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_datashare_consumer_access.access", "schemas.*", schemaName),
+				),
+			},
+			{
+				Config: config("[]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_datashare_consumer_access.access", "schemas.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -31,7 +31,7 @@ func redshiftDatasharePrivilege() *schema.Resource {
 			"Note: Data sharing is only supported on certain instance families, such as RA3.", datasharePrivilegeNamespaceAttr, datasharePrivilegeAccountAttr),
 		Exists: RedshiftResourceExistsFunc(resourceRedshiftDatasharePrivilegeExists),
 		Create: RedshiftResourceFunc(resourceRedshiftDatasharePrivilegeCreate),
-		Read:   RedshiftResourceFunc(resourceRedshiftDatasharePrivilegeRead),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftDatasharePrivilegeRead),
 		Delete: RedshiftResourceFunc(resourceRedshiftDatasharePrivilegeDelete),
 		CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
 			// Exactly one of "namespace" or "account" must be specified, however
@@ -47,10 +47,11 @@ func redshiftDatasharePrivilege() *schema.Resource {
 		},
 		Schema: map[string]*schema.Schema{
 			datasharePrivilegeShareNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "Name of the datashare",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "Name of the datashare",
 				StateFunc: func(val interface{}) string {
 					return strings.ToLower(val.(string))
 				},
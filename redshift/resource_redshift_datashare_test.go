@@ -4,11 +4,13 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -107,6 +109,219 @@ resource "redshift_datashare" "basic" {
 	})
 }
 
+func TestAccRedshiftDatashare_ConsumerNamespaces(t *testing.T) {
+	_ = getEnvOrSkip("REDSHIFT_DATASHARE_SUPPORTED", t)
+	consumerNamespace := getEnvOrSkip("REDSHIFT_DATASHARE_CONSUMER_NAMESPACE", t)
+	shareName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_datashare_consumers"), "-", "_")
+	config := func(consumerNamespaces string) string {
+		return fmt.Sprintf(`
+resource "redshift_datashare" "share" {
+	name                = %[1]q
+	consumer_namespaces = [%[2]s]
+}
+`, shareName, consumerNamespaces)
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftDatashareDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(fmt.Sprintf("%q", consumerNamespace)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftDatashareNamespacePrivilegeExists(shareName, consumerNamespace),
+					resource.TestCheckResourceAttr("redshift_datashare.share", "consumer_namespaces.#", "1"),
+				),
+			},
+			{
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						exists, err := checkDatasharePrivilegeNamespaceExists(testAccProvider.Meta().(*Client), shareName, consumerNamespace)
+						if err != nil {
+							return err
+						}
+						if exists {
+							return fmt.Errorf("Datashare %s is still granted to namespace %s", shareName, consumerNamespace)
+						}
+						return nil
+					},
+					resource.TestCheckResourceAttr("redshift_datashare.share", "consumer_namespaces.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftDatashare_FancyNames(t *testing.T) {
+	_ = getEnvOrSkip("REDSHIFT_DATASHARE_SUPPORTED", t)
+	shareName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_DataShare_Fancy"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_wOoOT_I22_@tH15"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_schema" "schema" {
+	%[1]s = %[2]q
+	%[3]s = true
+}
+
+resource "redshift_datashare" "fancy" {
+	%[4]s = %[5]q
+	%[6]s = [
+		%[2]q,
+	]
+	depends_on = [
+		redshift_schema.schema,
+	]
+}
+`, schemaNameAttr, schemaName, schemaCascadeOnDeleteAttr, dataShareNameAttr, shareName, dataShareSchemasAttr)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftDatashareDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftDatashareExists(shareName),
+					resource.TestCheckResourceAttr("redshift_datashare.fancy", dataShareNameAttr, strings.ToLower(shareName)),
+					resource.TestCheckResourceAttr("redshift_datashare.fancy", fmt.Sprintf("%s.#", dataShareSchemasAttr), "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_datashare.fancy", fmt.Sprintf("%s.*", dataShareSchemasAttr), strings.ToLower(schemaName)),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccRedshiftDatashare_MissingSchema(t *testing.T) {
+	_ = getEnvOrSkip("REDSHIFT_DATASHARE_SUPPORTED", t)
+	shareName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_datashare_missing"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_datashare" "share" {
+  name    = %[1]q
+  schemas = ["%[1]s_missing"]
+}
+`, shareName)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftDatashareDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(fmt.Sprintf("cannot add schema %s_missing to datashare %s: the schema does not exist", shareName, shareName)),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftDatashare_Functions(t *testing.T) {
+	_ = getEnvOrSkip("REDSHIFT_DATASHARE_SUPPORTED", t)
+	shareName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_datashare_functions"), "-", "_")
+	schemaConfig := fmt.Sprintf(`
+resource "redshift_schema" "schema" {
+	%[1]s = %[2]q
+	%[3]s = true
+}
+`, schemaNameAttr, shareName, schemaCascadeOnDeleteAttr)
+	config := schemaConfig + fmt.Sprintf(`
+resource "redshift_datashare" "functions" {
+	%[1]s = %[2]q
+	%[3]s = [
+		redshift_schema.schema.name,
+	]
+	%[4]s = [
+		"${redshift_schema.schema.name}.f_shared(int)",
+	]
+}
+`, dataShareNameAttr, shareName, dataShareSchemasAttr, dataShareFunctionsAttr)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftDatashareDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: schemaConfig,
+			},
+			{
+				PreConfig: func() {
+					conn, err := testAccProvider.Meta().(*Client).Connect()
+					if err != nil {
+						t.Fatalf("couldn't start redshift connection: %s", err)
+					}
+					for _, function := range []string{"f_shared", "f_private"} {
+						query := fmt.Sprintf("CREATE FUNCTION %s.%s(int) RETURNS int STABLE AS $$ SELECT $1 $$ LANGUAGE sql", shareName, function)
+						if _, err := conn.Exec(query); err != nil {
+							t.Fatalf("couldn't create function %s: %s", function, err)
+						}
+					}
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftDatashareExists(shareName),
+					resource.TestCheckResourceAttr("redshift_datashare.functions", fmt.Sprintf("%s.#", dataShareFunctionsAttr), "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_datashare.functions", fmt.Sprintf("%s.*", dataShareFunctionsAttr), fmt.Sprintf("%s.f_shared(int)", shareName)),
+				),
+			},
+		},
+	})
+}
+
+func TestDatashareFunctionsBySchema(t *testing.T) {
+	functions := schema.NewSet(hashCaseInsensitiveString, []interface{}{"Sales.F_One(int)", "sales.f_two()", "hr.f_three(varchar)"})
+
+	bySchema := datashareFunctionsBySchema(functions)
+	if len(bySchema) != 2 {
+		t.Fatalf("expected functions of 2 schemas, got %v", bySchema)
+	}
+	if len(bySchema["sales"]) != 2 || len(bySchema["hr"]) != 1 {
+		t.Errorf("unexpected grouping of functions: %v", bySchema)
+	}
+}
+
+func TestDatashareFunctionSQL(t *testing.T) {
+	for function, expected := range map[string]string{
+		"Sales.F_One(int)":                         "sales.f_one(int)",
+		"sales.f_one(integer, character varying)":  "sales.f_one(integer, character varying)",
+		"sales.f_none()":                           "sales.f_none()",
+		`my schema.select(int)`:                    `"my schema"."select"(int)`,
+		`sales.f"x(int)`:                           `sales."f""x"(int)`,
+		"sales.f(int); drop table users; --()":     "",
+		"sales.f(int) ; drop table users ;(int)":   "",
+		"sales.f(int, varchar(10))":                "",
+		"sales.f(varchar'); drop table users; --)": "",
+		"sales.f": "",
+	} {
+		actual, err := datashareFunctionSQL(function)
+		if expected == "" {
+			if err == nil {
+				t.Errorf("expected an error for %q, got %q", function, actual)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", function, err)
+		} else if actual != expected {
+			t.Errorf("expected %q for %q, got %q", expected, function, actual)
+		}
+	}
+}
+
+func TestMatchDatashareFunctions(t *testing.T) {
+	configured := schema.NewSet(hashCaseInsensitiveString, []interface{}{"Sales.F_One(int)", "sales.f_two(float)", "sales.f_dropped()"})
+	shared := []string{"sales.f_one(integer)", "sales.f_one(integer, character varying)", "sales.f_two(double precision)"}
+
+	functions := matchDatashareFunctions(configured, shared)
+	expected := schema.NewSet(hashCaseInsensitiveString, []interface{}{"Sales.F_One(int)", "sales.f_two(float)", "sales.f_one(integer, character varying)"})
+	if !functions.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected.List(), functions.List())
+	}
+}
+
 func testAccCheckRedshiftDatashareExists(shareName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		client := testAccProvider.Meta().(*Client)
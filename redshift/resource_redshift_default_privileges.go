@@ -1,62 +1,103 @@
 package redshift
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
+	"github.com/brainly/terraform-provider-redshift/redshift/internal/catalog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/lib/pq"
 )
 
 const (
-	defaultPrivilegesUserAttr       = "user"
-	defaultPrivilegesGroupAttr      = "group"
-	defaultPrivilegesOwnerAttr      = "owner"
-	defaultPrivilegesSchemaAttr     = "schema"
-	defaultPrivilegesPrivilegesAttr = "privileges"
-	defaultPrivilegesObjectTypeAttr = "object_type"
+	defaultPrivilegesUserAttr          = "user"
+	defaultPrivilegesGroupAttr         = "group"
+	defaultPrivilegesOwnerAttr         = "owner"
+	defaultPrivilegesOwnersAttr        = "owners"
+	defaultPrivilegesSchemaAttr        = "schema"
+	defaultPrivilegesPrivilegesAttr    = "privileges"
+	defaultPrivilegesObjectTypeAttr    = "object_type"
+	defaultPrivilegesOwnerIDAttr       = "owner_id"
+	defaultPrivilegesGranteeIDAttr     = "grantee_id"
+	defaultPrivilegesAdoptAttr         = "adopt_existing"
+	defaultPrivilegesCreateSchemaAttr  = "create_schema_if_missing"
+	defaultPrivilegesStrictAttr        = "strict_privileges"
+	defaultPrivilegesDetectOverlapAttr = "detect_overlapping_defaults"
+	defaultPrivilegesOverlappingAttr   = "overlapping_defaults"
 
 	defaultPrivilegesAllSchemasID = 0
 )
 
 var defaultPrivilegesAllowedObjectTypes = []string{
 	"table",
+	"function",
+	"procedure",
 }
 
 var defaultPrivilegesObjectTypesCodes = map[string]string{
-	"table": "r",
+	"table":     "r",
+	"function":  "f",
+	"procedure": "p",
+}
+
+// defaultPrivilegesACLCodes maps the privilege codes of pg_default_acl entries to the privilege names.
+var defaultPrivilegesACLCodes = map[rune]string{
+	'r': "select",
+	'w': "update",
+	'a': "insert",
+	'd': "delete",
+	'D': "drop",
+	'x': "references",
+	'R': "rule",
+	't': "trigger",
+	'P': "truncate",
+	'X': "execute",
 }
 
 func redshiftDefaultPrivileges() *schema.Resource {
 	return &schema.Resource{
-		Description: `Defines the default set of access privileges to be applied to objects that are created in the future by the specified user. By default, users can change only their own default access privileges. Only a superuser can specify default privileges for other users.`,
-		Read:        RedshiftResourceFunc(resourceRedshiftDefaultPrivilegesRead),
+		Description: `Defines the default set of access privileges to be applied to objects that are created in the future by the specified user. By default, users can change only their own default access privileges. Only a superuser can specify default privileges for other users. Updates only revoke the default privileges which are no longer configured and grant the missing ones.`,
+		Read:        RedshiftResourceReadFunc(RedshiftResourceInDatabase(resourceRedshiftDefaultPrivilegesRead)),
 		Create: RedshiftResourceFunc(
-			RedshiftResourceRetryOnPQErrors(resourceRedshiftDefaultPrivilegesCreate),
+			RedshiftResourceWaitForGrantee(
+				RedshiftResourceRetryOnPQErrors(RedshiftResourceInDatabase(resourceRedshiftDefaultPrivilegesAdoptOrCreate)),
+				defaultPrivilegesUserAttr, defaultPrivilegesGroupAttr,
+			),
 		),
 		Delete: RedshiftResourceFunc(
-			RedshiftResourceRetryOnPQErrors(resourceRedshiftDefaultPrivilegesDelete),
+			RedshiftResourceRetryOnPQErrors(RedshiftResourceInDatabase(resourceRedshiftDefaultPrivilegesDelete)),
 		),
-		// Since we revoke all when creating, we can use create as update
 		Update: RedshiftResourceFunc(
-			RedshiftResourceRetryOnPQErrors(resourceRedshiftDefaultPrivilegesCreate),
+			RedshiftResourceRetryOnPQErrors(RedshiftResourceInDatabase(resourceRedshiftDefaultPrivilegesUpdate)),
+		),
+
+		CustomizeDiff: customdiff.All(
+			validateDefaultPrivilegesDiff,
+			defaultPrivilegesSchemaExists,
 		),
 
 		Schema: map[string]*schema.Schema{
+			resourceDatabaseAttr:       resourceDatabaseSchema("default privileges"),
+			resourceWaitForGranteeAttr: resourceWaitForGranteeSchema(),
 			defaultPrivilegesSchemaAttr: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "If set, the specified default privileges are applied to new objects created in the specified schema. In this case, the user or user group that is the target of ALTER DEFAULT PRIVILEGES must have CREATE privilege for the specified schema. Default privileges that are specific to a schema are added to existing global default privileges. By default, default privileges are applied globally to the entire database.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "If set, the specified default privileges are applied to new objects created in the specified schema. In this case, the user or user group that is the target of ALTER DEFAULT PRIVILEGES must have CREATE privilege for the specified schema. Default privileges that are specific to a schema are added to existing global default privileges. By default, default privileges are applied globally to the entire database.",
 			},
 			defaultPrivilegesGroupAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ForceNew:     true,
 				ExactlyOneOf: []string{defaultPrivilegesGroupAttr, defaultPrivilegesUserAttr},
+				ValidateFunc: validateIdentifier,
 				Description:  "The name of the  group to which the specified default privileges are applied.",
 			},
 			defaultPrivilegesUserAttr: {
@@ -64,13 +105,25 @@ func redshiftDefaultPrivileges() *schema.Resource {
 				Optional:     true,
 				ForceNew:     true,
 				ExactlyOneOf: []string{defaultPrivilegesGroupAttr, defaultPrivilegesUserAttr},
+				ValidateFunc: validateIdentifier,
 				Description:  "The name of the user to which the specified default privileges are applied.",
 			},
 			defaultPrivilegesOwnerAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The name of the user for which default privileges are defined. Only a superuser can specify default privileges for other users.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{defaultPrivilegesOwnerAttr, defaultPrivilegesOwnersAttr},
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the user for which default privileges are defined. Only a superuser can specify default privileges for other users.",
+			},
+			defaultPrivilegesOwnersAttr: {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				MinItems:     1,
+				ExactlyOneOf: []string{defaultPrivilegesOwnerAttr, defaultPrivilegesOwnersAttr},
+				Elem:         &schema.Schema{Type: schema.TypeString, ValidateFunc: validateIdentifier},
+				Set:          schema.HashString,
+				Description:  "The names of the users for which the same default privileges are defined, e.g. all the users loading data into a schema. One `ALTER DEFAULT PRIVILEGES` statement is run per owner, in a single transaction. Owners can be added or removed without revoking the default privileges of the other ones.",
 			},
 			defaultPrivilegesObjectTypeAttr: {
 				Type:         schema.TypeString,
@@ -87,41 +140,114 @@ func redshiftDefaultPrivileges() *schema.Resource {
 					StateFunc: func(val interface{}) string {
 						return strings.ToLower(val.(string))
 					},
+					ValidateDiagFunc: privilegeNoOpWarning,
 				},
 				Set:         schema.HashString,
-				Description: "The list of privileges to apply as default privileges. See [ALTER DEFAULT PRIVILEGES command documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_DEFAULT_PRIVILEGES.html) to see what privileges are available to which object type.",
+				Description: "The list of privileges to apply as default privileges. See [ALTER DEFAULT PRIVILEGES command documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_DEFAULT_PRIVILEGES.html) to see what privileges are available to which object type. `all` can be used alone to grant all privileges of the object type.",
+			},
+			defaultPrivilegesAdoptAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When set to `true`, creating the resource does not revoke and re-grant the default privileges, but reads the ones already defined (for example by hand or by another tool) into the state. Any difference from the configured `privileges` is then shown in the next plan and applied by an update. Useful to bring existing default privileges under terraform management without a window in which they are revoked.",
+			},
+			defaultPrivilegesCreateSchemaAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When set to `true`, the `schema` is created (owned by the connecting user) if it does not exist yet. The schema is not dropped when the resource is destroyed. By default a missing schema is reported when planning.",
+			},
+			defaultPrivilegesStrictAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "By default privileges implied by the configured ones are not reported as a difference, e.g. `privileges = [\"all\"]` matches all the privileges of the object type. Set to `true` to require the privileges read back to be exactly the configured ones.",
+			},
+			defaultPrivilegesDetectOverlapAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When set to `true`, reading the resource also looks for default privileges of the same object type given to the same grantee by other owners, or by the same owners for the whole database when `schema` is set. They are recorded in `overlapping_defaults` and logged as warnings (visible with `TF_LOG=WARN`), as they explain why the privileges of new objects can differ from the configured ones.",
+			},
+			defaultPrivilegesOverlappingAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The default privileges overlapping with the ones of this resource, found when `detect_overlapping_defaults` is set.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						defaultPrivilegesOwnerAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The owner defining the default privileges.",
+						},
+						defaultPrivilegesSchemaAttr: {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The schema the default privileges apply to, empty when they apply to the whole database.",
+						},
+						defaultPrivilegesPrivilegesAttr: {
+							Type:        schema.TypeSet,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: "The privileges given to the grantee.",
+						},
+					},
+				},
+			},
+			defaultPrivilegesOwnerIDAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The ID of the owner user, as found in `pg_default_acl.defacluser`. Only set with `owner`.",
+			},
+			defaultPrivilegesGranteeIDAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The ID of the user (`usesysid`) or group (`grosysid`) to which the default privileges are applied.",
 			},
 		},
 	}
 }
 
 func resourceRedshiftDefaultPrivilegesDelete(db *DBConnection, d *schema.ResourceData) error {
-	revokeAlterDefaultQuery := createAlterDefaultsRevokeQuery(d)
-
 	tx, err := startTransaction(db.client, "")
 	if err != nil {
 		return err
 	}
 	defer deferredRollback(tx)
 
-	if _, err := tx.Exec(revokeAlterDefaultQuery); err != nil {
-		return err
+	for _, owner := range defaultPrivilegesOwners(d) {
+		revokeAlterDefaultQuery := createAlterDefaultsRevokeQuery(d, owner)
+		log.Printf("[DEBUG] %s\n", revokeAlterDefaultQuery)
+		if _, err := tx.Exec(revokeAlterDefaultQuery); err != nil {
+			return err
+		}
 	}
 
 	return tx.Commit()
 }
 
+func resourceRedshiftDefaultPrivilegesAdoptOrCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !d.Get(defaultPrivilegesAdoptAttr).(bool) {
+		return resourceRedshiftDefaultPrivilegesCreate(db, d)
+	}
+
+	log.Printf("[DEBUG] adopting existing default privileges instead of revoking them\n")
+	d.SetId(generateDefaultPrivilegesID(d))
+
+	return resourceRedshiftDefaultPrivilegesReadImpl(db, d)
+}
+
 func resourceRedshiftDefaultPrivilegesCreate(db *DBConnection, d *schema.ResourceData) error {
 	privilegesSet := d.Get(defaultPrivilegesPrivilegesAttr).(*schema.Set)
-	objectType := d.Get(defaultPrivilegesObjectTypeAttr).(string)
 
 	privileges := []string{}
 	for _, p := range privilegesSet.List() {
 		privileges = append(privileges, strings.ToUpper(p.(string)))
 	}
 
-	if !validatePrivileges(privileges, objectType) {
-		return fmt.Errorf("Invalid privileges list '%v' for object type '%s'", privileges, objectType)
+	if err := validateDefaultPrivileges(d); err != nil {
+		return err
 	}
 
 	tx, err := startTransaction(db.client, "")
@@ -130,14 +256,12 @@ func resourceRedshiftDefaultPrivilegesCreate(db *DBConnection, d *schema.Resourc
 	}
 	defer deferredRollback(tx)
 
-	revokeAlterDefaultQuery := createAlterDefaultsRevokeQuery(d)
-	if _, err := tx.Exec(revokeAlterDefaultQuery); err != nil {
+	if err := ensureDefaultPrivilegesSchema(tx, d); err != nil {
 		return err
 	}
 
-	if len(privileges) > 0 {
-		alterDefaultQuery := createAlterDefaultsGrantQuery(d, privileges)
-		if _, err := tx.Exec(alterDefaultQuery); err != nil {
+	for _, owner := range defaultPrivilegesOwners(d) {
+		if err := resetDefaultPrivileges(tx, d, owner, privileges); err != nil {
 			return err
 		}
 	}
@@ -151,6 +275,193 @@ func resourceRedshiftDefaultPrivilegesCreate(db *DBConnection, d *schema.Resourc
 	return resourceRedshiftDefaultPrivilegesReadImpl(db, d)
 }
 
+// resourceRedshiftDefaultPrivilegesUpdate applies only the difference to the previous state,
+// so default privileges which are kept are never revoked.
+func resourceRedshiftDefaultPrivilegesUpdate(db *DBConnection, d *schema.ResourceData) error {
+	objectType := d.Get(defaultPrivilegesObjectTypeAttr).(string)
+	oldRaw, newRaw := d.GetChange(defaultPrivilegesPrivilegesAttr)
+
+	privileges := []string{}
+	for _, p := range newRaw.(*schema.Set).List() {
+		privileges = append(privileges, strings.ToUpper(p.(string)))
+	}
+
+	if err := validateDefaultPrivileges(d); err != nil {
+		return err
+	}
+
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := ensureDefaultPrivilegesSchema(tx, d); err != nil {
+		return err
+	}
+
+	oldPrivileges := expandPrivileges(oldRaw.(*schema.Set), objectType)
+	newPrivileges := expandPrivileges(newRaw.(*schema.Set), objectType)
+
+	oldOwners, newOwners := d.GetChange(defaultPrivilegesOwnersAttr)
+	addedOwners := newOwners.(*schema.Set).Difference(oldOwners.(*schema.Set))
+	queries := []string{}
+	for _, owner := range oldOwners.(*schema.Set).Difference(newOwners.(*schema.Set)).List() {
+		queries = append(queries, createAlterDefaultsRevokeQuery(d, owner.(string)))
+	}
+	for _, owner := range defaultPrivilegesOwners(d) {
+		if addedOwners.Contains(owner) {
+			continue
+		}
+		if revoked := oldPrivileges.Difference(newPrivileges); revoked.Len() > 0 {
+			queries = append(queries, createAlterDefaultsRevokePrivilegesQuery(d, owner, privilegesList(revoked)))
+		}
+		if granted := newPrivileges.Difference(oldPrivileges); granted.Len() > 0 {
+			queries = append(queries, createAlterDefaultsGrantQuery(d, owner, privilegesList(granted)))
+		}
+	}
+	for _, query := range queries {
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	for _, owner := range addedOwners.List() {
+		if err := resetDefaultPrivileges(tx, d, owner.(string), privileges); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	d.SetId(generateDefaultPrivilegesID(d))
+
+	return resourceRedshiftDefaultPrivilegesReadImpl(db, d)
+}
+
+// resetDefaultPrivileges revokes all the default privileges of the owner, then grants the given ones.
+func resetDefaultPrivileges(tx *sql.Tx, d *schema.ResourceData, owner string, privileges []string) error {
+	queries := []string{createAlterDefaultsRevokeQuery(d, owner)}
+	if len(privileges) > 0 {
+		queries = append(queries, createAlterDefaultsGrantQuery(d, owner, privileges))
+	}
+	for _, query := range queries {
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultPrivilegesOwners returns the sorted names of the users for which the default privileges are defined.
+func defaultPrivilegesOwners(d *schema.ResourceData) []string {
+	if owner, ok := d.GetOk(defaultPrivilegesOwnerAttr); ok {
+		return []string{owner.(string)}
+	}
+	owners := []string{}
+	for _, owner := range d.Get(defaultPrivilegesOwnersAttr).(*schema.Set).List() {
+		owners = append(owners, owner.(string))
+	}
+	sort.Strings(owners)
+	return owners
+}
+
+// ensureDefaultPrivilegesSchema creates the schema when requested, or otherwise checks it exists.
+// A missing schema is reported as a retryable error, because a schema created concurrently
+// in the same apply might not be visible yet.
+func ensureDefaultPrivilegesSchema(tx *sql.Tx, d *schema.ResourceData) error {
+	schemaName, schemaNameSet := d.GetOk(defaultPrivilegesSchemaAttr)
+	if !schemaNameSet {
+		return nil
+	}
+
+	if d.Get(defaultPrivilegesCreateSchemaAttr).(bool) {
+		query := fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", pq.QuoteIdentifier(schemaName.(string)))
+		log.Printf("[DEBUG] %s\n", query)
+		_, err := tx.Exec(query)
+		return err
+	}
+
+	if _, err := catalog.NewResolver(tx).SchemaID(schemaName.(string)); err != nil {
+		if err == sql.ErrNoRows {
+			return &pq.Error{
+				Code:    pqErrorCodeInvalidSchemaName,
+				Message: fmt.Sprintf("schema %q does not exist", schemaName),
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// validateDefaultPrivilegesDiff validates the privileges for the object type at plan time.
+// Privileges known only at apply time are validated by validateDefaultPrivileges when they are applied.
+func validateDefaultPrivilegesDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown(defaultPrivilegesObjectTypeAttr) || !d.NewValueKnown(defaultPrivilegesPrivilegesAttr) {
+		return nil
+	}
+	return validateDefaultPrivileges(d)
+}
+
+// validateDefaultPrivileges returns an error naming the privileges attribute when they don't apply to the object type.
+func validateDefaultPrivileges(d resourceGetter) error {
+	objectType := d.Get(defaultPrivilegesObjectTypeAttr).(string)
+
+	privileges := []string{}
+	for _, p := range d.Get(defaultPrivilegesPrivilegesAttr).(*schema.Set).List() {
+		privileges = append(privileges, strings.ToUpper(p.(string)))
+	}
+
+	if !validatePrivileges(privileges, objectType) {
+		return fmt.Errorf("%s: invalid privileges list %v for object type %s", defaultPrivilegesPrivilegesAttr, privileges, objectType)
+	}
+	return nil
+}
+
+// defaultPrivilegesSchemaExists reports a missing schema when planning the creation of the resource.
+// The check is skipped when the schema name is not known yet (e.g. it references a redshift_schema
+// which is going to be created) or when the database cannot be reached.
+func defaultPrivilegesSchemaExists(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" || !d.NewValueKnown(defaultPrivilegesSchemaAttr) || d.Get(defaultPrivilegesCreateSchemaAttr).(bool) {
+		return nil
+	}
+	schemaName := d.Get(defaultPrivilegesSchemaAttr).(string)
+	if schemaName == "" {
+		return nil
+	}
+
+	client, ok := meta.(*Client)
+	if !ok || !d.NewValueKnown(resourceDatabaseAttr) {
+		return nil
+	}
+	client = client.ReadOnly()
+	if database, ok := d.GetOk(resourceDatabaseAttr); ok {
+		client = client.config.NewClient(database.(string))
+	}
+	db, err := client.Connect()
+	if err != nil {
+		log.Printf("[WARN] could not check if schema %s exists: %v", schemaName, err)
+		return nil
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM pg_namespace WHERE nspname = $1", schemaName).Scan(&count); err != nil {
+		log.Printf("[WARN] could not check if schema %s exists: %v", schemaName, err)
+		return nil
+	}
+	if count == 0 {
+		return fmt.Errorf(
+			"schema %q does not exist. Create it first, reference the name of the redshift_schema resource managing it (e.g. `schema = redshift_schema.example.name`), or set `%s` to create it",
+			schemaName,
+			defaultPrivilegesCreateSchemaAttr,
+		)
+	}
+	return nil
+}
+
 func resourceRedshiftDefaultPrivilegesRead(db *DBConnection, d *schema.ResourceData) error {
 	return resourceRedshiftDefaultPrivilegesReadImpl(db, d)
 }
@@ -159,7 +470,6 @@ func resourceRedshiftDefaultPrivilegesReadImpl(db *DBConnection, d *schema.Resou
 	var entityID int
 	var entityIsUser bool
 	schemaName, schemaNameSet := d.GetOk(defaultPrivilegesSchemaAttr)
-	ownerName := d.Get(defaultPrivilegesOwnerAttr).(string)
 
 	tx, err := startTransaction(db.client, "")
 	if err != nil {
@@ -167,10 +477,15 @@ func resourceRedshiftDefaultPrivilegesReadImpl(db *DBConnection, d *schema.Resou
 	}
 	defer deferredRollback(tx)
 
+	resolver := catalog.NewResolver(tx)
+
 	schemaID := defaultPrivilegesAllSchemasID
 	if schemaNameSet {
 		log.Printf("[DEBUG] getting ID for schema %s\n", schemaName)
-		schemaID, err = getSchemaIDFromName(tx, schemaName.(string))
+		schemaID, err = resolver.SchemaID(schemaName.(string))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("schema '%s' does not exist", schemaName)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to get schema ID for schema '%s': %w", schemaName, err)
 		}
@@ -178,33 +493,60 @@ func resourceRedshiftDefaultPrivilegesReadImpl(db *DBConnection, d *schema.Resou
 
 	if groupName, groupNameSet := d.GetOk(defaultPrivilegesGroupAttr); groupNameSet {
 		log.Printf("[DEBUG] getting ID for group %s\n", groupName.(string))
-		entityID, err = getGroupIDFromName(tx, groupName.(string))
+		entityID, err = resolver.GroupID(groupName.(string))
 		entityIsUser = false
 		if err != nil {
 			return fmt.Errorf("failed to get group ID: %w", err)
 		}
 	} else if userName, userNameSet := d.GetOk(defaultPrivilegesUserAttr); userNameSet {
 		log.Printf("[DEBUG] getting ID for user %s\n", userName.(string))
-		entityID, err = getUserIDFromName(tx, userName.(string))
+		entityID, err = resolver.UserID(userName.(string))
 		entityIsUser = true
 		if err != nil {
 			return fmt.Errorf("failed to get user ID: %w", err)
 		}
 	}
 
-	log.Printf("[DEBUG] getting ID for owner %s\n", ownerName)
-	ownerID, err := getUserIDFromName(tx, ownerName)
-	if err != nil {
-		return fmt.Errorf("failed to get user ID: %w", err)
+	d.Set(defaultPrivilegesGranteeIDAttr, entityID)
+
+	// With several owners, the default privileges of all of them are combined, so that
+	// a single update repairs every owner.
+	observed := []*schema.Set{}
+	for _, ownerName := range defaultPrivilegesOwners(d) {
+		log.Printf("[DEBUG] getting ID for owner %s\n", ownerName)
+		ownerID, err := resolver.UserID(ownerName)
+		if err != nil {
+			return fmt.Errorf("failed to get user ID: %w", err)
+		}
+		if _, ok := d.GetOk(defaultPrivilegesOwnerAttr); ok {
+			d.Set(defaultPrivilegesOwnerIDAttr, ownerID)
+		}
+
+		var privileges []string
+		switch strings.ToUpper(d.Get(defaultPrivilegesObjectTypeAttr).(string)) {
+		case "TABLE":
+			log.Printf("[DEBUG] reading default privileges of owner %s\n", ownerName)
+			if privileges, err = readGroupTableDefaultPrivileges(tx, entityID, schemaID, ownerID, entityIsUser); err != nil {
+				return fmt.Errorf("failed to read table privileges: %w", err)
+			}
+		case "FUNCTION", "PROCEDURE":
+			log.Printf("[DEBUG] reading default privileges of owner %s\n", ownerName)
+			if privileges, err = readCallableDefaultPrivileges(tx, d, entityID, schemaID, ownerID, entityIsUser); err != nil {
+				return fmt.Errorf("failed to read %s privileges: %w", d.Get(defaultPrivilegesObjectTypeAttr).(string), err)
+			}
+		}
+
+		observed = append(observed, schema.NewSet(schema.HashString, stringsToInterfaces(privileges)))
 	}
+	setDefaultPrivileges(d, observed)
 
-	switch strings.ToUpper(d.Get(defaultPrivilegesObjectTypeAttr).(string)) {
-	case "TABLE":
-		log.Println("[DEBUG] reading default privileges")
-		if err := readGroupTableDefaultPrivileges(tx, d, entityID, schemaID, ownerID, entityIsUser); err != nil {
-			return fmt.Errorf("failed to read table privileges: %w", err)
+	overlapping := []map[string]interface{}{}
+	if d.Get(defaultPrivilegesDetectOverlapAttr).(bool) {
+		if overlapping, err = readOverlappingDefaultPrivileges(tx, d, resolver, schemaID, entityIsUser); err != nil {
+			return fmt.Errorf("failed to read overlapping default privileges: %w", err)
 		}
 	}
+	d.Set(defaultPrivilegesOverlappingAttr, overlapping)
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
@@ -213,8 +555,8 @@ func resourceRedshiftDefaultPrivilegesReadImpl(db *DBConnection, d *schema.Resou
 	return nil
 }
 
-func readGroupTableDefaultPrivileges(tx *sql.Tx, d *schema.ResourceData, entityID, schemaID, ownerID int, entityIsUser bool) error {
-	var tableSelect, tableUpdate, tableInsert, tableDelete, tableDrop, tableReferences, tableRule, tableTrigger bool
+func readGroupTableDefaultPrivileges(tx *sql.Tx, entityID, schemaID, ownerID int, entityIsUser bool) ([]string, error) {
+	var tableSelect, tableUpdate, tableInsert, tableDelete, tableDrop, tableReferences, tableRule, tableTrigger, tableTruncate bool
 	var query string
 
 	if entityIsUser {
@@ -227,7 +569,8 @@ func readGroupTableDefaultPrivileges(tx *sql.Tx, d *schema.ResourceData, entityI
 		decode(charindex('D',split_part(split_part(regexp_replace(replace(array_to_string(defaclacl, '|'), '"', ''), 'group '||u.usename), u.usename||'=', 2) ,'/',1)),0,0,1) as drop,
 		decode(charindex('x',split_part(split_part(regexp_replace(replace(array_to_string(defaclacl, '|'), '"', ''), 'group '||u.usename), u.usename||'=', 2) ,'/',1)),0,0,1) as references,
 		decode(charindex('R',split_part(split_part(regexp_replace(replace(array_to_string(defaclacl, '|'), '"', ''), 'group '||u.usename), u.usename||'=', 2) ,'/',1)),0,0,1) as rule,
-		decode(charindex('t',split_part(split_part(regexp_replace(replace(array_to_string(defaclacl, '|'), '"', ''), 'group '||u.usename), u.usename||'=', 2) ,'/',1)),0,0,1) as trigger
+		decode(charindex('t',split_part(split_part(regexp_replace(replace(array_to_string(defaclacl, '|'), '"', ''), 'group '||u.usename), u.usename||'=', 2) ,'/',1)),0,0,1) as trigger,
+		decode(charindex('P',split_part(split_part(regexp_replace(replace(array_to_string(defaclacl, '|'), '"', ''), 'group '||u.usename), u.usename||'=', 2) ,'/',1)),0,0,1) as truncate
 	      FROM pg_user u, pg_default_acl acl
 	      WHERE 
 		acl.defaclnamespace = $1
@@ -246,7 +589,8 @@ func readGroupTableDefaultPrivileges(tx *sql.Tx, d *schema.ResourceData, entityI
 		decode(charindex('D',split_part(split_part(replace(array_to_string(defaclacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)),0,0,1) as drop,
 		decode(charindex('x',split_part(split_part(replace(array_to_string(defaclacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)),0,0,1) as references,
 		decode(charindex('R',split_part(split_part(replace(array_to_string(defaclacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)),0,0,1) as rule,
-		decode(charindex('t',split_part(split_part(replace(array_to_string(defaclacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)),0,0,1) as trigger
+		decode(charindex('t',split_part(split_part(replace(array_to_string(defaclacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)),0,0,1) as trigger,
+		decode(charindex('P',split_part(split_part(replace(array_to_string(defaclacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)),0,0,1) as truncate
 	      FROM pg_group gr, pg_default_acl acl
 	      WHERE 
 		acl.defaclnamespace = $1
@@ -265,8 +609,9 @@ func readGroupTableDefaultPrivileges(tx *sql.Tx, d *schema.ResourceData, entityI
 		&tableDrop,
 		&tableReferences,
 		&tableRule,
-		&tableTrigger); err != nil && err != sql.ErrNoRows {
-		return fmt.Errorf("failed to collect privileges: %w", err)
+		&tableTrigger,
+		&tableTruncate); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to collect privileges: %w", err)
 	}
 
 	privileges := []string{}
@@ -278,12 +623,159 @@ func readGroupTableDefaultPrivileges(tx *sql.Tx, d *schema.ResourceData, entityI
 	appendIfTrue(tableReferences, "references", &privileges)
 	appendIfTrue(tableRule, "rule", &privileges)
 	appendIfTrue(tableTrigger, "trigger", &privileges)
+	appendIfTrue(tableTruncate, "truncate", &privileges)
 
 	log.Printf("[DEBUG] Collected privileges for ID %d: %v\n", entityID, privileges)
 
-	d.Set(defaultPrivilegesPrivilegesAttr, privileges)
+	return privileges, nil
+}
 
-	return nil
+// readCallableDefaultPrivileges reads the EXECUTE default privilege on functions or procedures.
+func readCallableDefaultPrivileges(tx *sql.Tx, d *schema.ResourceData, entityID, schemaID, ownerID int, entityIsUser bool) ([]string, error) {
+	var execute bool
+	var query string
+
+	if entityIsUser {
+		query = `
+	      SELECT
+		decode(charindex('X',split_part(split_part(regexp_replace(replace(array_to_string(defaclacl, '|'), '"', ''), 'group '||u.usename), u.usename||'=', 2) ,'/',1)),0,0,1) as execute
+	      FROM pg_user u, pg_default_acl acl
+	      WHERE
+		acl.defaclnamespace = $1
+		AND regexp_replace(replace(array_to_string(acl.defaclacl, '|'), '"', ''), 'group '||u.usename) LIKE '%' || u.usename || '=%'
+		AND u.usesysid = $2
+		AND acl.defaclobjtype = $3
+		AND acl.defacluser = $4
+		`
+	} else {
+		query = `
+	      SELECT
+		decode(charindex('X',split_part(split_part(replace(array_to_string(defaclacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)),0,0,1) as execute
+	      FROM pg_group gr, pg_default_acl acl
+	      WHERE
+		acl.defaclnamespace = $1
+		AND replace(array_to_string(acl.defaclacl, '|'), '"', '') LIKE '%' || 'group ' || gr.groname || '=%'
+		AND gr.grosysid = $2
+		AND acl.defaclobjtype = $3
+		AND acl.defacluser = $4
+		`
+	}
+
+	objectTypeCode := defaultPrivilegesObjectTypesCodes[d.Get(defaultPrivilegesObjectTypeAttr).(string)]
+	if err := tx.QueryRow(query, schemaID, entityID, objectTypeCode, ownerID).Scan(&execute); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to collect privileges: %w", err)
+	}
+
+	privileges := []string{}
+	appendIfTrue(execute, "execute", &privileges)
+
+	log.Printf("[DEBUG] Collected privileges for ID %d: %v\n", entityID, privileges)
+
+	return privileges, nil
+}
+
+// readOverlappingDefaultPrivileges lists the default privileges of the object type given to the grantee
+// by other owners in the same schema or for the whole database, and by the owners of the resource
+// for the whole database when the resource applies to a schema.
+func readOverlappingDefaultPrivileges(tx *sql.Tx, d *schema.ResourceData, resolver *catalog.Resolver, schemaID int, entityIsUser bool) ([]map[string]interface{}, error) {
+	granteeName := d.Get(defaultPrivilegesUserAttr).(string)
+	if !entityIsUser {
+		granteeName = d.Get(defaultPrivilegesGroupAttr).(string)
+	}
+
+	ownerIDs := map[int]bool{}
+	for _, ownerName := range defaultPrivilegesOwners(d) {
+		ownerID, err := resolver.UserID(ownerName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user ID: %w", err)
+		}
+		ownerIDs[ownerID] = true
+	}
+
+	query := `
+	SELECT acl.defacluser, trim(u.usename), acl.defaclnamespace, COALESCE(trim(n.nspname), ''), array_to_string(acl.defaclacl, '|')
+	FROM pg_default_acl acl
+	JOIN pg_user u ON u.usesysid = acl.defacluser
+	LEFT JOIN pg_namespace n ON n.oid = acl.defaclnamespace
+	WHERE acl.defaclobjtype = $1
+		AND acl.defaclnamespace IN ($2, $3)
+	ORDER BY 2, 4`
+	objectTypeCode := defaultPrivilegesObjectTypesCodes[d.Get(defaultPrivilegesObjectTypeAttr).(string)]
+	rows, err := tx.Query(query, objectTypeCode, defaultPrivilegesAllSchemasID, schemaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overlapping := []map[string]interface{}{}
+	for rows.Next() {
+		var ownerID, namespaceID int
+		var ownerName, schemaName, acl string
+		if err := rows.Scan(&ownerID, &ownerName, &namespaceID, &schemaName, &acl); err != nil {
+			return nil, err
+		}
+		if ownerIDs[ownerID] && namespaceID == schemaID {
+			continue
+		}
+
+		privileges := parseDefaultACLPrivileges(acl, granteeName, entityIsUser)
+		if len(privileges) == 0 {
+			continue
+		}
+
+		log.Printf("[WARN] default privileges %v of owner %s in schema %q overlap with the ones of grantee %s", privileges, ownerName, schemaName, granteeName)
+		overlapping = append(overlapping, map[string]interface{}{
+			defaultPrivilegesOwnerAttr:      ownerName,
+			defaultPrivilegesSchemaAttr:     schemaName,
+			defaultPrivilegesPrivilegesAttr: stringsToInterfaces(privileges),
+		})
+	}
+
+	return overlapping, rows.Err()
+}
+
+// parseDefaultACLPrivileges returns the privileges given to the user or group in a `|` separated list
+// of ACL items, e.g. `john=rw/root|"group analysts"=r/root`.
+func parseDefaultACLPrivileges(acl, granteeName string, granteeIsUser bool) []string {
+	privileges := []string{}
+	for _, item := range strings.Split(acl, "|") {
+		item = strings.ReplaceAll(item, `"`, "")
+		if slash := strings.LastIndex(item, "/"); slash >= 0 {
+			item = item[:slash]
+		}
+		equals := strings.LastIndex(item, "=")
+		if equals < 0 {
+			continue
+		}
+		grantee, codes := item[:equals], item[equals+1:]
+
+		if strings.HasPrefix(grantee, "group ") {
+			if granteeIsUser || strings.TrimPrefix(grantee, "group ") != granteeName {
+				continue
+			}
+		} else if !granteeIsUser || grantee != granteeName {
+			continue
+		}
+
+		for _, code := range codes {
+			if privilege, ok := defaultPrivilegesACLCodes[code]; ok {
+				privileges = append(privileges, privilege)
+			}
+		}
+	}
+	sort.Strings(privileges)
+	return privileges
+}
+
+// setDefaultPrivileges stores the privileges observed for each owner, combined by combineObjectsPrivileges,
+// unless they match the configured ones.
+func setDefaultPrivileges(d *schema.ResourceData, observed []*schema.Set) {
+	declared := d.Get(defaultPrivilegesPrivilegesAttr).(*schema.Set)
+	objectType := d.Get(defaultPrivilegesObjectTypeAttr).(string)
+	combined := combineObjectsPrivileges(declared, objectType, observed)
+	if !privilegesMatch(declared, combined, objectType, d.Get(defaultPrivilegesStrictAttr).(bool)) {
+		d.Set(defaultPrivilegesPrivilegesAttr, combined)
+	}
 }
 
 func generateDefaultPrivilegesID(d *schema.ResourceData) string {
@@ -301,7 +793,7 @@ func generateDefaultPrivilegesID(d *schema.ResourceData) string {
 		schemaName = "noschema"
 	}
 
-	ownerName := fmt.Sprintf("on:%s", d.Get(defaultPrivilegesOwnerAttr).(string))
+	ownerName := fmt.Sprintf("on:%s", strings.Join(defaultPrivilegesOwners(d), ","))
 	objectType := fmt.Sprintf("ot:%s", d.Get(defaultPrivilegesObjectTypeAttr).(string))
 
 	return strings.Join([]string{
@@ -309,9 +801,8 @@ func generateDefaultPrivilegesID(d *schema.ResourceData) string {
 	}, "_")
 }
 
-func createAlterDefaultsGrantQuery(d *schema.ResourceData, privileges []string) string {
+func createAlterDefaultsGrantQuery(d *schema.ResourceData, ownerName string, privileges []string) string {
 	schemaName, schemaNameSet := d.GetOk(defaultPrivilegesSchemaAttr)
-	ownerName := d.Get(defaultPrivilegesOwnerAttr).(string)
 	objectType := strings.ToUpper(d.Get(defaultPrivilegesObjectTypeAttr).(string))
 
 	var entityName, toWhomIndicator string
@@ -338,9 +829,12 @@ func createAlterDefaultsGrantQuery(d *schema.ResourceData, privileges []string)
 	)
 }
 
-func createAlterDefaultsRevokeQuery(d *schema.ResourceData) string {
+func createAlterDefaultsRevokeQuery(d *schema.ResourceData, ownerName string) string {
+	return createAlterDefaultsRevokePrivilegesQuery(d, ownerName, []string{"ALL PRIVILEGES"})
+}
+
+func createAlterDefaultsRevokePrivilegesQuery(d *schema.ResourceData, ownerName string, privileges []string) string {
 	schemaName, schemaNameSet := d.GetOk(defaultPrivilegesSchemaAttr)
-	ownerName := d.Get(defaultPrivilegesOwnerAttr).(string)
 	objectType := strings.ToUpper(d.Get(defaultPrivilegesObjectTypeAttr).(string))
 
 	var entityName, fromWhomIndicator string
@@ -358,8 +852,9 @@ func createAlterDefaultsRevokeQuery(d *schema.ResourceData) string {
 	}
 
 	return fmt.Sprintf(
-		"%s REVOKE ALL PRIVILEGES ON %sS FROM %s %s",
+		"%s REVOKE %s ON %sS FROM %s %s",
 		alterQuery,
+		strings.Join(privileges, ","),
 		objectType,
 		fromWhomIndicator,
 		pq.QuoteIdentifier(entityName),
@@ -1,8 +1,10 @@
 package redshift
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -38,14 +40,14 @@ func TestAccRedshiftDefaultPrivileges_Basic(t *testing.T) {
 		  group = redshift_group.group.name
 		  owner = "root"
 		  object_type = "table"
-		  privileges = ["select", "update", "insert", "delete", "drop", "references", "rule", "trigger"]
+		  privileges = ["select", "update", "insert", "delete", "drop", "references", "rule", "trigger", "truncate"]
 		}
 		
 		resource "redshift_default_privileges" "user" {
 		  user = redshift_user.user.name
 		  owner = "root"
 		  object_type = "table"
-		  privileges = ["select", "update", "insert", "delete", "drop", "references", "rule", "trigger"]
+		  privileges = ["select", "update", "insert", "delete", "drop", "references", "rule", "trigger", "truncate"]
 		}
 		`, groupName, userName)
 		resource.Test(t, resource.TestCase{
@@ -59,7 +61,9 @@ func TestAccRedshiftDefaultPrivileges_Basic(t *testing.T) {
 						resource.TestCheckResourceAttr("redshift_default_privileges.group", "id", fmt.Sprintf("gn:%s_noschema_on:root_ot:table", groupName)),
 						resource.TestCheckResourceAttr("redshift_default_privileges.group", "group", groupName),
 						resource.TestCheckResourceAttr("redshift_default_privileges.group", "object_type", "table"),
-						resource.TestCheckResourceAttr("redshift_default_privileges.group", "privileges.#", "8"),
+						resource.TestCheckResourceAttrSet("redshift_default_privileges.group", "owner_id"),
+						resource.TestCheckResourceAttrSet("redshift_default_privileges.group", "grantee_id"),
+						resource.TestCheckResourceAttr("redshift_default_privileges.group", "privileges.#", "9"),
 						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "select"),
 						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "update"),
 						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "insert"),
@@ -68,11 +72,14 @@ func TestAccRedshiftDefaultPrivileges_Basic(t *testing.T) {
 						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "references"),
 						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "rule"),
 						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "trigger"),
+						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "truncate"),
 
 						resource.TestCheckResourceAttr("redshift_default_privileges.user", "id", fmt.Sprintf("un:%s_noschema_on:root_ot:table", userName)),
 						resource.TestCheckResourceAttr("redshift_default_privileges.user", "user", userName),
 						resource.TestCheckResourceAttr("redshift_default_privileges.user", "object_type", "table"),
-						resource.TestCheckResourceAttr("redshift_default_privileges.user", "privileges.#", "8"),
+						resource.TestCheckResourceAttrSet("redshift_default_privileges.user", "owner_id"),
+						resource.TestCheckResourceAttrSet("redshift_default_privileges.user", "grantee_id"),
+						resource.TestCheckResourceAttr("redshift_default_privileges.user", "privileges.#", "9"),
 						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.user", "privileges.*", "select"),
 						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.user", "privileges.*", "update"),
 						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.user", "privileges.*", "insert"),
@@ -81,6 +88,7 @@ func TestAccRedshiftDefaultPrivileges_Basic(t *testing.T) {
 						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.user", "privileges.*", "references"),
 						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.user", "privileges.*", "rule"),
 						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.user", "privileges.*", "trigger"),
+						resource.TestCheckTypeSetElemAttr("redshift_default_privileges.user", "privileges.*", "truncate"),
 					),
 				},
 			},
@@ -88,6 +96,49 @@ func TestAccRedshiftDefaultPrivileges_Basic(t *testing.T) {
 	}
 }
 
+func TestAccRedshiftDefaultPrivileges_Callables(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_default_privileges" "function" {
+  group       = redshift_group.group.name
+  owner       = "root"
+  object_type = "function"
+  privileges  = ["execute"]
+}
+
+resource "redshift_default_privileges" "procedure" {
+  group       = redshift_group.group.name
+  owner       = "root"
+  object_type = "procedure"
+  privileges  = ["all"]
+}
+`, groupName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDefaultPrivilegesDestory(defaultPrivilegesAllSchemasID, 100, "f", groupName),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.function", "id", fmt.Sprintf("gn:%s_noschema_on:root_ot:function", groupName)),
+					resource.TestCheckResourceAttr("redshift_default_privileges.function", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.function", "privileges.*", "execute"),
+
+					resource.TestCheckResourceAttr("redshift_default_privileges.procedure", "id", fmt.Sprintf("gn:%s_noschema_on:root_ot:procedure", groupName)),
+					resource.TestCheckResourceAttr("redshift_default_privileges.procedure", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.procedure", "privileges.*", "all"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftDefaultPrivileges_UpdateToRevoke(t *testing.T) {
 	groupNames := []string{
 		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_"),
@@ -203,6 +254,128 @@ func TestAccRedshiftDefaultPrivileges_UpdateToRevoke(t *testing.T) {
 	}
 }
 
+func TestAccRedshiftDefaultPrivileges_AdoptExisting(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	groupConfig := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+`, groupName)
+	config := groupConfig + `
+resource "redshift_default_privileges" "group" {
+  group = redshift_group.group.name
+  owner = "root"
+  object_type = "table"
+  privileges = ["select", "insert"]
+  adopt_existing = true
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDefaultPrivilegesDestory(defaultPrivilegesAllSchemasID, 100, "r", groupName),
+		Steps: []resource.TestStep{
+			{
+				Config: groupConfig,
+			},
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*Client)
+					db, err := client.Connect()
+					if err != nil {
+						t.Fatalf("could not connect: %v", err)
+					}
+					query := fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER root GRANT SELECT ON TABLES TO GROUP %s", groupName)
+					if _, err := db.Exec(query); err != nil {
+						t.Fatalf("could not set default privileges: %v", err)
+					}
+				},
+				Config:             config,
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "select"),
+				),
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "privileges.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "select"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "insert"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftDefaultPrivileges_MissingSchemaError(t *testing.T) {
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_missing_schema"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_default_privileges" "missing" {
+  group = "public"
+  owner = "root"
+  schema = %[1]q
+  object_type = "table"
+  privileges = ["select"]
+}
+`, schemaName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(fmt.Sprintf("schema \"%s\" does not exist", schemaName)),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftDefaultPrivileges_CreateSchemaIfMissing(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_default_privileges" "group" {
+  group = redshift_group.group.name
+  owner = "root"
+  schema = %[2]q
+  object_type = "table"
+  privileges = ["select"]
+  create_schema_if_missing = true
+}
+`, groupName, schemaName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			// The schema outlives the resource, drop it to clean up.
+			client := testAccProvider.Meta().(*Client)
+			db, err := client.Connect()
+			if err != nil {
+				return err
+			}
+			_, err = db.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s", schemaName))
+			return err
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftSchemaExists(schemaName),
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "schema", schemaName),
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.group", "privileges.*", "select"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftDefaultPrivileges_BothUserGroupError(t *testing.T) {
 	config := `
 resource "redshift_default_privileges" "both" {
@@ -246,6 +419,113 @@ resource "redshift_default_privileges" "none" {
 	})
 }
 
+func TestAccRedshiftDefaultPrivileges_Owners(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	ownerNames := []string{
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_etl_a"), "-", "_"),
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_etl_b"), "-", "_"),
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_etl_c"), "-", "_"),
+	}
+	config := func(owners []string, privileges []string) string {
+		return fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_user" "owners" {
+  for_each = toset(%[2]s)
+
+  name     = each.value
+  password = "TestPassword123"
+}
+
+resource "redshift_default_privileges" "group" {
+  group       = redshift_group.group.name
+  owners      = %[3]s
+  object_type = "table"
+  privileges  = %[4]s
+
+  depends_on = [redshift_user.owners]
+}
+`, groupName, tfArray(ownerNames), tfArray(owners), tfArray(privileges))
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config(ownerNames[:2], []string{"select"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "id", fmt.Sprintf("gn:%s_noschema_on:%s,%s_ot:table", groupName, ownerNames[0], ownerNames[1])),
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "owners.#", "2"),
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "privileges.#", "1"),
+					testAccCheckDefaultPrivilegesOwner(ownerNames[0], groupName, true),
+					testAccCheckDefaultPrivilegesOwner(ownerNames[1], groupName, true),
+					testAccCheckDefaultPrivilegesOwner(ownerNames[2], groupName, false),
+				),
+			},
+			{
+				Config: config(ownerNames[1:], []string{"select", "insert"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "id", fmt.Sprintf("gn:%s_noschema_on:%s,%s_ot:table", groupName, ownerNames[1], ownerNames[2])),
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "owners.#", "2"),
+					resource.TestCheckResourceAttr("redshift_default_privileges.group", "privileges.#", "2"),
+					testAccCheckDefaultPrivilegesOwner(ownerNames[0], groupName, false),
+					testAccCheckDefaultPrivilegesOwner(ownerNames[1], groupName, true),
+					testAccCheckDefaultPrivilegesOwner(ownerNames[2], groupName, true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftDefaultPrivileges_OwnerAndOwnersError(t *testing.T) {
+	config := `
+resource "redshift_default_privileges" "group" {
+  group       = "public"
+  owner       = "root"
+  owners      = ["root"]
+  object_type = "table"
+  privileges  = ["select"]
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile("only one of `owner,owners` can be specified"),
+			},
+		},
+	})
+}
+
+// testAccCheckDefaultPrivilegesOwner checks whether the owner defines default table privileges for the group.
+func testAccCheckDefaultPrivilegesOwner(ownerName, groupName string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		var ownerID int
+		if err := db.QueryRow("SELECT usesysid FROM pg_user WHERE usename = $1", ownerName).Scan(&ownerID); err != nil {
+			return fmt.Errorf("Error reading ID of user %s: %w", ownerName, err)
+		}
+
+		exists, err := checkDefACLExists(client, defaultPrivilegesAllSchemasID, ownerID, "r", groupName)
+		if err != nil {
+			return err
+		}
+		if exists != expected {
+			return fmt.Errorf("Expected default privileges of owner %s for group %s to exist: %t, got %t", ownerName, groupName, expected, exists)
+		}
+		return nil
+	}
+}
+
 func testAccCheckDefaultPrivilegesDestory(schemaID, ownerID int, objectType, groupName string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
 		client := testAccProvider.Meta().(*Client)
@@ -293,3 +573,100 @@ func checkDefACLExists(client *Client, schemaID, ownerID int, objectType, groupN
 
 	return true, nil
 }
+
+func TestParseDefaultACLPrivileges(t *testing.T) {
+	acl := `john=rw/root|group analysts=rP/root|"john.doe@example.com"=aX/root|group "data team"=D*/root`
+	tests := map[string]struct {
+		granteeName   string
+		granteeIsUser bool
+		expected      []string
+	}{
+		"user":                {granteeName: "john", granteeIsUser: true, expected: []string{"select", "update"}},
+		"group":               {granteeName: "analysts", granteeIsUser: false, expected: []string{"select", "truncate"}},
+		"quoted user":         {granteeName: "john.doe@example.com", granteeIsUser: true, expected: []string{"execute", "insert"}},
+		"quoted group":        {granteeName: "data team", granteeIsUser: false, expected: []string{"drop"}},
+		"group named as user": {granteeName: "john", granteeIsUser: false, expected: []string{}},
+		"user named as group": {granteeName: "analysts", granteeIsUser: true, expected: []string{}},
+		"missing grantee":     {granteeName: "jane", granteeIsUser: true, expected: []string{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			privileges := parseDefaultACLPrivileges(acl, tt.granteeName, tt.granteeIsUser)
+			if !reflect.DeepEqual(privileges, tt.expected) {
+				t.Errorf("Expected privileges %v, got %v", tt.expected, privileges)
+			}
+		})
+	}
+}
+
+func TestAccRedshiftDefaultPrivileges_DetectOverlapping(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	ownerName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_etl"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_user" "owner" {
+  name     = %[2]q
+  password = "TestPassword123"
+}
+
+resource "redshift_default_privileges" "root" {
+  group       = redshift_group.group.name
+  owner       = "root"
+  object_type = "table"
+  privileges  = ["select"]
+
+  detect_overlapping_defaults = true
+}
+
+resource "redshift_default_privileges" "etl" {
+  group       = redshift_group.group.name
+  owner       = redshift_user.owner.name
+  object_type = "table"
+  privileges  = ["select", "insert"]
+}
+`, groupName, ownerName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				// The overlapping default privileges of the other owner are found on the next refresh.
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_default_privileges.root", "overlapping_defaults.#", "1"),
+					resource.TestCheckResourceAttr("redshift_default_privileges.root", "overlapping_defaults.0.owner", ownerName),
+					resource.TestCheckResourceAttr("redshift_default_privileges.root", "overlapping_defaults.0.schema", ""),
+					resource.TestCheckResourceAttr("redshift_default_privileges.root", "overlapping_defaults.0.privileges.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.root", "overlapping_defaults.0.privileges.*", "select"),
+					resource.TestCheckTypeSetElemAttr("redshift_default_privileges.root", "overlapping_defaults.0.privileges.*", "insert"),
+					resource.TestCheckResourceAttr("redshift_default_privileges.etl", "overlapping_defaults.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateDefaultPrivilegesDiff(t *testing.T) {
+	config := map[string]interface{}{
+		defaultPrivilegesGroupAttr:      "test_group",
+		defaultPrivilegesOwnerAttr:      "test_owner",
+		defaultPrivilegesObjectTypeAttr: "function",
+		defaultPrivilegesPrivilegesAttr: []interface{}{"select"},
+	}
+	_, err := redshiftDefaultPrivileges().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil)
+	if err == nil || !strings.Contains(err.Error(), "privileges: invalid privileges list") {
+		t.Errorf("expected an error about the privileges of functions, got %v", err)
+	}
+
+	config[defaultPrivilegesPrivilegesAttr] = []interface{}{"execute"}
+	if _, err := redshiftDefaultPrivileges().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+}
@@ -1,24 +1,41 @@
 package redshift
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/brainly/terraform-provider-redshift/redshift/internal/catalog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/lib/pq"
 )
 
 const (
-	grantUserAttr       = "user"
-	grantGroupAttr      = "group"
-	grantSchemaAttr     = "schema"
-	grantObjectTypeAttr = "object_type"
-	grantObjectsAttr    = "objects"
-	grantPrivilegesAttr = "privileges"
+	grantUserAttr             = "user"
+	grantGroupAttr            = "group"
+	grantRoleAttr             = "role"
+	grantUsersAttr            = "users"
+	grantGroupsAttr           = "groups"
+	grantRolesAttr            = "roles"
+	grantSchemaAttr           = "schema"
+	grantDatabaseAttr         = resourceDatabaseAttr
+	grantObjectTypeAttr       = "object_type"
+	grantObjectsAttr          = "objects"
+	grantObjectsExcludeAttr   = "objects_exclude"
+	grantExpandedObjectsAttr  = "expanded_objects"
+	grantStrictPrivilegesAttr = "strict_privileges"
+	grantRevokeUnmanagedAttr  = "revoke_unmanaged"
+	grantWithGrantOptionAttr  = "with_grant_option"
+	grantPrivilegesAttr       = "privileges"
+	grantGranteeNameAttr      = "grantee_name"
+	grantObjectNamesAttr      = "object_names"
+	grantACLFingerprintAttr   = "acl_fingerprint"
 
 	grantToPublicName = "public"
 )
@@ -30,8 +47,12 @@ var grantAllowedObjectTypes = []string{
 	"function",
 	"procedure",
 	"language",
+	"system",
 }
 
+// grantGranteeAttrs are the attributes naming the grantees, exactly one of them has to be set.
+var grantGranteeAttrs = []string{grantUserAttr, grantGroupAttr, grantRoleAttr, grantUsersAttr, grantGroupsAttr, grantRolesAttr}
+
 var grantObjectTypesCodes = map[string][]string{
 	"table":     {"r", "m", "v"},
 	"procedure": {"p"},
@@ -41,36 +62,68 @@ var grantObjectTypesCodes = map[string][]string{
 func redshiftGrant() *schema.Resource {
 	return &schema.Resource{
 		Description: `
-Defines access privileges for users and  groups. Privileges include access options such as being able to read data in tables and views, write data, create tables, and drop tables. Use this command to give specific privileges for a table, database, schema, function, procedure, language, or column.
+Defines access privileges for users, groups and roles. Privileges include access options such as being able to read data in tables and views, write data, create tables, and drop tables. Use this command to give specific privileges for a table, database, schema, function, procedure, language, or column.
+
+Privileges in other databases than the one the provider connects to can be granted by setting ` + "`database`" + `. With the ` + "`database`" + ` object type it is the database the privileges are granted on, e.g. to grant ` + "`usage`" + ` on a database created from a datashare.
+
+System permissions, e.g. ` + "`create model`" + ` or ` + "`access catalog`" + `, can be granted to roles using the ` + "`system`" + ` object type. Only the configured system permissions are managed, other system permissions of the role (e.g. set in ` + "`redshift_role`" + `) are left untouched.
+
+Updates only revoke the privileges which are no longer configured and grant the missing ones, in a single transaction, so privileges which are kept are never revoked.
+
+The same privileges can be granted to several users, groups or roles with a single resource by setting ` + "`users`" + `, ` + "`groups`" + ` or ` + "`roles`" + ` instead of ` + "`user`" + `, ` + "`group`" + ` or ` + "`role`" + `. The privileges are granted and revoked with one statement for all of them, and adding or removing grantees only grants or revokes the privileges of those grantees.
+
+Existing grants can be imported with an ID in the ` + "`<user|group|role>/<name>/<object_type>[/<schema>[/<objects>]]`" + ` format, where ` + "`<objects>`" + ` is a comma separated list, e.g. ` + "`group/analysts/table/myschema/mytable`" + `. Without objects, the grant applies to all objects of the type in the schema. With the ` + "`database`" + ` object type the fourth part is the database, with the ` + "`schema`" + ` object type it is the list of schemas and with the ` + "`language`" + ` object type it is the list of languages. Only grants in the database the provider connects to can be imported.
 `,
-		Read: RedshiftResourceFunc(resourceRedshiftGrantRead),
+		Read: RedshiftResourceReadFunc(redshiftGrantInDatabase(resourceRedshiftGrantRead)),
 		Create: RedshiftResourceFunc(
-			RedshiftResourceRetryOnPQErrors(resourceRedshiftGrantCreate),
+			RedshiftResourceWaitForGrantee(
+				RedshiftResourceRetryOnPQErrors(RedshiftResourceWithStatementTimeout(redshiftGrantInDatabase(resourceRedshiftGrantCreate))),
+				grantGranteeAttrs...,
+			),
 		),
 		Delete: RedshiftResourceFunc(
-			RedshiftResourceRetryOnPQErrors(resourceRedshiftGrantDelete),
+			RedshiftResourceRetryOnPQErrors(RedshiftResourceWithStatementTimeout(redshiftGrantInDatabase(resourceRedshiftGrantDelete))),
 		),
 
-		// Since we revoke all when creating, we can use create as update
 		Update: RedshiftResourceFunc(
-			RedshiftResourceRetryOnPQErrors(resourceRedshiftGrantCreate),
+			RedshiftResourceRetryOnPQErrors(RedshiftResourceWithStatementTimeout(redshiftGrantInDatabase(resourceRedshiftGrantUpdate))),
+		),
+		Importer: &schema.ResourceImporter{
+			State: resourceRedshiftGrantImport,
+		},
+
+		CustomizeDiff: customdiff.All(
+			computedIfAnyChanged(grantObjectNamesAttr, grantObjectsAttr),
+			computedIfAnyChanged(grantExpandedObjectsAttr, grantObjectsExcludeAttr, grantPrivilegesAttr),
+			forceNewIfSetEmptied(grantUsersAttr),
+			forceNewIfSetEmptied(grantGroupsAttr),
+			forceNewIfSetEmptied(grantRolesAttr),
+			validateGrantDiff,
 		),
 
 		Schema: map[string]*schema.Schema{
+			resourceStatementTimeoutAttr: resourceStatementTimeoutSchema("grants on many objects"),
+			resourceWaitForGranteeAttr:   resourceWaitForGranteeSchema(),
+			grantACLFingerprintAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A hash of the access privileges of the objects the grant applies to, as last read. While it is unchanged, refreshing the grant skips reading the privileges in detail.",
+			},
 			grantUserAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ForceNew:     true,
-				ExactlyOneOf: []string{grantUserAttr, grantGroupAttr},
-				Description:  "The name of the user to grant privileges on. Either `user` or `group` parameter must be set.",
-				ValidateFunc: validation.StringDoesNotMatch(regexp.MustCompile("^(?i)public$"), "User name cannot be 'public'. To use GRANT ... TO PUBLIC set the group name to 'public' instead."),
+				ExactlyOneOf: grantGranteeAttrs,
+				Description:  "The name of the user to grant privileges on. Exactly one of `user`, `group`, `role`, `users`, `groups` or `roles` parameters must be set.",
+				ValidateFunc: validation.All(validation.StringDoesNotMatch(regexp.MustCompile("^(?i)public$"), "User name cannot be 'public'. To use GRANT ... TO PUBLIC set the group name to 'public' instead."), validateIdentifier),
 			},
 			grantGroupAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ForceNew:     true,
-				ExactlyOneOf: []string{grantUserAttr, grantGroupAttr},
-				Description:  "The name of the group to grant privileges on. Either `group` or `user` parameter must be set. Settings the group name to `public` or `PUBLIC` (it is case insensitive in this case) will result in a `GRANT ... TO PUBLIC` statement.",
+				ExactlyOneOf: grantGranteeAttrs,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the group to grant privileges on. Exactly one of `user`, `group`, `role`, `users`, `groups` or `roles` parameters must be set. Settings the group name to `public` or `PUBLIC` (it is case insensitive in this case) will result in a `GRANT ... TO PUBLIC` statement.",
 				StateFunc: func(val interface{}) string {
 					name := val.(string)
 					if strings.ToLower(name) == grantToPublicName {
@@ -79,11 +132,60 @@ Defines access privileges for users and  groups. Privileges include access optio
 					return name
 				},
 			},
+			grantRoleAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: grantGranteeAttrs,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the role to grant privileges on. Exactly one of `user`, `group`, `role`, `users`, `groups` or `roles` parameters must be set. The role has to exist.",
+			},
+			grantUsersAttr: {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ExactlyOneOf: grantGranteeAttrs,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.All(validation.StringDoesNotMatch(regexp.MustCompile("^(?i)public$"), "User name cannot be 'public'. To use GRANT ... TO PUBLIC set the group name to 'public' instead."), validateIdentifier),
+				},
+				Set:         schema.HashString,
+				Description: "The names of the users to grant the same privileges to, instead of a single `user`.",
+			},
+			grantGroupsAttr: {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ExactlyOneOf: grantGranteeAttrs,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.All(validation.StringDoesNotMatch(regexp.MustCompile("^(?i)public$"), "Group name cannot be 'public'. To use GRANT ... TO PUBLIC set `group` to 'public' instead."), validateIdentifier),
+				},
+				Set:         schema.HashString,
+				Description: "The names of the groups to grant the same privileges to, instead of a single `group`.",
+			},
+			grantRolesAttr: {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ExactlyOneOf: grantGranteeAttrs,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIdentifier,
+				},
+				Set:         schema.HashString,
+				Description: "The names of the roles to grant the same privileges to, instead of a single `role`. The roles have to exist.",
+			},
 			grantSchemaAttr: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "The database schema to grant privileges on.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The database schema to grant privileges on. With the `schema` object type, several schemas can be listed in `objects` instead.",
+			},
+			grantDatabaseAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				Description:  "The database to grant privileges in, or on when `object_type` is `database`. Defaults to the database the provider connects to. Connections to other databases use the credentials of the provider and are shared by all resources in the same database. Databases created from datashares only support the `usage` privilege.",
 			},
 			grantObjectTypeAttr: {
 				Type:         schema.TypeString,
@@ -96,13 +198,27 @@ Defines access privileges for users and  groups. Privileges include access optio
 				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
-					StateFunc: func(val interface{}) string {
-						return strings.ToLower(val.(string))
-					},
+					Type:         schema.TypeString,
+					ValidateFunc: validateNoControlCharacters,
 				},
+				Set:              schema.HashString,
+				DiffSuppressFunc: suppressGrantObjectsCaseDiff,
+				Description:      "The objects upon which to grant the privileges. An empty list (the default) means to grant permissions on all objects of the specified type. With the `schema` object type, the schemas to grant privileges on, instead of `schema`. Not supported for the `database` object type. Names are case insensitive unless the provider is configured with `case_sensitive_identifiers`.",
+			},
+			grantObjectsExcludeAttr: {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString, ValidateFunc: validateNoControlCharacters},
+				Set:           schema.HashString,
+				ConflictsWith: []string{grantObjectsAttr},
+				Description:   "The objects to leave out when granting on all objects of the specified type. The privileges are then granted on an explicit list of the other objects in the schema, determined on every apply and recorded in `expanded_objects`. Objects created later are reported as drift. Only supported when `object_type` is `table`.",
+			},
+			grantExpandedObjectsAttr: {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
 				Set:         schema.HashString,
-				Description: "The objects upon which to grant the privileges. An empty list (the default) means to grant permissions on all objects of the specified type. Ignored when `object_type` is one of (`database`, `schema`).",
+				Description: "The objects the privileges were granted on when `objects_exclude` is used.",
 			},
 			grantPrivilegesAttr: {
 				Type:     schema.TypeSet,
@@ -112,15 +228,109 @@ Defines access privileges for users and  groups. Privileges include access optio
 					StateFunc: func(val interface{}) string {
 						return strings.ToLower(val.(string))
 					},
+					ValidateDiagFunc: privilegeNoOpWarning,
 				},
 				Set:         schema.HashString,
-				Description: "The list of privileges to apply as default privileges. See [GRANT command documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html) to see what privileges are available to which object type. An empty list could be provided to revoke all privileges for this user or group. Required when `object_type` is set to `language`.",
+				Description: "The list of privileges to apply as default privileges. See [GRANT command documentation](https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html) to see what privileges are available to which object type. An empty list could be provided to revoke all privileges for this user or group. Required when `object_type` is set to `language`. `all` can be used alone to grant all privileges of the object type. Privileges of roles which are not supported by the provider, e.g. introduced by newer Redshift releases, are kept when read: they are considered granted by `all` and otherwise revoked.",
+			},
+			grantStrictPrivilegesAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "By default privileges implied by the configured ones are not reported as a difference, e.g. `privileges = [\"all\"]` matches all the privileges of the object type. Set to `true` to require the privileges read back to be exactly the configured ones.",
+			},
+			grantWithGrantOptionAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether the user can in turn grant the privileges to others (`WITH GRANT OPTION`). Only supported when granting to a `user` or `users`, and not for the `system` object type. The grant option is read back from the privileges of the user, and reported as a difference unless it is set for all the privileges the grant applies to.",
+			},
+			grantRevokeUnmanagedAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "When creating the grant, all privileges of the grantee on the objects are first revoked, so that only the configured privileges remain. Set to `false` to only grant the configured privileges, keeping the privileges managed elsewhere in place, e.g. column privileges or privileges granted `WITH GRANT OPTION`. Privileges which are read back are still compared with the configured ones.",
+			},
+			grantGranteeNameAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The normalized name of the user, group or role the privileges are granted to (`public` for grants to PUBLIC). Referencing it instead of `user`, `group` or `role` orders dependent resources after this grant. Empty when granting to `users`, `groups` or `roles`.",
+			},
+			grantObjectNamesAttr: {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The normalized names of `objects`, the way they are stored in the catalog. Referencing it instead of `objects` orders dependent resources after this grant.",
 			},
 		},
 	}
 }
 
 func resourceRedshiftGrantCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := validateGrant(d); err != nil {
+		return err
+	}
+
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	for _, roleName := range grantRoleNames(d) {
+		if _, err := catalog.NewResolver(tx).RoleID(db.client.normalizeIdentifier(roleName)); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("role %q does not exist", roleName)
+			}
+			return fmt.Errorf("failed to get role ID for role '%s': %w", roleName, err)
+		}
+	}
+
+	expandedObjects := schema.NewSet(schema.HashString, nil)
+	if grantsExpandedObjects(d) {
+		if expandedObjects, err = expandGrantTables(tx, db, d); err != nil {
+			return err
+		}
+	}
+	d.Set(grantExpandedObjectsAttr, expandedObjects)
+
+	if d.Get(grantRevokeUnmanagedAttr).(bool) {
+		if err := revokeGrants(tx, db, d); err != nil {
+			return err
+		}
+	}
+
+	if err := createGrants(tx, db, d); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateGrantID(d))
+	// The next refresh reads the privileges in detail.
+	d.Set(grantACLFingerprintAttr, "")
+
+	return resourceRedshiftGrantReadImpl(db, d)
+}
+
+// validateGrantDiff validates the combination of object type, schema, objects, grantees and privileges at plan time.
+// Configurations with values known only at apply time are validated by validateGrant when they are applied.
+func validateGrantDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, attr := range append([]string{grantObjectTypeAttr, grantSchemaAttr, grantObjectsAttr, grantObjectsExcludeAttr, grantPrivilegesAttr, grantWithGrantOptionAttr}, grantGranteeAttrs...) {
+		if !d.NewValueKnown(attr) {
+			return nil
+		}
+	}
+	return validateGrant(d)
+}
+
+// validateGrant checks the combination of attributes which can't be validated by the schema.
+// It returns an error naming the offending attribute when the attributes of the grant don't fit together.
+func validateGrant(d resourceGetter) error {
 	objectType := d.Get(grantObjectTypeAttr).(string)
 	schemaName := d.Get(grantSchemaAttr).(string)
 	objects := d.Get(grantObjectsAttr).(*schema.Set).List()
@@ -132,19 +342,59 @@ func resourceRedshiftGrantCreate(db *DBConnection, d *schema.ResourceData) error
 
 	// validate parameters
 	if (objectType == "table" || objectType == "function" || objectType == "procedure") && schemaName == "" {
-		return fmt.Errorf("parameter `%s` is required for objects of type table, function and procedure", grantSchemaAttr)
+		return fmt.Errorf("%s: required for objects of type table, function and procedure", grantSchemaAttr)
+	}
+
+	if objectType == "database" && len(objects) > 0 {
+		return fmt.Errorf("%s: cannot be specified when `%s` is `database`", grantObjectsAttr, grantObjectTypeAttr)
 	}
 
-	if (objectType == "database" || objectType == "schema") && len(objects) > 0 {
-		return fmt.Errorf("cannot specify `%s` when `%s` is `database` or `schema`", grantObjectsAttr, grantObjectTypeAttr)
+	if objectType == "schema" && (schemaName == "") == (len(objects) == 0) {
+		return fmt.Errorf("%s: exactly one of `%s` or `%s` is required when `%s` is `schema`", grantSchemaAttr, grantSchemaAttr, grantObjectsAttr, grantObjectTypeAttr)
+	}
+
+	if objectType == "system" {
+		if len(grantRoleNames(d)) == 0 {
+			return fmt.Errorf("%s: system permissions can only be granted to a `%s` or `%s`", grantObjectTypeAttr, grantRoleAttr, grantRolesAttr)
+		}
+		if schemaName != "" {
+			return fmt.Errorf("%s: cannot be specified when `%s` is `system`", grantSchemaAttr, grantObjectTypeAttr)
+		}
+		if len(objects) > 0 {
+			return fmt.Errorf("%s: cannot be specified when `%s` is `system`", grantObjectsAttr, grantObjectTypeAttr)
+		}
+	}
+
+	if d.Get(grantWithGrantOptionAttr).(bool) {
+		_, isUser := d.GetOk(grantUserAttr)
+		if pluralAttr, _ := grantPluralGrantees(d); !isUser && pluralAttr != grantUsersAttr {
+			return fmt.Errorf("%s: only supported when granting to a `%s` or `%s`", grantWithGrantOptionAttr, grantUserAttr, grantUsersAttr)
+		}
+		if objectType == "system" {
+			return fmt.Errorf("%s: not supported when `%s` is `system`", grantWithGrantOptionAttr, grantObjectTypeAttr)
+		}
 	}
 
 	if objectType == "language" && len(objects) == 0 {
-		return fmt.Errorf("parameter `%s` is required for objects of type language", grantObjectsAttr)
+		return fmt.Errorf("%s: required for objects of type language", grantObjectsAttr)
+	}
+
+	if objectType != "table" && grantsExpandedObjects(d) {
+		return fmt.Errorf("%s: only supported for objects of type table", grantObjectsExcludeAttr)
 	}
 
 	if !validatePrivileges(privileges, objectType) {
-		return fmt.Errorf("Invalid privileges list %v for object of type %s", privileges, objectType)
+		return fmt.Errorf("%s: invalid privileges list %v for object of type %s", grantPrivilegesAttr, privileges, objectType)
+	}
+
+	return nil
+}
+
+// resourceRedshiftGrantUpdate applies only the difference to the previous state, so privileges which are kept
+// are never revoked, not even within the transaction.
+func resourceRedshiftGrantUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := validateGrant(d); err != nil {
+		return err
 	}
 
 	tx, err := startTransaction(db.client, "")
@@ -153,11 +403,55 @@ func resourceRedshiftGrantCreate(db *DBConnection, d *schema.ResourceData) error
 	}
 	defer deferredRollback(tx)
 
-	if err := revokeGrants(tx, db.client.databaseName, d); err != nil {
+	oldObjects, newObjects, err := grantObjectsChange(tx, db, d)
+	if err != nil {
 		return err
 	}
+	if grantsExpandedObjects(d) {
+		d.Set(grantExpandedObjectsAttr, newObjects)
+	}
 
-	if err := createGrants(tx, db.client.databaseName, d); err != nil {
+	oldPrivileges, newPrivileges := d.GetChange(grantPrivilegesAttr)
+	queries := grantGranteesDeltaQueries(d, func(g grantee) []string {
+		return createGrantsDeltaQueries(
+			d,
+			g,
+			oldPrivileges.(*schema.Set),
+			newPrivileges.(*schema.Set),
+			oldObjects,
+			newObjects,
+			grantDatabaseName(db, d),
+			db.client.config.CaseSensitiveIdentifiers,
+		)
+	}, func(g grantee) []string {
+		return createGrantsDeltaQueries(
+			d,
+			g,
+			oldPrivileges.(*schema.Set),
+			schema.NewSet(schema.HashString, nil),
+			oldObjects,
+			oldObjects,
+			grantDatabaseName(db, d),
+			db.client.config.CaseSensitiveIdentifiers,
+		)
+	}, func(g grantee) []string {
+		queries := []string{}
+		// The privileges of the added grantees are revoked first as when creating the grant, see revokeGrants.
+		if d.Get(grantRevokeUnmanagedAttr).(bool) && d.Get(grantObjectTypeAttr).(string) != "system" && (newObjects == nil || newObjects.Len() > 0) {
+			queries = append(queries, createGrantsRevokeQueries(d, g, grantDatabaseName(db, d), db.client.config.CaseSensitiveIdentifiers)...)
+		}
+		return append(queries, createGrantsDeltaQueries(
+			d,
+			g,
+			schema.NewSet(schema.HashString, nil),
+			newPrivileges.(*schema.Set),
+			newObjects,
+			newObjects,
+			grantDatabaseName(db, d),
+			db.client.config.CaseSensitiveIdentifiers,
+		)...)
+	})
+	if err := execGrantQueries(tx, queries); err != nil {
 		return err
 	}
 
@@ -167,9 +461,157 @@ func resourceRedshiftGrantCreate(db *DBConnection, d *schema.ResourceData) error
 
 	d.SetId(generateGrantID(d))
 
+	// The next refresh reads the privileges in detail.
+	d.Set(grantACLFingerprintAttr, "")
+
 	return resourceRedshiftGrantReadImpl(db, d)
 }
 
+// grantGranteesDeltaQueries returns the statements of an update for the grantees kept (kept), removed (removed)
+// and added (added) to users, groups or roles, each given only those grantees.
+// Grants to a single user, group or role can't change grantee, so only kept is used for them.
+func grantGranteesDeltaQueries(d *schema.ResourceData, kept, removed, added func(grantee) []string) []string {
+	g := grantGrantees(d)
+	attr, _ := grantPluralGrantees(d)
+	if attr == "" || !d.HasChange(attr) {
+		return kept(g)
+	}
+
+	oldRaw, newRaw := d.GetChange(attr)
+	oldNames, newNames := oldRaw.(*schema.Set), newRaw.(*schema.Set)
+
+	queries := []string{}
+	for _, step := range []struct {
+		names *schema.Set
+		fn    func(grantee) []string
+	}{
+		{oldNames.Intersection(newNames), kept},
+		{oldNames.Difference(newNames), removed},
+		{newNames.Difference(oldNames), added},
+	} {
+		if step.names.Len() == 0 {
+			continue
+		}
+		names := []string{}
+		for _, name := range step.names.List() {
+			names = append(names, name.(string))
+		}
+		sort.Strings(names)
+		queries = append(queries, step.fn(g.withNames(names...))...)
+	}
+	return queries
+}
+
+// grantObjectsChange returns the objects the privileges applied to before and after the update,
+// or nil for all objects of the type.
+func grantObjectsChange(tx *sql.Tx, db *DBConnection, d *schema.ResourceData) (*schema.Set, *schema.Set, error) {
+	switch d.Get(grantObjectTypeAttr).(string) {
+	case "database", "system":
+		return nil, nil, nil
+	}
+
+	oldRaw, newRaw := d.GetChange(grantObjectsAttr)
+	oldObjects, newObjects := oldRaw.(*schema.Set), newRaw.(*schema.Set)
+
+	oldExcludes, _ := d.GetChange(grantObjectsExcludeAttr)
+	if oldExcludes.(*schema.Set).Len() > 0 {
+		oldExpanded, _ := d.GetChange(grantExpandedObjectsAttr)
+		oldObjects = oldExpanded.(*schema.Set)
+	} else if oldObjects.Len() == 0 {
+		oldObjects = nil
+	}
+
+	if grantsExpandedObjects(d) {
+		expanded, err := expandGrantTables(tx, db, d)
+		return oldObjects, expanded, err
+	}
+	if newObjects.Len() == 0 {
+		newObjects = nil
+	}
+	return oldObjects, newObjects, nil
+}
+
+// grantACLFingerprintQueries return the access privileges of all objects of the type the grant may apply to,
+// as stored in the catalog, without parsing them. The schema is passed as $1 where applicable.
+var grantACLFingerprintQueries = map[string]string{
+	"database": `SELECT datname, COALESCE(array_to_string(datacl, '|'), '') FROM pg_database WHERE datname = $1`,
+	"schema":   `SELECT nspname, COALESCE(array_to_string(nspacl, '|'), '') FROM pg_namespace WHERE nspname = ANY($1) ORDER BY nspname`,
+	"table": `
+	SELECT relname, COALESCE(array_to_string(relacl, '|'), '')
+	FROM pg_class cl
+	JOIN pg_namespace nsp ON nsp.oid = cl.relnamespace
+	WHERE nsp.nspname = $1 AND cl.relkind = ANY($2)
+	ORDER BY relname
+`,
+	"function": `
+	SELECT proname, COALESCE(array_to_string(pr.proacl, '|'), '')
+	FROM pg_proc_info pr
+	JOIN pg_namespace nsp ON nsp.oid = pr.pronamespace
+	WHERE nsp.nspname = $1 AND pr.prokind = ANY($2)
+	ORDER BY proname, 2
+`,
+	"language": `SELECT lanname, COALESCE(array_to_string(lanacl, '|'), '') FROM pg_language ORDER BY lanname`,
+}
+
+// grantACLFingerprint hashes the access privileges read by a single cheap query, together with the settings
+// which affect how they are interpreted. It returns an empty string when the grant can't be fingerprinted,
+// e.g. for roles, whose privileges are not stored in the ACLs.
+func grantACLFingerprint(db *DBConnection, d *schema.ResourceData) (string, error) {
+	if len(grantRoleNames(d)) > 0 {
+		return "", nil
+	}
+
+	objectType := d.Get(grantObjectTypeAttr).(string)
+	queryType := objectType
+	if objectType == "procedure" {
+		queryType = "function"
+	}
+	query, ok := grantACLFingerprintQueries[queryType]
+	if !ok {
+		return "", nil
+	}
+
+	var queryArgs []interface{}
+	switch objectType {
+	case "database":
+		queryArgs = []interface{}{grantDatabaseName(db, d)}
+	case "schema":
+		queryArgs = []interface{}{pq.Array(grantSchemaNames(db, d))}
+	case "table", "function", "procedure":
+		queryArgs = []interface{}{d.Get(grantSchemaAttr).(string), pq.Array(grantObjectTypesCodes[objectType])}
+	}
+
+	rows, err := db.Query(query, queryArgs...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	excluded := []string{}
+	for _, name := range normalizedIdentifiers(db, d.Get(grantObjectsExcludeAttr).(*schema.Set)).List() {
+		excluded = append(excluded, name.(string))
+	}
+	sort.Strings(excluded)
+
+	parts := []string{
+		strings.Join(grantGranteeNames(db, d), ","),
+		fmt.Sprintf("strict=%t", d.Get(grantStrictPrivilegesAttr).(bool)),
+		fmt.Sprintf("exclude=%s", strings.Join(excluded, ",")),
+	}
+	for rows.Next() {
+		var name, acl string
+		if err := rows.Scan(&name, &acl); err != nil {
+			return "", err
+		}
+		parts = append(parts, name+"="+acl)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	return fingerprint(parts), nil
+}
+
 func resourceRedshiftGrantDelete(db *DBConnection, d *schema.ResourceData) error {
 	tx, err := startTransaction(db.client, "")
 	if err != nil {
@@ -177,7 +619,7 @@ func resourceRedshiftGrantDelete(db *DBConnection, d *schema.ResourceData) error
 	}
 	defer deferredRollback(tx)
 
-	if err := revokeGrants(tx, db.client.databaseName, d); err != nil {
+	if err := revokeGrants(tx, db, d); err != nil {
 		return err
 	}
 
@@ -189,51 +631,193 @@ func resourceRedshiftGrantDelete(db *DBConnection, d *schema.ResourceData) error
 }
 
 func resourceRedshiftGrantRead(db *DBConnection, d *schema.ResourceData) error {
-	return resourceRedshiftGrantReadImpl(db, d)
+	fingerprint, err := grantACLFingerprint(db, d)
+	if err != nil {
+		return err
+	}
+	if fingerprint != "" && fingerprint == d.Get(grantACLFingerprintAttr).(string) {
+		log.Printf("[DEBUG] access privileges of grant %s are unchanged, skipping the detailed read", d.Id())
+		return nil
+	}
+
+	if err := resourceRedshiftGrantReadImpl(db, d); err != nil {
+		return err
+	}
+
+	d.Set(grantACLFingerprintAttr, fingerprint)
+
+	return nil
 }
 
 func resourceRedshiftGrantReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	d.Set(grantObjectNamesAttr, normalizedGrantObjects(db, d))
+
+	g := grantGrantees(d)
+	if attr, _ := grantPluralGrantees(d); attr == "" {
+		d.Set(grantGranteeNameAttr, grantGranteeName(db, g))
+		return readGranteeGrants(db, d, g)
+	}
+	d.Set(grantGranteeNameAttr, "")
+	return readPluralGranteesGrants(db, d, g)
+}
+
+// readPluralGranteesGrants reads the privileges of each of the users, groups or roles in turn, see readGranteeGrants.
+// The privileges of the first grantee which don't match the configured ones are reported.
+func readPluralGranteesGrants(db *DBConnection, d *schema.ResourceData, g grantee) error {
+	privileges := d.Get(grantPrivilegesAttr).(*schema.Set)
+	withGrantOption := d.Get(grantWithGrantOptionAttr).(bool)
+	for _, name := range g.names {
+		if err := readGranteeGrants(db, d, g.withNames(name)); err != nil {
+			return err
+		}
+		if !d.Get(grantPrivilegesAttr).(*schema.Set).Equal(privileges) || d.Get(grantWithGrantOptionAttr).(bool) != withGrantOption {
+			log.Printf("[DEBUG] privileges of %s %s differ from the configured ones", g.kind, name)
+			return nil
+		}
+	}
+	return nil
+}
+
+// readGranteeGrants reads the privileges of a single user, group or role (g).
+func readGranteeGrants(db *DBConnection, d *schema.ResourceData, g grantee) error {
 	objectType := d.Get(grantObjectTypeAttr).(string)
 
+	// Roles are not listed in the ACLs parsed below, their privileges are read from the svv_*_privileges views.
+	if g.kind == catalog.Role {
+		return readRoleGrants(db, d, g)
+	}
+
+	var err error
 	switch objectType {
 	case "database":
-		return readDatabaseGrants(db, d)
+		err = readDatabaseGrants(db, d, g)
 	case "schema":
-		return readSchemaGrants(db, d)
+		err = readSchemaGrants(db, d, g)
 	case "table":
-		return readTableGrants(db, d)
+		err = readTableGrants(db, d, g)
 	case "function", "procedure":
-		return readCallableGrants(db, d)
+		err = readCallableGrants(db, d, g)
 	case "language":
-		return readLanguageGrants(db, d)
+		err = readLanguageGrants(db, d, g)
 	default:
 		return fmt.Errorf("Unsupported %s %s", grantObjectTypeAttr, objectType)
 	}
+	if err != nil {
+		return err
+	}
+
+	if g.kind == catalog.User {
+		return readGrantOption(db, d, g)
+	}
+	return nil
+}
+
+// grantOptionQueries count the privileges of the user ($1) matching the configured ones ($2),
+// and how many of them were granted without grant option, for each object type.
+// Objects are filtered by database, schema ($3) and names ($4) where applicable, unless all objects ($5) are granted on.
+var grantOptionQueries = map[string]string{
+	"database": `
+	SELECT COUNT(*), SUM(CASE WHEN admin_option THEN 0 ELSE 1 END)
+	FROM svv_database_privileges
+	WHERE identity_type = 'user' AND identity_name = $1 AND lower(privilege_type) = ANY($2) AND database_name = $3
+`,
+	"schema": `
+	SELECT COUNT(*), SUM(CASE WHEN admin_option THEN 0 ELSE 1 END)
+	FROM svv_schema_privileges
+	WHERE identity_type = 'user' AND identity_name = $1 AND lower(privilege_type) = ANY($2) AND namespace_name = ANY($3)
+`,
+	"table": `
+	SELECT COUNT(*), SUM(CASE WHEN admin_option THEN 0 ELSE 1 END)
+	FROM svv_relation_privileges
+	WHERE identity_type = 'user' AND identity_name = $1 AND lower(privilege_type) = ANY($2) AND namespace_name = $3
+		AND ($5 OR relation_name = ANY($4))
+`,
+	"function": `
+	SELECT COUNT(*), SUM(CASE WHEN admin_option THEN 0 ELSE 1 END)
+	FROM svv_function_privileges
+	WHERE identity_type = 'user' AND identity_name = $1 AND lower(privilege_type) = ANY($2) AND namespace_name = $3
+		AND ($5 OR function_name = ANY($4))
+`,
+	"language": `
+	SELECT COUNT(*), SUM(CASE WHEN admin_option THEN 0 ELSE 1 END)
+	FROM svv_language_privileges
+	WHERE identity_type = 'user' AND identity_name = $1 AND lower(privilege_type) = ANY($2) AND language_name = ANY($3)
+`,
+}
+
+// readGrantOption reads whether the configured privileges of the user were granted WITH GRANT OPTION,
+// from the admin_option column of the svv_*_privileges views.
+// The grant option is only reported as missing when some of the privileges were granted without it.
+func readGrantOption(db *DBConnection, d *schema.ResourceData, g grantee) error {
+	objectType := d.Get(grantObjectTypeAttr).(string)
+	privileges := []string{}
+	for _, p := range expandPrivileges(d.Get(grantPrivilegesAttr).(*schema.Set), objectType).List() {
+		privileges = append(privileges, p.(string))
+	}
+
+	queryType := objectType
+	queryArgs := []interface{}{grantGranteeName(db, g), pq.Array(privileges)}
+	switch objectType {
+	case "database":
+		queryArgs = append(queryArgs, grantDatabaseName(db, d))
+	case "schema":
+		queryArgs = append(queryArgs, pq.Array(grantSchemaNames(db, d)))
+	case "language":
+		languages := []string{}
+		for _, language := range d.Get(grantObjectsAttr).(*schema.Set).List() {
+			languages = append(languages, language.(string))
+		}
+		queryArgs = append(queryArgs, pq.Array(languages))
+	case "function", "procedure":
+		queryType = "function"
+		callables := stripArgumentsFromCallablesDefinitions(normalizedGrantObjects(db, d))
+		queryArgs = append(queryArgs, d.Get(grantSchemaAttr).(string), pq.Array(callables), len(callables) == 0)
+	default:
+		objects := []string{}
+		for _, object := range grantTargetObjects(d).List() {
+			objects = append(objects, db.client.normalizeIdentifier(object.(string)))
+		}
+		queryArgs = append(queryArgs, d.Get(grantSchemaAttr).(string), pq.Array(objects), len(objects) == 0)
+	}
+
+	var total int
+	var withoutOption sql.NullInt64
+	if err := db.QueryRow(grantOptionQueries[queryType], queryArgs...).Scan(&total, &withoutOption); err != nil {
+		return fmt.Errorf("Error reading the grant option: %w", err)
+	}
+	if total > 0 {
+		d.Set(grantWithGrantOptionAttr, withoutOption.Int64 == 0)
+	}
+
+	return nil
 }
 
-func readDatabaseGrants(db *DBConnection, d *schema.ResourceData) error {
+func readDatabaseGrants(db *DBConnection, d *schema.ResourceData, g grantee) error {
 	var entityName, query string
-	var databaseCreate, databaseTemp bool
+	var databaseCreate, databaseTemp, databaseUsage bool
+	databaseName := grantDatabaseName(db, d)
 
-	_, isUser := d.GetOk(grantUserAttr)
+	isUser := g.kind == catalog.User
 
 	if isUser {
-		entityName = d.Get(grantUserAttr).(string)
+		entityName = g.names[0]
 		query = `
   SELECT
     decode(charindex('C',split_part(split_part(regexp_replace(replace(array_to_string(db.datacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0,0,1) as create,
-    decode(charindex('T',split_part(split_part(regexp_replace(replace(array_to_string(db.datacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0,0,1) as temporary
+    decode(charindex('T',split_part(split_part(regexp_replace(replace(array_to_string(db.datacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0,0,1) as temporary,
+    decode(charindex('U',split_part(split_part(regexp_replace(replace(array_to_string(db.datacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0,0,1) as usage
   FROM pg_database db, pg_user u
   WHERE
     db.datname=$1 
     AND u.usename=$2
 `
 	} else {
-		entityName = d.Get(grantGroupAttr).(string)
+		entityName = g.names[0]
 		query = `
   SELECT
     decode(charindex('C',split_part(split_part(replace(array_to_string(db.datacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)), 0,0,1) as create,
-    decode(charindex('T',split_part(split_part(replace(array_to_string(db.datacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)), 0,0,1) as temporary
+    decode(charindex('T',split_part(split_part(replace(array_to_string(db.datacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)), 0,0,1) as temporary,
+    decode(charindex('U',split_part(split_part(replace(array_to_string(db.datacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)), 0,0,1) as usage
   FROM pg_database db, pg_group gr
   WHERE
     db.datname=$1 
@@ -241,104 +825,148 @@ func readDatabaseGrants(db *DBConnection, d *schema.ResourceData) error {
 `
 	}
 
-	queryArgs := []interface{}{db.client.databaseName, entityName}
+	queryArgs := []interface{}{databaseName, entityName}
 
 	// Handle GRANT TO PUBLIC
-	if isGrantToPublic(d) {
+	if g.isPublic() {
 		query = `
   SELECT
     decode(charindex('C',split_part(split_part(regexp_replace(replace(array_to_string(db.datacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0,0,1) as create,
-    decode(charindex('T',split_part(split_part(regexp_replace(replace(array_to_string(db.datacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0,0,1) as temporary
+    decode(charindex('T',split_part(split_part(regexp_replace(replace(array_to_string(db.datacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0,0,1) as temporary,
+    decode(charindex('U',split_part(split_part(regexp_replace(replace(array_to_string(db.datacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0,0,1) as usage
   FROM pg_database db
   WHERE
     db.datname=$1 
 `
-		queryArgs = []interface{}{db.client.databaseName}
+		queryArgs = []interface{}{databaseName}
 	}
 
-	if err := db.QueryRow(query, queryArgs...).Scan(&databaseCreate, &databaseTemp); err != nil {
+	if err := db.QueryRow(query, queryArgs...).Scan(&databaseCreate, &databaseTemp, &databaseUsage); err != nil {
 		return err
 	}
 
 	privileges := []string{}
 	appendIfTrue(databaseCreate, "create", &privileges)
 	appendIfTrue(databaseTemp, "temporary", &privileges)
+	appendIfTrue(databaseUsage, "usage", &privileges)
 
-	log.Printf("[DEBUG] Collected database '%s' privileges for %s: %v", db.client.databaseName, entityName, privileges)
+	log.Printf("[DEBUG] Collected database '%s' privileges for %s: %v", databaseName, entityName, privileges)
 
-	d.Set(grantPrivilegesAttr, privileges)
+	setGrantPrivileges(d, privileges)
 
 	return nil
 }
 
-func readSchemaGrants(db *DBConnection, d *schema.ResourceData) error {
+func readSchemaGrants(db *DBConnection, d *schema.ResourceData, g grantee) error {
 	var entityName, query string
-	var schemaCreate, schemaUsage bool
 
-	_, isUser := d.GetOk(grantUserAttr)
-	schemaName := d.Get(grantSchemaAttr).(string)
+	isUser := g.kind == catalog.User
+	schemaNames := grantSchemaNames(db, d)
 
 	if isUser {
-		entityName = d.Get(grantUserAttr).(string)
+		entityName = g.names[0]
 		query = `
 	SELECT
+		ns.nspname,
 		decode(charindex('C',split_part(split_part(regexp_replace(replace(array_to_string(ns.nspacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0,0,1) as create,
 		decode(charindex('U',split_part(split_part(regexp_replace(replace(array_to_string(ns.nspacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0,0,1) as usage
 	FROM pg_namespace ns, pg_user u
 	WHERE
-		ns.nspname=$1 
+		ns.nspname = ANY($1)
 		AND u.usename=$2
 	`
 	} else {
-		entityName = d.Get(grantGroupAttr).(string)
+		entityName = g.names[0]
 		query = `
   SELECT
+    ns.nspname,
     decode(charindex('C',split_part(split_part(replace(array_to_string(ns.nspacl, '|'), '"', ''),'group ' || gr.groname || '=',2 ) ,'/',1)), 0,0,1) as create,
     decode(charindex('U',split_part(split_part(replace(array_to_string(ns.nspacl, '|'), '"', ''),'group ' || gr.groname || '=',2 ) ,'/',1)), 0,0,1) as usage
   FROM pg_namespace ns, pg_group gr
   WHERE
-    ns.nspname=$1 
+    ns.nspname = ANY($1)
     AND gr.groname=$2
 `
 	}
 
-	queryArgs := []interface{}{schemaName, entityName}
+	queryArgs := []interface{}{pq.Array(schemaNames), entityName}
 
 	// Handle GRANT TO PUBLIC
-	if isGrantToPublic(d) {
+	if g.isPublic() {
 		query = `
 			SELECT
+				ns.nspname,
 				decode(charindex('C',split_part(split_part(regexp_replace(replace(array_to_string(ns.nspacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0,0,1) as create,
 				decode(charindex('U',split_part(split_part(regexp_replace(replace(array_to_string(ns.nspacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0,0,1) as usage
 			FROM pg_namespace ns
 			WHERE
-				ns.nspname=$1
+				ns.nspname = ANY($1)
 			`
-		queryArgs = []interface{}{schemaName}
+		queryArgs = []interface{}{pq.Array(schemaNames)}
 	}
 
-	if err := db.QueryRow(query, queryArgs...).Scan(&schemaCreate, &schemaUsage); err != nil {
+	rows, err := db.Query(query, queryArgs...)
+	if err != nil {
 		return err
 	}
+	defer rows.Close()
 
-	privileges := []string{}
-	appendIfTrue(schemaCreate, "create", &privileges)
-	appendIfTrue(schemaUsage, "usage", &privileges)
+	privilegesBySchema := map[string]*schema.Set{}
+	for rows.Next() {
+		var schemaName string
+		var schemaCreate, schemaUsage bool
+		if err := rows.Scan(&schemaName, &schemaCreate, &schemaUsage); err != nil {
+			return err
+		}
 
-	log.Printf("[DEBUG] Collected schema '%s' privileges for %s: %v", schemaName, entityName, privileges)
+		privileges := []string{}
+		appendIfTrue(schemaCreate, "create", &privileges)
+		appendIfTrue(schemaUsage, "usage", &privileges)
+		privilegesBySchema[schemaName] = schema.NewSet(schema.HashString, stringsToInterfaces(privileges))
+
+		log.Printf("[DEBUG] Collected schema '%s' privileges for %s: %v", schemaName, entityName, privileges)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
 
-	d.Set(grantPrivilegesAttr, privileges)
+	// Missing schemas are read as having no privileges.
+	observed := []*schema.Set{}
+	for _, schemaName := range schemaNames {
+		privileges, ok := privilegesBySchema[schemaName]
+		if !ok {
+			privileges = schema.NewSet(schema.HashString, nil)
+		}
+		observed = append(observed, privileges)
+	}
+	setGrantObjectsPrivileges(d, observed)
 
 	return nil
 }
 
-func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
+// grantSchemaNames returns the schemas the privileges are granted on when object_type is schema:
+// the configured objects, or otherwise the configured schema.
+func grantSchemaNames(db *DBConnection, d *schema.ResourceData) []string {
+	objects := normalizedGrantObjects(db, d)
+	if objects.Len() == 0 {
+		return []string{d.Get(grantSchemaAttr).(string)}
+	}
+
+	names := []string{}
+	for _, name := range objects.List() {
+		names = append(names, name.(string))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readTableGrants(db *DBConnection, d *schema.ResourceData, g grantee) error {
 	log.Printf("[DEBUG] Reading table grants")
 	var entityName, query string
-	_, isUser := d.GetOk(grantUserAttr)
+	isUser := g.kind == catalog.User
 
 	if isUser {
-		entityName = d.Get(grantUserAttr).(string)
+		entityName = g.names[0]
 		query = `
   SELECT
     relname,
@@ -349,7 +977,8 @@ func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
     decode(charindex('D',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),null,0,0,0,1) as drop,
     decode(charindex('x',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),null,0,0,0,1) as references,
     decode(charindex('R',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),null,0,0,0,1) as rule,
-    decode(charindex('t',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),null,0,0,0,1) as trigger
+    decode(charindex('t',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),null,0,0,0,1) as trigger,
+    decode(charindex('P',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'group '||u.usename), u.usename||'=', 2) ,'/',1)),null,0,0,0,1) as truncate
   FROM pg_user u, pg_class cl
   JOIN pg_namespace nsp ON nsp.oid = cl.relnamespace
   WHERE
@@ -358,7 +987,7 @@ func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
     AND nsp.nspname=$3
 `
 	} else {
-		entityName = d.Get(grantGroupAttr).(string)
+		entityName = g.names[0]
 		query = `
   SELECT
     relname,
@@ -369,7 +998,8 @@ func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
     decode(charindex('D',split_part(split_part(replace(array_to_string(relacl, '|'), '"', ''),'group ' || gr.groname || '=',2 ) ,'/',1)), null,0, 0,0, 1) as drop,
     decode(charindex('x',split_part(split_part(replace(array_to_string(relacl, '|'), '"', ''),'group ' || gr.groname || '=',2 ) ,'/',1)), null,0, 0,0, 1) as references,
     decode(charindex('R',split_part(split_part(replace(array_to_string(relacl, '|'), '"', ''),'group ' || gr.groname || '=',2 ) ,'/',1)), null,0, 0,0, 1) as rule,
-    decode(charindex('t',split_part(split_part(replace(array_to_string(relacl, '|'), '"', ''),'group ' || gr.groname || '=',2 ) ,'/',1)), null,0, 0,0, 1) as trigger
+    decode(charindex('t',split_part(split_part(replace(array_to_string(relacl, '|'), '"', ''),'group ' || gr.groname || '=',2 ) ,'/',1)), null,0, 0,0, 1) as trigger,
+    decode(charindex('P',split_part(split_part(replace(array_to_string(relacl, '|'), '"', ''),'group ' || gr.groname || '=',2 ) ,'/',1)), null,0, 0,0, 1) as truncate
   FROM pg_group gr, pg_class cl
   JOIN pg_namespace nsp ON nsp.oid = cl.relnamespace
   WHERE
@@ -380,12 +1010,13 @@ func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
 	}
 
 	schemaName := d.Get(grantSchemaAttr).(string)
-	objects := d.Get(grantObjectsAttr).(*schema.Set)
+	objects := normalizedGrantObjects(db, d)
+	excluded := normalizedIdentifiers(db, d.Get(grantObjectsExcludeAttr).(*schema.Set))
 	queryArgs := []interface{}{
 		pq.Array(grantObjectTypesCodes["table"]), entityName, schemaName,
 	}
 
-	if isGrantToPublic(d) {
+	if g.isPublic() {
 		query = `
 		SELECT
 		  relname,
@@ -396,7 +1027,8 @@ func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
 		  decode(charindex('D',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)),null,0,0,0,1) as drop,
 		  decode(charindex('x',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)),null,0,0,0,1) as references,
 		  decode(charindex('R',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)),null,0,0,0,1) as rule,
-		  decode(charindex('t',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)),null,0,0,0,1) as trigger
+		  decode(charindex('t',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)),null,0,0,0,1) as trigger,
+		  decode(charindex('P',split_part(split_part(regexp_replace(replace(array_to_string(relacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)),null,0,0,0,1) as truncate
 		FROM pg_class cl
 		JOIN pg_namespace nsp ON nsp.oid = cl.relnamespace
 		WHERE
@@ -414,17 +1046,24 @@ func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
 	}
 	defer rows.Close()
 
+	// The privileges of each table are collected, so that missing privileges on any of them are reported.
+	seen := schema.NewSet(schema.HashString, nil)
+	observed := []*schema.Set{}
 	for rows.Next() {
 		var objName string
-		var tableSelect, tableUpdate, tableInsert, tableDelete, tableDrop, tableReferences, tableRule, tableTrigger bool
+		var tableSelect, tableUpdate, tableInsert, tableDelete, tableDrop, tableReferences, tableRule, tableTrigger, tableTruncate bool
 
-		if err := rows.Scan(&objName, &tableSelect, &tableUpdate, &tableInsert, &tableDelete, &tableDrop, &tableReferences, &tableRule, &tableTrigger); err != nil {
+		if err := rows.Scan(&objName, &tableSelect, &tableUpdate, &tableInsert, &tableDelete, &tableDrop, &tableReferences, &tableRule, &tableTrigger, &tableTruncate); err != nil {
 			return err
 		}
 
 		if objects.Len() > 0 && !objects.Contains(objName) {
 			continue
 		}
+		if excluded.Contains(objName) {
+			continue
+		}
+		seen.Add(objName)
 
 		privilegesSet := schema.NewSet(schema.HashString, nil)
 		if tableSelect {
@@ -451,32 +1090,42 @@ func readTableGrants(db *DBConnection, d *schema.ResourceData) error {
 		if tableTrigger {
 			privilegesSet.Add("trigger")
 		}
-
-		if !privilegesSet.Equal(d.Get(grantPrivilegesAttr).(*schema.Set)) {
-			d.Set(grantPrivilegesAttr, privilegesSet)
-			break
+		if tableTruncate {
+			privilegesSet.Add("truncate")
 		}
 
+		observed = append(observed, privilegesSet)
+
 		log.Printf("[DEBUG] Collected table grants; table: '%v'; privileges: %v; for: %s", objName, privilegesSet.List(), entityName)
 	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Listed tables which don't exist (anymore) have no privileges.
+	if missing := objects.Difference(seen); missing.Len() > 0 {
+		log.Printf("[DEBUG] Tables %v of the grant for %s were not found", missing.List(), entityName)
+		observed = append(observed, schema.NewSet(schema.HashString, nil))
+	}
+	setGrantObjectsPrivileges(d, observed)
 
 	return nil
 }
 
-func readCallableGrants(db *DBConnection, d *schema.ResourceData) error {
+func readCallableGrants(db *DBConnection, d *schema.ResourceData, g grantee) error {
 	log.Printf("[DEBUG] Reading callable grants")
 
 	var entityName, query string
 
-	_, isUser := d.GetOk(grantUserAttr)
+	isUser := g.kind == catalog.User
 	schemaName := d.Get(grantSchemaAttr).(string)
 	objectType := d.Get(grantObjectTypeAttr).(string)
 
 	if isUser {
-		entityName = d.Get(grantUserAttr).(string)
+		entityName = g.names[0]
 		query = `
 	SELECT
-		proname,
+		proname || '(' || oidvectortypes(pr.proargtypes) || ')',
 		decode(nvl(charindex('X',split_part(split_part(regexp_replace(replace(array_to_string(pr.proacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0), 0,0,1) as execute
 	FROM pg_proc_info pr
 		JOIN pg_namespace nsp ON nsp.oid = pr.pronamespace,
@@ -487,10 +1136,10 @@ func readCallableGrants(db *DBConnection, d *schema.ResourceData) error {
 		AND pr.prokind=ANY($3)
 `
 	} else {
-		entityName = d.Get(grantGroupAttr).(string)
+		entityName = g.names[0]
 		query = `
 	SELECT
-		proname,
+		proname || '(' || oidvectortypes(pr.proargtypes) || ')',
 		decode(nvl(charindex('X',split_part(split_part(replace(array_to_string(pr.proacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)), 0), 0,0,1) as execute
 	FROM pg_proc_info pr
 		JOIN pg_namespace nsp ON nsp.oid = pr.pronamespace,
@@ -502,15 +1151,15 @@ func readCallableGrants(db *DBConnection, d *schema.ResourceData) error {
 `
 	}
 
-	callables := stripArgumentsFromCallablesDefinitions(d.Get(grantObjectsAttr).(*schema.Set))
+	callables := callableSignatures(normalizedGrantObjects(db, d))
 	queryArgs := []interface{}{
 		schemaName, entityName, pq.Array(grantObjectTypesCodes[objectType]),
 	}
 
-	if isGrantToPublic(d) {
+	if g.isPublic() {
 		query = `
 	SELECT
-		proname,
+		proname || '(' || oidvectortypes(pr.proargtypes) || ')',
 		decode(nvl(charindex('X',split_part(split_part(regexp_replace(replace(array_to_string(pr.proacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0), 0,0,1) as execute
 	FROM pg_proc_info pr
 		JOIN pg_namespace nsp ON nsp.oid = pr.pronamespace
@@ -527,35 +1176,36 @@ func readCallableGrants(db *DBConnection, d *schema.ResourceData) error {
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
 
-	contains := func(callables []string, objName string) bool {
-		for _, callable := range callables {
-			if callable == objName {
-				return true
-			}
-		}
-		return false
-	}
-	defer rows.Close()
-
-	privilegesSet := schema.NewSet(schema.HashString, nil)
+	// Execute status per signature, so that overloaded callables sharing the name are told apart.
+	executeBySignature := map[string]bool{}
 	for rows.Next() {
-		var objName string
+		var signature string
 		var callableExecute bool
 
-		if err := rows.Scan(&objName, &callableExecute); err != nil {
+		if err := rows.Scan(&signature, &callableExecute); err != nil {
 			return err
 		}
-		if len(callables) > 0 && !contains(callables, objName) {
-			continue
-		}
+		executeBySignature[signature] = callableExecute
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
 
-		if callableExecute {
-			privilegesSet.Add("execute")
+	// Without explicit objects the grant covers all callables in the schema.
+	if len(callables) == 0 {
+		for signature := range executeBySignature {
+			callables = append(callables, signature)
 		}
 	}
 
-	if !privilegesSet.Equal(d.Get(grantPrivilegesAttr).(*schema.Set)) {
+	privilegesSet := schema.NewSet(schema.HashString, nil)
+	if allCallablesExecutable(callables, executeBySignature) {
+		privilegesSet.Add("execute")
+	}
+
+	if !grantPrivilegesMatch(d, privilegesSet) {
 		d.Set(grantPrivilegesAttr, privilegesSet)
 	}
 	log.Printf("[DEBUG] Reading callable grants - Done")
@@ -563,29 +1213,46 @@ func readCallableGrants(db *DBConnection, d *schema.ResourceData) error {
 	return nil
 }
 
-func readLanguageGrants(db *DBConnection, d *schema.ResourceData) error {
+// allCallablesExecutable reports whether every callable has the execute privilege.
+// Callables are signatures as returned by callableSignature.
+// Callables missing from executeBySignature (e.g. dropped ones) are reported as not executable.
+func allCallablesExecutable(callables []string, executeBySignature map[string]bool) bool {
+	if len(callables) == 0 {
+		return false
+	}
+	for _, callable := range callables {
+		if !executeBySignature[callable] {
+			return false
+		}
+	}
+	return true
+}
+
+func readLanguageGrants(db *DBConnection, d *schema.ResourceData, g grantee) error {
 	log.Printf("[DEBUG] Reading language grants")
 
 	var entityName, query string
 
-	_, isUser := d.GetOk(grantUserAttr)
+	isUser := g.kind == catalog.User
 
 	if isUser {
-		entityName = d.Get(grantUserAttr).(string)
+		entityName = g.names[0]
 		query = `
   SELECT
 		lanname,
-    decode(nvl(charindex('U',split_part(split_part(regexp_replace(replace(array_to_string(lg.lanacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0), 0,0,1) as usage
+    decode(nvl(charindex('U',split_part(split_part(regexp_replace(replace(array_to_string(lg.lanacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0), 0,0,1) as usage,
+    decode(nvl(charindex('C',split_part(split_part(regexp_replace(replace(array_to_string(lg.lanacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0), 0,0,1) as create
   FROM pg_language lg, pg_user u
   WHERE
     u.usename=$1
 `
 	} else {
-		entityName = d.Get(grantGroupAttr).(string)
+		entityName = g.names[0]
 		query = `
   SELECT
 		lanname,
-    decode(nvl(charindex('U',split_part(split_part(replace(array_to_string(lg.lanacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)), 0), 0,0,1) as usage
+    decode(nvl(charindex('U',split_part(split_part(replace(array_to_string(lg.lanacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)), 0), 0,0,1) as usage,
+    decode(nvl(charindex('C',split_part(split_part(replace(array_to_string(lg.lanacl, '|'), '"', ''),'group ' || gr.groname,2 ) ,'/',1)), 0), 0,0,1) as create
   FROM pg_language lg, pg_group gr
   WHERE
     gr.groname=$1
@@ -595,11 +1262,12 @@ func readLanguageGrants(db *DBConnection, d *schema.ResourceData) error {
 	queryArgs := []interface{}{entityName}
 
 	// Handle GRANT TO PUBLIC
-	if isGrantToPublic(d) {
+	if g.isPublic() {
 		query = `
 		SELECT
 			  lanname,
-		  decode(nvl(charindex('U',split_part(split_part(regexp_replace(replace(array_to_string(lg.lanacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0), 0,0,1) as usage
+		  decode(nvl(charindex('U',split_part(split_part(regexp_replace(replace(array_to_string(lg.lanacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0), 0,0,1) as usage,
+		  decode(nvl(charindex('C',split_part(split_part(regexp_replace(replace(array_to_string(lg.lanacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0), 0,0,1) as create
 		FROM pg_language lg
 	  `
 		queryArgs = []interface{}{}
@@ -610,14 +1278,15 @@ func readLanguageGrants(db *DBConnection, d *schema.ResourceData) error {
 		return err
 	}
 
-	objects := d.Get(grantObjectsAttr).(*schema.Set)
+	objects := normalizedGrantObjects(db, d)
 	defer rows.Close()
 
+	observed := []*schema.Set{}
 	for rows.Next() {
 		var objName string
-		var languageUsage bool
+		var languageUsage, languageCreate bool
 
-		if err := rows.Scan(&objName, &languageUsage); err != nil {
+		if err := rows.Scan(&objName, &languageUsage, &languageCreate); err != nil {
 			return err
 		}
 
@@ -629,199 +1298,666 @@ func readLanguageGrants(db *DBConnection, d *schema.ResourceData) error {
 		if languageUsage {
 			privilegesSet.Add("usage")
 		}
-
-		if !privilegesSet.Equal(d.Get(grantPrivilegesAttr).(*schema.Set)) {
-			d.Set(grantPrivilegesAttr, privilegesSet)
-			break
+		if languageCreate {
+			privilegesSet.Add("create")
 		}
+
+		observed = append(observed, privilegesSet)
 	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	setGrantObjectsPrivileges(d, observed)
 	log.Printf("[DEBUG] Reading language grants - Done")
 
 	return nil
 }
 
-func revokeGrants(tx *sql.Tx, databaseName string, d *schema.ResourceData) error {
-	query := createGrantsRevokeQuery(d, databaseName)
-	_, err := tx.Exec(query)
-	return err
+// roleGrantsQueries list the objects and privileges granted to the role ($1) for each object type.
+// Objects are filtered by schema ($2) where applicable.
+var roleGrantsQueries = map[string]string{
+	"database": `
+	SELECT database_name, lower(privilege_type)
+	FROM svv_database_privileges
+	WHERE identity_type = 'role' AND identity_name = $1 AND database_name = $2
+`,
+	"schema": `
+	SELECT namespace_name, lower(privilege_type)
+	FROM svv_schema_privileges
+	WHERE identity_type = 'role' AND identity_name = $1 AND namespace_name = ANY($2)
+`,
+	"table": `
+	SELECT relation_name, lower(privilege_type)
+	FROM svv_relation_privileges
+	WHERE identity_type = 'role' AND identity_name = $1 AND namespace_name = $2
+`,
+	"function": `
+	SELECT function_name, lower(privilege_type)
+	FROM svv_function_privileges
+	WHERE identity_type = 'role' AND identity_name = $1 AND namespace_name = $2
+`,
+	"language": `
+	SELECT language_name, lower(privilege_type)
+	FROM svv_language_privileges
+	WHERE identity_type = 'role' AND identity_name = $1
+`,
+}
+
+func readRoleGrants(db *DBConnection, d *schema.ResourceData, g grantee) error {
+	objectType := d.Get(grantObjectTypeAttr).(string)
+	roleName := grantGranteeName(db, g)
+	log.Printf("[DEBUG] Reading %s grants of role %s", objectType, roleName)
+
+	if objectType == "system" {
+		return readRoleSystemPrivileges(db, d, roleName)
+	}
+
+	queryType := objectType
+	if objectType == "procedure" {
+		queryType = "function"
+	}
+	queryArgs := []interface{}{roleName}
+	switch objectType {
+	case "database":
+		queryArgs = append(queryArgs, grantDatabaseName(db, d))
+	case "schema":
+		queryArgs = append(queryArgs, pq.Array(grantSchemaNames(db, d)))
+	case "language":
+	default:
+		queryArgs = append(queryArgs, d.Get(grantSchemaAttr).(string))
+	}
+
+	rows, err := db.Query(roleGrantsQueries[queryType], queryArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	privilegesByObject := map[string]*schema.Set{}
+	for rows.Next() {
+		var objName, privilege string
+		if err := rows.Scan(&objName, &privilege); err != nil {
+			return err
+		}
+		if privilege == "temp" {
+			privilege = "temporary"
+		}
+		// Privileges unknown to the provider (e.g. alter on tables) are kept,
+		// so that they show up as a difference instead of being silently left in place.
+		if !isKnownPrivilege(privilege, objectType) {
+			log.Printf("[WARN] %s %s of role %s has the %q privilege, which is not supported by the provider and is reported as a difference unless %q is granted", objectType, objName, roleName, privilege, privilegeAll)
+		}
+		if privilegesByObject[objName] == nil {
+			privilegesByObject[objName] = schema.NewSet(schema.HashString, nil)
+		}
+		privilegesByObject[objName].Add(privilege)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return setRoleGrantPrivileges(db, d, privilegesByObject)
+}
+
+// readRoleSystemPrivileges reads the system permissions of the role.
+// Permissions which are not configured are ignored, as they may be managed elsewhere, e.g. in redshift_role.
+func readRoleSystemPrivileges(db *DBConnection, d *schema.ResourceData, roleName string) error {
+	rows, err := db.Query("SELECT lower(system_privilege) FROM svv_system_privileges WHERE identity_type = 'role' AND identity_name = $1", roleName)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	managed := expandPrivileges(d.Get(grantPrivilegesAttr).(*schema.Set), "system")
+	privileges := []string{}
+	for rows.Next() {
+		var privilege string
+		if err := rows.Scan(&privilege); err != nil {
+			return err
+		}
+		if managed.Contains(privilege) {
+			privileges = append(privileges, privilege)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Collected system privileges for role %s: %v", roleName, privileges)
+
+	setGrantPrivileges(d, privileges)
+
+	return nil
 }
 
-func createGrants(tx *sql.Tx, databaseName string, d *schema.ResourceData) error {
+// setRoleGrantPrivileges combines the privileges of every object the grant applies to, like the ACL based reads do.
+func setRoleGrantPrivileges(db *DBConnection, d *schema.ResourceData, privilegesByObject map[string]*schema.Set) error {
+	objectType := d.Get(grantObjectTypeAttr).(string)
+
+	var objects []string
+	switch objectType {
+	case "database":
+		objects = []string{grantDatabaseName(db, d)}
+	case "schema":
+		objects = grantSchemaNames(db, d)
+	case "function", "procedure":
+		objects = stripArgumentsFromCallablesDefinitions(normalizedGrantObjects(db, d))
+	default:
+		for _, object := range normalizedGrantObjects(db, d).List() {
+			objects = append(objects, object.(string))
+		}
+	}
+
+	// Without explicit objects the grant covers all objects of the type in the schema.
+	if len(objects) == 0 {
+		all, err := schemaObjectNames(db, d.Get(grantSchemaAttr).(string), objectType)
+		if err != nil {
+			return err
+		}
+		excluded := normalizedIdentifiers(db, d.Get(grantObjectsExcludeAttr).(*schema.Set))
+		for _, objName := range all {
+			if !excluded.Contains(objName) {
+				objects = append(objects, objName)
+			}
+		}
+		if len(objects) == 0 {
+			setGrantPrivileges(d, []string{})
+			return nil
+		}
+	}
+
+	observed := []*schema.Set{}
+	for _, objName := range objects {
+		privileges, ok := privilegesByObject[objName]
+		if !ok {
+			privileges = schema.NewSet(schema.HashString, nil)
+		}
+		observed = append(observed, privileges)
+	}
+	setGrantObjectsPrivileges(d, observed)
+
+	return nil
+}
+
+// schemaObjectNames lists the names of the tables, functions or procedures in the schema.
+func schemaObjectNames(db *DBConnection, schemaName, objectType string) ([]string, error) {
+	query := `
+	SELECT relname
+	FROM pg_class cl
+	JOIN pg_namespace nsp ON nsp.oid = cl.relnamespace
+	WHERE
+		nsp.nspname = $1
+		AND cl.relkind = ANY($2)
+`
+	if objectType == "function" || objectType == "procedure" {
+		query = `
+	SELECT DISTINCT proname
+	FROM pg_proc_info pr
+	JOIN pg_namespace nsp ON nsp.oid = pr.pronamespace
+	WHERE
+		nsp.nspname = $1
+		AND pr.prokind = ANY($2)
+`
+	}
+
+	rows, err := db.Query(query, schemaName, pq.Array(grantObjectTypesCodes[objectType]))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func revokeGrants(tx *sql.Tx, db *DBConnection, d *schema.ResourceData) error {
+	if grantsExpandedObjects(d) && d.Get(grantExpandedObjectsAttr).(*schema.Set).Len() == 0 {
+		log.Printf("[DEBUG] no objects to revoke privileges from")
+		return nil
+	}
+
+	queries := createGrantsRevokeQueries(d, grantGrantees(d), grantDatabaseName(db, d), db.client.config.CaseSensitiveIdentifiers)
+	if len(queries) == 0 {
+		log.Printf("[DEBUG] no privileges to revoke")
+		return nil
+	}
+	return execGrantQueries(tx, queries)
+}
+
+func createGrants(tx *sql.Tx, db *DBConnection, d *schema.ResourceData) error {
 	if d.Get(grantPrivilegesAttr).(*schema.Set).Len() == 0 {
 		log.Printf("[DEBUG] no privileges to grant for %s", d.Get(grantGroupAttr).(string))
 		return nil
 	}
 
-	query := createGrantsQuery(d, databaseName)
-	_, err := tx.Exec(query)
-	return err
+	if grantsExpandedObjects(d) && d.Get(grantExpandedObjectsAttr).(*schema.Set).Len() == 0 {
+		log.Printf("[DEBUG] no objects to grant privileges on")
+		return nil
+	}
+
+	return execGrantQueries(tx, createGrantsQueries(d, grantGrantees(d), grantDatabaseName(db, d), db.client.config.CaseSensitiveIdentifiers))
 }
 
-func createGrantsRevokeQuery(d *schema.ResourceData, databaseName string) string {
-	var query, toWhomIndicator, entityName string
+func execGrantQueries(tx *sql.Tx, queries []string) error {
+	for _, query := range queries {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	if groupName, isGroup := d.GetOk(grantGroupAttr); isGroup {
-		toWhomIndicator = "GROUP"
-		entityName = groupName.(string)
-	} else if userName, isUser := d.GetOk(grantUserAttr); isUser {
-		entityName = userName.(string)
+func createGrantsRevokeQueries(d *schema.ResourceData, grantees grantee, databaseName string, caseSensitive bool) []string {
+	var queries []string
+	switch strings.ToUpper(d.Get(grantObjectTypeAttr).(string)) {
+	case "LANGUAGE":
+		queries = grantStatements(d, grantees, "REVOKE", languageRevokedPrivileges(d), grantTargetObjects(d), databaseName, caseSensitive)
+	case "SYSTEM":
+		// Only the previously granted system permissions are revoked, as REVOKE ALL would also revoke
+		// the permissions managed outside of this resource.
+		previous, _ := d.GetChange(grantPrivilegesAttr)
+		if previous.(*schema.Set).Len() == 0 {
+			return nil
+		}
+		queries = grantStatements(d, grantees, "REVOKE", systemPrivilegesList(previous.(*schema.Set)), nil, databaseName, caseSensitive)
+	default:
+		queries = grantStatements(d, grantees, "REVOKE", "ALL PRIVILEGES", grantTargetObjects(d), databaseName, caseSensitive)
+	}
+	for _, query := range queries {
+		log.Printf("[DEBUG] Created REVOKE query: %s", query)
 	}
+	return queries
+}
 
-	fromEntityName := pq.QuoteIdentifier(entityName)
-	if isGrantToPublic(d) {
-		toWhomIndicator = ""
-		fromEntityName = "PUBLIC"
+// languageRevokedPrivileges lists the privileges to revoke on languages.
+// CREATE is only revoked when it was granted before, as not every Redshift version supports it on languages.
+func languageRevokedPrivileges(d *schema.ResourceData) string {
+	previous, _ := d.GetChange(grantPrivilegesAttr)
+	if previous.(*schema.Set).Contains("create") {
+		return "USAGE, CREATE"
 	}
+	return "USAGE"
+}
 
-	switch strings.ToUpper(d.Get(grantObjectTypeAttr).(string)) {
-	case "DATABASE":
-		query = fmt.Sprintf(
-			"REVOKE ALL PRIVILEGES ON DATABASE %s FROM %s %s",
-			pq.QuoteIdentifier(databaseName),
-			toWhomIndicator,
-			fromEntityName,
-		)
-	case "SCHEMA":
-		query = fmt.Sprintf(
-			"REVOKE ALL PRIVILEGES ON SCHEMA %s FROM %s %s",
-			pq.QuoteIdentifier(d.Get(grantSchemaAttr).(string)),
-			toWhomIndicator,
-			fromEntityName,
-		)
-	case "TABLE":
-		objects := d.Get(grantObjectsAttr).(*schema.Set)
-		if objects.Len() > 0 {
-			query = fmt.Sprintf(
-				"REVOKE ALL PRIVILEGES ON %s %s FROM %s %s",
-				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				setToPgIdentList(objects, d.Get(grantSchemaAttr).(string)),
-				toWhomIndicator,
-				fromEntityName,
-			)
-		} else {
-			query = fmt.Sprintf(
-				"REVOKE ALL PRIVILEGES ON ALL %sS IN SCHEMA %s FROM %s %s",
-				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				pq.QuoteIdentifier(d.Get(grantSchemaAttr).(string)),
-				toWhomIndicator,
-				fromEntityName,
-			)
-		}
-	case "FUNCTION", "PROCEDURE":
-		objects := d.Get(grantObjectsAttr).(*schema.Set)
-		if objects.Len() > 0 {
-			query = fmt.Sprintf(
-				"REVOKE ALL PRIVILEGES ON %s %s FROM %s %s",
-				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				setToPgIdentListNotQuoted(objects, d.Get(grantSchemaAttr).(string)),
-				toWhomIndicator,
-				fromEntityName,
-			)
-		} else {
-			query = fmt.Sprintf(
-				"REVOKE ALL PRIVILEGES ON ALL %sS IN SCHEMA %s FROM %s %s",
-				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				pq.QuoteIdentifier(d.Get(grantSchemaAttr).(string)),
-				toWhomIndicator,
-				fromEntityName,
-			)
+func createGrantsQueries(d *schema.ResourceData, grantees grantee, databaseName string, caseSensitive bool) []string {
+	queries := grantStatements(d, grantees, "GRANT", grantPrivilegesList(d, d.Get(grantPrivilegesAttr).(*schema.Set)), grantTargetObjects(d), databaseName, caseSensitive)
+	for _, query := range queries {
+		log.Printf("[DEBUG] Created GRANT query: %s", query)
+	}
+	return queries
+}
+
+// createGrantsDeltaQueries returns the statements changing the privileges from the previous state to the configured one:
+// privileges no longer configured are revoked and missing ones granted, while the privileges kept are left untouched.
+// oldObjects and newObjects are the objects the privileges apply to, or nil for all objects of the type.
+func createGrantsDeltaQueries(d *schema.ResourceData, grantees grantee, oldPrivileges, newPrivileges, oldObjects, newObjects *schema.Set, databaseName string, caseSensitive bool) []string {
+	objectType := d.Get(grantObjectTypeAttr).(string)
+	oldPrivileges = expandPrivileges(oldPrivileges, objectType)
+	newPrivileges = expandPrivileges(newPrivileges, objectType)
+
+	queries := []string{}
+	add := func(verb string, privileges, objects *schema.Set) {
+		if privileges.Len() == 0 || (objects != nil && objects.Len() == 0) {
+			return
+		}
+		for _, query := range grantStatements(d, grantees, verb, grantPrivilegesList(d, privileges), objects, databaseName, caseSensitive) {
+			log.Printf("[DEBUG] Created %s query: %s", verb, query)
+			queries = append(queries, query)
 		}
-	case "LANGUAGE":
-		objects := d.Get(grantObjectsAttr).(*schema.Set)
-		query = fmt.Sprintf(
-			"REVOKE USAGE ON LANGUAGE %s FROM %s %s",
-			setToPgIdentList(objects, ""),
-			toWhomIndicator,
-			fromEntityName,
-		)
 	}
-	log.Printf("[DEBUG] Created REVOKE query: %s", query)
-	return query
+
+	// Switching between all objects of the type and a list of objects leaves no common objects to compare.
+	if (oldObjects == nil) != (newObjects == nil) {
+		add("REVOKE", oldPrivileges, oldObjects)
+		add("GRANT", newPrivileges, newObjects)
+		return queries
+	}
+
+	keptObjects := oldObjects
+	if oldObjects != nil {
+		keptObjects = oldObjects.Intersection(newObjects)
+		add("REVOKE", oldPrivileges, oldObjects.Difference(newObjects))
+		add("GRANT", newPrivileges, newObjects.Difference(oldObjects))
+	}
+	add("REVOKE", oldPrivileges.Difference(newPrivileges), keptObjects)
+	add("GRANT", newPrivileges.Difference(oldPrivileges), keptObjects)
+
+	return queries
 }
 
-func createGrantsQuery(d *schema.ResourceData, databaseName string) string {
-	var query, toWhomIndicator, entityName string
-	privileges := []string{}
-	for _, p := range d.Get(grantPrivilegesAttr).(*schema.Set).List() {
-		privileges = append(privileges, p.(string))
+// grantStatements builds the GRANT or REVOKE statements of the privileges on the objects,
+// listing at most maxObjectsPerStatement objects per statement.
+func grantStatements(d *schema.ResourceData, grantees grantee, verb, privileges string, objects *schema.Set, databaseName string, caseSensitive bool) []string {
+	if objects == nil || objects.Len() <= maxObjectsPerStatement {
+		return []string{grantStatement(d, grantees, verb, privileges, objects, databaseName, caseSensitive)}
 	}
 
-	if groupName, isGroup := d.GetOk(grantGroupAttr); isGroup {
-		toWhomIndicator = "GROUP"
-		entityName = groupName.(string)
-	} else if userName, isUser := d.GetOk(grantUserAttr); isUser {
-		entityName = userName.(string)
+	statements := []string{}
+	for _, chunk := range chunkIdentifiers(objects, maxObjectsPerStatement) {
+		statements = append(statements, grantStatement(d, grantees, verb, privileges, chunk, databaseName, caseSensitive))
 	}
+	return statements
+}
 
-	toEntityName := pq.QuoteIdentifier(entityName)
-	if isGrantToPublic(d) {
-		toWhomIndicator = ""
-		toEntityName = "PUBLIC"
+// grantStatement builds a GRANT or REVOKE statement of the privileges on the objects, see grantObjectsClause.
+func grantStatement(d *schema.ResourceData, grantees grantee, verb, privileges string, objects *schema.Set, databaseName string, caseSensitive bool) string {
+	preposition := "TO"
+	if verb == "REVOKE" {
+		preposition = "FROM"
 	}
 
-	switch strings.ToUpper(d.Get(grantObjectTypeAttr).(string)) {
+	if strings.ToUpper(d.Get(grantObjectTypeAttr).(string)) == "SYSTEM" {
+		return fmt.Sprintf("%s %s %s %s", verb, privileges, preposition, grantGranteeClause(grantees))
+	}
+	statement := fmt.Sprintf(
+		"%s %s ON %s %s %s",
+		verb,
+		privileges,
+		grantObjectsClause(d, objects, databaseName, caseSensitive),
+		preposition,
+		grantGranteeClause(grantees),
+	)
+	if verb == "GRANT" && d.Get(grantWithGrantOptionAttr).(bool) {
+		statement += " WITH GRANT OPTION"
+	}
+	return statement
+}
+
+// grantObjectsClause returns the objects of GRANT and REVOKE statements.
+// An empty or nil set of objects means all objects of the type in the schema.
+func grantObjectsClause(d *schema.ResourceData, objects *schema.Set, databaseName string, caseSensitive bool) string {
+	objectType := strings.ToUpper(d.Get(grantObjectTypeAttr).(string))
+	schemaName := d.Get(grantSchemaAttr).(string)
+
+	switch objectType {
 	case "DATABASE":
-		query = fmt.Sprintf(
-			"GRANT %s ON DATABASE %s TO %s %s",
-			strings.Join(privileges, ","),
-			pq.QuoteIdentifier(databaseName),
-			toWhomIndicator,
-			toEntityName,
-		)
+		return fmt.Sprintf("DATABASE %s", pq.QuoteIdentifier(databaseName))
 	case "SCHEMA":
-		query = fmt.Sprintf(
-			"GRANT %s ON SCHEMA %s TO %s %s",
-			strings.Join(privileges, ","),
-			pq.QuoteIdentifier(d.Get(grantSchemaAttr).(string)),
-			toWhomIndicator,
-			toEntityName,
-		)
-	case "TABLE", "LANGUAGE":
-		objects := d.Get(grantObjectsAttr).(*schema.Set)
-		if objects.Len() > 0 {
-			query = fmt.Sprintf(
-				"GRANT %s ON %s %s TO %s %s",
-				strings.Join(privileges, ","),
-				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				setToPgIdentList(objects, d.Get(grantSchemaAttr).(string)),
-				toWhomIndicator,
-				toEntityName,
-			)
-		} else {
-			query = fmt.Sprintf(
-				"GRANT %s ON ALL %sS IN SCHEMA %s TO %s %s",
-				strings.Join(privileges, ","),
-				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				pq.QuoteIdentifier(d.Get(grantSchemaAttr).(string)),
-				toWhomIndicator,
-				toEntityName,
-			)
-		}
-	case "FUNCTION", "PROCEDURE":
-		objects := d.Get(grantObjectsAttr).(*schema.Set)
-		if objects.Len() > 0 {
-			query = fmt.Sprintf(
-				"GRANT %s ON %s %s TO %s %s",
-				strings.Join(privileges, ","),
-				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				setToPgIdentListNotQuoted(objects, d.Get(grantSchemaAttr).(string)),
-				toWhomIndicator,
-				toEntityName,
-			)
-		} else {
-			query = fmt.Sprintf(
-				"GRANT %s ON ALL %sS IN SCHEMA %s TO %s %s",
-				strings.Join(privileges, ","),
-				strings.ToUpper(d.Get(grantObjectTypeAttr).(string)),
-				pq.QuoteIdentifier(d.Get(grantSchemaAttr).(string)),
-				toWhomIndicator,
-				toEntityName,
-			)
+		if objects != nil && objects.Len() > 0 {
+			return fmt.Sprintf("SCHEMA %s", setToPgIdentList(objects, ""))
+		}
+		return fmt.Sprintf("SCHEMA %s", pq.QuoteIdentifier(schemaName))
+	}
+
+	if objects == nil || objects.Len() == 0 {
+		return fmt.Sprintf("ALL %sS IN SCHEMA %s", objectType, pq.QuoteIdentifier(schemaName))
+	}
+	if objectType == "FUNCTION" || objectType == "PROCEDURE" {
+		return fmt.Sprintf("%s %s", objectType, callablesIdentList(objects, schemaName, caseSensitive))
+	}
+	return fmt.Sprintf("%s %s", objectType, setToPgIdentList(objects, schemaName))
+}
+
+// grantGranteeClause returns the users, groups or roles of GRANT and REVOKE statements.
+func grantGranteeClause(g grantee) string {
+	if g.isPublic() {
+		return "PUBLIC"
+	}
+	prefix := ""
+	switch g.kind {
+	case catalog.Group:
+		prefix = "GROUP "
+	case catalog.Role:
+		prefix = "ROLE "
+	}
+	grantees := []string{}
+	for _, name := range g.names {
+		grantees = append(grantees, prefix+pq.QuoteIdentifier(name))
+	}
+	return strings.Join(grantees, ", ")
+}
+
+// grantee is the user, group or role the privileges are granted to, or several of them of the same kind.
+// Privileges granted to public are granted to the group named public.
+type grantee struct {
+	kind  catalog.Kind
+	names []string
+}
+
+// grantGrantees returns the grantees set in the attributes naming a single or several users, groups or roles.
+// Several names are returned in sorted order.
+func grantGrantees(d resourceGetter) grantee {
+	if attr, names := grantPluralGrantees(d); attr != "" {
+		return grantee{kind: granteeAttrKinds[attr], names: names}
+	}
+	for _, attr := range []string{grantUserAttr, grantGroupAttr, grantRoleAttr} {
+		if name, ok := d.GetOk(attr); ok {
+			return grantee{kind: granteeAttrKinds[attr], names: []string{name.(string)}}
+		}
+	}
+	return grantee{}
+}
+
+// withNames returns grantees of the same kind with the given names.
+func (g grantee) withNames(names ...string) grantee {
+	return grantee{kind: g.kind, names: names}
+}
+
+func (g grantee) isPublic() bool {
+	return g.kind == catalog.Group && len(g.names) == 1 && strings.ToLower(g.names[0]) == grantToPublicName
+}
+
+// grantPluralGrantees returns the attribute naming several users, groups or roles which is set, if any,
+// and their names in sorted order.
+func grantPluralGrantees(d resourceGetter) (string, []string) {
+	for _, attr := range []string{grantUsersAttr, grantGroupsAttr, grantRolesAttr} {
+		names := []string{}
+		for _, name := range d.Get(attr).(*schema.Set).List() {
+			names = append(names, name.(string))
+		}
+		if len(names) > 0 {
+			sort.Strings(names)
+			return attr, names
 		}
 	}
+	return "", nil
+}
+
+// grantRoleNames returns the names of the roles the privileges are granted to, if any.
+func grantRoleNames(d resourceGetter) []string {
+	if roleName, isRole := d.GetOk(grantRoleAttr); isRole {
+		return []string{roleName.(string)}
+	}
+	if attr, names := grantPluralGrantees(d); attr == grantRolesAttr {
+		return names
+	}
+	return nil
+}
+
+// grantPrivilegesList lists the privileges for GRANT and REVOKE statements.
+func grantPrivilegesList(d *schema.ResourceData, privileges *schema.Set) string {
+	if d.Get(grantObjectTypeAttr).(string) == "system" {
+		return systemPrivilegesList(privileges)
+	}
+	list := []string{}
+	for _, p := range privileges.List() {
+		list = append(list, p.(string))
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+// systemPrivilegesList lists the system permissions for GRANT and REVOKE statements, sorted for stable queries.
+func systemPrivilegesList(privileges *schema.Set) string {
+	list := []string{}
+	for _, p := range privileges.List() {
+		list = append(list, strings.ToUpper(p.(string)))
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
+
+// callablesIdentList lists the callables for GRANT and REVOKE statements.
+// Names are quoted only with case sensitive identifiers, as otherwise Redshift folds them to lower case anyway.
+func callablesIdentList(objects *schema.Set, schemaName string, caseSensitive bool) string {
+	if caseSensitive {
+		return setToPgCallableIdentList(objects, schemaName)
+	}
+	return setToPgIdentListNotQuoted(objects, schemaName)
+}
+
+// suppressGrantObjectsCaseDiff suppresses the diff of objects stored lower cased in the state by earlier versions of the provider,
+// when the configured objects only differ from them in case.
+func suppressGrantObjectsCaseDiff(_, _, _ string, d *schema.ResourceData) bool {
+	oldObjects, newObjects := d.GetChange(grantObjectsAttr)
+	return grantObjectsOnlyDifferInCase(oldObjects.(*schema.Set), newObjects.(*schema.Set))
+}
+
+func grantObjectsOnlyDifferInCase(oldObjects, newObjects *schema.Set) bool {
+	if oldObjects.Len() != newObjects.Len() {
+		return false
+	}
+	for _, object := range oldObjects.List() {
+		if object.(string) != strings.ToLower(object.(string)) {
+			return false
+		}
+	}
+	for _, object := range newObjects.List() {
+		if !oldObjects.Contains(strings.ToLower(object.(string))) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizedGrantObjects returns the configured objects the way they are stored in the catalog.
+func normalizedGrantObjects(db *DBConnection, d *schema.ResourceData) *schema.Set {
+	return normalizedIdentifiers(db, d.Get(grantObjectsAttr).(*schema.Set))
+}
+
+func normalizedIdentifiers(db *DBConnection, identifiers *schema.Set) *schema.Set {
+	normalized := schema.NewSet(schema.HashString, nil)
+	for _, identifier := range identifiers.List() {
+		normalized.Add(db.client.normalizeIdentifier(identifier.(string)))
+	}
+	return normalized
+}
 
-	log.Printf("[DEBUG] Created GRANT query: %s", query)
-	return query
+// grantsExpandedObjects reports whether the privileges are granted on an explicit list of objects
+// determined at apply time, rather than on the configured objects.
+func grantsExpandedObjects(d resourceGetter) bool {
+	return d.Get(grantObjectsExcludeAttr).(*schema.Set).Len() > 0
+}
+
+// grantTargetObjects returns the objects the GRANT and REVOKE statements apply to.
+func grantTargetObjects(d *schema.ResourceData) *schema.Set {
+	if grantsExpandedObjects(d) {
+		return d.Get(grantExpandedObjectsAttr).(*schema.Set)
+	}
+	return d.Get(grantObjectsAttr).(*schema.Set)
+}
+
+// expandGrantTables lists the tables of the schema which are not excluded by objects_exclude.
+func expandGrantTables(tx *sql.Tx, db *DBConnection, d *schema.ResourceData) (*schema.Set, error) {
+	excluded := normalizedIdentifiers(db, d.Get(grantObjectsExcludeAttr).(*schema.Set))
+
+	rows, err := tx.Query(`
+	SELECT relname
+	FROM pg_class cl
+	JOIN pg_namespace nsp ON nsp.oid = cl.relnamespace
+	WHERE
+		cl.relkind = ANY($1)
+		AND nsp.nspname = $2
+`, pq.Array(grantObjectTypesCodes["table"]), d.Get(grantSchemaAttr).(string))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := schema.NewSet(schema.HashString, nil)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if !excluded.Contains(name) {
+			tables.Add(name)
+		}
+	}
+	return tables, rows.Err()
+}
+
+// redshiftGrantInDatabase runs fn connected to the database of the grant, see RedshiftResourceInDatabase.
+// Privileges on databases are granted from the database the provider connects to,
+// as databases created from datashares can't be connected to.
+func redshiftGrantInDatabase(fn func(*DBConnection, *schema.ResourceData) error) func(*DBConnection, *schema.ResourceData) error {
+	inDatabase := RedshiftResourceInDatabase(fn)
+	return func(db *DBConnection, d *schema.ResourceData) error {
+		if d.Get(grantObjectTypeAttr).(string) == "database" {
+			return fn(db, d)
+		}
+		return inDatabase(db, d)
+	}
+}
+
+// grantDatabaseName returns the database the privileges are granted on when object_type is database.
+func grantDatabaseName(db *DBConnection, d *schema.ResourceData) string {
+	if databaseName, ok := d.GetOk(grantDatabaseAttr); ok {
+		return databaseName.(string)
+	}
+	return db.client.databaseName
+}
+
+// grantGranteeName returns the name of a single user, group or role (g) the way it is stored in the catalog.
+func grantGranteeName(db *DBConnection, g grantee) string {
+	if g.isPublic() {
+		return grantToPublicName
+	}
+	return db.client.normalizeIdentifier(g.names[0])
+}
+
+// grantGranteeNames returns the names of the users, groups or roles the way they are stored in the catalog.
+func grantGranteeNames(db *DBConnection, d *schema.ResourceData) []string {
+	g := grantGrantees(d)
+	if len(g.names) == 1 {
+		return []string{grantGranteeName(db, g)}
+	}
+	normalized := []string{}
+	for _, name := range g.names {
+		normalized = append(normalized, db.client.normalizeIdentifier(name))
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// grantPrivilegesMatch reports whether the observed privileges match the configured ones, see privilegesMatch.
+func grantPrivilegesMatch(d *schema.ResourceData, observed *schema.Set) bool {
+	return privilegesMatch(
+		d.Get(grantPrivilegesAttr).(*schema.Set),
+		observed,
+		d.Get(grantObjectTypeAttr).(string),
+		d.Get(grantStrictPrivilegesAttr).(bool),
+	)
+}
+
+// setGrantObjectsPrivileges stores the privileges observed on the objects of the grant, combined by combineObjectsPrivileges,
+// unless they match the configured ones. Without objects the configured privileges are kept.
+func setGrantObjectsPrivileges(d *schema.ResourceData, observed []*schema.Set) {
+	if len(observed) == 0 {
+		return
+	}
+	combined := combineObjectsPrivileges(d.Get(grantPrivilegesAttr).(*schema.Set), d.Get(grantObjectTypeAttr).(string), observed)
+	if !grantPrivilegesMatch(d, combined) {
+		d.Set(grantPrivilegesAttr, combined)
+	}
+}
+
+// setGrantPrivileges stores the observed privileges, unless they match the configured ones.
+func setGrantPrivileges(d *schema.ResourceData, privileges []string) {
+	observed := schema.NewSet(schema.HashString, nil)
+	for _, p := range privileges {
+		observed.Add(p)
+	}
+	if !grantPrivilegesMatch(d, observed) {
+		d.Set(grantPrivilegesAttr, observed)
+	}
 }
 
 func isGrantToPublic(d *schema.ResourceData) bool {
@@ -834,6 +1970,22 @@ func isGrantToPublic(d *schema.ResourceData) bool {
 	return false
 }
 
+// grantIDReplacer escapes the quotes and backslashes of the names in grant IDs, which are awkward to use
+// in the CLI, e.g. to import or move the grant. Dots, underscores, spaces and the commas of function signatures
+// are kept, so the IDs of grants with ordinary names are unchanged. The ID is never parsed, so names containing
+// the underscore separating its parts don't need to be escaped.
+var grantIDReplacer = strings.NewReplacer(
+	"%", "%25",
+	`"`, "%22",
+	"'", "%27",
+	"\\", "%5C",
+)
+
+// escapeGrantIDComponent escapes a name in a grant ID, see grantIDReplacer.
+func escapeGrantIDComponent(name string) string {
+	return grantIDReplacer.Replace(name)
+}
+
 func generateGrantID(d *schema.ResourceData) string {
 	parts := []string{}
 
@@ -843,23 +1995,147 @@ func generateGrantID(d *schema.ResourceData) string {
 			name = strings.ToLower(name)
 		}
 
-		parts = append(parts, fmt.Sprintf("gn:%s", name))
+		parts = append(parts, fmt.Sprintf("gn:%s", escapeGrantIDComponent(name)))
 	}
 
 	if _, isUser := d.GetOk(grantUserAttr); isUser {
-		parts = append(parts, fmt.Sprintf("un:%s", d.Get(grantUserAttr).(string)))
+		parts = append(parts, fmt.Sprintf("un:%s", escapeGrantIDComponent(d.Get(grantUserAttr).(string))))
+	}
+
+	if _, isRole := d.GetOk(grantRoleAttr); isRole {
+		parts = append(parts, fmt.Sprintf("rn:%s", escapeGrantIDComponent(d.Get(grantRoleAttr).(string))))
+	}
+
+	if attr, names := grantPluralGrantees(d); attr != "" {
+		prefix := map[string]string{grantUsersAttr: "uns", grantGroupsAttr: "gns", grantRolesAttr: "rns"}[attr]
+		escaped := []string{}
+		for _, name := range names {
+			// Commas separate the names.
+			escaped = append(escaped, strings.ReplaceAll(escapeGrantIDComponent(name), ",", "%2C"))
+		}
+		parts = append(parts, fmt.Sprintf("%s:%s", prefix, strings.Join(escaped, ",")))
 	}
 
 	objectType := fmt.Sprintf("ot:%s", d.Get(grantObjectTypeAttr).(string))
 	parts = append(parts, objectType)
 
-	if objectType != "ot:database" && objectType != "ot:language" {
-		parts = append(parts, d.Get(grantSchemaAttr).(string))
+	if databaseName, ok := d.GetOk(grantDatabaseAttr); ok {
+		parts = append(parts, escapeGrantIDComponent(databaseName.(string)))
+	}
+
+	switch objectType {
+	case "ot:database", "ot:language", "ot:system":
+	case "ot:schema":
+		// Grants on a list of schemas have no schema.
+		if schemaName, ok := d.GetOk(grantSchemaAttr); ok {
+			parts = append(parts, escapeGrantIDComponent(schemaName.(string)))
+		}
+	default:
+		parts = append(parts, escapeGrantIDComponent(d.Get(grantSchemaAttr).(string)))
 	}
 
 	for _, object := range d.Get(grantObjectsAttr).(*schema.Set).List() {
-		parts = append(parts, object.(string))
+		parts = append(parts, escapeGrantIDComponent(object.(string)))
 	}
 
 	return strings.Join(parts, "_")
 }
+
+// resourceRedshiftGrantImport populates the grant from an import ID
+// in the <user|group|role>/<name>/<object_type>[/<schema>[/<objects>]] format.
+// The privileges are then set by the read following the import.
+func resourceRedshiftGrantImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 5)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("Invalid import ID %q, expected <user|group|role>/<name>/<object_type>[/<schema>[/<objects>]]", d.Id())
+	}
+
+	granteeType, granteeName, objectType := parts[0], parts[1], parts[2]
+	switch granteeType {
+	case grantUserAttr, grantGroupAttr, grantRoleAttr:
+	default:
+		return nil, fmt.Errorf("Invalid import ID %q, the grantee type has to be one of user, group or role, got %q", d.Id(), granteeType)
+	}
+	if granteeName == "" {
+		return nil, fmt.Errorf("Invalid import ID %q, the grantee name is empty", d.Id())
+	}
+	if !sliceContainsStr(grantAllowedObjectTypes, objectType) {
+		return nil, fmt.Errorf("Invalid import ID %q, unsupported object type %q", d.Id(), objectType)
+	}
+
+	objects := []string{}
+	switch objectType {
+	case "system":
+		if len(parts) > 3 {
+			return nil, fmt.Errorf("Invalid import ID %q, grants of system permissions have no objects", d.Id())
+		}
+	case "database":
+		if len(parts) > 4 {
+			return nil, fmt.Errorf("Invalid import ID %q, grants on a database have no objects", d.Id())
+		}
+		if len(parts) == 4 {
+			d.Set(grantDatabaseAttr, parts[3])
+		}
+	case "language":
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("Invalid import ID %q, expected the languages after the object type", d.Id())
+		}
+		objects = splitImportObjects(parts[3])
+	case "schema":
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("Invalid import ID %q, expected the schemas after the object type", d.Id())
+		}
+		if schemas := splitImportObjects(parts[3]); len(schemas) == 1 {
+			d.Set(grantSchemaAttr, schemas[0])
+		} else {
+			objects = schemas
+		}
+	default:
+		if len(parts) < 4 || parts[3] == "" {
+			return nil, fmt.Errorf("Invalid import ID %q, expected the schema after the object type", d.Id())
+		}
+		d.Set(grantSchemaAttr, parts[3])
+		if len(parts) == 5 {
+			objects = splitImportObjects(parts[4])
+		}
+	}
+
+	d.Set(granteeType, granteeName)
+	d.Set(grantObjectTypeAttr, objectType)
+	d.Set(grantObjectsAttr, stringsToInterfaces(objects))
+	d.Set(grantStrictPrivilegesAttr, false)
+	d.Set(grantRevokeUnmanagedAttr, true)
+	d.Set(grantWithGrantOptionAttr, false)
+	d.SetId(generateGrantID(d))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// splitImportObjects splits a comma separated list of objects,
+// keeping the commas in the argument lists of functions and procedures.
+func splitImportObjects(list string) []string {
+	objects := []string{}
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				objects = append(objects, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	objects = append(objects, strings.TrimSpace(list[start:]))
+
+	nonEmpty := []string{}
+	for _, object := range objects {
+		if object != "" {
+			nonEmpty = append(nonEmpty, object)
+		}
+	}
+	return nonEmpty
+}
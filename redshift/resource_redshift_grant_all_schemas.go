@@ -0,0 +1,341 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	grantAllSchemasExcludeAttr = "exclude_schemas"
+	grantAllSchemasSchemasAttr = "schemas"
+)
+
+// Schemas which are managed by Redshift itself and never receive grants from this resource.
+var grantAllSchemasSystemSchemas = []string{
+	"information_schema",
+	"catalog_history",
+}
+
+func redshiftGrantAllSchemas() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Grants privileges on every local (non-external) schema in the database to a user or a group. The list of schemas is evaluated each time the resource is read: schemas created after the last apply, or schemas on which the privileges were changed outside of terraform, show up as a difference in ` + "`privileges`" + ` and are fixed by the next apply.
+System schemas (` + "`pg_*`" + `, ` + "`information_schema`" + `, ` + "`catalog_history`" + `) are always skipped.
+`,
+		Read: RedshiftResourceReadFunc(resourceRedshiftGrantAllSchemasRead),
+		Create: RedshiftResourceFunc(
+			RedshiftResourceWaitForGrantee(
+				RedshiftResourceRetryOnPQErrors(RedshiftResourceWithStatementTimeout(resourceRedshiftGrantAllSchemasCreate)),
+				grantUserAttr, grantGroupAttr,
+			),
+		),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(RedshiftResourceWithStatementTimeout(resourceRedshiftGrantAllSchemasDelete)),
+		),
+
+		// Since we revoke all when creating, we can use create as update
+		Update: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(RedshiftResourceWithStatementTimeout(resourceRedshiftGrantAllSchemasCreate)),
+		),
+
+		Schema: map[string]*schema.Schema{
+			resourceStatementTimeoutAttr: resourceStatementTimeoutSchema("grants in many schemas"),
+			resourceWaitForGranteeAttr:   resourceWaitForGranteeSchema(),
+			grantUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{grantUserAttr, grantGroupAttr},
+				Description:  "The name of the user to grant privileges on. Either `user` or `group` parameter must be set.",
+				ValidateFunc: validation.All(validation.StringDoesNotMatch(regexp.MustCompile("^(?i)public$"), "User name cannot be 'public'. To use GRANT ... TO PUBLIC set the group name to 'public' instead."), validateIdentifier),
+			},
+			grantGroupAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{grantUserAttr, grantGroupAttr},
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the group to grant privileges on. Either `group` or `user` parameter must be set. Settings the group name to `public` or `PUBLIC` (it is case insensitive in this case) will result in a `GRANT ... TO PUBLIC` statement.",
+				StateFunc: func(val interface{}) string {
+					name := val.(string)
+					if strings.ToLower(name) == grantToPublicName {
+						return strings.ToLower(name)
+					}
+					return name
+				},
+			},
+			grantPrivilegesAttr: {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					StateFunc: func(val interface{}) string {
+						return strings.ToLower(val.(string))
+					},
+					ValidateFunc: validation.StringInSlice([]string{"create", "usage"}, true),
+				},
+				Set:         schema.HashString,
+				Description: "The list of privileges to grant on every schema (`create`, `usage`). An empty list could be provided to revoke all privileges for this user or group.",
+			},
+			grantAllSchemasExcludeAttr: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIdentifier,
+					StateFunc: func(val interface{}) string {
+						return strings.ToLower(val.(string))
+					},
+				},
+				Set:         hashCaseInsensitiveString,
+				Description: "Names of the schemas which should be left untouched. Privileges on excluded schemas are neither granted nor revoked.",
+			},
+			grantAllSchemasSchemasAttr: {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Description: "Names of the schemas the privileges are managed on, as of the last read.",
+			},
+		},
+	}
+}
+
+func resourceRedshiftGrantAllSchemasCreate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	schemas, err := listGrantAllSchemasSchemas(tx, d)
+	if err != nil {
+		return err
+	}
+
+	for _, schemaName := range schemas {
+		if err := revokeGrantAllSchemasPrivileges(tx, d, schemaName); err != nil {
+			return err
+		}
+		if err := grantGrantAllSchemasPrivileges(tx, d, schemaName); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateGrantAllSchemasID(d))
+
+	return resourceRedshiftGrantAllSchemasRead(db, d)
+}
+
+func resourceRedshiftGrantAllSchemasDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	schemas, err := listGrantAllSchemasSchemas(tx, d)
+	if err != nil {
+		return err
+	}
+
+	for _, schemaName := range schemas {
+		if err := revokeGrantAllSchemasPrivileges(tx, d, schemaName); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func resourceRedshiftGrantAllSchemasRead(db *DBConnection, d *schema.ResourceData) error {
+	var entityName, query string
+	_, isUser := d.GetOk(grantUserAttr)
+
+	if isUser {
+		entityName = d.Get(grantUserAttr).(string)
+		query = `
+	SELECT
+		ns.nspname,
+		decode(charindex('C',split_part(split_part(regexp_replace(replace(array_to_string(ns.nspacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0,0,1) as create,
+		decode(charindex('U',split_part(split_part(regexp_replace(replace(array_to_string(ns.nspacl, '|'), '"', ''),'group '||u.usename,'__avoidGroupPrivs__'), u.usename||'=', 2) ,'/',1)), 0,0,1) as usage
+	FROM pg_namespace ns, pg_user u
+	WHERE
+		ns.nspname NOT LIKE 'pg\\_%'
+		AND NOT ns.nspname = ANY($1)
+		AND ns.oid NOT IN (SELECT esoid FROM pg_external_schema)
+		AND u.usename=$2
+`
+	} else {
+		entityName = d.Get(grantGroupAttr).(string)
+		query = `
+	SELECT
+		ns.nspname,
+		decode(charindex('C',split_part(split_part(replace(array_to_string(ns.nspacl, '|'), '"', ''),'group ' || gr.groname || '=',2 ) ,'/',1)), 0,0,1) as create,
+		decode(charindex('U',split_part(split_part(replace(array_to_string(ns.nspacl, '|'), '"', ''),'group ' || gr.groname || '=',2 ) ,'/',1)), 0,0,1) as usage
+	FROM pg_namespace ns, pg_group gr
+	WHERE
+		ns.nspname NOT LIKE 'pg\\_%'
+		AND NOT ns.nspname = ANY($1)
+		AND ns.oid NOT IN (SELECT esoid FROM pg_external_schema)
+		AND gr.groname=$2
+`
+	}
+
+	queryArgs := []interface{}{pq.Array(grantAllSchemasSystemSchemas), entityName}
+
+	// Handle GRANT TO PUBLIC
+	if isGrantToPublic(d) {
+		query = `
+	SELECT
+		ns.nspname,
+		decode(charindex('C',split_part(split_part(regexp_replace(replace(array_to_string(ns.nspacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0,0,1) as create,
+		decode(charindex('U',split_part(split_part(regexp_replace(replace(array_to_string(ns.nspacl, '|'), '"', ''),'[^|]+=','__avoidUserPrivs__'), '=', 2) ,'/',1)), 0,0,1) as usage
+	FROM pg_namespace ns
+	WHERE
+		ns.nspname NOT LIKE 'pg\\_%'
+		AND NOT ns.nspname = ANY($1)
+		AND ns.oid NOT IN (SELECT esoid FROM pg_external_schema)
+`
+		queryArgs = []interface{}{pq.Array(grantAllSchemasSystemSchemas)}
+	}
+
+	rows, err := db.Query(query, queryArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	excluded := d.Get(grantAllSchemasExcludeAttr).(*schema.Set)
+	configured := d.Get(grantPrivilegesAttr).(*schema.Set)
+	privilegesSet := configured
+	schemas := schema.NewSet(schema.HashString, nil)
+
+	for rows.Next() {
+		var schemaName string
+		var schemaCreate, schemaUsage bool
+
+		if err := rows.Scan(&schemaName, &schemaCreate, &schemaUsage); err != nil {
+			return err
+		}
+
+		if excluded.Contains(schemaName) {
+			continue
+		}
+		schemas.Add(schemaName)
+
+		privileges := schema.NewSet(schema.HashString, nil)
+		if schemaCreate {
+			privileges.Add("create")
+		}
+		if schemaUsage {
+			privileges.Add("usage")
+		}
+
+		log.Printf("[DEBUG] Collected schema '%s' privileges for %s: %v", schemaName, entityName, privileges.List())
+
+		// Report the first schema which differs from the configuration,
+		// so any drift shows up as a difference in privileges.
+		if privilegesSet == configured && !privileges.Equal(configured) {
+			privilegesSet = privileges
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set(grantPrivilegesAttr, privilegesSet)
+	d.Set(grantAllSchemasSchemasAttr, schemas)
+
+	return nil
+}
+
+func listGrantAllSchemasSchemas(tx *sql.Tx, d *schema.ResourceData) ([]string, error) {
+	query := `
+	SELECT nspname
+	FROM pg_namespace
+	WHERE
+		nspname NOT LIKE 'pg\\_%'
+		AND NOT nspname = ANY($1)
+		AND oid NOT IN (SELECT esoid FROM pg_external_schema)
+`
+	rows, err := tx.Query(query, pq.Array(grantAllSchemasSystemSchemas))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	excluded := d.Get(grantAllSchemasExcludeAttr).(*schema.Set)
+	schemas := []string{}
+	for rows.Next() {
+		var schemaName string
+		if err := rows.Scan(&schemaName); err != nil {
+			return nil, err
+		}
+		if excluded.Contains(schemaName) {
+			continue
+		}
+		schemas = append(schemas, schemaName)
+	}
+
+	return schemas, rows.Err()
+}
+
+func grantAllSchemasGrantee(d *schema.ResourceData) string {
+	if isGrantToPublic(d) {
+		return "PUBLIC"
+	}
+	if groupName, isGroup := d.GetOk(grantGroupAttr); isGroup {
+		return fmt.Sprintf("GROUP %s", pq.QuoteIdentifier(groupName.(string)))
+	}
+	return pq.QuoteIdentifier(d.Get(grantUserAttr).(string))
+}
+
+func revokeGrantAllSchemasPrivileges(tx *sql.Tx, d *schema.ResourceData, schemaName string) error {
+	query := fmt.Sprintf("REVOKE ALL PRIVILEGES ON SCHEMA %s FROM %s", pq.QuoteIdentifier(schemaName), grantAllSchemasGrantee(d))
+	log.Printf("[DEBUG] %s\n", query)
+	_, err := tx.Exec(query)
+	return err
+}
+
+func grantGrantAllSchemasPrivileges(tx *sql.Tx, d *schema.ResourceData, schemaName string) error {
+	privileges := []string{}
+	for _, p := range d.Get(grantPrivilegesAttr).(*schema.Set).List() {
+		privileges = append(privileges, strings.ToUpper(p.(string)))
+	}
+	if len(privileges) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("GRANT %s ON SCHEMA %s TO %s", strings.Join(privileges, ","), pq.QuoteIdentifier(schemaName), grantAllSchemasGrantee(d))
+	log.Printf("[DEBUG] %s\n", query)
+	_, err := tx.Exec(query)
+	return err
+}
+
+func generateGrantAllSchemasID(d *schema.ResourceData) string {
+	if isGrantToPublic(d) {
+		return "gn:public_ot:all_schemas"
+	}
+	if groupName, isGroup := d.GetOk(grantGroupAttr); isGroup {
+		return fmt.Sprintf("gn:%s_ot:all_schemas", escapeGrantIDComponent(groupName.(string)))
+	}
+	return fmt.Sprintf("un:%s_ot:all_schemas", escapeGrantIDComponent(d.Get(grantUserAttr).(string)))
+}
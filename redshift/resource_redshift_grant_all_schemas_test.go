@@ -0,0 +1,96 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftGrantAllSchemas_Group(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	excludedSchemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_excluded"), "-", "_")
+	config := func(privileges string) string {
+		return fmt.Sprintf(`
+resource "redshift_group" "group" {
+	name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+	name = %[2]q
+}
+
+resource "redshift_schema" "excluded" {
+	name = %[3]q
+}
+
+resource "redshift_grant_all_schemas" "group" {
+	group           = redshift_group.group.name
+	privileges      = %[4]s
+	exclude_schemas = [redshift_schema.excluded.name]
+
+	depends_on = [redshift_schema.schema]
+}
+`, groupName, schemaName, excludedSchemaName, privileges)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config(`["usage"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant_all_schemas.group", "id", fmt.Sprintf("gn:%s_ot:all_schemas", escapeGrantIDComponent(groupName))),
+					resource.TestCheckResourceAttr("redshift_grant_all_schemas.group", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant_all_schemas.group", "privileges.*", "usage"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant_all_schemas.group", "schemas.*", schemaName),
+					resource.TestCheckTypeSetElemAttr("redshift_grant_all_schemas.group", "schemas.*", "public"),
+					testAccCheckRedshiftGrantAllSchemasSkipped("redshift_grant_all_schemas.group", excludedSchemaName),
+				),
+			},
+			{
+				Config: config(`["create", "usage"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant_all_schemas.group", "privileges.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant_all_schemas.group", "privileges.*", "create"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant_all_schemas.group", "privileges.*", "usage"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftGrantAllSchemasSkipped(resourceName string, schemaName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+
+		for key, value := range rs.Primary.Attributes {
+			if strings.HasPrefix(key, grantAllSchemasSchemasAttr+".") && value == schemaName {
+				return fmt.Errorf("expected schema %s to be excluded", schemaName)
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestGenerateGrantAllSchemasIDHostileNames(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftGrantAllSchemas().Schema, map[string]interface{}{
+		grantUserAttr:       `john"doe`,
+		grantPrivilegesAttr: []interface{}{"usage"},
+	})
+
+	if expected, actual := "un:john%22doe_ot:all_schemas", generateGrantAllSchemasID(d); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
@@ -1,12 +1,17 @@
 package redshift
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/lib/pq"
 )
@@ -60,6 +65,43 @@ resource "redshift_grant" "user" {
 	})
 }
 
+func TestAccRedshiftGrant_SchemaAllPrivileges(t *testing.T) {
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_schema"), "-", "_")
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_user"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_schema" "test" {
+	name = %[1]q
+}
+
+resource "redshift_user" "test" {
+	name = %[2]q
+	password = "Foo123456$"
+}
+
+resource "redshift_grant" "all" {
+	user = redshift_user.test.name
+	schema = redshift_schema.test.name
+	object_type = "schema"
+	privileges  = ["ALL"]
+}
+`, schemaName, userName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.all", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.all", "privileges.*", "all"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftGrant_DatabaseToPublic(t *testing.T) {
 	config := `
 resource "redshift_grant" "public" {
@@ -140,6 +182,8 @@ resource "redshift_grant" "public" {
 					resource.TestCheckResourceAttr("redshift_grant.public", "object_type", "table"),
 					resource.TestCheckResourceAttr("redshift_grant.public", "objects.#", "1"),
 					resource.TestCheckTypeSetElemAttr("redshift_grant.public", "objects.*", "pg_user_info"),
+					resource.TestCheckResourceAttr("redshift_grant.public", "grantee_name", "public"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.public", "object_names.*", "pg_user_info"),
 					resource.TestCheckResourceAttr("redshift_grant.public", "privileges.#", "8"),
 					resource.TestCheckTypeSetElemAttr("redshift_grant.public", "privileges.*", "select"),
 					resource.TestCheckTypeSetElemAttr("redshift_grant.public", "privileges.*", "update"),
@@ -216,6 +260,44 @@ func TestAccRedshiftGrant_BasicDatabase(t *testing.T) {
 	}
 }
 
+func TestAccRedshiftGrant_OtherDatabase(t *testing.T) {
+	dbName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_db"), "-", "_")
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_database" "db" {
+  name = %[1]q
+}
+
+resource "redshift_user" "user" {
+  name = %[2]q
+  password = "TestPassword123"
+}
+
+resource "redshift_grant" "grant" {
+  user = redshift_user.user.name
+  database = redshift_database.db.name
+  object_type = "database"
+  privileges = ["temporary"]
+}
+`, dbName, userName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.grant", "id", fmt.Sprintf("un:%s_ot:database_%s", userName, dbName)),
+					resource.TestCheckResourceAttr("redshift_grant.grant", "database", dbName),
+					resource.TestCheckResourceAttr("redshift_grant.grant", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "privileges.*", "temporary"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftGrant_BasicSchema(t *testing.T) {
 	groupNames := []string{
 		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_"),
@@ -288,6 +370,59 @@ func TestAccRedshiftGrant_BasicSchema(t *testing.T) {
 	}
 }
 
+func TestAccRedshiftGrant_MultipleSchemas(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	schemaNames := []string{
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_a"), "-", "_"),
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_b"), "-", "_"),
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_c"), "-", "_"),
+	}
+
+	config := func(schemaCount int) string {
+		return fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  for_each = toset([%[2]q, %[3]q, %[4]q])
+  name     = each.key
+}
+
+resource "redshift_grant" "grant" {
+  group       = redshift_group.group.name
+  object_type = "schema"
+  objects     = slice([for name in [%[2]q, %[3]q, %[4]q] : redshift_schema.schema[name].name], 0, %[5]d)
+  privileges  = ["usage"]
+}
+`, groupName, schemaNames[0], schemaNames[1], schemaNames[2], schemaCount)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config(2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.grant", "objects.#", "2"),
+					resource.TestCheckResourceAttr("redshift_grant.grant", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "privileges.*", "usage"),
+				),
+			},
+			{
+				Config: config(3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.grant", "objects.#", "3"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "objects.*", schemaNames[2]),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "privileges.*", "usage"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftGrant_BasicTable(t *testing.T) {
 	groupNames := []string{
 		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_"),
@@ -342,6 +477,8 @@ func TestAccRedshiftGrant_BasicTable(t *testing.T) {
 						resource.TestCheckResourceAttr("redshift_grant.grant", "object_type", "table"),
 						resource.TestCheckResourceAttr("redshift_grant.grant", "objects.#", "1"),
 						resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "objects.*", "pg_user_info"),
+						resource.TestCheckResourceAttr("redshift_grant.grant", "grantee_name", groupName),
+						resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "object_names.*", "pg_user_info"),
 						resource.TestCheckResourceAttr("redshift_grant.grant", "privileges.#", "8"),
 						resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "privileges.*", "select"),
 						resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "privileges.*", "update"),
@@ -358,6 +495,8 @@ func TestAccRedshiftGrant_BasicTable(t *testing.T) {
 						resource.TestCheckResourceAttr("redshift_grant.grant_user", "object_type", "table"),
 						resource.TestCheckResourceAttr("redshift_grant.grant_user", "objects.#", "1"),
 						resource.TestCheckTypeSetElemAttr("redshift_grant.grant_user", "objects.*", "pg_user_info"),
+						resource.TestCheckResourceAttr("redshift_grant.grant_user", "grantee_name", userName),
+						resource.TestCheckTypeSetElemAttr("redshift_grant.grant_user", "object_names.*", "pg_user_info"),
 						resource.TestCheckResourceAttr("redshift_grant.grant_user", "privileges.#", "8"),
 						resource.TestCheckTypeSetElemAttr("redshift_grant.grant_user", "privileges.*", "select"),
 						resource.TestCheckTypeSetElemAttr("redshift_grant.grant_user", "privileges.*", "update"),
@@ -369,11 +508,350 @@ func TestAccRedshiftGrant_BasicTable(t *testing.T) {
 						resource.TestCheckTypeSetElemAttr("redshift_grant.grant_user", "privileges.*", "trigger"),
 					),
 				},
+				{
+					ResourceName:      "redshift_grant.grant",
+					ImportState:       true,
+					ImportStateId:     fmt.Sprintf("group/%s/table/pg_catalog/pg_user_info", groupName),
+					ImportStateVerify: true,
+				},
+				{
+					ResourceName:      "redshift_grant.grant_user",
+					ImportState:       true,
+					ImportStateId:     fmt.Sprintf("user/%s/table/pg_catalog/pg_user_info", userName),
+					ImportStateVerify: true,
+				},
 			},
 		})
 	}
 }
 
+func TestAccRedshiftGrant_TableDriftOnSingleObject(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	tables := []string{"table_a", "table_b", "table_c", "table_d", "table_e"}
+
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[2]q
+}
+
+resource "redshift_table" "tables" {
+  for_each = toset(%[3]s)
+
+  name   = each.key
+  schema = redshift_schema.schema.name
+
+  column {
+    name = "id"
+    type = "integer"
+  }
+}
+
+resource "redshift_grant" "grant" {
+  group       = redshift_group.group.name
+  schema      = redshift_schema.schema.name
+  object_type = "table"
+  objects     = [for table in redshift_table.tables : table.name]
+  privileges  = ["select"]
+}
+`, groupName, schemaName, fmt.Sprintf("[%q, %q, %q, %q, %q]", tables[0], tables[1], tables[2], tables[3], tables[4]))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.grant", "objects.#", "5"),
+					resource.TestCheckResourceAttr("redshift_grant.grant", "privileges.#", "1"),
+				),
+			},
+			{
+				// Revoking SELECT on a single table has to be detected as drift.
+				PreConfig: func() {
+					conn, err := testAccProvider.Meta().(*Client).Connect()
+					if err != nil {
+						t.Fatalf("couldn't start redshift connection: %s", err)
+					}
+					query := fmt.Sprintf("REVOKE SELECT ON %s.%s FROM GROUP %s", schemaName, tables[2], groupName)
+					if _, err := conn.Exec(query); err != nil {
+						t.Fatalf("couldn't revoke privilege: %s", err)
+					}
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.grant", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "privileges.*", "select"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGrant_HostileObjectNames(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc.schema"), "-", "_")
+	tables := []string{"table.with.dots", `table"with"quotes`}
+
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[2]q
+}
+
+resource "redshift_table" "tables" {
+  for_each = toset([%[3]q, %[4]q])
+
+  name   = each.key
+  schema = redshift_schema.schema.name
+
+  column {
+    name = "id"
+    type = "integer"
+  }
+}
+
+resource "redshift_grant" "grant" {
+  group       = redshift_group.group.name
+  schema      = redshift_schema.schema.name
+  object_type = "table"
+  objects     = [for table in redshift_table.tables : table.name]
+  privileges  = ["select"]
+}
+`, groupName, schemaName, tables[0], tables[1])
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.grant", "objects.#", "2"),
+					resource.TestCheckResourceAttr("redshift_grant.grant", "privileges.#", "1"),
+					resource.TestMatchResourceAttr("redshift_grant.grant", "id", regexp.MustCompile(`^gn:[^"]+$`)),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGrant_TableObjectsExclude(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_exclude"), "-", "_")
+	baseConfig := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[2]q
+  cascade_on_delete = true
+}
+`, groupName, schemaName)
+	config := baseConfig + `
+resource "redshift_grant" "grant" {
+  group = redshift_group.group.name
+  schema = redshift_schema.schema.name
+  object_type = "table"
+  objects_exclude = ["secret_data"]
+  privileges = ["select"]
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: baseConfig,
+			},
+			{
+				PreConfig: func() {
+					conn, err := testAccProvider.Meta().(*Client).Connect()
+					if err != nil {
+						t.Fatalf("couldn't start redshift connection: %s", err)
+					}
+					for _, table := range []string{"public_data", "secret_data"} {
+						if _, err := conn.Exec(fmt.Sprintf("CREATE TABLE %s.%s (id int)", schemaName, table)); err != nil {
+							t.Fatalf("couldn't create table %s: %s", table, err)
+						}
+					}
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.grant", "objects.#", "0"),
+					resource.TestCheckResourceAttr("redshift_grant.grant", "expanded_objects.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "expanded_objects.*", "public_data"),
+					resource.TestCheckResourceAttr("redshift_grant.grant", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "privileges.*", "select"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGrant_KeepUnmanaged(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	baseConfig := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[2]q
+}
+
+resource "redshift_table" "table" {
+  name   = "test_table"
+  schema = redshift_schema.schema.name
+
+  column {
+    name = "id"
+    type = "integer"
+  }
+}
+`, groupName, schemaName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: baseConfig,
+			},
+			{
+				PreConfig: func() {
+					conn, err := testAccProvider.Meta().(*Client).Connect()
+					if err != nil {
+						t.Fatalf("couldn't start redshift connection: %s", err)
+					}
+					query := fmt.Sprintf("GRANT UPDATE (id) ON %s.test_table TO GROUP %s", schemaName, groupName)
+					if _, err := conn.Exec(query); err != nil {
+						t.Fatalf("couldn't grant column privilege: %s", err)
+					}
+				},
+				Config: baseConfig + `
+resource "redshift_grant" "grant" {
+  group            = redshift_group.group.name
+  schema           = redshift_schema.schema.name
+  object_type      = "table"
+  objects          = [redshift_table.table.name]
+  privileges       = ["select"]
+  revoke_unmanaged = false
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.grant", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.grant", "privileges.*", "select"),
+					func(s *terraform.State) error {
+						conn, err := testAccProvider.Meta().(*Client).Connect()
+						if err != nil {
+							return err
+						}
+						var count int
+						query := "SELECT COUNT(*) FROM svv_column_privileges WHERE identity_type = 'group' AND identity_name = $1 AND namespace_name = $2 AND privilege_type = 'UPDATE'"
+						if err := conn.QueryRow(query, groupName, schemaName).Scan(&count); err != nil {
+							return err
+						}
+						if count != 1 {
+							return fmt.Errorf("expected the column privilege to be kept, found %d", count)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGrant_WithGrantOption(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[2]q
+}
+
+resource "redshift_table" "table" {
+  name   = "test_table"
+  schema = redshift_schema.schema.name
+
+  column {
+    name = "id"
+    type = "integer"
+  }
+}
+
+resource "redshift_grant" "schema" {
+  user              = redshift_user.user.name
+  schema            = redshift_schema.schema.name
+  object_type       = "schema"
+  privileges        = ["usage"]
+  with_grant_option = true
+}
+
+resource "redshift_grant" "table" {
+  user              = redshift_user.user.name
+  schema            = redshift_schema.schema.name
+  object_type       = "table"
+  objects           = [redshift_table.table.name]
+  privileges        = ["select", "insert"]
+  with_grant_option = true
+}
+`, userName, schemaName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.schema", "with_grant_option", "true"),
+					resource.TestCheckResourceAttr("redshift_grant.schema", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("redshift_grant.table", "with_grant_option", "true"),
+					resource.TestCheckResourceAttr("redshift_grant.table", "privileges.#", "2"),
+				),
+			},
+			{
+				// Dropping the grant option recreates the grants without it.
+				Config: strings.ReplaceAll(config, "with_grant_option = true", "with_grant_option = false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.schema", "with_grant_option", "false"),
+					resource.TestCheckResourceAttr("redshift_grant.table", "with_grant_option", "false"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRedshiftGrant_BasicCallables(t *testing.T) {
 	groupNames := []string{
 		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_"),
@@ -779,3 +1257,886 @@ func testAccRedshiftGrant_basicCallables_dropResources(t *testing.T, db *DBConne
 	}
 	return nil
 }
+
+func TestAllCallablesExecutable(t *testing.T) {
+	executeBySignature := map[string]bool{
+		"granted_a(integer)":                     true,
+		"granted_b()":                            true,
+		"overloaded(integer)":                    true,
+		"overloaded(integer, character varying)": false,
+	}
+
+	cases := map[string]struct {
+		callables []string
+		expected  bool
+	}{
+		"all granted":         {[]string{"granted_a(int)", "granted_b()"}, true},
+		"partially revoked":   {[]string{"granted_a(int)", "overloaded(int, varchar)"}, false},
+		"granted overload":    {[]string{"overloaded(int4)"}, true},
+		"revoked overload":    {[]string{"overloaded(int, varchar(20))"}, false},
+		"missing callable":    {[]string{"granted_a(int)", "dropped(int)"}, false},
+		"different signature": {[]string{"granted_a(bigint)"}, false},
+		"no callables":        {[]string{}, false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			callables := callableSignatures(schema.NewSet(schema.HashString, stringsToInterfaces(c.callables)))
+			if actual := allCallablesExecutable(callables, executeBySignature); actual != c.expected {
+				t.Errorf("expected %t, got %t", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGrantGranteeClause(t *testing.T) {
+	cases := map[string]struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		"user":   {map[string]interface{}{grantUserAttr: "john"}, `"john"`},
+		"group":  {map[string]interface{}{grantGroupAttr: "analysts"}, `GROUP "analysts"`},
+		"public": {map[string]interface{}{grantGroupAttr: "PUBLIC"}, "PUBLIC"},
+		"role":   {map[string]interface{}{grantRoleAttr: "reader"}, `ROLE "reader"`},
+		"groups": {map[string]interface{}{grantGroupsAttr: []interface{}{"b", "a"}}, `GROUP "a", GROUP "b"`},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			c.raw[grantObjectTypeAttr] = "database"
+			c.raw[grantPrivilegesAttr] = []interface{}{"usage"}
+			d := schema.TestResourceDataRaw(t, redshiftGrant().Schema, c.raw)
+			if actual := grantGranteeClause(grantGrantees(d)); actual != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGrantObjectsOnlyDifferInCase(t *testing.T) {
+	cases := map[string]struct {
+		old      []string
+		new      []string
+		expected bool
+	}{
+		"same objects":              {[]string{"foo", "bar"}, []string{"foo", "bar"}, true},
+		"lower cased in state":      {[]string{"foo", "bar"}, []string{"Foo", "BAR"}, true},
+		"mixed case in state":       {[]string{"Foo"}, []string{"FOO"}, false},
+		"different objects":         {[]string{"foo"}, []string{"Baz"}, false},
+		"added object":              {[]string{"foo"}, []string{"Foo", "bar"}, false},
+		"removed object":            {[]string{"foo", "bar"}, []string{"Foo"}, false},
+		"case sensitive duplicates": {[]string{"foo"}, []string{"Foo", "FOO"}, false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldObjects := schema.NewSet(schema.HashString, stringsToInterfaces(c.old))
+			newObjects := schema.NewSet(schema.HashString, stringsToInterfaces(c.new))
+			if actual := grantObjectsOnlyDifferInCase(oldObjects, newObjects); actual != c.expected {
+				t.Errorf("expected %t, got %t", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCallableSignature(t *testing.T) {
+	cases := map[string]string{
+		"my_function(float)":                   "my_function(double precision)",
+		"my_function(int, varchar(20))":        "my_function(integer, character varying)",
+		"my_function(numeric(18,2), BOOL)":     "my_function(numeric, boolean)",
+		"my_function()":                        "my_function()",
+		"my_function":                          "my_function()",
+		"my_function( timestamp , character )": "my_function(timestamp without time zone, character)",
+	}
+
+	for def, expected := range cases {
+		t.Run(def, func(t *testing.T) {
+			if actual := callableSignature(def); actual != expected {
+				t.Errorf("expected %q, got %q", expected, actual)
+			}
+		})
+	}
+}
+
+func TestCreateGrantsDeltaQueries(t *testing.T) {
+	set := func(values ...string) *schema.Set {
+		s := schema.NewSet(schema.HashString, nil)
+		for _, v := range values {
+			s.Add(v)
+		}
+		return s
+	}
+
+	cases := map[string]struct {
+		objectType    string
+		oldPrivileges *schema.Set
+		newPrivileges *schema.Set
+		oldObjects    *schema.Set
+		newObjects    *schema.Set
+		expected      []string
+	}{
+		"added privilege": {
+			objectType:    "schema",
+			oldPrivileges: set("usage"),
+			newPrivileges: set("create", "usage"),
+			expected:      []string{`GRANT create ON SCHEMA "test_schema" TO GROUP "test_group"`},
+		},
+		"removed privilege": {
+			objectType:    "table",
+			oldPrivileges: set("insert", "select"),
+			newPrivileges: set("select"),
+			expected:      []string{`REVOKE insert ON ALL TABLES IN SCHEMA "test_schema" FROM GROUP "test_group"`},
+		},
+		"all to some": {
+			objectType:    "database",
+			oldPrivileges: set("all"),
+			newPrivileges: set("temporary"),
+			expected:      []string{`REVOKE create ON DATABASE "test_db" FROM GROUP "test_group"`},
+		},
+		"unchanged": {
+			objectType:    "schema",
+			oldPrivileges: set("all"),
+			newPrivileges: set("create", "usage"),
+			expected:      []string{},
+		},
+		"changed objects": {
+			objectType:    "table",
+			oldPrivileges: set("select"),
+			newPrivileges: set("insert", "select"),
+			oldObjects:    set("kept", "removed"),
+			newObjects:    set("added", "kept"),
+			expected: []string{
+				`REVOKE select ON TABLE "test_schema"."removed" FROM GROUP "test_group"`,
+				`GRANT insert,select ON TABLE "test_schema"."added" TO GROUP "test_group"`,
+				`GRANT insert ON TABLE "test_schema"."kept" TO GROUP "test_group"`,
+			},
+		},
+		"added schema": {
+			objectType:    "schema",
+			oldPrivileges: set("usage"),
+			newPrivileges: set("usage"),
+			oldObjects:    set("kept"),
+			newObjects:    set("added", "kept"),
+			expected:      []string{`GRANT usage ON SCHEMA "added" TO GROUP "test_group"`},
+		},
+		"added create on language": {
+			objectType:    "language",
+			oldPrivileges: set("usage"),
+			newPrivileges: set("create", "usage"),
+			oldObjects:    set("plpythonu"),
+			newObjects:    set("plpythonu"),
+			expected:      []string{`GRANT create ON LANGUAGE "plpythonu" TO GROUP "test_group"`},
+		},
+		"objects to all objects": {
+			objectType:    "table",
+			oldPrivileges: set("select"),
+			newPrivileges: set("select"),
+			oldObjects:    set("listed"),
+			expected: []string{
+				`REVOKE select ON TABLE "test_schema"."listed" FROM GROUP "test_group"`,
+				`GRANT select ON ALL TABLES IN SCHEMA "test_schema" TO GROUP "test_group"`,
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				grantGroupAttr:      "test_group",
+				grantObjectTypeAttr: c.objectType,
+			}
+			if c.objectType != "database" && c.objectType != "language" && (c.objectType != "schema" || c.newObjects == nil) {
+				raw[grantSchemaAttr] = "test_schema"
+			}
+			d := schema.TestResourceDataRaw(t, redshiftGrant().Schema, raw)
+
+			actual := createGrantsDeltaQueries(d, grantGrantees(d), c.oldPrivileges, c.newPrivileges, c.oldObjects, c.newObjects, "test_db", false)
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCreateGrantsQueriesChunksObjects(t *testing.T) {
+	objects := []interface{}{}
+	for i := 0; i < 1200; i++ {
+		objects = append(objects, fmt.Sprintf("table_%04d", i))
+	}
+	d := schema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+		grantGroupAttr:      "test_group",
+		grantObjectTypeAttr: "table",
+		grantSchemaAttr:     "test_schema",
+		grantObjectsAttr:    objects,
+		grantPrivilegesAttr: []interface{}{"select"},
+	})
+
+	for name, queries := range map[string][]string{
+		"grant":  createGrantsQueries(d, grantGrantees(d), "test_db", false),
+		"revoke": createGrantsRevokeQueries(d, grantGrantees(d), "test_db", false),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if len(queries) != 3 {
+				t.Fatalf("expected 3 statements, got %d", len(queries))
+			}
+			listed := 0
+			for _, query := range queries {
+				count := strings.Count(query, `"test_schema".`)
+				if count > maxObjectsPerStatement {
+					t.Errorf("expected at most %d objects per statement, got %d", maxObjectsPerStatement, count)
+				}
+				listed += count
+			}
+			if listed != len(objects) {
+				t.Errorf("expected %d objects to be listed, got %d", len(objects), listed)
+			}
+		})
+	}
+}
+
+func TestCreateGrantsQueriesWithGrantOption(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+		grantUserAttr:            "test_user",
+		grantObjectTypeAttr:      "schema",
+		grantSchemaAttr:          "test_schema",
+		grantPrivilegesAttr:      []interface{}{"usage"},
+		grantWithGrantOptionAttr: true,
+	})
+
+	expected := []string{`GRANT usage ON SCHEMA "test_schema" TO "test_user" WITH GRANT OPTION`}
+	if actual := createGrantsQueries(d, grantGrantees(d), "test_db", false); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if actual := createGrantsRevokeQueries(d, grantGrantees(d), "test_db", false); strings.Contains(strings.Join(actual, ";"), "GRANT OPTION") {
+		t.Errorf("expected revoke statements without grant option, got %v", actual)
+	}
+
+	d = schema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+		grantGroupAttr:           "test_group",
+		grantObjectTypeAttr:      "schema",
+		grantSchemaAttr:          "test_schema",
+		grantPrivilegesAttr:      []interface{}{"usage"},
+		grantWithGrantOptionAttr: true,
+	})
+	if err := validateGrant(d); err == nil {
+		t.Error("expected an error when granting with grant option to a group")
+	}
+}
+
+func TestCreateGrantsQueriesPluralGrantees(t *testing.T) {
+	cases := map[string]struct {
+		grantees map[string]interface{}
+		expected string
+	}{
+		"users": {
+			grantees: map[string]interface{}{grantUsersAttr: []interface{}{"user_b", "user_a"}},
+			expected: `GRANT usage ON SCHEMA "test_schema" TO "user_a", "user_b"`,
+		},
+		"groups": {
+			grantees: map[string]interface{}{grantGroupsAttr: []interface{}{"group_b", "group_a"}},
+			expected: `GRANT usage ON SCHEMA "test_schema" TO GROUP "group_a", GROUP "group_b"`,
+		},
+		"roles": {
+			grantees: map[string]interface{}{grantRolesAttr: []interface{}{"role_b", "role_a"}},
+			expected: `GRANT usage ON SCHEMA "test_schema" TO ROLE "role_a", ROLE "role_b"`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				grantObjectTypeAttr: "schema",
+				grantSchemaAttr:     "test_schema",
+				grantPrivilegesAttr: []interface{}{"usage"},
+			}
+			for attr, names := range c.grantees {
+				raw[attr] = names
+			}
+			d := schema.TestResourceDataRaw(t, redshiftGrant().Schema, raw)
+
+			if actual := createGrantsQueries(d, grantGrantees(d), "test_db", false); !reflect.DeepEqual(actual, []string{c.expected}) {
+				t.Errorf("expected %v, got %v", []string{c.expected}, actual)
+			}
+		})
+	}
+}
+
+func TestGenerateGrantIDPluralGrantees(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+		grantGroupsAttr:     []interface{}{"group_b", "group_a"},
+		grantObjectTypeAttr: "schema",
+		grantSchemaAttr:     "test_schema",
+		grantPrivilegesAttr: []interface{}{"usage"},
+	})
+
+	if expected, actual := "gns:group_a,group_b_ot:schema_test_schema", generateGrantID(d); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestGenerateGrantIDHostileNames(t *testing.T) {
+	cases := map[string]struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		"dots": {
+			raw: map[string]interface{}{
+				grantUserAttr:       "john.doe",
+				grantObjectTypeAttr: "table",
+				grantSchemaAttr:     "my.schema",
+				grantObjectsAttr:    []interface{}{"my.table"},
+			},
+			expected: "un:john.doe_ot:table_my.schema_my.table",
+		},
+		"quotes": {
+			raw: map[string]interface{}{
+				grantGroupAttr:      `ana"lysts`,
+				grantObjectTypeAttr: "table",
+				grantSchemaAttr:     `my'schema`,
+				grantObjectsAttr:    []interface{}{`my"table`},
+			},
+			expected: "gn:ana%22lysts_ot:table_my%27schema_my%22table",
+		},
+		"underscores": {
+			raw: map[string]interface{}{
+				grantUserAttr:       "john",
+				grantObjectTypeAttr: "table",
+				grantSchemaAttr:     "my_schema",
+				grantObjectsAttr:    []interface{}{"my_table"},
+			},
+			expected: "un:john_ot:table_my_schema_my_table",
+		},
+		"separators": {
+			raw: map[string]interface{}{
+				grantUsersAttr:      []interface{}{"a,b", "c d"},
+				grantObjectTypeAttr: "schema",
+				grantSchemaAttr:     `my\schema%`,
+			},
+			expected: `uns:a%2Cb,c d_ot:schema_my%5Cschema%25`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, redshiftGrant().Schema, c.raw)
+			if actual := generateGrantID(d); actual != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCreateGrantsQueriesHostileNames(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftGrant().Schema, map[string]interface{}{
+		grantUserAttr:       `john"doe`,
+		grantObjectTypeAttr: "table",
+		grantSchemaAttr:     "my.schema",
+		grantObjectsAttr:    []interface{}{`my"table`},
+		grantPrivilegesAttr: []interface{}{"select"},
+	})
+
+	expected := []string{`GRANT select ON TABLE "my.schema"."my""table" TO "john""doe"`}
+	if actual := createGrantsQueries(d, grantGrantees(d), "test_db", false); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestAccRedshiftGrant_PluralGrantees(t *testing.T) {
+	userNames := []string{}
+	for i := 0; i < 3; i++ {
+		userNames = append(userNames, strings.ReplaceAll(acctest.RandomWithPrefix(fmt.Sprintf("tf_acc_user_%d", i)), "-", "_"))
+	}
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_plural"), "-", "_")
+
+	config := func(grantees ...int) string {
+		users := []string{}
+		for _, i := range grantees {
+			users = append(users, fmt.Sprintf("redshift_user.user[%d].name", i))
+		}
+		return fmt.Sprintf(`
+resource "redshift_user" "user" {
+  count = 3
+  name  = [%[1]q, %[2]q, %[3]q][count.index]
+}
+
+resource "redshift_schema" "schema" {
+  name = %[4]q
+}
+
+resource "redshift_grant" "grant" {
+  users       = [%[5]s]
+  schema      = redshift_schema.schema.name
+  object_type = "schema"
+  privileges  = ["usage"]
+}
+`, userNames[0], userNames[1], userNames[2], schemaName, strings.Join(users, ", "))
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config(0, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.grant", "users.#", "2"),
+					resource.TestCheckResourceAttr("redshift_grant.grant", "grantee_name", ""),
+					testAccCheckSchemaUsage(userNames[0], schemaName, true),
+					testAccCheckSchemaUsage(userNames[1], schemaName, true),
+					testAccCheckSchemaUsage(userNames[2], schemaName, false),
+				),
+			},
+			{
+				Config: config(1, 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.grant", "users.#", "2"),
+					testAccCheckSchemaUsage(userNames[0], schemaName, false),
+					testAccCheckSchemaUsage(userNames[1], schemaName, true),
+					testAccCheckSchemaUsage(userNames[2], schemaName, true),
+				),
+			},
+			{
+				// A grantee without the privileges is reported as a difference.
+				PreConfig: func() {
+					conn, err := testAccProvider.Meta().(*Client).Connect()
+					if err != nil {
+						t.Fatalf("couldn't start redshift connection: %s", err)
+					}
+					if _, err := conn.Exec(fmt.Sprintf("REVOKE USAGE ON SCHEMA %s FROM %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(userNames[2]))); err != nil {
+						t.Fatalf("couldn't revoke usage: %s", err)
+					}
+				},
+				Config:             config(1, 2),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// testAccCheckSchemaUsage checks whether the user has the usage privilege on the schema.
+func testAccCheckSchemaUsage(userName, schemaName string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn, err := testAccProvider.Meta().(*Client).Connect()
+		if err != nil {
+			return err
+		}
+		var usage bool
+		if err := conn.QueryRow("SELECT has_schema_privilege($1, $2, 'usage')", userName, schemaName).Scan(&usage); err != nil {
+			return err
+		}
+		if usage != expected {
+			return fmt.Errorf("expected user %s to have usage on schema %s: %t, got %t", userName, schemaName, expected, usage)
+		}
+		return nil
+	}
+}
+
+func TestAccRedshiftGrant_Role(t *testing.T) {
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[2]q
+}
+
+resource "redshift_table" "table" {
+  name   = "test_table"
+  schema = redshift_schema.schema.name
+
+  column {
+    name = "id"
+    type = "integer"
+  }
+}
+
+resource "redshift_grant" "database" {
+  role        = redshift_role.role.name
+  object_type = "database"
+  privileges  = ["temporary"]
+}
+
+resource "redshift_grant" "schema" {
+  role        = redshift_role.role.name
+  schema      = redshift_schema.schema.name
+  object_type = "schema"
+  privileges  = ["usage", "create"]
+}
+
+resource "redshift_grant" "table" {
+  role        = redshift_role.role.name
+  schema      = redshift_schema.schema.name
+  object_type = "table"
+  objects     = [redshift_table.table.name]
+  privileges  = ["select", "insert"]
+}
+
+resource "redshift_grant" "language" {
+  role        = redshift_role.role.name
+  object_type = "language"
+  objects     = ["plpythonu"]
+  privileges  = ["usage"]
+}
+`, roleName, schemaName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.database", "id", fmt.Sprintf("rn:%s_ot:database", roleName)),
+					resource.TestCheckResourceAttr("redshift_grant.database", "grantee_name", roleName),
+					resource.TestCheckResourceAttr("redshift_grant.database", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.database", "privileges.*", "temporary"),
+
+					resource.TestCheckResourceAttr("redshift_grant.schema", "privileges.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.schema", "privileges.*", "usage"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.schema", "privileges.*", "create"),
+
+					resource.TestCheckResourceAttr("redshift_grant.table", "id", fmt.Sprintf("rn:%s_ot:table_%s_test_table", roleName, schemaName)),
+					resource.TestCheckResourceAttr("redshift_grant.table", "privileges.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.table", "privileges.*", "select"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.table", "privileges.*", "insert"),
+
+					resource.TestCheckResourceAttr("redshift_grant.language", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.language", "privileges.*", "usage"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGrant_RoleUnknownPrivileges(t *testing.T) {
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[2]q
+}
+
+resource "redshift_table" "table" {
+  name   = "test_table"
+  schema = redshift_schema.schema.name
+
+  column {
+    name = "id"
+    type = "integer"
+  }
+}
+
+resource "redshift_grant" "schema_all" {
+  role        = redshift_role.role.name
+  schema      = redshift_schema.schema.name
+  object_type = "schema"
+  privileges  = ["all"]
+}
+
+resource "redshift_grant" "table_all" {
+  role        = redshift_role.role.name
+  schema      = redshift_schema.schema.name
+  object_type = "table"
+  objects     = [redshift_table.table.name]
+  privileges  = ["all"]
+}
+`, roleName, schemaName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				// Privileges granted by ALL which are unknown to the provider must not cause a difference.
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.schema_all", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.schema_all", "privileges.*", "all"),
+					resource.TestCheckResourceAttr("redshift_grant.table_all", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.table_all", "privileges.*", "all"),
+				),
+			},
+			{
+				// A privilege unknown to the provider granted outside of Terraform is revoked.
+				PreConfig: func() {
+					conn, err := testAccProvider.Meta().(*Client).Connect()
+					if err != nil {
+						t.Fatalf("couldn't start redshift connection: %s", err)
+					}
+					query := fmt.Sprintf("GRANT ALTER ON TABLE %s.test_table TO ROLE %s", schemaName, roleName)
+					if _, err := conn.Exec(query); err != nil {
+						t.Fatalf("couldn't grant alter: %s", err)
+					}
+				},
+				Config: strings.Replace(config, `objects     = [redshift_table.table.name]
+  privileges  = ["all"]`, `objects     = [redshift_table.table.name]
+  privileges  = ["select"]`, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.table_all", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.table_all", "privileges.*", "select"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGrant_MissingRole(t *testing.T) {
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_grant" "database" {
+  role        = %[1]q
+  object_type = "database"
+  privileges  = ["temporary"]
+}
+`, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile("does not exist"),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGrant_RoleSystemPrivileges(t *testing.T) {
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role"), "-", "_")
+	config := func(privileges string) string {
+		return fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name          = %[1]q
+  create_schema = true
+}
+
+resource "redshift_grant" "system" {
+  role        = redshift_role.role.name
+  object_type = "system"
+  privileges  = %[2]s
+}
+`, roleName, privileges)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config(`["create model", "access catalog"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.system", "id", fmt.Sprintf("rn:%s_ot:system", roleName)),
+					resource.TestCheckResourceAttr("redshift_grant.system", "privileges.#", "2"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.system", "privileges.*", "create model"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.system", "privileges.*", "access catalog"),
+					resource.TestCheckResourceAttr("redshift_role.role", "create_schema", "true"),
+				),
+			},
+			{
+				Config: config(`["alter system"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.system", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.system", "privileges.*", "alter system"),
+					resource.TestCheckResourceAttr("redshift_role.role", "create_schema", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedshiftGrant_WaitForGrantee(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_grant" "user" {
+  user             = %[1]q
+  object_type      = "schema"
+  schema           = "public"
+  privileges       = ["usage"]
+  wait_for_grantee = true
+}
+`, userName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: func(s *terraform.State) error {
+			conn, err := testAccProvider.Meta().(*Client).Connect()
+			if err != nil {
+				return err
+			}
+			_, err = conn.Exec(fmt.Sprintf("DROP USER IF EXISTS %s", userName))
+			return err
+		},
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					conn, err := testAccProvider.Meta().(*Client).Connect()
+					if err != nil {
+						t.Fatalf("couldn't start redshift connection: %s", err)
+					}
+					// The user is created by "another team" while the grant is being applied.
+					go func() {
+						time.Sleep(2 * granteeWaitPollInterval)
+						if _, err := conn.Exec(fmt.Sprintf("CREATE USER %s PASSWORD DISABLE", userName)); err != nil {
+							t.Errorf("couldn't create user: %s", err)
+						}
+					}()
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_grant.user", "privileges.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_grant.user", "privileges.*", "usage"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceRedshiftGrantImport(t *testing.T) {
+	cases := map[string]struct {
+		importID   string
+		expectedID string
+		schemaName string
+		objects    []string
+	}{
+		"table": {
+			importID:   "group/analysts/table/myschema/mytable",
+			expectedID: "gn:analysts_ot:table_myschema_mytable",
+			schemaName: "myschema",
+			objects:    []string{"mytable"},
+		},
+		"all tables": {
+			importID:   "user/alice/table/myschema",
+			expectedID: "un:alice_ot:table_myschema",
+			schemaName: "myschema",
+		},
+		"functions": {
+			importID:   "role/etl/function/myschema/f(integer, text),g()",
+			expectedID: "rn:etl_ot:function_myschema_f(integer, text)_g()",
+			schemaName: "myschema",
+			objects:    []string{"f(integer, text)", "g()"},
+		},
+		"schema": {
+			importID:   "group/public/schema/myschema",
+			expectedID: "gn:public_ot:schema_myschema",
+			schemaName: "myschema",
+		},
+		"schemas": {
+			importID:   "group/analysts/schema/a,b",
+			expectedID: "gn:analysts_ot:schema_b_a",
+			objects:    []string{"a", "b"},
+		},
+		"database": {
+			importID:   "group/analysts/database",
+			expectedID: "gn:analysts_ot:database",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := redshiftGrant().TestResourceData()
+			d.SetId(c.importID)
+
+			if _, err := resourceRedshiftGrantImport(d, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d.Id() != c.expectedID {
+				t.Errorf("expected ID %q, got %q", c.expectedID, d.Id())
+			}
+			if schemaName := d.Get(grantSchemaAttr).(string); schemaName != c.schemaName {
+				t.Errorf("expected schema %q, got %q", c.schemaName, schemaName)
+			}
+			objects := d.Get(grantObjectsAttr).(*schema.Set)
+			if !objects.Equal(schema.NewSet(schema.HashString, stringsToInterfaces(c.objects))) {
+				t.Errorf("expected objects %v, got %v", c.objects, objects.List())
+			}
+		})
+	}
+
+	for _, importID := range []string{"analysts", "team/analysts/table/myschema", "group/analysts/view/myschema", "group/analysts/table", "role/etl/system/myschema"} {
+		d := redshiftGrant().TestResourceData()
+		d.SetId(importID)
+		if _, err := resourceRedshiftGrantImport(d, nil); err == nil {
+			t.Errorf("expected an error for import ID %q", importID)
+		}
+	}
+}
+
+func TestValidateGrantDiff(t *testing.T) {
+	// unknownValue is how the SDK represents values only known at apply time in raw configurations.
+	const unknownValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+	cases := map[string]struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		"table without schema": {
+			config: map[string]interface{}{
+				grantGroupAttr:      "test_group",
+				grantObjectTypeAttr: "table",
+				grantPrivilegesAttr: []interface{}{"select"},
+			},
+			expected: "schema: required",
+		},
+		"database with objects": {
+			config: map[string]interface{}{
+				grantGroupAttr:      "test_group",
+				grantObjectTypeAttr: "database",
+				grantObjectsAttr:    []interface{}{"test_db"},
+				grantPrivilegesAttr: []interface{}{"create"},
+			},
+			expected: "objects: cannot be specified",
+		},
+		"language without objects": {
+			config: map[string]interface{}{
+				grantGroupAttr:      "test_group",
+				grantObjectTypeAttr: "language",
+				grantPrivilegesAttr: []interface{}{"usage"},
+			},
+			expected: "objects: required",
+		},
+		"valid": {
+			config: map[string]interface{}{
+				grantGroupAttr:      "test_group",
+				grantObjectTypeAttr: "table",
+				grantSchemaAttr:     "test_schema",
+				grantObjectsAttr:    []interface{}{"test_table"},
+				grantPrivilegesAttr: []interface{}{"select"},
+			},
+		},
+		"schema known at apply time": {
+			config: map[string]interface{}{
+				grantGroupAttr:      "test_group",
+				grantObjectTypeAttr: "table",
+				grantSchemaAttr:     unknownValue,
+				grantPrivilegesAttr: []interface{}{"select"},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := redshiftGrant().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(c.config), nil)
+			switch {
+			case c.expected == "" && err != nil:
+				t.Errorf("expected no error, got %s", err)
+			case c.expected != "" && (err == nil || !strings.Contains(err.Error(), c.expected)):
+				t.Errorf("expected an error containing %q, got %v", c.expected, err)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package redshift
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
 	"regexp"
 	"strings"
 
@@ -12,17 +15,33 @@ import (
 )
 
 const (
-	groupNameAttr  = "name"
-	groupUsersAttr = "users"
+	groupNameAttr                    = "name"
+	groupUsersAttr                   = "users"
+	groupIgnoreMembersAttr           = "ignore_members"
+	groupRevokeOnDeleteAttr          = "revoke_on_delete"
+	groupRevokeOnDeleteDatabasesAttr = "revoke_on_delete_databases"
+
+	// groupRemainingPrivilegesLimit limits the number of objects listed when the group can't be dropped.
+	groupRemainingPrivilegesLimit = 20
+
+	// groupMembersBatchSize is the number of group members read by a single query.
+	groupMembersBatchSize = 1000
 )
 
+// groupDefaultACLObjectTypes maps the object types of pg_default_acl to the ALTER DEFAULT PRIVILEGES object types.
+var groupDefaultACLObjectTypes = map[string]string{
+	"r": "TABLES",
+	"f": "FUNCTIONS",
+	"p": "PROCEDURES",
+}
+
 func redshiftGroup() *schema.Resource {
 	return &schema.Resource{
 		Description: `
 Groups are collections of users who are all granted whatever privileges are associated with the group. You can use groups to assign privileges by role. For example, you can create different groups for sales, administration, and support and give the users in each group the appropriate access to the data they require for their work. You can grant or revoke privileges at the group level, and those changes will apply to all members of the group, except for superusers.
 `,
 		Create: RedshiftResourceFunc(resourceRedshiftGroupCreate),
-		Read:   RedshiftResourceFunc(resourceRedshiftGroupRead),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftGroupRead),
 		Update: RedshiftResourceFunc(resourceRedshiftGroupUpdate),
 		Delete: RedshiftResourceFunc(
 			RedshiftResourceRetryOnPQErrors(resourceRedshiftGroupDelete),
@@ -31,24 +50,46 @@ Groups are collections of users who are all granted whatever privileges are asso
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: normalizedNameDiff(groupNameAttr),
 
 		Schema: map[string]*schema.Schema{
 			groupNameAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
 				Description:  "Name of the user group. Group names beginning with two underscores are reserved for Amazon Redshift internal use.",
-				ValidateFunc: validation.StringDoesNotMatch(regexp.MustCompile("^__.*"), "Group names beginning with two underscores are reserved for Amazon Redshift internal use"),
+				ValidateFunc: validation.All(validation.StringDoesNotMatch(regexp.MustCompile("^__.*"), "Group names beginning with two underscores are reserved for Amazon Redshift internal use"), validateIdentifier),
 				StateFunc: func(val interface{}) string {
 					return strings.ToLower(val.(string))
 				},
 			},
+			normalizedNameAttr: normalizedNameSchema("group"),
 			groupUsersAttr: {
 				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					Type:         schema.TypeString,
+					ValidateFunc: validateIdentifier,
 				},
-				Description: "List of the user names to add to the group",
+				Description: "List of the user names to add to the group. All the other members are removed from the group, so set `ignore_members` when members are added with `redshift_group_membership`.",
+			},
+			groupIgnoreMembersAttr: {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{groupUsersAttr},
+				Description:   "Only manage the existence and the name of the group, not its members, e.g. when they are added with `redshift_group_membership` or automatically on IAM authentication. The members are neither read nor changed, and are kept when this is set on an existing group.",
+			},
+			groupRevokeOnDeleteAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Indicates to revoke all privileges of the group in the current database before dropping it: on the database, schemas, tables, functions and procedures, as well as default privileges. By default only privileges on tables are revoked, so dropping a group with other privileges fails.",
+			},
+			groupRevokeOnDeleteDatabasesAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         hashCaseInsensitiveString,
+				Description: "Other databases to revoke the privileges of the group in when `revoke_on_delete` is set. A group can't be dropped while it has privileges in any database. Each database is cleaned up in its own transaction, before the group is dropped.",
 			},
 		},
 	}
@@ -73,22 +114,87 @@ func resourceRedshiftGroupRead(db *DBConnection, d *schema.ResourceData) error {
 }
 
 func resourceRedshiftGroupReadImpl(db *DBConnection, d *schema.ResourceData) error {
-	var (
-		groupName  string
-		groupUsers []string
-	)
-
-	sql := `SELECT ARRAY(SELECT u.usename FROM pg_user_info u, pg_group g WHERE g.grosysid = $1 AND u.usesysid = ANY(g.grolist)) AS members, groname FROM pg_group WHERE grosysid = $1`
-	if err := db.QueryRow(sql, d.Id()).Scan(pq.Array(&groupUsers), &groupName); err != nil {
+	var groupName string
+	if err := db.QueryRow("SELECT groname FROM pg_group WHERE grosysid = $1", d.Id()).Scan(&groupName); err != nil {
 		return err
 	}
 
 	d.Set(groupNameAttr, groupName)
+	d.Set(normalizedNameAttr, normalizeName(db.client, groupName))
+
+	if d.Get(groupIgnoreMembersAttr).(bool) {
+		d.Set(groupUsersAttr, nil)
+		return nil
+	}
+
+	groupUsers, err := groupMembers(db, d.Id())
+	if err != nil {
+		return err
+	}
 	d.Set(groupUsersAttr, groupUsers)
 
 	return nil
 }
 
+// groupMembers lists the names of the members of the group with the given grosysid.
+// Instead of aggregating all members into a single array, which some drivers fail to parse
+// for groups with thousands of members, grolist is unnested by subscript in batches.
+// The batches are read within a single transaction, so that they see the same members as the count.
+func groupMembers(db *DBConnection, groupID string) ([]string, error) {
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("could not start transaction: %w", err)
+	}
+	defer deferredRollback(tx)
+
+	var memberCount int
+	if err := tx.QueryRow("SELECT COALESCE(array_upper(grolist, 1), 0) FROM pg_group WHERE grosysid = $1", groupID).Scan(&memberCount); err != nil {
+		return nil, err
+	}
+
+	members := []string{}
+	for first := 1; first <= memberCount; first += groupMembersBatchSize {
+		last := first + groupMembersBatchSize - 1
+		batch, err := groupMembersBatch(tx, groupID, first, last)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, batch...)
+	}
+	log.Printf("[DEBUG] Read %d members of group %s", len(members), groupID)
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return members, nil
+}
+
+// groupMembersBatch lists the names of the members at the first to last positions of grolist.
+func groupMembersBatch(tx *sql.Tx, groupID string, first, last int) ([]string, error) {
+	rows, err := tx.Query(`
+	SELECT u.usename
+	FROM pg_group g
+	CROSS JOIN generate_series($2::int, $3::int) AS i
+	JOIN pg_user_info u ON u.usesysid = g.grolist[i]
+	WHERE g.grosysid = $1
+`, groupID, first, last)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading members of group %s: %w", groupID, err)
+	}
+	defer rows.Close()
+
+	members := []string{}
+	for rows.Next() {
+		var member string
+		if err := rows.Scan(&member); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
 func resourceRedshiftGroupCreate(db *DBConnection, d *schema.ResourceData) error {
 	groupName := d.Get(groupNameAttr).(string)
 
@@ -133,6 +239,15 @@ func resourceRedshiftGroupCreate(db *DBConnection, d *schema.ResourceData) error
 
 func resourceRedshiftGroupDelete(db *DBConnection, d *schema.ResourceData) error {
 	groupName := d.Get(groupNameAttr).(string)
+	revokeOnDelete := d.Get(groupRevokeOnDeleteAttr).(bool)
+
+	if revokeOnDelete {
+		for _, databaseName := range d.Get(groupRevokeOnDeleteDatabasesAttr).(*schema.Set).List() {
+			if err := revokeGroupPrivilegesInDatabase(db, groupName, databaseName.(string)); err != nil {
+				return err
+			}
+		}
+	}
 
 	tx, err := startTransaction(db.client, "")
 	if err != nil {
@@ -160,13 +275,162 @@ func resourceRedshiftGroupDelete(db *DBConnection, d *schema.ResourceData) error
 		}
 	}
 
+	if revokeOnDelete {
+		if err := revokeGroupPrivileges(tx, groupName, db.client.databaseName); err != nil {
+			return err
+		}
+	}
+
 	if _, err := tx.Exec(fmt.Sprintf("DROP GROUP %s", pq.QuoteIdentifier(groupName))); err != nil {
-		return err
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && isRetryablePQError(string(pqErr.Code)) {
+			return err
+		}
+		return groupDropError(db, groupName, err)
 	}
 
 	return tx.Commit()
 }
 
+// revokeGroupPrivilegesInDatabase revokes the privileges of the group in another database of the cluster.
+func revokeGroupPrivilegesInDatabase(db *DBConnection, groupName, databaseName string) error {
+	tx, err := startTransaction(db.client, databaseName)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := revokeGroupPrivileges(tx, groupName, databaseName); err != nil {
+		return fmt.Errorf("could not revoke privileges of group %s in database %s: %w", groupName, databaseName, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+	return nil
+}
+
+// revokeGroupPrivileges revokes all privileges of the group in the database the transaction is connected to.
+func revokeGroupPrivileges(tx *sql.Tx, groupName, databaseName string) error {
+	group := pq.QuoteIdentifier(groupName)
+	queries := []string{
+		fmt.Sprintf("REVOKE ALL ON DATABASE %s FROM GROUP %s", pq.QuoteIdentifier(databaseName), group),
+	}
+
+	schemas, err := tx.Query("SELECT nspname FROM pg_namespace WHERE (nspowner != 1 OR nspname = 'public') AND nspname NOT LIKE 'pg\\_temp\\_%'")
+	if err != nil {
+		return err
+	}
+	defer schemas.Close()
+
+	for schemas.Next() {
+		var schemaName string
+		if err := schemas.Scan(&schemaName); err != nil {
+			return err
+		}
+		schemaName = pq.QuoteIdentifier(schemaName)
+		queries = append(queries,
+			fmt.Sprintf("REVOKE ALL ON SCHEMA %s FROM GROUP %s", schemaName, group),
+			fmt.Sprintf("REVOKE ALL ON ALL TABLES IN SCHEMA %s FROM GROUP %s", schemaName, group),
+			fmt.Sprintf("REVOKE ALL ON ALL FUNCTIONS IN SCHEMA %s FROM GROUP %s", schemaName, group),
+			fmt.Sprintf("REVOKE ALL ON ALL PROCEDURES IN SCHEMA %s FROM GROUP %s", schemaName, group),
+		)
+	}
+	if err := schemas.Err(); err != nil {
+		return err
+	}
+
+	// Default privileges are defined per owner and optionally per schema.
+	// The name is escaped, so that underscores and percent signs in it aren't wildcards of LIKE.
+	defaultACLs, err := tx.Query(`
+	SELECT u.usename, COALESCE(n.nspname, ''), d.defaclobjtype
+	FROM pg_default_acl d
+	JOIN pg_user u ON u.usesysid = d.defacluser
+	LEFT JOIN pg_namespace n ON n.oid = d.defaclnamespace
+	WHERE replace(array_to_string(d.defaclacl, '|'), '"', '') LIKE '%group ' || $1 || '=%'
+`, escapeLikePattern(groupName))
+	if err != nil {
+		return err
+	}
+	defer defaultACLs.Close()
+
+	for defaultACLs.Next() {
+		var owner, schemaName, objectType string
+		if err := defaultACLs.Scan(&owner, &schemaName, &objectType); err != nil {
+			return err
+		}
+		inSchema := ""
+		if schemaName != "" {
+			inSchema = fmt.Sprintf(" IN SCHEMA %s", pq.QuoteIdentifier(schemaName))
+		}
+		objectTypes, ok := groupDefaultACLObjectTypes[objectType]
+		if !ok {
+			return fmt.Errorf("unsupported object type %q of the default privileges of group %s granted by %s", objectType, groupName, owner)
+		}
+		queries = append(queries, fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER %s%s REVOKE ALL ON %s FROM GROUP %s", pq.QuoteIdentifier(owner), inSchema, objectTypes, group))
+	}
+	if err := defaultACLs.Err(); err != nil {
+		return err
+	}
+
+	for _, query := range queries {
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupDropError extends the error of DROP GROUP with the objects of the current database the group still has privileges on.
+func groupDropError(db *DBConnection, groupName string, dropErr error) error {
+	remaining, err := groupRemainingPrivileges(db, groupName)
+	if err != nil {
+		log.Printf("[WARN] could not list the remaining privileges of group %s: %v", groupName, err)
+		return dropErr
+	}
+	if len(remaining) == 0 {
+		return fmt.Errorf("could not drop group %s: %w. It has no privileges left in database %s, check the other databases of the cluster or set `%s` to revoke them", groupName, dropErr, db.client.databaseName, groupRevokeOnDeleteDatabasesAttr)
+	}
+	return fmt.Errorf("could not drop group %s: %w. It still has privileges in database %s on: %s. Set `%s` to revoke them before dropping the group", groupName, dropErr, db.client.databaseName, strings.Join(remaining, ", "), groupRevokeOnDeleteAttr)
+}
+
+// groupRemainingPrivileges lists the objects of the current database the group has privileges on, including default privileges.
+func groupRemainingPrivileges(db *DBConnection, groupName string) ([]string, error) {
+	rows, err := db.Query(`
+	SELECT 'database ' || quote_ident(datname) FROM pg_database WHERE replace(array_to_string(datacl, '|'), '"', '') LIKE '%group ' || $1 || '=%'
+	UNION ALL
+	SELECT 'schema ' || quote_ident(nspname) FROM pg_namespace WHERE replace(array_to_string(nspacl, '|'), '"', '') LIKE '%group ' || $1 || '=%'
+	UNION ALL
+	SELECT 'relation ' || quote_ident(n.nspname) || '.' || quote_ident(c.relname)
+	FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE replace(array_to_string(c.relacl, '|'), '"', '') LIKE '%group ' || $1 || '=%'
+	UNION ALL
+	SELECT 'function ' || quote_ident(n.nspname) || '.' || quote_ident(p.proname)
+	FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace
+	WHERE replace(array_to_string(p.proacl, '|'), '"', '') LIKE '%group ' || $1 || '=%'
+	UNION ALL
+	SELECT 'default privileges of ' || quote_ident(u.usename)
+	FROM pg_default_acl d JOIN pg_user u ON u.usesysid = d.defacluser
+	WHERE replace(array_to_string(d.defaclacl, '|'), '"', '') LIKE '%group ' || $1 || '=%'
+	LIMIT $2
+`, escapeLikePattern(groupName), groupRemainingPrivilegesLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	remaining := []string{}
+	for rows.Next() {
+		var object string
+		if err := rows.Scan(&object); err != nil {
+			return nil, err
+		}
+		remaining = append(remaining, object)
+	}
+	return remaining, rows.Err()
+}
+
 func resourceRedshiftGroupUpdate(db *DBConnection, d *schema.ResourceData) error {
 	tx, err := startTransaction(db.client, "")
 	if err != nil {
@@ -226,7 +490,8 @@ func checkIfUserExists(tx *sql.Tx, name string) (bool, error) {
 }
 
 func setUsersNames(tx *sql.Tx, db *DBConnection, d *schema.ResourceData) error {
-	if !d.HasChange(groupUsersAttr) {
+	// Dropping users from the configuration when starting to ignore the members must not remove them from the group.
+	if !d.HasChange(groupUsersAttr) || d.Get(groupIgnoreMembersAttr).(bool) {
 		return nil
 	}
 
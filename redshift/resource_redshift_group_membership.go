@@ -0,0 +1,219 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/brainly/terraform-provider-redshift/redshift/internal/catalog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	groupMembershipGroupAttr = "group"
+	groupMembershipUsersAttr = "users"
+)
+
+func redshiftGroupMembership() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Adds users to a group without managing its other members, e.g. when several teams add their users to a shared group.
+Only the listed users are added to the group with ` + "`ALTER GROUP ... ADD USER`" + `, and removed with ` + "`ALTER GROUP ... DROP USER`" + ` when they are no longer listed or the resource is destroyed.
+
+` + "`redshift_group`" + ` manages all the members of the group in ` + "`users`" + `. When a group has memberships managed by this resource, set ` + "`ignore_members = true`" + ` on the ` + "`redshift_group`" + ` resource, otherwise the two resources keep removing each other's members.
+`,
+		Create: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftGroupMembershipCreate),
+		),
+		Read: RedshiftResourceReadFunc(resourceRedshiftGroupMembershipRead),
+		Update: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftGroupMembershipUpdate),
+		),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftGroupMembershipDelete),
+		),
+		Schema: map[string]*schema.Schema{
+			groupMembershipGroupAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the group to add the users to.",
+				ValidateFunc: validateIdentifier,
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			groupMembershipUsersAttr: {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIdentifier,
+				},
+				Description: "The names of the users to add to the group. Other members of the group are left untouched.",
+			},
+		},
+	}
+}
+
+func resourceRedshiftGroupMembershipCreate(db *DBConnection, d *schema.ResourceData) error {
+	groupName := d.Get(groupMembershipGroupAttr).(string)
+
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := alterGroupUsers(tx, groupName, "ADD", d.Get(groupMembershipUsersAttr).(*schema.Set)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateGroupMembershipID(d))
+
+	return resourceRedshiftGroupMembershipRead(db, d)
+}
+
+func resourceRedshiftGroupMembershipRead(db *DBConnection, d *schema.ResourceData) error {
+	groupName := db.client.normalizeIdentifier(d.Get(groupMembershipGroupAttr).(string))
+
+	groupID, err := catalog.NewResolver(db).GroupID(groupName)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift group %s of the membership (%s) not found", groupName, d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading group %s: %w", groupName, err)
+	}
+
+	members, err := groupMembers(db, strconv.Itoa(groupID))
+	if err != nil {
+		return err
+	}
+	isMember := map[string]bool{}
+	for _, member := range members {
+		isMember[member] = true
+	}
+
+	// Only the configured users are read back, the other members are managed elsewhere.
+	users := schema.NewSet(schema.HashString, nil)
+	for _, user := range d.Get(groupMembershipUsersAttr).(*schema.Set).List() {
+		if isMember[db.client.normalizeIdentifier(user.(string))] {
+			users.Add(user)
+		}
+	}
+	d.Set(groupMembershipUsersAttr, users)
+
+	return nil
+}
+
+func resourceRedshiftGroupMembershipUpdate(db *DBConnection, d *schema.ResourceData) error {
+	groupName := d.Get(groupMembershipGroupAttr).(string)
+	oldUsers, newUsers := d.GetChange(groupMembershipUsersAttr)
+
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	removedUsers, err := existingUsers(tx, db, oldUsers.(*schema.Set).Difference(newUsers.(*schema.Set)))
+	if err != nil {
+		return err
+	}
+	if err := alterGroupUsers(tx, groupName, "DROP", removedUsers); err != nil {
+		return err
+	}
+	if err := alterGroupUsers(tx, groupName, "ADD", newUsers.(*schema.Set).Difference(oldUsers.(*schema.Set))); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateGroupMembershipID(d))
+
+	return resourceRedshiftGroupMembershipRead(db, d)
+}
+
+func resourceRedshiftGroupMembershipDelete(db *DBConnection, d *schema.ResourceData) error {
+	groupName := d.Get(groupMembershipGroupAttr).(string)
+
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if _, err := catalog.NewResolver(tx).GroupID(db.client.normalizeIdentifier(groupName)); err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("[WARN] Redshift group %s was already dropped", groupName)
+			return nil
+		}
+		return fmt.Errorf("failed to get group ID for group '%s': %w", groupName, err)
+	}
+
+	users, err := existingUsers(tx, db, d.Get(groupMembershipUsersAttr).(*schema.Set))
+	if err != nil {
+		return err
+	}
+	if err := alterGroupUsers(tx, groupName, "DROP", users); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// alterGroupUsers adds the users to the group or drops them from it, depending on the action (ADD or DROP).
+func alterGroupUsers(tx *sql.Tx, groupName, action string, users *schema.Set) error {
+	if users.Len() == 0 {
+		return nil
+	}
+
+	names := []string{}
+	for _, name := range users.List() {
+		names = append(names, pq.QuoteIdentifier(name.(string)))
+	}
+	sort.Strings(names)
+
+	query := fmt.Sprintf("ALTER GROUP %s %s USER %s", pq.QuoteIdentifier(groupName), action, strings.Join(names, ", "))
+	log.Printf("[DEBUG] %s\n", query)
+	_, err := tx.Exec(query)
+	return err
+}
+
+// existingUsers returns the users which still exist, as dropping a user which was already dropped from a group fails.
+func existingUsers(tx *sql.Tx, db *DBConnection, users *schema.Set) (*schema.Set, error) {
+	existing := schema.NewSet(schema.HashString, nil)
+	for _, name := range users.List() {
+		exists, err := checkIfUserExists(tx, db.client.normalizeIdentifier(name.(string)))
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			existing.Add(name)
+		}
+	}
+	return existing, nil
+}
+
+func generateGroupMembershipID(d *schema.ResourceData) string {
+	users := []string{}
+	for _, user := range d.Get(groupMembershipUsersAttr).(*schema.Set).List() {
+		users = append(users, strings.ToLower(user.(string)))
+	}
+	sort.Strings(users)
+
+	return fmt.Sprintf("gn:%s_un:%s", strings.ToLower(d.Get(groupMembershipGroupAttr).(string)), strings.Join(users, ","))
+}
@@ -0,0 +1,103 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftGroupMembership_Basic(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group_membership"), "-", "_")
+	userNames := []string{}
+	for i := 0; i < 3; i++ {
+		userNames = append(userNames, strings.ReplaceAll(acctest.RandomWithPrefix(fmt.Sprintf("tf_acc_member_%d", i)), "-", "_"))
+	}
+
+	config := func(teamB bool) string {
+		membership := ""
+		if teamB {
+			membership = `
+resource "redshift_group_membership" "team_b" {
+  group = redshift_group.group.name
+  users = [redshift_user.user[2].name]
+}
+`
+		}
+		return fmt.Sprintf(`
+resource "redshift_user" "user" {
+  count = 3
+  name  = [%[2]q, %[3]q, %[4]q][count.index]
+}
+
+# The first user is managed by the group itself.
+resource "redshift_group" "group" {
+  name  = %[1]q
+  users = [redshift_user.user[0].name]
+
+  lifecycle {
+    ignore_changes = [users]
+  }
+}
+
+resource "redshift_group_membership" "team_a" {
+  group = redshift_group.group.name
+  users = [redshift_user.user[1].name]
+}
+%[5]s
+`, groupName, userNames[0], userNames[1], userNames[2], membership)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_group_membership.team_a", "id", fmt.Sprintf("gn:%s_un:%s", groupName, userNames[1])),
+					resource.TestCheckResourceAttr("redshift_group_membership.team_a", "users.#", "1"),
+					resource.TestCheckResourceAttr("redshift_group_membership.team_b", "users.#", "1"),
+					testAccCheckGroupMembers(groupName, userNames...),
+				),
+			},
+			{
+				// Destroying a membership only drops its own users from the group.
+				Config: config(false),
+				Check:  testAccCheckGroupMembers(groupName, userNames[0], userNames[1]),
+			},
+		},
+	})
+}
+
+// testAccCheckGroupMembers checks that the group has exactly the given members.
+func testAccCheckGroupMembers(groupName string, expected ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		db, err := testAccProvider.Meta().(*Client).Connect()
+		if err != nil {
+			return err
+		}
+
+		var groupID string
+		if err := db.QueryRow("SELECT grosysid FROM pg_group WHERE groname = $1", groupName).Scan(&groupID); err != nil {
+			return fmt.Errorf("Error reading group %s: %w", groupName, err)
+		}
+		members, err := groupMembers(db, groupID)
+		if err != nil {
+			return err
+		}
+		if len(members) != len(expected) {
+			return fmt.Errorf("expected group %s to have members %v, got %v", groupName, expected, members)
+		}
+		for _, name := range expected {
+			if !sliceContainsStr(members, name) {
+				return fmt.Errorf("expected group %s to have members %v, got %v", groupName, expected, members)
+			}
+		}
+		return nil
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
 func TestAccRedshiftGroup_Basic(t *testing.T) {
@@ -25,6 +26,7 @@ func TestAccRedshiftGroup_Basic(t *testing.T) {
 
 					testAccCheckRedshiftGroupExists("sOme_fancy_name-@www"),
 					resource.TestCheckResourceAttr("redshift_group.fancy_name", "name", "some_fancy_name-@www"),
+					resource.TestCheckResourceAttr("redshift_group.fancy_name", "normalized_name", "some_fancy_name-@www"),
 
 					testAccCheckRedshiftGroupExists("group_defaults"),
 					resource.TestCheckResourceAttr("redshift_group.group_defaults", "name", "group_defaults"),
@@ -190,6 +192,52 @@ resource "redshift_user" "user2" {
 	})
 }
 
+func TestAccRedshiftGroup_IgnoreMembers(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group"), "-", "_")
+	userNames := []string{
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group_user"), "-", "_"),
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group_user"), "-", "_"),
+	}
+	config := func(group string) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "user" {
+  count = 2
+  name  = [%[1]q, %[2]q][count.index]
+}
+
+resource "redshift_group" "group" {
+  name = %[3]q
+  %[4]s
+}
+
+resource "redshift_group_membership" "membership" {
+  group = redshift_group.group.name
+  users = [redshift_user.user[1].name]
+}
+`, userNames[0], userNames[1], groupName, group)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("users = [redshift_user.user[0].name]\n  lifecycle {\n    ignore_changes = [users]\n  }"),
+				Check:  testAccCheckGroupMembers(groupName, userNames...),
+			},
+			{
+				// Ignoring the members keeps the users the group used to manage.
+				Config: config("ignore_members = true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_group.group", "users.#", "0"),
+					testAccCheckGroupMembers(groupName, userNames...),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckRedshiftGroupDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*Client)
 
@@ -276,3 +324,52 @@ resource "redshift_user" "group_test_user2" {
   name = "group_test_user2"
 }
 `
+
+func TestAccRedshiftGroup_RevokeOnDelete(t *testing.T) {
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group_revoke"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_group" "group" {
+  name             = %q
+  revoke_on_delete = true
+}
+`, groupName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftGroupExists(groupName),
+					resource.TestCheckResourceAttr("redshift_group.group", "revoke_on_delete", "true"),
+					// Privileges granted outside of Terraform would make DROP GROUP fail without revoke_on_delete.
+					testAccGrantToRedshiftGroup(groupName),
+				),
+			},
+		},
+	})
+}
+
+func testAccGrantToRedshiftGroup(groupName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		group := pq.QuoteIdentifier(groupName)
+		for _, query := range []string{
+			fmt.Sprintf("GRANT TEMP ON DATABASE %s TO GROUP %s", pq.QuoteIdentifier(client.databaseName), group),
+			fmt.Sprintf("GRANT USAGE ON SCHEMA public TO GROUP %s", group),
+			fmt.Sprintf("ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT EXECUTE ON FUNCTIONS TO GROUP %s", group),
+		} {
+			if _, err := db.Exec(query); err != nil {
+				return fmt.Errorf("could not grant privileges to group: %w", err)
+			}
+		}
+		return nil
+	}
+}
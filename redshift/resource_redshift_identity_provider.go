@@ -0,0 +1,205 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	identityProviderNameAttr           = "name"
+	identityProviderTypeAttr           = "type"
+	identityProviderNamespaceAttr      = "namespace"
+	identityProviderParametersAttr     = "parameters"
+	identityProviderApplicationARNAttr = "application_arn"
+	identityProviderIAMRoleAttr        = "iam_role"
+)
+
+func redshiftIdentityProvider() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Defines a native identity provider, e.g. Azure AD, whose users and groups can connect to the cluster with single sign-on. The users and roles of the identity provider are named after its namespace, e.g. ` + "`aad:alice`" + `.
+
+The identity provider can't be dropped while users or roles of its namespace exist. Its parameters are not read back, as Redshift doesn't expose the client secret, so changes made to them outside of Terraform are not detected.
+`,
+		Create: RedshiftResourceFunc(resourceRedshiftIdentityProviderCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftIdentityProviderRead),
+		Update: RedshiftResourceFunc(resourceRedshiftIdentityProviderUpdate),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftIdentityProviderDelete),
+		),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			identityProviderNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the identity provider.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			identityProviderTypeAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"azure", "awsidc"}, true),
+				Description:  "The type of the identity provider, `azure` or `awsidc` (AWS IAM Identity Center).",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			identityProviderNamespaceAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				Description:  "The namespace of the identity provider, prefixing the names of its users and roles.",
+			},
+			identityProviderParametersAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+				Description:      "The parameters of the identity provider, as a JSON object, e.g. the `issuer`, `client_id`, `client_secret` and `audience` of an Azure AD application. Use `jsonencode` to build it.",
+			},
+			identityProviderApplicationARNAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{identityProviderIAMRoleAttr},
+				Description:  "The ARN of the IAM Identity Center managed application of an `awsidc` identity provider.",
+			},
+			identityProviderIAMRoleAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{identityProviderApplicationARNAttr},
+				Description:  "The ARN of the IAM role used to connect to IAM Identity Center, for an `awsidc` identity provider.",
+			},
+		},
+	}
+}
+
+func resourceRedshiftIdentityProviderCreate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := createIdentityProviderQuery(d)
+	// The query is not logged, as the parameters usually contain a client secret.
+	log.Printf("[DEBUG] creating identity provider %s\n", d.Get(identityProviderNameAttr).(string))
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("could not create identity provider %s: %w", d.Get(identityProviderNameAttr).(string), err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(strings.ToLower(d.Get(identityProviderNameAttr).(string)))
+
+	return resourceRedshiftIdentityProviderRead(db, d)
+}
+
+func resourceRedshiftIdentityProviderRead(db *DBConnection, d *schema.ResourceData) error {
+	var providerType, namespace string
+
+	err := db.QueryRow(`
+	SELECT lower(trim(type)), trim(namespc)
+	FROM svv_identity_providers
+	WHERE name = $1`, d.Id()).Scan(&providerType, &namespace)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift identity provider (%s) not found", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading identity provider: %w", err)
+	}
+
+	d.Set(identityProviderNameAttr, d.Id())
+	d.Set(identityProviderTypeAttr, providerType)
+	d.Set(identityProviderNamespaceAttr, namespace)
+
+	return nil
+}
+
+func resourceRedshiftIdentityProviderUpdate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if query := alterIdentityProviderQuery(d); query != "" {
+		log.Printf("[DEBUG] updating identity provider %s\n", d.Get(identityProviderNameAttr).(string))
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("Error updating identity provider: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourceRedshiftIdentityProviderRead(db, d)
+}
+
+func resourceRedshiftIdentityProviderDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := fmt.Sprintf("DROP IDENTITY PROVIDER %s", pq.QuoteIdentifier(d.Get(identityProviderNameAttr).(string)))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func createIdentityProviderQuery(d *schema.ResourceData) string {
+	query := fmt.Sprintf(
+		"CREATE IDENTITY PROVIDER %s TYPE %s NAMESPACE '%s'",
+		pq.QuoteIdentifier(d.Get(identityProviderNameAttr).(string)),
+		strings.ToLower(d.Get(identityProviderTypeAttr).(string)),
+		pqQuoteLiteral(d.Get(identityProviderNamespaceAttr).(string)),
+	)
+	if parameters, ok := d.GetOk(identityProviderParametersAttr); ok {
+		query = fmt.Sprintf("%s PARAMETERS '%s'", query, pqQuoteLiteral(parameters.(string)))
+	}
+	if applicationARN, ok := d.GetOk(identityProviderApplicationARNAttr); ok {
+		query = fmt.Sprintf("%s APPLICATION_ARN '%s' IAM_ROLE '%s'", query, pqQuoteLiteral(applicationARN.(string)), pqQuoteLiteral(d.Get(identityProviderIAMRoleAttr).(string)))
+	}
+	return query
+}
+
+// alterIdentityProviderQuery returns the statement updating the changed namespace and parameters, or an empty string when neither changed.
+func alterIdentityProviderQuery(d *schema.ResourceData) string {
+	changes := []string{}
+	if d.HasChange(identityProviderNamespaceAttr) {
+		changes = append(changes, fmt.Sprintf("NAMESPACE '%s'", pqQuoteLiteral(d.Get(identityProviderNamespaceAttr).(string))))
+	}
+	if d.HasChange(identityProviderParametersAttr) {
+		changes = append(changes, fmt.Sprintf("PARAMETERS '%s'", pqQuoteLiteral(d.Get(identityProviderParametersAttr).(string))))
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("ALTER IDENTITY PROVIDER %s %s", pq.QuoteIdentifier(d.Get(identityProviderNameAttr).(string)), strings.Join(changes, " "))
+}
@@ -0,0 +1,121 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Acceptance tests of identity providers require the parameters of an Azure AD application:
+//
+//	REDSHIFT_IDENTITY_PROVIDER_AZURE_PARAMETERS - JSON object with the issuer, client_id, client_secret and audience
+func TestAccRedshiftIdentityProvider_Azure(t *testing.T) {
+	parameters := getEnvOrSkip("REDSHIFT_IDENTITY_PROVIDER_AZURE_PARAMETERS", t)
+	providerName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_idp"), "-", "_")
+	config := func(namespace string) string {
+		return fmt.Sprintf(`
+resource "redshift_identity_provider" "azure" {
+  name       = %[1]q
+  type       = "azure"
+  namespace  = %[2]q
+  parameters = %[3]q
+}
+`, providerName, namespace, parameters)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftIdentityProviderDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(providerName + "_aad"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_identity_provider.azure", "id", providerName),
+					resource.TestCheckResourceAttr("redshift_identity_provider.azure", "type", "azure"),
+					resource.TestCheckResourceAttr("redshift_identity_provider.azure", "namespace", providerName+"_aad"),
+				),
+			},
+			{
+				Config: config(providerName + "_azure"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_identity_provider.azure", "namespace", providerName+"_azure"),
+				),
+			},
+			{
+				ResourceName:            "redshift_identity_provider.azure",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{identityProviderParametersAttr},
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftIdentityProviderDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.Connect()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_identity_provider" {
+			continue
+		}
+
+		var name string
+		err := db.QueryRow("SELECT name FROM svv_identity_providers WHERE name = $1", rs.Primary.ID).Scan(&name)
+		switch {
+		case err == sql.ErrNoRows:
+			continue
+		case err != nil:
+			return fmt.Errorf("Error checking identity provider %s", err)
+		}
+		return fmt.Errorf("Identity provider %s still exists after destroy", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+func TestCreateIdentityProviderQuery(t *testing.T) {
+	cases := map[string]struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		"azure": {
+			config: map[string]interface{}{
+				identityProviderNameAttr:       "azure_ad",
+				identityProviderTypeAttr:       "Azure",
+				identityProviderNamespaceAttr:  "aad",
+				identityProviderParametersAttr: `{"issuer":"https://sts.windows.net/tenant/","client_secret":"it's secret"}`,
+			},
+			expected: `CREATE IDENTITY PROVIDER "azure_ad" TYPE azure NAMESPACE 'aad' PARAMETERS '{"issuer":"https://sts.windows.net/tenant/","client_secret":"it''s secret"}'`,
+		},
+		"awsidc": {
+			config: map[string]interface{}{
+				identityProviderNameAttr:           "idc",
+				identityProviderTypeAttr:           "awsidc",
+				identityProviderNamespaceAttr:      "awsidc",
+				identityProviderApplicationARNAttr: "arn:aws:sso::123456789012:application/ssoins-1/apl-1",
+				identityProviderIAMRoleAttr:        "arn:aws:iam::123456789012:role/redshift-idc",
+			},
+			expected: `CREATE IDENTITY PROVIDER "idc" TYPE awsidc NAMESPACE 'awsidc' APPLICATION_ARN 'arn:aws:sso::123456789012:application/ssoins-1/apl-1' IAM_ROLE 'arn:aws:iam::123456789012:role/redshift-idc'`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, redshiftIdentityProvider().Schema, c.config)
+			if actual := createIdentityProviderQuery(d); actual != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package redshift
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	maskingPolicyNameAttr  = "name"
+	maskingPolicyWithAttr  = "with"
+	maskingPolicyUsingAttr = "using"
+)
+
+func redshiftMaskingPolicy() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Defines a dynamic data masking policy, which replaces the values of the columns it is attached to with ` + "`redshift_masking_policy_attachment`" + ` by masking expressions computed from the input columns, e.g. to hide personal data from some users and roles.
+
+Managing masking policies requires a superuser or a user with the ` + "`sys:secadmin`" + ` role. Redshift stores the masking expressions rewritten, e.g. with explicit casts, so they are compared with the configured ones ignoring casts, parentheses, whitespace and the case outside of string literals. Changes made outside of Terraform which only differ in these are not detected.
+`,
+		Create: RedshiftResourceFunc(resourceRedshiftMaskingPolicyCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftMaskingPolicyRead),
+		Update: RedshiftResourceFunc(resourceRedshiftMaskingPolicyUpdate),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftMaskingPolicyDelete),
+		),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			maskingPolicyNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the policy.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			maskingPolicyWithAttr: {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Description: "The input columns of the policy, referenced by `using`, in order. They are mapped to columns of the table when attaching the policy.",
+				Elem:        policyColumnsResource(),
+			},
+			maskingPolicyUsingAttr: {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The masking expressions, one for each column the policy is attached to, e.g. `'XXXX'::varchar(16)` or `SHA2(credit_card, 256)`.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotWhiteSpace,
+				},
+			},
+		},
+	}
+}
+
+// maskingPolicyExpression is a masking expression of a policy, as stored in the policy_expression column of svv_masking_policy.
+type maskingPolicyExpression struct {
+	Expression string `json:"expr"`
+}
+
+func resourceRedshiftMaskingPolicyCreate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := createMaskingPolicyQuery(d)
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("could not create masking policy %s: %w", d.Get(maskingPolicyNameAttr).(string), err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(strings.ToLower(d.Get(maskingPolicyNameAttr).(string)))
+
+	return resourceRedshiftMaskingPolicyRead(db, d)
+}
+
+func resourceRedshiftMaskingPolicyRead(db *DBConnection, d *schema.ResourceData) error {
+	var inputColumns, expressions string
+
+	err := db.QueryRow(`
+	SELECT COALESCE(input_columns, ''), COALESCE(policy_expression, '')
+	FROM svv_masking_policy
+	WHERE policy_name = $1`, d.Id()).Scan(&inputColumns, &expressions)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift masking policy (%s) not found", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading masking policy: %w", err)
+	}
+
+	columns, err := parsePolicyColumns(inputColumns)
+	if err != nil {
+		return fmt.Errorf("Error parsing the input columns of masking policy %s: %w", d.Id(), err)
+	}
+
+	d.Set(maskingPolicyNameAttr, d.Id())
+	d.Set(maskingPolicyWithAttr, flattenPolicyColumns(columns))
+
+	using, err := parseMaskingPolicyExpressions(expressions)
+	if err != nil {
+		return fmt.Errorf("Error parsing the expressions of masking policy %s: %w", d.Id(), err)
+	}
+	// The stored expressions are rewritten by Redshift, so the configured ones are kept unless they differ in meaning.
+	if !maskingPolicyExpressionsEqual(d.Get(maskingPolicyUsingAttr).([]interface{}), using) {
+		d.Set(maskingPolicyUsingAttr, using)
+	}
+
+	return nil
+}
+
+func resourceRedshiftMaskingPolicyUpdate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if d.HasChange(maskingPolicyUsingAttr) {
+		query := fmt.Sprintf("ALTER MASKING POLICY %s USING (%s)", pq.QuoteIdentifier(d.Get(maskingPolicyNameAttr).(string)), maskingPolicyUsing(d))
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("Error updating masking policy USING: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourceRedshiftMaskingPolicyRead(db, d)
+}
+
+func resourceRedshiftMaskingPolicyDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := fmt.Sprintf("DROP MASKING POLICY %s", pq.QuoteIdentifier(d.Get(maskingPolicyNameAttr).(string)))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func createMaskingPolicyQuery(d *schema.ResourceData) string {
+	return fmt.Sprintf(
+		"CREATE MASKING POLICY %s WITH (%s) USING (%s)",
+		pq.QuoteIdentifier(d.Get(maskingPolicyNameAttr).(string)),
+		policyColumnsDefinition(d.Get(maskingPolicyWithAttr).([]interface{})),
+		maskingPolicyUsing(d),
+	)
+}
+
+func maskingPolicyUsing(d *schema.ResourceData) string {
+	expressions := []string{}
+	for _, expression := range d.Get(maskingPolicyUsingAttr).([]interface{}) {
+		expressions = append(expressions, expression.(string))
+	}
+	return strings.Join(expressions, ", ")
+}
+
+// parseMaskingPolicyExpressions parses the masking expressions of a policy,
+// stored as JSON in svv_masking_policy, e.g. `[{"expr":"'XXXX'::text","typmod":-1,"type":"text"}]`.
+func parseMaskingPolicyExpressions(raw string) ([]string, error) {
+	expressions := []maskingPolicyExpression{}
+	if strings.TrimSpace(raw) != "" {
+		if err := json.Unmarshal([]byte(raw), &expressions); err != nil {
+			return nil, err
+		}
+	}
+
+	using := []string{}
+	for _, expression := range expressions {
+		using = append(using, expression.Expression)
+	}
+	return using, nil
+}
+
+// maskingPolicyExpressionsEqual reports whether the configured expressions match the ones stored by Redshift,
+// once both are normalized by normalizeMaskingPolicyExpression.
+func maskingPolicyExpressionsEqual(configured []interface{}, stored []string) bool {
+	if len(configured) != len(stored) {
+		return false
+	}
+	for i, expression := range configured {
+		if normalizeMaskingPolicyExpression(expression.(string)) != normalizeMaskingPolicyExpression(stored[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+var maskingPolicyCastRegexp = regexp.MustCompile(`::"?[a-z_][a-z0-9_]*"?(\(\d+(,\d+)?\))?`)
+
+// normalizeMaskingPolicyExpression removes from the expression what Redshift changes when storing it,
+// i.e. casts, parentheses, whitespace and the case outside of string literals,
+// e.g. `SHA2(credit_card, 256)` and `sha2((credit_card)::text, 256)` are both normalized to `sha2credit_card,256`.
+func normalizeMaskingPolicyExpression(expression string) string {
+	normalizeCode := func(code string) string {
+		code = strings.ToLower(strings.Join(strings.Fields(code), ""))
+		code = maskingPolicyCastRegexp.ReplaceAllString(code, "")
+		return strings.NewReplacer("(", "", ")", "", `"`, "").Replace(code)
+	}
+
+	var normalized strings.Builder
+	for i, part := range strings.Split(expression, "'") {
+		// Odd parts are the contents of string literals, which are kept as they are.
+		if i%2 == 1 {
+			normalized.WriteString("'" + part + "'")
+		} else {
+			normalized.WriteString(normalizeCode(part))
+		}
+	}
+	return normalized.String()
+}
@@ -0,0 +1,342 @@
+package redshift
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	maskingPolicyAttachmentPolicyAttr       = "policy"
+	maskingPolicyAttachmentSchemaAttr       = "schema"
+	maskingPolicyAttachmentTableAttr        = "table"
+	maskingPolicyAttachmentColumnsAttr      = "columns"
+	maskingPolicyAttachmentInputColumnsAttr = "input_columns"
+	maskingPolicyAttachmentUserAttr         = "user"
+	maskingPolicyAttachmentRoleAttr         = "role"
+	maskingPolicyAttachmentPublicAttr       = "public"
+	maskingPolicyAttachmentPriorityAttr     = "priority"
+)
+
+func redshiftMaskingPolicyAttachment() *schema.Resource {
+	granteeAttrs := []string{maskingPolicyAttachmentUserAttr, maskingPolicyAttachmentRoleAttr, maskingPolicyAttachmentPublicAttr}
+
+	return &schema.Resource{
+		Description: `
+Attaches a masking policy to columns of a table for a user, a role or everyone with ` + "`ATTACH MASKING POLICY`" + `, so that they read the masked values of the columns. When several policies are attached to the same column for a user, the one with the highest priority applies.
+`,
+		Create: RedshiftResourceFunc(resourceRedshiftMaskingPolicyAttachmentCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftMaskingPolicyAttachmentRead),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftMaskingPolicyAttachmentDelete),
+		),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			maskingPolicyAttachmentPolicyAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the masking policy.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			maskingPolicyAttachmentSchemaAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "public",
+				ForceNew:     true,
+				Description:  "The schema of the table.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			maskingPolicyAttachmentTableAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the table to attach the policy to.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			maskingPolicyAttachmentColumnsAttr: {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Description: "The columns of the table whose values are masked, in the order of the masking expressions of the policy.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIdentifier,
+				},
+			},
+			maskingPolicyAttachmentInputColumnsAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The columns of the table passed as the input columns of the policy, in order. Defaults to `columns`.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIdentifier,
+				},
+			},
+			maskingPolicyAttachmentUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: granteeAttrs,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the user the policy applies to.",
+			},
+			maskingPolicyAttachmentRoleAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: granteeAttrs,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the role the policy applies to.",
+			},
+			maskingPolicyAttachmentPublicAttr: {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: granteeAttrs,
+				Description:  "Set to `true` to apply the policy to everyone.",
+			},
+			maskingPolicyAttachmentPriorityAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(0, 10000),
+				Description:  "The priority of the policy over the other policies attached to the same columns for the same user.",
+			},
+		},
+	}
+}
+
+func resourceRedshiftMaskingPolicyAttachmentCreate(db *DBConnection, d *schema.ResourceData) error {
+	granteeID, err := maskingPolicyAttachmentGranteeID(d)
+	if err != nil {
+		return err
+	}
+
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := maskingPolicyAttachmentQuery(d, "ATTACH")
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("could not attach masking policy %s: %w", d.Get(maskingPolicyAttachmentPolicyAttr).(string), err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(fmt.Sprintf(
+		"%s:%s.%s:%s",
+		strings.ToLower(d.Get(maskingPolicyAttachmentPolicyAttr).(string)),
+		strings.ToLower(d.Get(maskingPolicyAttachmentSchemaAttr).(string)),
+		strings.ToLower(d.Get(maskingPolicyAttachmentTableAttr).(string)),
+		granteeID,
+	))
+
+	return resourceRedshiftMaskingPolicyAttachmentRead(db, d)
+}
+
+func resourceRedshiftMaskingPolicyAttachmentRead(db *DBConnection, d *schema.ResourceData) error {
+	policyName, schemaName, tableName, granteeType, granteeName, err := parseMaskingPolicyAttachmentID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	var outputColumns, inputColumns string
+	var priority int
+	err = db.QueryRow(`
+	SELECT COALESCE(output_columns, ''), COALESCE(input_columns, ''), priority
+	FROM svv_attached_masking_policy
+	WHERE policy_name = $1 AND schema_name = $2 AND table_name = $3 AND lower(grantee_type) = $4 AND ($4 = 'public' OR grantee = $5)`,
+		policyName, schemaName, tableName, granteeType, granteeName).Scan(&outputColumns, &inputColumns, &priority)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift masking policy attachment (%s) not found", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading masking policy attachment: %w", err)
+	}
+
+	columns, err := parseMaskingPolicyAttachmentColumns(outputColumns)
+	if err != nil {
+		return fmt.Errorf("Error parsing the columns of masking policy attachment %s: %w", d.Id(), err)
+	}
+	input, err := parseMaskingPolicyAttachmentColumns(inputColumns)
+	if err != nil {
+		return fmt.Errorf("Error parsing the input columns of masking policy attachment %s: %w", d.Id(), err)
+	}
+
+	d.Set(maskingPolicyAttachmentPolicyAttr, policyName)
+	d.Set(maskingPolicyAttachmentSchemaAttr, schemaName)
+	d.Set(maskingPolicyAttachmentTableAttr, tableName)
+	d.Set(maskingPolicyAttachmentColumnsAttr, columns)
+	d.Set(maskingPolicyAttachmentInputColumnsAttr, input)
+	d.Set(maskingPolicyAttachmentPriorityAttr, priority)
+	switch granteeType {
+	case "user":
+		d.Set(maskingPolicyAttachmentUserAttr, granteeName)
+	case "role":
+		d.Set(maskingPolicyAttachmentRoleAttr, granteeName)
+	case "public":
+		d.Set(maskingPolicyAttachmentPublicAttr, true)
+	}
+
+	return nil
+}
+
+func resourceRedshiftMaskingPolicyAttachmentDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	policyName, schemaName, tableName, granteeType, granteeName, err := parseMaskingPolicyAttachmentID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	var attached bool
+	if err := tx.QueryRow(`
+	SELECT EXISTS (
+	  SELECT 1 FROM svv_attached_masking_policy
+	  WHERE policy_name = $1 AND schema_name = $2 AND table_name = $3 AND lower(grantee_type) = $4 AND ($4 = 'public' OR grantee = $5)
+	)`, policyName, schemaName, tableName, granteeType, granteeName).Scan(&attached); err != nil {
+		return err
+	}
+	if !attached {
+		log.Printf("[WARN] Redshift masking policy attachment (%s) was already removed", d.Id())
+		return nil
+	}
+
+	query := maskingPolicyAttachmentQuery(d, "DETACH")
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// maskingPolicyAttachmentQuery returns the ATTACH or DETACH statement of the policy, depending on the action.
+func maskingPolicyAttachmentQuery(d *schema.ResourceData, action string) string {
+	columns := quotedIdentifiers(d.Get(maskingPolicyAttachmentColumnsAttr).([]interface{}))
+	query := fmt.Sprintf(
+		"%s MASKING POLICY %s ON %s(%s)",
+		action,
+		pq.QuoteIdentifier(d.Get(maskingPolicyAttachmentPolicyAttr).(string)),
+		tableIdentifier(d.Get(maskingPolicyAttachmentSchemaAttr).(string), d.Get(maskingPolicyAttachmentTableAttr).(string)),
+		columns,
+	)
+
+	if action == "DETACH" {
+		return fmt.Sprintf("%s FROM %s", query, maskingPolicyAttachmentGrantee(d))
+	}
+
+	if input := quotedIdentifiers(d.Get(maskingPolicyAttachmentInputColumnsAttr).([]interface{})); input != "" && input != columns {
+		query = fmt.Sprintf("%s USING (%s)", query, input)
+	}
+	query = fmt.Sprintf("%s TO %s", query, maskingPolicyAttachmentGrantee(d))
+	if priority := d.Get(maskingPolicyAttachmentPriorityAttr).(int); priority != 0 {
+		query = fmt.Sprintf("%s PRIORITY %d", query, priority)
+	}
+	return query
+}
+
+// maskingPolicyAttachmentGrantee returns the grantee of the ATTACH and DETACH statements, e.g. `ROLE "analyst"`.
+func maskingPolicyAttachmentGrantee(d *schema.ResourceData) string {
+	if role, ok := d.GetOk(maskingPolicyAttachmentRoleAttr); ok {
+		return "ROLE " + pq.QuoteIdentifier(role.(string))
+	}
+	if user, ok := d.GetOk(maskingPolicyAttachmentUserAttr); ok {
+		return pq.QuoteIdentifier(user.(string))
+	}
+	return "PUBLIC"
+}
+
+// maskingPolicyAttachmentGranteeID returns the grantee part of the ID, e.g. "role:analyst" or "public".
+func maskingPolicyAttachmentGranteeID(d *schema.ResourceData) (string, error) {
+	if role, ok := d.GetOk(maskingPolicyAttachmentRoleAttr); ok {
+		return "role:" + strings.ToLower(role.(string)), nil
+	}
+	if user, ok := d.GetOk(maskingPolicyAttachmentUserAttr); ok {
+		return "user:" + strings.ToLower(user.(string)), nil
+	}
+	if d.Get(maskingPolicyAttachmentPublicAttr).(bool) {
+		return "public", nil
+	}
+	return "", fmt.Errorf("one of %s, %s or %s = true must be set", maskingPolicyAttachmentUserAttr, maskingPolicyAttachmentRoleAttr, maskingPolicyAttachmentPublicAttr)
+}
+
+// quotedIdentifiers returns the comma separated list of the quoted identifiers.
+func quotedIdentifiers(identifiers []interface{}) string {
+	quoted := []string{}
+	for _, identifier := range identifiers {
+		quoted = append(quoted, pq.QuoteIdentifier(identifier.(string)))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// parseMaskingPolicyAttachmentColumns parses the columns of an attached policy, stored as JSON in svv_attached_masking_policy, e.g. `["credit_card"]`.
+func parseMaskingPolicyAttachmentColumns(raw string) ([]string, error) {
+	columns := []string{}
+	if strings.TrimSpace(raw) == "" {
+		return columns, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// parseMaskingPolicyAttachmentID returns the policy, schema, table, grantee type and grantee name of the ID,
+// e.g. "mask_email:public.users:role:analyst" or "mask_email:public.users:public".
+func parseMaskingPolicyAttachmentID(id string) (string, string, string, string, string, error) {
+	invalid := fmt.Errorf("invalid masking policy attachment ID %q, expected <policy>:<schema>.<table>:<user|role>:<name> or <policy>:<schema>.<table>:public", id)
+
+	parts := strings.SplitN(id, ":", 4)
+	if len(parts) < 3 || parts[0] == "" {
+		return "", "", "", "", "", invalid
+	}
+	schemaAndTable := strings.SplitN(parts[1], ".", 2)
+	if len(schemaAndTable) != 2 || schemaAndTable[0] == "" || schemaAndTable[1] == "" {
+		return "", "", "", "", "", invalid
+	}
+
+	granteeType, granteeName := parts[2], ""
+	switch {
+	case granteeType == "public" && len(parts) == 3:
+	case (granteeType == "user" || granteeType == "role") && len(parts) == 4 && parts[3] != "":
+		granteeName = parts[3]
+	default:
+		return "", "", "", "", "", invalid
+	}
+
+	return parts[0], schemaAndTable[0], schemaAndTable[1], granteeType, granteeName, nil
+}
@@ -0,0 +1,169 @@
+package redshift
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccRedshiftMaskingPolicyAttachment_Basic(t *testing.T) {
+	prefix := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_masking"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name = "%[1]s_role"
+}
+
+resource "redshift_schema" "schema" {
+  name = "%[1]s_schema"
+}
+
+resource "redshift_table" "table" {
+  name   = "payments"
+  schema = redshift_schema.schema.name
+
+  column {
+    name = "credit_card"
+    type = "varchar(16)"
+  }
+}
+
+resource "redshift_masking_policy" "policy" {
+  name  = "%[1]s_policy"
+  using = ["'XXXXXXXXXXXXXXXX'::varchar(16)"]
+
+  with {
+    name = "credit_card"
+    type = "varchar(16)"
+  }
+}
+
+resource "redshift_masking_policy_attachment" "role" {
+  policy   = redshift_masking_policy.policy.name
+  schema   = redshift_table.table.schema
+  table    = redshift_table.table.name
+  columns  = ["credit_card"]
+  role     = redshift_role.role.name
+  priority = 10
+}
+
+resource "redshift_masking_policy_attachment" "public" {
+  policy  = redshift_masking_policy.policy.name
+  schema  = redshift_table.table.schema
+  table   = redshift_table.table.name
+  columns = ["credit_card"]
+  public  = true
+}
+`, prefix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftMaskingPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_masking_policy_attachment.role", "id", fmt.Sprintf("%[1]s_policy:%[1]s_schema.payments:role:%[1]s_role", prefix)),
+					resource.TestCheckResourceAttr("redshift_masking_policy_attachment.role", "priority", "10"),
+					resource.TestCheckResourceAttr("redshift_masking_policy_attachment.role", "input_columns.0", "credit_card"),
+					resource.TestCheckResourceAttr("redshift_masking_policy_attachment.public", "id", fmt.Sprintf("%[1]s_policy:%[1]s_schema.payments:public", prefix)),
+				),
+			},
+			{
+				ResourceName:      "redshift_masking_policy_attachment.role",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "redshift_masking_policy_attachment.public",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestMaskingPolicyAttachmentQuery(t *testing.T) {
+	cases := map[string]struct {
+		config   map[string]interface{}
+		action   string
+		expected string
+	}{
+		"user": {
+			config: map[string]interface{}{
+				maskingPolicyAttachmentUserAttr: "bob",
+			},
+			action:   "ATTACH",
+			expected: `ATTACH MASKING POLICY "mask_card" ON "sales"."payments"("credit_card") TO "bob"`,
+		},
+		"role with priority and input columns": {
+			config: map[string]interface{}{
+				maskingPolicyAttachmentRoleAttr:         "analyst",
+				maskingPolicyAttachmentInputColumnsAttr: []interface{}{"card_number"},
+				maskingPolicyAttachmentPriorityAttr:     20,
+			},
+			action:   "ATTACH",
+			expected: `ATTACH MASKING POLICY "mask_card" ON "sales"."payments"("credit_card") USING ("card_number") TO ROLE "analyst" PRIORITY 20`,
+		},
+		"detach public": {
+			config: map[string]interface{}{
+				maskingPolicyAttachmentPublicAttr:   true,
+				maskingPolicyAttachmentPriorityAttr: 20,
+			},
+			action:   "DETACH",
+			expected: `DETACH MASKING POLICY "mask_card" ON "sales"."payments"("credit_card") FROM PUBLIC`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			c.config[maskingPolicyAttachmentPolicyAttr] = "mask_card"
+			c.config[maskingPolicyAttachmentSchemaAttr] = "sales"
+			c.config[maskingPolicyAttachmentTableAttr] = "payments"
+			c.config[maskingPolicyAttachmentColumnsAttr] = []interface{}{"credit_card"}
+			d := schema.TestResourceDataRaw(t, redshiftMaskingPolicyAttachment().Schema, c.config)
+			if actual := maskingPolicyAttachmentQuery(d, c.action); actual != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseMaskingPolicyAttachmentID(t *testing.T) {
+	cases := map[string][]string{
+		"mask_card:sales.payments:role:analyst": {"mask_card", "sales", "payments", "role", "analyst"},
+		"mask_card:sales.payments:user:bob":     {"mask_card", "sales", "payments", "user", "bob"},
+		"mask_card:sales.payments:public":       {"mask_card", "sales", "payments", "public", ""},
+	}
+	for id, expected := range cases {
+		policyName, schemaName, tableName, granteeType, granteeName, err := parseMaskingPolicyAttachmentID(id)
+		if err != nil {
+			t.Errorf("expected %q to be valid, got %s", id, err)
+			continue
+		}
+		if actual := []string{policyName, schemaName, tableName, granteeType, granteeName}; !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %q to be parsed as %v, got %v", id, expected, actual)
+		}
+	}
+
+	for _, id := range []string{"mask_card:sales.payments", "mask_card:payments:public", "mask_card:sales.payments:role", "mask_card:sales.payments:public:bob", "mask_card:sales.payments:group:analysts"} {
+		if _, _, _, _, _, err := parseMaskingPolicyAttachmentID(id); err == nil {
+			t.Errorf("expected %q to be an invalid ID", id)
+		}
+	}
+}
+
+func TestParseMaskingPolicyAttachmentColumns(t *testing.T) {
+	columns, err := parseMaskingPolicyAttachmentColumns(`["credit_card", "email"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"credit_card", "email"}; !reflect.DeepEqual(columns, expected) {
+		t.Errorf("expected %v, got %v", expected, columns)
+	}
+}
@@ -0,0 +1,137 @@
+package redshift
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftMaskingPolicy_Basic(t *testing.T) {
+	policyName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_masking_policy"), "-", "_")
+	config := func(using string) string {
+		return fmt.Sprintf(`
+resource "redshift_masking_policy" "policy" {
+  name  = %[1]q
+  using = [%[2]q]
+
+  with {
+    name = "credit_card"
+    type = "varchar(16)"
+  }
+}
+`, policyName, using)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftMaskingPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("'XXXXXXXXXXXXXXXX'::varchar(16)"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_masking_policy.policy", "id", policyName),
+					resource.TestCheckResourceAttr("redshift_masking_policy.policy", "with.#", "1"),
+					resource.TestCheckResourceAttr("redshift_masking_policy.policy", "with.0.name", "credit_card"),
+					resource.TestCheckResourceAttr("redshift_masking_policy.policy", "using.#", "1"),
+				),
+			},
+			{
+				Config: config("SHA2(credit_card, 256)::varchar(16)"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_masking_policy.policy", "using.0", "SHA2(credit_card, 256)::varchar(16)"),
+				),
+			},
+			{
+				ResourceName:            "redshift_masking_policy.policy",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{maskingPolicyUsingAttr},
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftMaskingPolicyDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.Connect()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_masking_policy" {
+			continue
+		}
+
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM svv_masking_policy WHERE policy_name = $1", rs.Primary.ID).Scan(&count); err != nil {
+			return fmt.Errorf("Error checking masking policy %s", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("Masking policy %s still exists after destroy", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func TestCreateMaskingPolicyQuery(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftMaskingPolicy().Schema, map[string]interface{}{
+		maskingPolicyNameAttr: "mask_card",
+		maskingPolicyWithAttr: []interface{}{
+			map[string]interface{}{policyColumnNameAttr: "credit_card", policyColumnTypeAttr: "varchar(16)"},
+			map[string]interface{}{policyColumnNameAttr: "is_admin", policyColumnTypeAttr: "boolean"},
+		},
+		maskingPolicyUsingAttr: []interface{}{"CASE WHEN is_admin THEN credit_card ELSE 'XXXX' END", "false"},
+	})
+
+	expected := `CREATE MASKING POLICY "mask_card" WITH ("credit_card" varchar(16), "is_admin" boolean) USING (CASE WHEN is_admin THEN credit_card ELSE 'XXXX' END, false)`
+	if actual := createMaskingPolicyQuery(d); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
+
+func TestParseMaskingPolicyExpressions(t *testing.T) {
+	expressions, err := parseMaskingPolicyExpressions(`[{"expr":"'XXXX'::text","typmod":-1,"type":"text"},{"expr":"false","typmod":-1,"type":"boolean"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"'XXXX'::text", "false"}
+	if !reflect.DeepEqual(expressions, expected) {
+		t.Errorf("expected %v, got %v", expected, expressions)
+	}
+
+	if _, err := parseMaskingPolicyExpressions("not json"); err == nil {
+		t.Error("expected an error for invalid expressions")
+	}
+}
+
+func TestMaskingPolicyExpressionsEqual(t *testing.T) {
+	for name, tc := range map[string]struct {
+		configured []interface{}
+		stored     []string
+		expected   bool
+	}{
+		"same":                {[]interface{}{"'XXXX'::text"}, []string{"'XXXX'::text"}, true},
+		"added cast":          {[]interface{}{"'XXXX'"}, []string{"'XXXX'::character varying(16)"}, true},
+		"function":            {[]interface{}{"SHA2(credit_card, 256)"}, []string{"sha2((credit_card)::text, 256)"}, true},
+		"quoted identifier":   {[]interface{}{`"Credit_Card"`}, []string{"credit_card"}, true},
+		"changed literal":     {[]interface{}{"'XXXX'::text"}, []string{"'xxxx'::text"}, false},
+		"changed function":    {[]interface{}{"SHA2(credit_card, 256)"}, []string{"md5((credit_card)::text)"}, false},
+		"different count":     {[]interface{}{"'XXXX'", "false"}, []string{"'XXXX'::text"}, false},
+		"cast inside literal": {[]interface{}{"'a::text'"}, []string{"'a'"}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if actual := maskingPolicyExpressionsEqual(tc.configured, tc.stored); actual != tc.expected {
+				t.Errorf("expected %t for %v and %v, got %t", tc.expected, tc.configured, tc.stored, actual)
+			}
+		})
+	}
+}
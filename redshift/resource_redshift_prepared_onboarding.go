@@ -0,0 +1,370 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	preparedOnboardingNameAttr         = "name"
+	preparedOnboardingPasswordAttr     = "password"
+	preparedOnboardingGroupsAttr       = "groups"
+	preparedOnboardingSchemaAccessAttr = "schema_access"
+
+	preparedOnboardingSchemaAccessSchemaAttr          = "schema"
+	preparedOnboardingSchemaAccessPrivilegesAttr      = "privileges"
+	preparedOnboardingSchemaAccessTablePrivilegesAttr = "table_privileges"
+)
+
+// preparedOnboardingTablePrivileges are the table privileges which are read back from svv_relation_privileges.
+// It doesn't report rule and trigger, so they would show up as a difference on every plan.
+var preparedOnboardingTablePrivileges = []string{"select", "update", "insert", "delete", "drop", "references", "truncate"}
+
+func redshiftPreparedOnboarding() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Onboards a user in a single resource: creates the user, adds it to existing groups and grants it a standard set of privileges on schemas and their tables. Everything is applied in a single transaction, so a failed apply leaves no half-onboarded user behind.
+
+Use it to keep the configuration of many similar users short. Users needing more settings or finer grained privileges are better managed with ` + "`redshift_user`" + `, ` + "`redshift_group`" + ` and ` + "`redshift_grant`" + `. Do not manage the group memberships of the user in the ` + "`users`" + ` of ` + "`redshift_group`" + ` as well, as they would override each other.
+
+Note: Table privileges are granted on the tables existing at the time of the apply. Use ` + "`redshift_default_privileges`" + ` for tables created later. The user can't be dropped while it owns objects.
+`,
+		Create: RedshiftResourceFunc(resourceRedshiftPreparedOnboardingCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftPreparedOnboardingRead),
+		Update: RedshiftResourceFunc(resourceRedshiftPreparedOnboardingUpdate),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftPreparedOnboardingDelete),
+		),
+		Exists: RedshiftResourceExistsFunc(resourceRedshiftUserExists),
+		Schema: map[string]*schema.Schema{
+			preparedOnboardingNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the user to create. The user name can't be `PUBLIC`.",
+				ValidateFunc: validation.All(validation.StringNotInSlice([]string{"public"}, true), validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			preparedOnboardingPasswordAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The password of the user, or its MD5 hash. The password is disabled when omitted, e.g. for users authenticating with IAM.",
+			},
+			preparedOnboardingGroupsAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateIdentifier},
+				Set:         schema.HashString,
+				Description: "The existing groups to add the user to.",
+			},
+			preparedOnboardingSchemaAccessAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The privileges to grant the user on a schema and on all its tables.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						preparedOnboardingSchemaAccessSchemaAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The name of the schema.",
+							ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+						},
+						preparedOnboardingSchemaAccessPrivilegesAttr: {
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice(allowedPrivileges["schema"], true),
+								StateFunc: func(val interface{}) string {
+									return strings.ToLower(val.(string))
+								},
+							},
+							Set:         schema.HashString,
+							Description: "The privileges on the schema, any of `" + strings.Join(allowedPrivileges["schema"], "`, `") + "`.",
+						},
+						preparedOnboardingSchemaAccessTablePrivilegesAttr: {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice(preparedOnboardingTablePrivileges, true),
+								StateFunc: func(val interface{}) string {
+									return strings.ToLower(val.(string))
+								},
+							},
+							Set:         schema.HashString,
+							Description: "The privileges on all tables of the schema, any of `" + strings.Join(preparedOnboardingTablePrivileges, "`, `") + "`.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceRedshiftPreparedOnboardingCreate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	userName := strings.ToLower(d.Get(preparedOnboardingNameAttr).(string))
+
+	query := fmt.Sprintf("CREATE USER %s WITH %s", pq.QuoteIdentifier(userName), preparedOnboardingPassword(d))
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating user %s: %w", userName, err)
+	}
+
+	queries := []string{}
+	for _, groupName := range d.Get(preparedOnboardingGroupsAttr).(*schema.Set).List() {
+		queries = append(queries, preparedOnboardingGroupQuery("ADD", groupName.(string), userName))
+	}
+	for _, access := range d.Get(preparedOnboardingSchemaAccessAttr).(*schema.Set).List() {
+		queries = append(queries, preparedOnboardingSchemaAccessQueries("GRANT", access.(map[string]interface{}), userName)...)
+	}
+	if err := execPreparedOnboardingQueries(tx, queries); err != nil {
+		return err
+	}
+
+	var usesysid string
+	if err := tx.QueryRow("SELECT usesysid FROM pg_user_info WHERE usename = $1", userName).Scan(&usesysid); err != nil {
+		return fmt.Errorf("user does not exist in pg_user_info table: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(usesysid)
+
+	return resourceRedshiftPreparedOnboardingRead(db, d)
+}
+
+func resourceRedshiftPreparedOnboardingRead(db *DBConnection, d *schema.ResourceData) error {
+	var userName string
+	err := db.QueryRow("SELECT trim(usename) FROM pg_user_info WHERE usesysid = $1", d.Id()).Scan(&userName)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift User (%s) not found", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading User: %w", err)
+	}
+
+	rows, err := db.Query("SELECT groname FROM pg_group WHERE $1 = ANY(grolist)", d.Id())
+	if err != nil {
+		return fmt.Errorf("Error reading group memberships: %w", err)
+	}
+	defer rows.Close()
+
+	groups := schema.NewSet(schema.HashString, nil)
+	for rows.Next() {
+		var groupName string
+		if err := rows.Scan(&groupName); err != nil {
+			return err
+		}
+		groups.Add(groupName)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	schemaAccess := []interface{}{}
+	for _, raw := range d.Get(preparedOnboardingSchemaAccessAttr).(*schema.Set).List() {
+		access, err := readPreparedOnboardingSchemaAccess(db, userName, raw.(map[string]interface{}))
+		if err != nil {
+			return err
+		}
+		schemaAccess = append(schemaAccess, access)
+	}
+
+	d.Set(preparedOnboardingNameAttr, userName)
+	d.Set(preparedOnboardingGroupsAttr, groups)
+	d.Set(preparedOnboardingSchemaAccessAttr, schemaAccess)
+
+	return nil
+}
+
+// readPreparedOnboardingSchemaAccess reads the privileges of the user on the configured schema.
+// Table privileges are only reported when the user has them on all tables of the schema.
+func readPreparedOnboardingSchemaAccess(db *DBConnection, userName string, configured map[string]interface{}) (map[string]interface{}, error) {
+	schemaName := configured[preparedOnboardingSchemaAccessSchemaAttr].(string)
+
+	schemaPrivileges := schema.NewSet(schema.HashString, nil)
+	rows, err := db.Query(`
+	SELECT lower(privilege_type)
+	FROM svv_schema_privileges
+	WHERE identity_type = 'user' AND identity_name = $1 AND namespace_name = $2
+`, userName, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading schema privileges: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var privilege string
+		if err := rows.Scan(&privilege); err != nil {
+			return nil, err
+		}
+		schemaPrivileges.Add(privilege)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tables, err := schemaObjectNames(db, schemaName, "table")
+	if err != nil {
+		return nil, err
+	}
+
+	tablePrivileges := schema.NewSet(schema.HashString, nil)
+	if len(tables) > 0 {
+		rows, err := db.Query(`
+	SELECT lower(privilege_type), COUNT(DISTINCT relation_name)
+	FROM svv_relation_privileges
+	WHERE identity_type = 'user' AND identity_name = $1 AND namespace_name = $2
+	GROUP BY 1
+`, userName, schemaName)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading table privileges: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var privilege string
+			var count int
+			if err := rows.Scan(&privilege, &count); err != nil {
+				return nil, err
+			}
+			if count == len(tables) && sliceContainsStr(preparedOnboardingTablePrivileges, privilege) {
+				tablePrivileges.Add(privilege)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	} else {
+		// Without tables the configured privileges can't be verified, so they are kept.
+		tablePrivileges = configured[preparedOnboardingSchemaAccessTablePrivilegesAttr].(*schema.Set)
+	}
+
+	return map[string]interface{}{
+		preparedOnboardingSchemaAccessSchemaAttr:          schemaName,
+		preparedOnboardingSchemaAccessPrivilegesAttr:      schemaPrivileges,
+		preparedOnboardingSchemaAccessTablePrivilegesAttr: tablePrivileges,
+	}, nil
+}
+
+func resourceRedshiftPreparedOnboardingUpdate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	userName := strings.ToLower(d.Get(preparedOnboardingNameAttr).(string))
+
+	queries := []string{}
+	if d.HasChange(preparedOnboardingPasswordAttr) {
+		queries = append(queries, fmt.Sprintf("ALTER USER %s %s", pq.QuoteIdentifier(userName), preparedOnboardingPassword(d)))
+	}
+
+	oldGroups, newGroups := d.GetChange(preparedOnboardingGroupsAttr)
+	for _, groupName := range oldGroups.(*schema.Set).Difference(newGroups.(*schema.Set)).List() {
+		queries = append(queries, preparedOnboardingGroupQuery("DROP", groupName.(string), userName))
+	}
+	for _, groupName := range newGroups.(*schema.Set).Difference(oldGroups.(*schema.Set)).List() {
+		queries = append(queries, preparedOnboardingGroupQuery("ADD", groupName.(string), userName))
+	}
+
+	oldAccess, newAccess := d.GetChange(preparedOnboardingSchemaAccessAttr)
+	for _, access := range oldAccess.(*schema.Set).Difference(newAccess.(*schema.Set)).List() {
+		queries = append(queries, preparedOnboardingSchemaAccessQueries("REVOKE", access.(map[string]interface{}), userName)...)
+	}
+	for _, access := range newAccess.(*schema.Set).Difference(oldAccess.(*schema.Set)).List() {
+		queries = append(queries, preparedOnboardingSchemaAccessQueries("GRANT", access.(map[string]interface{}), userName)...)
+	}
+
+	if err := execPreparedOnboardingQueries(tx, queries); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourceRedshiftPreparedOnboardingRead(db, d)
+}
+
+func resourceRedshiftPreparedOnboardingDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	userName := strings.ToLower(d.Get(preparedOnboardingNameAttr).(string))
+
+	queries := []string{}
+	for _, access := range d.Get(preparedOnboardingSchemaAccessAttr).(*schema.Set).List() {
+		queries = append(queries, preparedOnboardingSchemaAccessQueries("REVOKE", access.(map[string]interface{}), userName)...)
+	}
+	for _, groupName := range d.Get(preparedOnboardingGroupsAttr).(*schema.Set).List() {
+		queries = append(queries, preparedOnboardingGroupQuery("DROP", groupName.(string), userName))
+	}
+	queries = append(queries, fmt.Sprintf("DROP USER %s", pq.QuoteIdentifier(userName)))
+
+	if err := execPreparedOnboardingQueries(tx, queries); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func execPreparedOnboardingQueries(tx *sql.Tx, queries []string) error {
+	for _, query := range queries {
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("could not onboard user: %w", err)
+		}
+	}
+	return nil
+}
+
+func preparedOnboardingPassword(d *schema.ResourceData) string {
+	if password := d.Get(preparedOnboardingPasswordAttr).(string); password != "" {
+		return fmt.Sprintf("PASSWORD '%s'", pqQuoteLiteral(password))
+	}
+	return "PASSWORD DISABLE"
+}
+
+func preparedOnboardingGroupQuery(action, groupName, userName string) string {
+	return fmt.Sprintf("ALTER GROUP %s %s USER %s", pq.QuoteIdentifier(groupName), action, pq.QuoteIdentifier(userName))
+}
+
+// preparedOnboardingSchemaAccessQueries returns the statements granting or revoking the privileges of a schema_access block.
+func preparedOnboardingSchemaAccessQueries(verb string, access map[string]interface{}, userName string) []string {
+	preposition := "TO"
+	if verb == "REVOKE" {
+		preposition = "FROM"
+	}
+	quotedSchema := pq.QuoteIdentifier(access[preparedOnboardingSchemaAccessSchemaAttr].(string))
+
+	queries := []string{}
+	if schemaPrivileges := access[preparedOnboardingSchemaAccessPrivilegesAttr].(*schema.Set); schemaPrivileges.Len() > 0 {
+		queries = append(queries, fmt.Sprintf("%s %s ON SCHEMA %s %s %s", verb, strings.Join(privilegesList(schemaPrivileges), ","), quotedSchema, preposition, pq.QuoteIdentifier(userName)))
+	}
+	if tablePrivileges := access[preparedOnboardingSchemaAccessTablePrivilegesAttr].(*schema.Set); tablePrivileges.Len() > 0 {
+		queries = append(queries, fmt.Sprintf("%s %s ON ALL TABLES IN SCHEMA %s %s %s", verb, strings.Join(privilegesList(tablePrivileges), ","), quotedSchema, preposition, pq.QuoteIdentifier(userName)))
+	}
+	return queries
+}
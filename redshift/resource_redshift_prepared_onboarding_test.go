@@ -0,0 +1,93 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftPreparedOnboarding_Basic(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user"), "-", "_")
+	groupNames := []string{
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group_a"), "-", "_"),
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_group_b"), "-", "_"),
+	}
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+
+	config := func(group string, tablePrivileges string) string {
+		return fmt.Sprintf(`
+resource "redshift_group" "a" {
+  name = %[2]q
+}
+
+resource "redshift_group" "b" {
+  name = %[3]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[4]q
+}
+
+resource "redshift_prepared_onboarding" "analyst" {
+  name   = %[1]q
+  groups = [redshift_group.%[5]s.name]
+
+  schema_access {
+    schema           = redshift_schema.schema.name
+    privileges       = ["usage"]
+    table_privileges = %[6]s
+  }
+}
+`, userName, groupNames[0], groupNames[1], schemaName, group, tablePrivileges)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftPreparedOnboardingDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("a", `["select"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftUserExists(userName),
+					resource.TestCheckResourceAttr("redshift_prepared_onboarding.analyst", "name", userName),
+					resource.TestCheckResourceAttr("redshift_prepared_onboarding.analyst", "groups.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_prepared_onboarding.analyst", "groups.*", groupNames[0]),
+					resource.TestCheckResourceAttr("redshift_prepared_onboarding.analyst", "schema_access.#", "1"),
+				),
+			},
+			{
+				Config: config("b", `["select", "insert"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_prepared_onboarding.analyst", "groups.#", "1"),
+					resource.TestCheckTypeSetElemAttr("redshift_prepared_onboarding.analyst", "groups.*", groupNames[1]),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftPreparedOnboardingDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_prepared_onboarding" {
+			continue
+		}
+
+		exists, err := checkUserExists(client, rs.Primary.Attributes[preparedOnboardingNameAttr])
+		if err != nil {
+			return fmt.Errorf("Error checking user %s", err)
+		}
+
+		if exists {
+			return fmt.Errorf("User still exists after destroy")
+		}
+	}
+
+	return nil
+}
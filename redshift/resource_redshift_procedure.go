@@ -0,0 +1,321 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	procedureNameAttr      = "name"
+	procedureSchemaAttr    = "schema"
+	procedureArgumentAttr  = "argument"
+	procedureBodyAttr      = "body"
+	procedureSecurityAttr  = "security"
+	procedureOwnerAttr     = "owner"
+	procedureSignatureAttr = "signature"
+
+	procedureArgumentNameAttr = "name"
+	procedureArgumentTypeAttr = "type"
+	procedureArgumentModeAttr = "mode"
+
+	procedureSecurityInvoker = "INVOKER"
+	procedureSecurityDefiner = "DEFINER"
+)
+
+func redshiftProcedure() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Stored procedures encapsulate logic for data transformation, data validation and business-specific rules. The procedure is created with ` + "`CREATE OR REPLACE PROCEDURE`" + `, so changes of its body are applied in place. The body is compared with the one stored in the catalog, so changes made outside of Terraform are detected.
+`,
+		Create: RedshiftResourceFunc(resourceRedshiftProcedureCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftProcedureRead),
+		Update: RedshiftResourceFunc(resourceRedshiftProcedureUpdate),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftProcedureDelete),
+		),
+		Exists: RedshiftResourceExistsFunc(resourceRedshiftProcedureExists),
+		Schema: map[string]*schema.Schema{
+			procedureNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the procedure.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			procedureSchemaAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "public",
+				ValidateFunc: validateIdentifier,
+				Description:  "The schema the procedure is created in.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			procedureArgumentAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The arguments of the procedure, in order. Changing them creates a new procedure, as they are part of its signature.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						procedureArgumentNameAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							ValidateFunc: validateIdentifier,
+							Description:  "The name of the argument.",
+						},
+						procedureArgumentTypeAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							Description:  "The data type of the argument.",
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+						},
+						procedureArgumentModeAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							Default:      "IN",
+							Description:  "The mode of the argument, one of `IN`, `OUT` or `INOUT`. `OUT` arguments are not part of the signature.",
+							ValidateFunc: validation.StringInSlice([]string{"IN", "OUT", "INOUT"}, true),
+						},
+					},
+				},
+			},
+			procedureBodyAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The body of the procedure in PL/pgSQL, i.e. the text between the `$$` quotes. Leading and trailing whitespace is ignored.",
+				ValidateFunc: validation.StringDoesNotContainAny("$$"),
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return strings.TrimSpace(old) == strings.TrimSpace(new)
+				},
+			},
+			procedureSecurityAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      procedureSecurityInvoker,
+				Description:  "Whether the procedure runs with the privileges of the user calling it (`INVOKER`) or of its owner (`DEFINER`).",
+				ValidateFunc: validation.StringInSlice([]string{procedureSecurityInvoker, procedureSecurityDefiner}, false),
+			},
+			procedureOwnerAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the user owning the procedure. Defaults to the user creating it.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			procedureSignatureAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The signature of the procedure, i.e. its name followed by the types of its `IN` and `INOUT` arguments, e.g. `my_procedure(integer, character varying)`. It can be used in the `objects` of `redshift_grant`.",
+			},
+		},
+	}
+}
+
+func resourceRedshiftProcedureExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	var name string
+	err := db.QueryRow("SELECT proname FROM pg_proc_info WHERE prooid = $1", d.Id()).Scan(&name)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
+
+func resourceRedshiftProcedureCreate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := createOrReplaceProcedure(tx, d); err != nil {
+		return err
+	}
+
+	if err := setProcedureOwner(tx, d); err != nil {
+		return err
+	}
+
+	var procedureID string
+	query := fmt.Sprintf("SELECT '%s'::regprocedure::oid", pqQuoteLiteral(procedureQualifiedSignature(d)))
+	log.Printf("[DEBUG] %s\n", query)
+	if err := tx.QueryRow(query).Scan(&procedureID); err != nil {
+		return fmt.Errorf("could not get the ID of procedure %s: %w", procedureQualifiedSignature(d), err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(procedureID)
+
+	return resourceRedshiftProcedureRead(db, d)
+}
+
+func resourceRedshiftProcedureRead(db *DBConnection, d *schema.ResourceData) error {
+	var name, schemaName, body, owner string
+	var securityDefiner bool
+
+	err := db.QueryRow(`
+	SELECT p.proname, n.nspname, p.prosrc, p.prosecdef, COALESCE(trim(u.usename), '')
+	FROM pg_proc_info p
+	JOIN pg_namespace n ON n.oid = p.pronamespace
+	LEFT JOIN pg_user_info u ON u.usesysid = p.proowner
+	WHERE p.prooid = $1
+`, d.Id()).Scan(&name, &schemaName, &body, &securityDefiner, &owner)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift Procedure (%s) not found", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading procedure: %w", err)
+	}
+
+	security := procedureSecurityInvoker
+	if securityDefiner {
+		security = procedureSecurityDefiner
+	}
+
+	d.Set(procedureNameAttr, name)
+	d.Set(procedureSchemaAttr, schemaName)
+	d.Set(procedureBodyAttr, body)
+	d.Set(procedureSecurityAttr, security)
+	d.Set(procedureOwnerAttr, owner)
+	d.Set(procedureSignatureAttr, procedureSignature(d))
+
+	return nil
+}
+
+func resourceRedshiftProcedureUpdate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if d.HasChanges(procedureBodyAttr, procedureSecurityAttr) {
+		if err := createOrReplaceProcedure(tx, d); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange(procedureOwnerAttr) {
+		if err := setProcedureOwner(tx, d); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourceRedshiftProcedureRead(db, d)
+}
+
+func resourceRedshiftProcedureDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := fmt.Sprintf("DROP PROCEDURE %s", procedureQualifiedSignature(d))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func createOrReplaceProcedure(tx *sql.Tx, d *schema.ResourceData) error {
+	arguments := []string{}
+	for _, raw := range d.Get(procedureArgumentAttr).([]interface{}) {
+		argument := raw.(map[string]interface{})
+		parts := []string{}
+		if name := argument[procedureArgumentNameAttr].(string); name != "" {
+			parts = append(parts, pq.QuoteIdentifier(name))
+		}
+		parts = append(parts, strings.ToUpper(argument[procedureArgumentModeAttr].(string)), argument[procedureArgumentTypeAttr].(string))
+		arguments = append(arguments, strings.Join(parts, " "))
+	}
+
+	query := fmt.Sprintf(
+		"CREATE OR REPLACE PROCEDURE %s.%s(%s) AS $$%s$$ LANGUAGE plpgsql SECURITY %s",
+		pq.QuoteIdentifier(d.Get(procedureSchemaAttr).(string)),
+		pq.QuoteIdentifier(d.Get(procedureNameAttr).(string)),
+		strings.Join(arguments, ", "),
+		d.Get(procedureBodyAttr).(string),
+		d.Get(procedureSecurityAttr).(string),
+	)
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("could not create procedure %s: %w", procedureQualifiedSignature(d), err)
+	}
+	return nil
+}
+
+func setProcedureOwner(tx *sql.Tx, d *schema.ResourceData) error {
+	owner, ok := d.GetOk(procedureOwnerAttr)
+	if !ok {
+		return nil
+	}
+
+	query := fmt.Sprintf("ALTER PROCEDURE %s OWNER TO %s", procedureQualifiedSignature(d), pq.QuoteIdentifier(strings.ToLower(owner.(string))))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Error updating procedure OWNER: %w", err)
+	}
+	return nil
+}
+
+// procedureArgumentTypes returns the normalized types of the arguments identifying the procedure, that is without OUT arguments.
+func procedureArgumentTypes(d *schema.ResourceData) []string {
+	types := []string{}
+	for _, raw := range d.Get(procedureArgumentAttr).([]interface{}) {
+		argument := raw.(map[string]interface{})
+		if strings.ToUpper(argument[procedureArgumentModeAttr].(string)) == "OUT" {
+			continue
+		}
+		types = append(types, normalizeColumnType(argument[procedureArgumentTypeAttr].(string)))
+	}
+	return types
+}
+
+// procedureSignature returns the unqualified signature, in the format used by redshift_grant.
+func procedureSignature(d *schema.ResourceData) string {
+	return fmt.Sprintf("%s(%s)", strings.ToLower(d.Get(procedureNameAttr).(string)), strings.Join(procedureArgumentTypes(d), ", "))
+}
+
+// procedureQualifiedSignature returns the signature qualified with the schema, for use in statements.
+func procedureQualifiedSignature(d *schema.ResourceData) string {
+	return fmt.Sprintf(
+		"%s.%s(%s)",
+		pq.QuoteIdentifier(strings.ToLower(d.Get(procedureSchemaAttr).(string))),
+		pq.QuoteIdentifier(strings.ToLower(d.Get(procedureNameAttr).(string))),
+		strings.Join(procedureArgumentTypes(d), ", "),
+	)
+}
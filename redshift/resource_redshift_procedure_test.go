@@ -0,0 +1,120 @@
+package redshift
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestProcedureSignature(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftProcedure().Schema, map[string]interface{}{
+		procedureNameAttr:   "My_Procedure",
+		procedureSchemaAttr: "my_schema",
+		procedureBodyAttr:   "BEGIN END;",
+		procedureArgumentAttr: []interface{}{
+			map[string]interface{}{"name": "a", "type": "int"},
+			map[string]interface{}{"name": "b", "type": "VARCHAR(10)", "mode": "INOUT"},
+			map[string]interface{}{"name": "c", "type": "bool", "mode": "out"},
+		},
+	})
+
+	if expected, actual := "my_procedure(integer, character varying(10))", procedureSignature(d); actual != expected {
+		t.Errorf("expected signature %q, got %q", expected, actual)
+	}
+	if expected, actual := `"my_schema"."my_procedure"(integer, character varying(10))`, procedureQualifiedSignature(d); actual != expected {
+		t.Errorf("expected qualified signature %q, got %q", expected, actual)
+	}
+}
+
+func TestAccRedshiftProcedure_Basic(t *testing.T) {
+	procedureName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_procedure"), "-", "_")
+	config := func(body, security string) string {
+		return fmt.Sprintf(`
+resource "redshift_procedure" "procedure" {
+  name     = %[1]q
+  security = %[3]q
+
+  argument {
+    name = "value"
+    type = "int"
+  }
+
+  argument {
+    name = "result"
+    type = "int"
+    mode = "OUT"
+  }
+
+  body = <<-EOT
+  BEGIN
+    %[2]s
+  END;
+  EOT
+}
+
+resource "redshift_grant" "public" {
+  group       = "public"
+  schema      = "public"
+  object_type = "procedure"
+  objects     = [redshift_procedure.procedure.signature]
+  privileges  = ["execute"]
+}
+`, procedureName, body, security)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftProcedureDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("result := value;", "INVOKER"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_procedure.procedure", "name", procedureName),
+					resource.TestCheckResourceAttr("redshift_procedure.procedure", "schema", "public"),
+					resource.TestCheckResourceAttr("redshift_procedure.procedure", "signature", fmt.Sprintf("%s(integer)", procedureName)),
+					resource.TestCheckResourceAttr("redshift_procedure.procedure", "security", "INVOKER"),
+					resource.TestCheckResourceAttrSet("redshift_procedure.procedure", "owner"),
+					resource.TestCheckResourceAttr("redshift_grant.public", "privileges.#", "1"),
+				),
+			},
+			{
+				Config: config("result := value * 2;", "DEFINER"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_procedure.procedure", "security", "DEFINER"),
+					resource.TestMatchResourceAttr("redshift_procedure.procedure", "body", regexp.MustCompile(`value \* 2`)),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftProcedureDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.Connect()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_procedure" {
+			continue
+		}
+
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM pg_proc_info WHERE prooid = $1", rs.Primary.ID).Scan(&count); err != nil {
+			return fmt.Errorf("Error checking procedure %s", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("Procedure %s still exists after destroy", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
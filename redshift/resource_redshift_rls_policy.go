@@ -0,0 +1,274 @@
+package redshift
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	rlsPolicyNameAttr  = "name"
+	rlsPolicyWithAttr  = "with"
+	rlsPolicyAliasAttr = "alias"
+	rlsPolicyUsingAttr = "using"
+
+	// attributes of the columns of the WITH clause of RLS and masking policies
+	policyColumnNameAttr = "name"
+	policyColumnTypeAttr = "type"
+)
+
+func redshiftRLSPolicy() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Defines a row-level security (RLS) policy, a predicate restricting the rows of the tables it is attached to with ` + "`redshift_rls_policy_attachment`" + ` that users and roles can read. Row-level security must also be turned on for the tables, e.g. with ` + "`row_level_security`" + ` of ` + "`redshift_table_attributes`" + `.
+
+Managing RLS policies requires a superuser or a user with the ` + "`sys:secadmin`" + ` role.
+`,
+		Create: RedshiftResourceFunc(resourceRedshiftRLSPolicyCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftRLSPolicyRead),
+		Update: RedshiftResourceFunc(resourceRedshiftRLSPolicyUpdate),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftRLSPolicyDelete),
+		),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			rlsPolicyNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the policy.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			rlsPolicyWithAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The columns of the tables the policy is attached to which are referenced by `using`, in order. The policy can only be attached to tables with all these columns.",
+				Elem:        policyColumnsResource(),
+			},
+			rlsPolicyAliasAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The alias of the tables the policy is attached to, to qualify their columns in `using`.",
+			},
+			rlsPolicyUsingAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The predicate the rows have to match to be visible, e.g. `owner = current_user`. It can use the columns of `with`, as well as lookup tables the policy has been granted access to.",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return normalizeRLSPredicate(old) == normalizeRLSPredicate(new)
+				},
+			},
+		},
+	}
+}
+
+// policyColumnsResource is the schema of the columns of the WITH clause of RLS and masking policies.
+func policyColumnsResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			policyColumnNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the column.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+			},
+			policyColumnTypeAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The data type of the column, e.g. `varchar(64)`.",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return normalizeColumnType(old) == normalizeColumnType(new)
+				},
+			},
+		},
+	}
+}
+
+// policyColumn is a column of the WITH clause of a policy, as stored in the polatts column of svv_rls_policy
+// and in the input_columns column of svv_masking_policy.
+type policyColumn struct {
+	Name string `json:"colname"`
+	Type string `json:"type"`
+}
+
+func resourceRedshiftRLSPolicyCreate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := createRLSPolicyQuery(d)
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("could not create RLS policy %s: %w", d.Get(rlsPolicyNameAttr).(string), err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(strings.ToLower(d.Get(rlsPolicyNameAttr).(string)))
+
+	return resourceRedshiftRLSPolicyRead(db, d)
+}
+
+func resourceRedshiftRLSPolicyRead(db *DBConnection, d *schema.ResourceData) error {
+	var alias, attributes, using string
+
+	err := db.QueryRow(`
+	SELECT COALESCE(trim(polalias), ''), COALESCE(polatts, ''), COALESCE(polqual, '')
+	FROM svv_rls_policy
+	WHERE polname = $1`, d.Id()).Scan(&alias, &attributes, &using)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift RLS policy (%s) not found", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading RLS policy: %w", err)
+	}
+
+	columns, err := parsePolicyColumns(attributes)
+	if err != nil {
+		return fmt.Errorf("Error parsing the columns of RLS policy %s: %w", d.Id(), err)
+	}
+
+	d.Set(rlsPolicyNameAttr, d.Id())
+	d.Set(rlsPolicyWithAttr, flattenPolicyColumns(columns))
+	d.Set(rlsPolicyAliasAttr, alias)
+	d.Set(rlsPolicyUsingAttr, using)
+
+	return nil
+}
+
+func resourceRedshiftRLSPolicyUpdate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if d.HasChange(rlsPolicyUsingAttr) {
+		query := fmt.Sprintf("ALTER RLS POLICY %s USING (%s)", pq.QuoteIdentifier(d.Get(rlsPolicyNameAttr).(string)), d.Get(rlsPolicyUsingAttr).(string))
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("Error updating RLS policy USING: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourceRedshiftRLSPolicyRead(db, d)
+}
+
+func resourceRedshiftRLSPolicyDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := fmt.Sprintf("DROP RLS POLICY %s", pq.QuoteIdentifier(d.Get(rlsPolicyNameAttr).(string)))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func createRLSPolicyQuery(d *schema.ResourceData) string {
+	query := fmt.Sprintf("CREATE RLS POLICY %s", pq.QuoteIdentifier(d.Get(rlsPolicyNameAttr).(string)))
+
+	if columns := policyColumnsDefinition(d.Get(rlsPolicyWithAttr).([]interface{})); columns != "" {
+		query = fmt.Sprintf("%s WITH (%s)", query, columns)
+		if alias := d.Get(rlsPolicyAliasAttr).(string); alias != "" {
+			query = fmt.Sprintf("%s AS %s", query, pq.QuoteIdentifier(alias))
+		}
+	}
+
+	return fmt.Sprintf("%s USING (%s)", query, d.Get(rlsPolicyUsingAttr).(string))
+}
+
+// policyColumnsDefinition returns the column definitions of the WITH clause of a policy, e.g. `"region" varchar(16)`.
+func policyColumnsDefinition(with []interface{}) string {
+	columns := []string{}
+	for _, raw := range with {
+		column := raw.(map[string]interface{})
+		columns = append(columns, fmt.Sprintf("%s %s", pq.QuoteIdentifier(column[policyColumnNameAttr].(string)), column[policyColumnTypeAttr].(string)))
+	}
+	return strings.Join(columns, ", ")
+}
+
+// parsePolicyColumns parses the columns of the WITH clause of a policy,
+// stored as JSON in svv_rls_policy and svv_masking_policy, e.g. `[{"colname":"region","type":"character varying(16)"}]`.
+func parsePolicyColumns(attributes string) ([]policyColumn, error) {
+	columns := []policyColumn{}
+	if strings.TrimSpace(attributes) == "" {
+		return columns, nil
+	}
+	if err := json.Unmarshal([]byte(attributes), &columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+func flattenPolicyColumns(columns []policyColumn) []map[string]interface{} {
+	with := []map[string]interface{}{}
+	for _, column := range columns {
+		with = append(with, map[string]interface{}{
+			policyColumnNameAttr: column.Name,
+			policyColumnTypeAttr: column.Type,
+		})
+	}
+	return with
+}
+
+// normalizeRLSPredicate returns the predicate without the enclosing parentheses and with collapsed whitespace,
+// as Redshift stores the predicates of policies between parentheses.
+func normalizeRLSPredicate(predicate string) string {
+	predicate = strings.Join(strings.Fields(predicate), " ")
+	for strings.HasPrefix(predicate, "(") && strings.HasSuffix(predicate, ")") && enclosedInParentheses(predicate) {
+		predicate = strings.TrimSpace(predicate[1 : len(predicate)-1])
+	}
+	return predicate
+}
+
+// enclosedInParentheses returns whether the opening parenthesis of the expression is closed at its very end,
+// e.g. `(a = 1)` but not `(a = 1) OR (b = 2)`.
+func enclosedInParentheses(expression string) bool {
+	depth := 0
+	for i, c := range expression {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 && i != len(expression)-1 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
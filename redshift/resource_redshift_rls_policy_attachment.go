@@ -0,0 +1,284 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	rlsPolicyAttachmentPolicyAttr = "policy"
+	rlsPolicyAttachmentSchemaAttr = "schema"
+	rlsPolicyAttachmentTableAttr  = "table"
+	rlsPolicyAttachmentUsersAttr  = "users"
+	rlsPolicyAttachmentRolesAttr  = "roles"
+)
+
+func redshiftRLSPolicyAttachment() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Attaches a row-level security policy to a table for users and roles with ` + "`ATTACH RLS POLICY`" + `, so that they only see the rows of the table matching the policy. The policy only applies once row-level security is turned on for the table, e.g. with ` + "`row_level_security`" + ` of ` + "`redshift_table_attributes`" + `.
+`,
+		Create: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftRLSPolicyAttachmentCreate),
+		),
+		Read: RedshiftResourceReadFunc(resourceRedshiftRLSPolicyAttachmentRead),
+		Update: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftRLSPolicyAttachmentUpdate),
+		),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftRLSPolicyAttachmentDelete),
+		),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			rlsPolicyAttachmentPolicyAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the RLS policy.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			rlsPolicyAttachmentSchemaAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "public",
+				ForceNew:     true,
+				Description:  "The schema of the table.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			rlsPolicyAttachmentTableAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the table to attach the policy to.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			rlsPolicyAttachmentUsersAttr: {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				AtLeastOneOf: []string{rlsPolicyAttachmentUsersAttr, rlsPolicyAttachmentRolesAttr},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIdentifier,
+				},
+				Set:         hashCaseInsensitiveString,
+				Description: "The names of the users the policy applies to.",
+			},
+			rlsPolicyAttachmentRolesAttr: {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				AtLeastOneOf: []string{rlsPolicyAttachmentUsersAttr, rlsPolicyAttachmentRolesAttr},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIdentifier,
+				},
+				Set:         hashCaseInsensitiveString,
+				Description: "The names of the roles the policy applies to.",
+			},
+		},
+	}
+}
+
+func resourceRedshiftRLSPolicyAttachmentCreate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	users := d.Get(rlsPolicyAttachmentUsersAttr).(*schema.Set)
+	roles := d.Get(rlsPolicyAttachmentRolesAttr).(*schema.Set)
+	if err := alterRLSPolicyAttachment(tx, d, "ATTACH", users, roles); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(fmt.Sprintf(
+		"%s:%s.%s",
+		strings.ToLower(d.Get(rlsPolicyAttachmentPolicyAttr).(string)),
+		strings.ToLower(d.Get(rlsPolicyAttachmentSchemaAttr).(string)),
+		strings.ToLower(d.Get(rlsPolicyAttachmentTableAttr).(string)),
+	))
+
+	return resourceRedshiftRLSPolicyAttachmentRead(db, d)
+}
+
+func resourceRedshiftRLSPolicyAttachmentRead(db *DBConnection, d *schema.ResourceData) error {
+	policyName, schemaName, tableName, err := parseRLSPolicyAttachmentID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.Query(`
+	SELECT trim(grantee), lower(trim(granteekind))
+	FROM svv_rls_attached_policy
+	WHERE polname = $1 AND relschema = $2 AND relname = $3`, policyName, schemaName, tableName)
+	if err != nil {
+		return fmt.Errorf("Error reading RLS policy attachment: %w", err)
+	}
+	defer rows.Close()
+
+	users := []string{}
+	roles := []string{}
+	for rows.Next() {
+		var grantee, granteeKind string
+		if err := rows.Scan(&grantee, &granteeKind); err != nil {
+			return err
+		}
+		if granteeKind == "role" {
+			roles = append(roles, grantee)
+		} else {
+			users = append(users, grantee)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(users) == 0 && len(roles) == 0 {
+		log.Printf("[WARN] Redshift RLS policy %s is not attached to table %s.%s", policyName, schemaName, tableName)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set(rlsPolicyAttachmentPolicyAttr, policyName)
+	d.Set(rlsPolicyAttachmentSchemaAttr, schemaName)
+	d.Set(rlsPolicyAttachmentTableAttr, tableName)
+	d.Set(rlsPolicyAttachmentUsersAttr, users)
+	d.Set(rlsPolicyAttachmentRolesAttr, roles)
+
+	return nil
+}
+
+func resourceRedshiftRLSPolicyAttachmentUpdate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	oldUsers, newUsers := d.GetChange(rlsPolicyAttachmentUsersAttr)
+	oldRoles, newRoles := d.GetChange(rlsPolicyAttachmentRolesAttr)
+
+	removedUsers := oldUsers.(*schema.Set).Difference(newUsers.(*schema.Set))
+	removedRoles := oldRoles.(*schema.Set).Difference(newRoles.(*schema.Set))
+	if err := alterRLSPolicyAttachment(tx, d, "DETACH", removedUsers, removedRoles); err != nil {
+		return err
+	}
+
+	addedUsers := newUsers.(*schema.Set).Difference(oldUsers.(*schema.Set))
+	addedRoles := newRoles.(*schema.Set).Difference(oldRoles.(*schema.Set))
+	if err := alterRLSPolicyAttachment(tx, d, "ATTACH", addedUsers, addedRoles); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourceRedshiftRLSPolicyAttachmentRead(db, d)
+}
+
+func resourceRedshiftRLSPolicyAttachmentDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	var attached bool
+	if err := tx.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM svv_rls_attached_policy WHERE polname = $1 AND relschema = $2 AND relname = $3)",
+		strings.ToLower(d.Get(rlsPolicyAttachmentPolicyAttr).(string)),
+		strings.ToLower(d.Get(rlsPolicyAttachmentSchemaAttr).(string)),
+		strings.ToLower(d.Get(rlsPolicyAttachmentTableAttr).(string)),
+	).Scan(&attached); err != nil {
+		return err
+	}
+	if !attached {
+		log.Printf("[WARN] Redshift RLS policy attachment (%s) was already removed", d.Id())
+		return nil
+	}
+
+	users := d.Get(rlsPolicyAttachmentUsersAttr).(*schema.Set)
+	roles := d.Get(rlsPolicyAttachmentRolesAttr).(*schema.Set)
+	if err := alterRLSPolicyAttachment(tx, d, "DETACH", users, roles); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// alterRLSPolicyAttachment attaches the policy to the table for the users and roles, or detaches it, depending on the action (ATTACH or DETACH).
+func alterRLSPolicyAttachment(tx *sql.Tx, d *schema.ResourceData, action string, users, roles *schema.Set) error {
+	query := rlsPolicyAttachmentQuery(d, action, users, roles)
+	if query == "" {
+		return nil
+	}
+
+	log.Printf("[DEBUG] %s\n", query)
+	_, err := tx.Exec(query)
+	return err
+}
+
+// rlsPolicyAttachmentQuery returns the ATTACH or DETACH statement for the users and roles, or an empty string when there are none.
+func rlsPolicyAttachmentQuery(d *schema.ResourceData, action string, users, roles *schema.Set) string {
+	grantees := []string{}
+	for _, user := range users.List() {
+		grantees = append(grantees, pq.QuoteIdentifier(user.(string)))
+	}
+	for _, role := range roles.List() {
+		grantees = append(grantees, "ROLE "+pq.QuoteIdentifier(role.(string)))
+	}
+	if len(grantees) == 0 {
+		return ""
+	}
+	sort.Strings(grantees)
+
+	preposition := "TO"
+	if action == "DETACH" {
+		preposition = "FROM"
+	}
+
+	return fmt.Sprintf(
+		"%s RLS POLICY %s ON %s %s %s",
+		action,
+		pq.QuoteIdentifier(d.Get(rlsPolicyAttachmentPolicyAttr).(string)),
+		tableIdentifier(d.Get(rlsPolicyAttachmentSchemaAttr).(string), d.Get(rlsPolicyAttachmentTableAttr).(string)),
+		preposition,
+		strings.Join(grantees, ", "),
+	)
+}
+
+// parseRLSPolicyAttachmentID returns the policy, schema and table of the ID, e.g. "region_policy:public.sales".
+func parseRLSPolicyAttachmentID(id string) (string, string, string, error) {
+	policyAndTable := strings.SplitN(id, ":", 2)
+	if len(policyAndTable) != 2 {
+		return "", "", "", fmt.Errorf("invalid RLS policy attachment ID %q, expected <policy>:<schema>.<table>", id)
+	}
+	schemaAndTable := strings.SplitN(policyAndTable[1], ".", 2)
+	if len(schemaAndTable) != 2 || policyAndTable[0] == "" || schemaAndTable[0] == "" || schemaAndTable[1] == "" {
+		return "", "", "", fmt.Errorf("invalid RLS policy attachment ID %q, expected <policy>:<schema>.<table>", id)
+	}
+	return policyAndTable[0], schemaAndTable[0], schemaAndTable[1], nil
+}
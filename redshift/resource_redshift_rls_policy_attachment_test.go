@@ -0,0 +1,140 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccRedshiftRLSPolicyAttachment_Basic(t *testing.T) {
+	prefix := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_rls"), "-", "_")
+	config := func(grantees string) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name = "%[1]s_user"
+}
+
+resource "redshift_role" "role" {
+  name = "%[1]s_role"
+}
+
+resource "redshift_schema" "schema" {
+  name = "%[1]s_schema"
+}
+
+resource "redshift_table" "table" {
+  name   = "sales"
+  schema = redshift_schema.schema.name
+
+  column {
+    name = "region"
+    type = "varchar(16)"
+  }
+
+  column {
+    name = "amount"
+    type = "integer"
+  }
+}
+
+resource "redshift_table_attributes" "table" {
+  name               = redshift_table.table.name
+  schema             = redshift_table.table.schema
+  row_level_security = true
+}
+
+resource "redshift_rls_policy" "policy" {
+  name  = "%[1]s_policy"
+  using = "region = 'eu'"
+
+  with {
+    name = "region"
+    type = "varchar(16)"
+  }
+}
+
+resource "redshift_rls_policy_attachment" "attachment" {
+  policy = redshift_rls_policy.policy.name
+  schema = redshift_table.table.schema
+  table  = redshift_table.table.name
+  %[2]s
+}
+`, prefix, grantees)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftRLSPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`users = [redshift_user.user.name]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_rls_policy_attachment.attachment", "id", fmt.Sprintf("%[1]s_policy:%[1]s_schema.sales", prefix)),
+					resource.TestCheckResourceAttr("redshift_rls_policy_attachment.attachment", "users.#", "1"),
+					resource.TestCheckResourceAttr("redshift_rls_policy_attachment.attachment", "roles.#", "0"),
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "row_level_security", "true"),
+				),
+			},
+			{
+				Config: config(`roles = [redshift_role.role.name]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_rls_policy_attachment.attachment", "users.#", "0"),
+					resource.TestCheckResourceAttr("redshift_rls_policy_attachment.attachment", "roles.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "redshift_rls_policy_attachment.attachment",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestRLSPolicyAttachmentQuery(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, redshiftRLSPolicyAttachment().Schema, map[string]interface{}{
+		rlsPolicyAttachmentPolicyAttr: "by_region",
+		rlsPolicyAttachmentSchemaAttr: "sales",
+		rlsPolicyAttachmentTableAttr:  "orders",
+		rlsPolicyAttachmentUsersAttr:  []interface{}{"bob", "alice"},
+		rlsPolicyAttachmentRolesAttr:  []interface{}{"analyst"},
+	})
+	users := d.Get(rlsPolicyAttachmentUsersAttr).(*schema.Set)
+	roles := d.Get(rlsPolicyAttachmentRolesAttr).(*schema.Set)
+
+	expected := `ATTACH RLS POLICY "by_region" ON "sales"."orders" TO "alice", "bob", ROLE "analyst"`
+	if actual := rlsPolicyAttachmentQuery(d, "ATTACH", users, roles); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+
+	expected = `DETACH RLS POLICY "by_region" ON "sales"."orders" FROM ROLE "analyst"`
+	if actual := rlsPolicyAttachmentQuery(d, "DETACH", schema.NewSet(schema.HashString, nil), roles); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+
+	empty := schema.NewSet(schema.HashString, nil)
+	if actual := rlsPolicyAttachmentQuery(d, "DETACH", empty, empty); actual != "" {
+		t.Errorf("expected no statement without grantees, got %s", actual)
+	}
+}
+
+func TestParseRLSPolicyAttachmentID(t *testing.T) {
+	policyName, schemaName, tableName, err := parseRLSPolicyAttachmentID("by_region:sales.orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policyName != "by_region" || schemaName != "sales" || tableName != "orders" {
+		t.Errorf("expected by_region:sales.orders, got %s:%s.%s", policyName, schemaName, tableName)
+	}
+
+	for _, id := range []string{"by_region", "by_region:orders", ":sales.orders", "by_region:sales."} {
+		if _, _, _, err := parseRLSPolicyAttachmentID(id); err == nil {
+			t.Errorf("expected %q to be an invalid ID", id)
+		}
+	}
+}
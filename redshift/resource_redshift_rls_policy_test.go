@@ -0,0 +1,147 @@
+package redshift
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftRLSPolicy_Basic(t *testing.T) {
+	policyName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_rls_policy"), "-", "_")
+	config := func(using string) string {
+		return fmt.Sprintf(`
+resource "redshift_rls_policy" "policy" {
+  name  = %[1]q
+  alias = "s"
+  using = %[2]q
+
+  with {
+    name = "region"
+    type = "varchar(16)"
+  }
+}
+`, policyName, using)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftRLSPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("s.region = 'eu'"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_rls_policy.policy", "id", policyName),
+					resource.TestCheckResourceAttr("redshift_rls_policy.policy", "with.#", "1"),
+					resource.TestCheckResourceAttr("redshift_rls_policy.policy", "with.0.name", "region"),
+				),
+			},
+			{
+				Config: config("s.region IN ('eu', 'us')"),
+			},
+			{
+				ResourceName:      "redshift_rls_policy.policy",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftRLSPolicyDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.Connect()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_rls_policy" {
+			continue
+		}
+
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM svv_rls_policy WHERE polname = $1", rs.Primary.ID).Scan(&count); err != nil {
+			return fmt.Errorf("Error checking RLS policy %s", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("RLS policy %s still exists after destroy", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func TestCreateRLSPolicyQuery(t *testing.T) {
+	cases := map[string]struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		"without columns": {
+			config: map[string]interface{}{
+				rlsPolicyNameAttr:  "always_false",
+				rlsPolicyUsingAttr: "false",
+			},
+			expected: `CREATE RLS POLICY "always_false" USING (false)`,
+		},
+		"with columns and alias": {
+			config: map[string]interface{}{
+				rlsPolicyNameAttr: "by_region",
+				rlsPolicyWithAttr: []interface{}{
+					map[string]interface{}{policyColumnNameAttr: "region", policyColumnTypeAttr: "varchar(16)"},
+					map[string]interface{}{policyColumnNameAttr: "owner", policyColumnTypeAttr: "varchar(64)"},
+				},
+				rlsPolicyAliasAttr: "s",
+				rlsPolicyUsingAttr: "s.owner = current_user",
+			},
+			expected: `CREATE RLS POLICY "by_region" WITH ("region" varchar(16), "owner" varchar(64)) AS "s" USING (s.owner = current_user)`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, redshiftRLSPolicy().Schema, c.config)
+			if actual := createRLSPolicyQuery(d); actual != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseRLSPolicyColumns(t *testing.T) {
+	columns, err := parsePolicyColumns(`[{"colname":"region","type":"character varying(16)"},{"colname":"id","type":"integer"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []policyColumn{{Name: "region", Type: "character varying(16)"}, {Name: "id", Type: "integer"}}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("expected %v, got %v", expected, columns)
+	}
+
+	if columns, err := parsePolicyColumns(""); err != nil || len(columns) != 0 {
+		t.Errorf("expected no columns, got %v, %v", columns, err)
+	}
+}
+
+func TestNormalizeRLSPredicate(t *testing.T) {
+	cases := map[string]string{
+		"s.region = 'eu'":             "s.region = 'eu'",
+		"(s.region = 'eu')":           "s.region = 'eu'",
+		"((s.region  =\n 'eu'))":      "s.region = 'eu'",
+		"(a = 1) OR (b = 2)":          "(a = 1) OR (b = 2)",
+		"((a = 1) OR (b = 2))":        "(a = 1) OR (b = 2)",
+		"lower(owner) = current_user": "lower(owner) = current_user",
+	}
+
+	for predicate, expected := range cases {
+		if actual := normalizeRLSPredicate(predicate); actual != expected {
+			t.Errorf("expected %q to be normalized to %q, got %q", predicate, expected, actual)
+		}
+	}
+}
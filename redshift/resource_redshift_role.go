@@ -0,0 +1,267 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/brainly/terraform-provider-redshift/redshift/internal/catalog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	roleNameAttr  = "name"
+	roleOwnerAttr = "owner"
+)
+
+// roleSystemPrivileges maps the boolean attributes of redshift_role to the system privileges they grant.
+// Only commonly delegated privileges are exposed.
+var roleSystemPrivileges = []struct {
+	attr        string
+	privilege   string
+	description string
+}{
+	{"create_schema", "CREATE SCHEMA", "Allows members of the role to create schemas."},
+	{"create_table", "CREATE TABLE", "Allows members of the role to create tables."},
+	{"create_role", "CREATE ROLE", "Allows members of the role to create roles."},
+	{"create_user", "CREATE USER", "Allows members of the role to create users."},
+	{"access_system_table", "ACCESS SYSTEM TABLE", "Allows members of the role to see all rows of the system tables and views, including rows generated by other users."},
+}
+
+func redshiftRole() *schema.Resource {
+	roleSchema := map[string]*schema.Schema{
+		roleNameAttr: {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "The name of the role. Changing it renames the role.",
+			ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+			StateFunc: func(val interface{}) string {
+				return strings.ToLower(val.(string))
+			},
+		},
+		roleOwnerAttr: {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validateIdentifier,
+			Description:  "The name of the role owner. Defaults to the user creating the role.",
+			StateFunc: func(val interface{}) string {
+				return strings.ToLower(val.(string))
+			},
+		},
+	}
+	for _, p := range roleSystemPrivileges {
+		roleSchema[p.attr] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: fmt.Sprintf("%s Grants the `%s` system privilege to the role.", p.description, p.privilege),
+		}
+	}
+
+	return &schema.Resource{
+		Description: `
+Roles are collections of privileges, which can be granted to users and other roles. Unlike groups, roles can also hold system privileges, e.g. to allow creating schemas, which can't be granted to users directly.
+`,
+		Create: RedshiftResourceFunc(resourceRedshiftRoleCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftRoleRead),
+		Update: RedshiftResourceFunc(resourceRedshiftRoleUpdate),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftRoleDelete),
+		),
+		Exists: RedshiftResourceExistsFunc(resourceRedshiftRoleExists),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: roleSchema,
+	}
+}
+
+func resourceRedshiftRoleExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	var name string
+	err := db.QueryRow("SELECT role_name FROM svv_roles WHERE role_id = $1", d.Id()).Scan(&name)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
+
+func resourceRedshiftRoleCreate(db *DBConnection, d *schema.ResourceData) error {
+	roleName := d.Get(roleNameAttr).(string)
+
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := fmt.Sprintf("CREATE ROLE %s", pq.QuoteIdentifier(roleName))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Could not create redshift role: %w", err)
+	}
+
+	if err := setRoleOwner(tx, d); err != nil {
+		return err
+	}
+
+	if err := setRoleSystemPrivileges(tx, d); err != nil {
+		return err
+	}
+
+	roleID, err := catalog.NewResolver(tx).RoleID(strings.ToLower(roleName))
+	if err != nil {
+		return fmt.Errorf("failed to get role ID for role '%s': %w", roleName, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(strconv.Itoa(roleID))
+
+	return resourceRedshiftRoleRead(db, d)
+}
+
+func resourceRedshiftRoleRead(db *DBConnection, d *schema.ResourceData) error {
+	var roleName, roleOwner string
+
+	err := db.QueryRow("SELECT role_name, COALESCE(role_owner, '') FROM svv_roles WHERE role_id = $1", d.Id()).Scan(&roleName, &roleOwner)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift Role (%s) not found", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading role: %w", err)
+	}
+
+	rows, err := db.Query("SELECT system_privilege FROM svv_system_privileges WHERE identity_type = 'role' AND identity_id = $1", d.Id())
+	if err != nil {
+		return fmt.Errorf("Error reading role system privileges: %w", err)
+	}
+	defer rows.Close()
+
+	privileges := map[string]bool{}
+	for rows.Next() {
+		var privilege string
+		if err := rows.Scan(&privilege); err != nil {
+			return err
+		}
+		privileges[strings.ToUpper(privilege)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set(roleNameAttr, roleName)
+	d.Set(roleOwnerAttr, roleOwner)
+	for _, p := range roleSystemPrivileges {
+		d.Set(p.attr, privileges[p.privilege])
+	}
+
+	return nil
+}
+
+func resourceRedshiftRoleUpdate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := setRoleName(tx, d); err != nil {
+		return err
+	}
+
+	if d.HasChange(roleOwnerAttr) {
+		if err := setRoleOwner(tx, d); err != nil {
+			return err
+		}
+	}
+
+	if err := setRoleSystemPrivileges(tx, d); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourceRedshiftRoleRead(db, d)
+}
+
+func resourceRedshiftRoleDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := fmt.Sprintf("DROP ROLE %s", pq.QuoteIdentifier(d.Get(roleNameAttr).(string)))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setRoleName(tx *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(roleNameAttr) {
+		return nil
+	}
+
+	oldRaw, newRaw := d.GetChange(roleNameAttr)
+	query := fmt.Sprintf("ALTER ROLE %s RENAME TO %s", pq.QuoteIdentifier(oldRaw.(string)), pq.QuoteIdentifier(newRaw.(string)))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Error updating role NAME: %w", err)
+	}
+
+	return nil
+}
+
+func setRoleOwner(tx *sql.Tx, d *schema.ResourceData) error {
+	roleOwner, ok := d.GetOk(roleOwnerAttr)
+	if !ok {
+		return nil
+	}
+
+	query := fmt.Sprintf("ALTER ROLE %s OWNER TO %s", pq.QuoteIdentifier(d.Get(roleNameAttr).(string)), pq.QuoteIdentifier(roleOwner.(string)))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Error updating role OWNER: %w", err)
+	}
+
+	return nil
+}
+
+func setRoleSystemPrivileges(tx *sql.Tx, d *schema.ResourceData) error {
+	roleName := pq.QuoteIdentifier(d.Get(roleNameAttr).(string))
+	for _, p := range roleSystemPrivileges {
+		if !d.HasChange(p.attr) {
+			continue
+		}
+
+		query := fmt.Sprintf("REVOKE %s FROM ROLE %s", p.privilege, roleName)
+		if d.Get(p.attr).(bool) {
+			query = fmt.Sprintf("GRANT %s TO ROLE %s", p.privilege, roleName)
+		}
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("Error updating role system privilege %s: %w", p.privilege, err)
+		}
+	}
+
+	return nil
+}
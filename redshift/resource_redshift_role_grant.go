@@ -0,0 +1,185 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/brainly/terraform-provider-redshift/redshift/internal/catalog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	roleGrantRoleAttr        = "role"
+	roleGrantUserAttr        = "user"
+	roleGrantGranteeRoleAttr = "grantee_role"
+)
+
+func redshiftRoleGrant() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Assigns a role to a user or to another role. The grantee gets all privileges of the role, including the privileges of the roles granted to it.
+`,
+		Create: RedshiftResourceFunc(resourceRedshiftRoleGrantCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftRoleGrantRead),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftRoleGrantDelete),
+		),
+		Schema: map[string]*schema.Schema{
+			roleGrantRoleAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the role to grant.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			roleGrantUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "The name of the user to grant the role to.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				ExactlyOneOf: []string{roleGrantUserAttr, roleGrantGranteeRoleAttr},
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			roleGrantGranteeRoleAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "The name of the role to grant the role to.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				ExactlyOneOf: []string{roleGrantUserAttr, roleGrantGranteeRoleAttr},
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+		},
+	}
+}
+
+func resourceRedshiftRoleGrantCreate(db *DBConnection, d *schema.ResourceData) error {
+	roleName := strings.ToLower(d.Get(roleGrantRoleAttr).(string))
+
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	resolver := catalog.NewResolver(tx)
+	if _, err := resolver.RoleID(roleName); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("role %q does not exist", roleName)
+		}
+		return err
+	}
+
+	grantee, err := roleGrantGrantee(d)
+	if err != nil {
+		return err
+	}
+	if granteeRole, ok := d.GetOk(roleGrantGranteeRoleAttr); ok {
+		if _, err := resolver.RoleID(strings.ToLower(granteeRole.(string))); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("role %q does not exist", granteeRole)
+			}
+			return err
+		}
+	}
+
+	query := fmt.Sprintf("GRANT ROLE %s TO %s", pq.QuoteIdentifier(roleName), grantee)
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Could not grant role %s: %w", roleName, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateRoleGrantID(d))
+
+	return resourceRedshiftRoleGrantRead(db, d)
+}
+
+func resourceRedshiftRoleGrantRead(db *DBConnection, d *schema.ResourceData) error {
+	roleName := strings.ToLower(d.Get(roleGrantRoleAttr).(string))
+
+	var query, granteeName string
+	if userName, ok := d.GetOk(roleGrantUserAttr); ok {
+		query = "SELECT user_name FROM svv_user_grants WHERE role_name = $1 AND user_name = $2"
+		granteeName = strings.ToLower(userName.(string))
+	} else {
+		query = "SELECT role_name FROM svv_role_grants WHERE granted_role_name = $1 AND role_name = $2"
+		granteeName = strings.ToLower(d.Get(roleGrantGranteeRoleAttr).(string))
+	}
+
+	log.Printf("[DEBUG] %s, $1=%s, $2=%s\n", query, roleName, granteeName)
+	var name string
+	err := db.QueryRow(query, roleName, granteeName).Scan(&name)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift role grant (%s) not found", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading role grant: %w", err)
+	}
+
+	return nil
+}
+
+func resourceRedshiftRoleGrantDelete(db *DBConnection, d *schema.ResourceData) error {
+	roleName := strings.ToLower(d.Get(roleGrantRoleAttr).(string))
+
+	grantee, err := roleGrantGrantee(d)
+	if err != nil {
+		return err
+	}
+
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := fmt.Sprintf("REVOKE ROLE %s FROM %s", pq.QuoteIdentifier(roleName), grantee)
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Could not revoke role %s: %w", roleName, err)
+	}
+
+	return tx.Commit()
+}
+
+// roleGrantGrantee returns the quoted grantee of the role grant, prefixed with ROLE when the role is granted to another role.
+func roleGrantGrantee(d *schema.ResourceData) (string, error) {
+	if userName, ok := d.GetOk(roleGrantUserAttr); ok {
+		return pq.QuoteIdentifier(strings.ToLower(userName.(string))), nil
+	}
+	if granteeRole, ok := d.GetOk(roleGrantGranteeRoleAttr); ok {
+		return fmt.Sprintf("ROLE %s", pq.QuoteIdentifier(strings.ToLower(granteeRole.(string)))), nil
+	}
+	return "", fmt.Errorf("Either %s or %s is required", roleGrantUserAttr, roleGrantGranteeRoleAttr)
+}
+
+func generateRoleGrantID(d *schema.ResourceData) string {
+	parts := []string{fmt.Sprintf("rn:%s", strings.ToLower(d.Get(roleGrantRoleAttr).(string)))}
+
+	if userName, ok := d.GetOk(roleGrantUserAttr); ok {
+		parts = append(parts, fmt.Sprintf("un:%s", strings.ToLower(userName.(string))))
+	}
+	if granteeRole, ok := d.GetOk(roleGrantGranteeRoleAttr); ok {
+		parts = append(parts, fmt.Sprintf("grn:%s", strings.ToLower(granteeRole.(string))))
+	}
+
+	return strings.Join(parts, "_")
+}
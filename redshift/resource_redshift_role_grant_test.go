@@ -0,0 +1,89 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftRoleGrant_Basic(t *testing.T) {
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role"), "-", "_")
+	parentRoleName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role_parent"), "-", "_")
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role_user"), "-", "_")
+
+	config := fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name = %[1]q
+}
+
+resource "redshift_role" "parent" {
+  name = %[2]q
+}
+
+resource "redshift_user" "user" {
+  name = %[3]q
+}
+
+resource "redshift_role_grant" "user" {
+  role = redshift_role.role.name
+  user = redshift_user.user.name
+}
+
+resource "redshift_role_grant" "role" {
+  role         = redshift_role.role.name
+  grantee_role = redshift_role.parent.name
+}
+`, roleName, parentRoleName, userName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftRoleGrantDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_role_grant.user", "id", fmt.Sprintf("rn:%s_un:%s", roleName, userName)),
+					resource.TestCheckResourceAttr("redshift_role_grant.user", "role", roleName),
+					resource.TestCheckResourceAttr("redshift_role_grant.user", "user", userName),
+					resource.TestCheckResourceAttr("redshift_role_grant.role", "id", fmt.Sprintf("rn:%s_grn:%s", roleName, parentRoleName)),
+					resource.TestCheckResourceAttr("redshift_role_grant.role", "grantee_role", parentRoleName),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftRoleGrantDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.Connect()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_role_grant" {
+			continue
+		}
+
+		var count int
+		roleName := rs.Primary.Attributes[roleGrantRoleAttr]
+		if userName := rs.Primary.Attributes[roleGrantUserAttr]; userName != "" {
+			err = db.QueryRow("SELECT COUNT(*) FROM svv_user_grants WHERE role_name = $1 AND user_name = $2", roleName, userName).Scan(&count)
+		} else {
+			err = db.QueryRow("SELECT COUNT(*) FROM svv_role_grants WHERE granted_role_name = $1 AND role_name = $2", roleName, rs.Primary.Attributes[roleGrantGranteeRoleAttr]).Scan(&count)
+		}
+		if err != nil {
+			return fmt.Errorf("Error checking role grant %s", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("Role grant %s still exists after destroy", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
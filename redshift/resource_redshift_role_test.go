@@ -0,0 +1,94 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftRole_Basic(t *testing.T) {
+	roleName := strings.ReplaceAll(acctest.RandomWithPrefix("TF_acc_role"), "-", "_")
+	roleNameUpdated := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role_updated"), "-", "_")
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_role_owner"), "-", "_")
+
+	configCreate := fmt.Sprintf(`
+resource "redshift_role" "role" {
+  name          = %[1]q
+  create_schema = true
+}
+`, roleName)
+
+	configUpdate := fmt.Sprintf(`
+resource "redshift_user" "owner" {
+  name      = %[2]q
+  superuser = true
+  password  = "Foobarbaz1"
+}
+
+resource "redshift_role" "role" {
+  name                = %[1]q
+  owner               = redshift_user.owner.name
+  access_system_table = true
+}
+`, roleNameUpdated, userName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: configCreate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_role.role", "name", strings.ToLower(roleName)),
+					resource.TestCheckResourceAttrSet("redshift_role.role", "owner"),
+					resource.TestCheckResourceAttr("redshift_role.role", "create_schema", "true"),
+					resource.TestCheckResourceAttr("redshift_role.role", "create_role", "false"),
+					resource.TestCheckResourceAttr("redshift_role.role", "access_system_table", "false"),
+				),
+			},
+			{
+				Config: configUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_role.role", "name", roleNameUpdated),
+					resource.TestCheckResourceAttr("redshift_role.role", "owner", userName),
+					resource.TestCheckResourceAttr("redshift_role.role", "create_schema", "false"),
+					resource.TestCheckResourceAttr("redshift_role.role", "access_system_table", "true"),
+				),
+			},
+			{
+				ResourceName:      "redshift_role.role",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftRoleDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.Connect()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_role" {
+			continue
+		}
+
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM svv_roles WHERE role_id = $1", rs.Primary.ID).Scan(&count); err != nil {
+			return fmt.Errorf("Error checking role %s", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("Role %s still exists after destroy", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
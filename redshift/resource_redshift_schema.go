@@ -1,12 +1,15 @@
 package redshift
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/brainly/terraform-provider-redshift/redshift/internal/catalog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -18,12 +21,15 @@ const (
 	schemaOwnerAttr           = "owner"
 	schemaQuotaAttr           = "quota"
 	schemaCascadeOnDeleteAttr = "cascade_on_delete"
+	schemaDropTimeoutAttr     = "drop_cascade_timeout"
 	schemaExternalSchemaAttr  = "external_schema"
 	dataCatalogAttr           = "external_schema.0.data_catalog_source.0"
 	hiveMetastoreAttr         = "external_schema.0.hive_metastore_source.0"
 	rdsPostgresAttr           = "external_schema.0.rds_postgres_source.0"
 	rdsMysqlAttr              = "external_schema.0.rds_mysql_source.0"
 	redshiftAttr              = "external_schema.0.redshift_source.0"
+
+	schemaDropProgressInterval = 30 * time.Second
 )
 
 func redshiftSchema() *schema.Resource {
@@ -31,34 +37,44 @@ func redshiftSchema() *schema.Resource {
 		Description: `
 A database contains one or more named schemas. Each schema in a database contains tables and other kinds of named objects. By default, a database has a single schema, which is named PUBLIC. You can use schemas to group database objects under a common name. Schemas are similar to file system directories, except that schemas cannot be nested.
 `,
-		Create: RedshiftResourceFunc(resourceRedshiftSchemaCreate),
-		Read:   RedshiftResourceFunc(resourceRedshiftSchemaRead),
-		Update: RedshiftResourceFunc(resourceRedshiftSchemaUpdate),
+		Create: RedshiftResourceFunc(RedshiftResourceWithStatementTimeout(RedshiftResourceInDatabase(resourceRedshiftSchemaCreate))),
+		Read:   RedshiftResourceReadFunc(RedshiftResourceInDatabase(resourceRedshiftSchemaRead)),
+		Update: RedshiftResourceFunc(RedshiftResourceWithStatementTimeout(RedshiftResourceInDatabase(resourceRedshiftSchemaUpdate))),
 		Delete: RedshiftResourceFunc(
-			RedshiftResourceRetryOnPQErrors(resourceRedshiftSchemaDelete),
+			RedshiftResourceRetryOnPQErrors(RedshiftResourceWithStatementTimeout(RedshiftResourceInDatabase(resourceRedshiftSchemaDelete))),
 		),
-		Exists: RedshiftResourceExistsFunc(resourceRedshiftSchemaExists),
+		Exists: RedshiftResourceExistsFunc(RedshiftResourceExistsInDatabase(resourceRedshiftSchemaExists)),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
-		CustomizeDiff: forceNewIfListSizeChanged(schemaExternalSchemaAttr),
+		CustomizeDiff: customdiff.All(
+			normalizedNameDiff(schemaNameAttr),
+			forceNewIfListSizeChanged(schemaExternalSchemaAttr),
+		),
 		Schema: map[string]*schema.Schema{
+			resourceDatabaseAttr:         resourceDatabaseSchema("schema"),
+			resourceStatementTimeoutAttr: resourceStatementTimeoutSchema("drops of schemas with many objects"),
 			schemaNameAttr: {
 				Type:        schema.TypeString,
 				Required:    true,
 				Description: "Name of the schema. The schema name can't be `PUBLIC`.",
-				ValidateFunc: validation.StringNotInSlice([]string{
-					"public",
-				}, true),
+				ValidateFunc: validation.All(
+					validation.StringNotInSlice([]string{
+						"public",
+					}, true),
+					validateIdentifier,
+				),
 				StateFunc: func(val interface{}) string {
 					return strings.ToLower(val.(string))
 				},
 			},
+			normalizedNameAttr: normalizedNameSchema("schema"),
 			schemaOwnerAttr: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				Description: "Name of the schema owner.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "Name of the schema owner.",
 				StateFunc: func(val interface{}) string {
 					return strings.ToLower(val.(string))
 				},
@@ -84,6 +100,15 @@ A database contains one or more named schemas. Each schema in a database contain
 					schemaExternalSchemaAttr,
 				},
 			},
+			schemaDropTimeoutAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "The maximum time in seconds to wait for `DROP SCHEMA ... CASCADE` to finish when `cascade_on_delete` is set. When exceeded, the statement is cancelled and the schema is left intact. Progress is logged while waiting. By default there is no limit.",
+				ValidateFunc: validation.IntAtLeast(0),
+				ConflictsWith: []string{
+					schemaExternalSchemaAttr,
+				},
+			},
 			schemaExternalSchemaAttr: {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -437,6 +462,7 @@ func resourceRedshiftSchemaReadImpl(db *DBConnection, d *schema.ResourceData) er
 		return err
 	}
 	d.Set(schemaNameAttr, schemaName)
+	d.Set(normalizedNameAttr, normalizeName(db.client, schemaName))
 	d.Set(schemaOwnerAttr, schemaOwner)
 	switch {
 	case schemaType == "local":
@@ -585,13 +611,64 @@ func resourceRedshiftSchemaDelete(db *DBConnection, d *schema.ResourceData) erro
 	}
 
 	query := fmt.Sprintf("DROP SCHEMA %s %s", pq.QuoteIdentifier(schemaName), cascade_or_restrict)
-	if _, err := tx.Exec(query); err != nil {
+	if cascade_or_restrict == "RESTRICT" {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	if err := dropSchemaCascade(tx, schemaName, query, d.Get(schemaDropTimeoutAttr).(int)); err != nil {
 		return err
 	}
 
 	return tx.Commit()
 }
 
+// dropSchemaCascade runs the DROP SCHEMA ... CASCADE query, logging progress while it runs
+// and cancelling it when it takes longer than timeout seconds (if not 0).
+// As the objects are dropped within the transaction, their number can't be observed from outside it
+// and only the elapsed time is reported.
+func dropSchemaCascade(tx *sql.Tx, schemaName, query string, timeout int) error {
+	var objects int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM pg_class cl JOIN pg_namespace nsp ON nsp.oid = cl.relnamespace WHERE nsp.nspname = $1", schemaName).Scan(&objects); err != nil {
+		return err
+	}
+	log.Printf("[INFO] dropping schema %s with %d relations", schemaName, objects)
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		start := time.Now()
+		ticker := time.NewTicker(schemaDropProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				log.Printf("[INFO] still dropping schema %s with %d relations after %s", schemaName, objects, time.Since(start).Round(time.Second))
+			}
+		}
+	}()
+
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("dropping schema %s did not finish within %d seconds (%s) and was cancelled: %w", schemaName, timeout, schemaDropTimeoutAttr, err)
+		}
+		return err
+	}
+	return nil
+}
+
 func resourceRedshiftSchemaCreate(db *DBConnection, d *schema.ResourceData) error {
 	tx, err := startTransaction(db.client, "")
 	if err != nil {
@@ -791,7 +868,8 @@ func resourceRedshiftSchemaUpdate(db *DBConnection, d *schema.ResourceData) erro
 	}
 	defer deferredRollback(tx)
 
-	if err := setSchemaName(tx, d); err != nil {
+	resolver := catalog.NewResolver(tx)
+	if err := setSchemaName(tx, resolver, d); err != nil {
 		return err
 	}
 
@@ -810,7 +888,8 @@ func resourceRedshiftSchemaUpdate(db *DBConnection, d *schema.ResourceData) erro
 	return resourceRedshiftSchemaReadImpl(db, d)
 }
 
-func setSchemaName(tx *sql.Tx, d *schema.ResourceData) error {
+// setSchemaName renames the schema and keeps the resolver of the transaction in sync with the new name.
+func setSchemaName(tx *sql.Tx, resolver *catalog.Resolver, d *schema.ResourceData) error {
 	if !d.HasChange(schemaNameAttr) {
 		return nil
 	}
@@ -827,6 +906,7 @@ func setSchemaName(tx *sql.Tx, d *schema.ResourceData) error {
 	if _, err := tx.Exec(query); err != nil {
 		return fmt.Errorf("Error updating schema NAME: %w", err)
 	}
+	resolver.Rename(catalog.Schema, oldValue, newValue)
 
 	return nil
 }
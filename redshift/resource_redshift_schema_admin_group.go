@@ -0,0 +1,261 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	schemaAdminGroupSchemaAttr = "schema"
+	schemaAdminGroupGroupAttr  = "group"
+	schemaAdminGroupOwnerAttr  = "owner"
+)
+
+// schemaAdminGroupObjectTypes are the object types of the schema the admin group gets all privileges on,
+// including objects created later.
+var schemaAdminGroupObjectTypes = []string{"TABLES", "FUNCTIONS", "PROCEDURES"}
+
+func redshiftSchemaAdminGroup() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Makes a group the administrator of a schema. Redshift schemas can only be owned by users, so the schema is owned by a (service) user, while the group gets all privileges on the schema and its tables, functions and procedures. Default privileges of the owner make the group administer objects created later as well.
+
+Note: Do not manage the privileges of the group on the schema in ` + "`redshift_grant`" + ` or ` + "`redshift_default_privileges`" + ` resources as well, as they would override each other.
+`,
+		Create: RedshiftResourceFunc(resourceRedshiftSchemaAdminGroupCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftSchemaAdminGroupRead),
+		Update: RedshiftResourceFunc(resourceRedshiftSchemaAdminGroupUpdate),
+		Delete: RedshiftResourceFunc(
+			RedshiftResourceRetryOnPQErrors(resourceRedshiftSchemaAdminGroupDelete),
+		),
+		Schema: map[string]*schema.Schema{
+			schemaAdminGroupSchemaAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the schema.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			schemaAdminGroupGroupAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the group administering the schema.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+			schemaAdminGroupOwnerAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the (service) user owning the schema. Defaults to the current owner of the schema. The owner is not changed back when the resource is destroyed.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToLower(val.(string))
+				},
+			},
+		},
+	}
+}
+
+func resourceRedshiftSchemaAdminGroupCreate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	schemaName := strings.ToLower(d.Get(schemaAdminGroupSchemaAttr).(string))
+	groupName := strings.ToLower(d.Get(schemaAdminGroupGroupAttr).(string))
+
+	owner, err := setSchemaAdminGroupOwner(tx, d)
+	if err != nil {
+		return err
+	}
+
+	if err := grantSchemaAdminGroup(tx, schemaName, groupName, owner); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", schemaName, groupName))
+
+	return resourceRedshiftSchemaAdminGroupRead(db, d)
+}
+
+func resourceRedshiftSchemaAdminGroupRead(db *DBConnection, d *schema.ResourceData) error {
+	schemaName := strings.ToLower(d.Get(schemaAdminGroupSchemaAttr).(string))
+	groupName := strings.ToLower(d.Get(schemaAdminGroupGroupAttr).(string))
+
+	var owner string
+	var schemaCreate, schemaUsage bool
+	err := db.QueryRow(`
+	SELECT
+		trim(u.usename),
+		decode(charindex('C',split_part(split_part(replace(array_to_string(ns.nspacl, '|'), '"', ''),'group ' || gr.groname || '=',2 ) ,'/',1)), 0,0,1) as create,
+		decode(charindex('U',split_part(split_part(replace(array_to_string(ns.nspacl, '|'), '"', ''),'group ' || gr.groname || '=',2 ) ,'/',1)), 0,0,1) as usage
+	FROM pg_namespace ns
+	JOIN pg_user_info u ON u.usesysid = ns.nspowner
+	CROSS JOIN pg_group gr
+	WHERE
+		ns.nspname = $1
+		AND gr.groname = $2
+`, schemaName, groupName).Scan(&owner, &schemaCreate, &schemaUsage)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Schema (%s) or group (%s) not found", schemaName, groupName)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading schema admin group: %w", err)
+	}
+
+	// Missing privileges are granted again by recreating the resource.
+	if !schemaCreate || !schemaUsage {
+		log.Printf("[WARN] Group %s is missing privileges on schema %s", groupName, schemaName)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set(schemaAdminGroupOwnerAttr, owner)
+
+	return nil
+}
+
+func resourceRedshiftSchemaAdminGroupUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChange(schemaAdminGroupOwnerAttr) {
+		return resourceRedshiftSchemaAdminGroupRead(db, d)
+	}
+
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	schemaName := strings.ToLower(d.Get(schemaAdminGroupSchemaAttr).(string))
+	groupName := strings.ToLower(d.Get(schemaAdminGroupGroupAttr).(string))
+
+	// Default privileges are defined per owner, so they move to the new owner.
+	oldOwner, _ := d.GetChange(schemaAdminGroupOwnerAttr)
+	if err := revokeSchemaAdminGroupDefaultPrivileges(tx, schemaName, groupName, oldOwner.(string)); err != nil {
+		return err
+	}
+
+	owner, err := setSchemaAdminGroupOwner(tx, d)
+	if err != nil {
+		return err
+	}
+
+	if err := grantSchemaAdminGroup(tx, schemaName, groupName, owner); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourceRedshiftSchemaAdminGroupRead(db, d)
+}
+
+func resourceRedshiftSchemaAdminGroupDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	schemaName := strings.ToLower(d.Get(schemaAdminGroupSchemaAttr).(string))
+	groupName := strings.ToLower(d.Get(schemaAdminGroupGroupAttr).(string))
+	quotedSchema, quotedGroup := pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(groupName)
+
+	queries := []string{fmt.Sprintf("REVOKE ALL ON SCHEMA %s FROM GROUP %s", quotedSchema, quotedGroup)}
+	for _, objectType := range schemaAdminGroupObjectTypes {
+		queries = append(queries, fmt.Sprintf("REVOKE ALL ON ALL %s IN SCHEMA %s FROM GROUP %s", objectType, quotedSchema, quotedGroup))
+	}
+	for _, query := range queries {
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	if err := revokeSchemaAdminGroupDefaultPrivileges(tx, schemaName, groupName, d.Get(schemaAdminGroupOwnerAttr).(string)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// setSchemaAdminGroupOwner changes the owner of the schema when configured and returns the resulting owner.
+func setSchemaAdminGroupOwner(tx *sql.Tx, d *schema.ResourceData) (string, error) {
+	schemaName := strings.ToLower(d.Get(schemaAdminGroupSchemaAttr).(string))
+
+	if owner, ok := d.GetOk(schemaAdminGroupOwnerAttr); ok {
+		ownerName := strings.ToLower(owner.(string))
+		query := fmt.Sprintf("ALTER SCHEMA %s OWNER TO %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(ownerName))
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return "", fmt.Errorf("Error updating schema OWNER: %w", err)
+		}
+		return ownerName, nil
+	}
+
+	var ownerName string
+	err := tx.QueryRow("SELECT trim(u.usename) FROM pg_namespace ns JOIN pg_user_info u ON u.usesysid = ns.nspowner WHERE ns.nspname = $1", schemaName).Scan(&ownerName)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("schema %q does not exist", schemaName)
+	}
+	return ownerName, err
+}
+
+// grantSchemaAdminGroup grants the group all privileges on the schema and its objects,
+// and on the objects the owner creates in the schema later.
+func grantSchemaAdminGroup(tx *sql.Tx, schemaName, groupName, owner string) error {
+	quotedSchema, quotedGroup := pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(groupName)
+
+	queries := []string{fmt.Sprintf("GRANT ALL ON SCHEMA %s TO GROUP %s", quotedSchema, quotedGroup)}
+	for _, objectType := range schemaAdminGroupObjectTypes {
+		queries = append(queries,
+			fmt.Sprintf("GRANT ALL ON ALL %s IN SCHEMA %s TO GROUP %s", objectType, quotedSchema, quotedGroup),
+			fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER %s IN SCHEMA %s GRANT ALL ON %s TO GROUP %s", pq.QuoteIdentifier(owner), quotedSchema, objectType, quotedGroup),
+		)
+	}
+
+	for _, query := range queries {
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("could not grant privileges on schema %s to group %s: %w", schemaName, groupName, err)
+		}
+	}
+	return nil
+}
+
+func revokeSchemaAdminGroupDefaultPrivileges(tx *sql.Tx, schemaName, groupName, owner string) error {
+	if owner == "" {
+		return nil
+	}
+
+	for _, objectType := range schemaAdminGroupObjectTypes {
+		query := fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER %s IN SCHEMA %s REVOKE ALL ON %s FROM GROUP %s", pq.QuoteIdentifier(owner), pq.QuoteIdentifier(schemaName), objectType, pq.QuoteIdentifier(groupName))
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRedshiftSchemaAdminGroup_Basic(t *testing.T) {
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_admin"), "-", "_")
+	groupName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_admin_group"), "-", "_")
+	ownerName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema_admin_owner"), "-", "_")
+
+	config := func(owner string) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "owner" {
+  name = %[3]q
+}
+
+resource "redshift_group" "group" {
+  name = %[2]q
+}
+
+resource "redshift_schema" "schema" {
+  name = %[1]q
+}
+
+resource "redshift_schema_admin_group" "admin" {
+  schema = redshift_schema.schema.name
+  group  = redshift_group.group.name
+  %[4]s
+}
+`, schemaName, groupName, ownerName, owner)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_schema_admin_group.admin", "id", fmt.Sprintf("%s.%s", schemaName, groupName)),
+					resource.TestCheckResourceAttrSet("redshift_schema_admin_group.admin", "owner"),
+				),
+			},
+			{
+				Config: config("owner = redshift_user.owner.name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_schema_admin_group.admin", "owner", ownerName),
+				),
+			},
+		},
+	})
+}
@@ -34,6 +34,10 @@ func TestAccRedshiftSchema_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("redshift_schema.schema_configured", "quota", "15360"),
 					resource.TestCheckResourceAttr("redshift_schema.schema_configured", "cascade_on_delete", "false"),
 
+					testAccCheckRedshiftSchemaExists("schema_cascade"),
+					resource.TestCheckResourceAttr("redshift_schema.schema_cascade", "cascade_on_delete", "true"),
+					resource.TestCheckResourceAttr("redshift_schema.schema_cascade", "drop_cascade_timeout", "600"),
+
 					testAccCheckRedshiftSchemaExists("wOoOT_I22_@tH15"),
 					resource.TestCheckResourceAttr("redshift_schema.fancy_name", "name", "wooot_i22_@th15"),
 				),
@@ -552,6 +556,75 @@ resource "redshift_schema" "redshift" {
 	})
 }
 
+func TestAccRedshiftSchema_OtherDatabase(t *testing.T) {
+	dbName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_db"), "-", "_")
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_database" "db" {
+  name = %[1]q
+}
+
+resource "redshift_schema" "schema" {
+  database = redshift_database.db.name
+  name = %[2]q
+}
+
+resource "redshift_table" "table" {
+  database = redshift_database.db.name
+  schema = redshift_schema.schema.name
+  name = "events"
+
+  column {
+    name = "id"
+    type = "integer"
+  }
+}
+
+resource "redshift_default_privileges" "public" {
+  database = redshift_database.db.name
+  group = "public"
+  owner = redshift_schema.schema.owner
+  schema = redshift_schema.schema.name
+  object_type = "table"
+  privileges = ["select"]
+}
+
+resource "redshift_grant" "public" {
+  database = redshift_database.db.name
+  group = "public"
+  schema = redshift_schema.schema.name
+  object_type = "schema"
+  privileges = ["usage"]
+}
+`, dbName, schemaName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_schema.schema", "database", dbName),
+					resource.TestCheckResourceAttr("redshift_table.table", "database", dbName),
+					resource.TestCheckResourceAttr("redshift_grant.public", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("redshift_default_privileges.public", "privileges.#", "1"),
+					func(s *terraform.State) error {
+						client := testAccProvider.Meta().(*Client)
+						if exists, err := checkSchemaExists(client.config.NewClient(dbName), schemaName); err != nil || !exists {
+							return fmt.Errorf("schema %s not found in database %s: %v", schemaName, dbName, err)
+						}
+						if exists, err := checkSchemaExists(client, schemaName); err != nil || exists {
+							return fmt.Errorf("schema %s unexpectedly found in database %s: %v", schemaName, client.databaseName, err)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckRedshiftSchemaDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*Client)
 
@@ -628,6 +701,12 @@ resource "redshift_schema" "schema_configured" {
   owner = upper(redshift_user.schema_test_user1.name)
 }
 
+resource "redshift_schema" "schema_cascade" {
+  name = "schema_cascade"
+  cascade_on_delete = true
+  drop_cascade_timeout = 600
+}
+
 resource "redshift_schema" "fancy_name" {
   name = "wOoOT_I22_@tH15"
 }
@@ -0,0 +1,922 @@
+package redshift
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	tableNameAttr         = "name"
+	tableSchemaAttr       = "schema"
+	tableOwnerAttr        = "owner"
+	tableCommentAttr      = "comment"
+	tableDistStyleAttr    = "diststyle"
+	tableDistKeyAttr      = "distkey"
+	tableSortKeyStyleAttr = "sortkey_style"
+	tableSortKeysAttr     = "sortkeys"
+	tableColumnAttr       = "column"
+
+	tableColumnNameAttr     = "name"
+	tableColumnTypeAttr     = "type"
+	tableColumnEncodingAttr = "encoding"
+	tableColumnNullableAttr = "nullable"
+	tableColumnCommentAttr  = "comment"
+
+	tableDistStyleAuto = "AUTO"
+	tableDistStyleEven = "EVEN"
+	tableDistStyleKey  = "KEY"
+	tableDistStyleAll  = "ALL"
+
+	tableSortKeyStyleCompound    = "COMPOUND"
+	tableSortKeyStyleInterleaved = "INTERLEAVED"
+)
+
+// tableDistStyles maps pg_class.reldiststyle to the distribution styles.
+// Tables with the AUTO style report the style Redshift currently applies as AUTO(ALL), AUTO(EVEN) or AUTO(KEY).
+var tableDistStyles = map[int]string{
+	0:  tableDistStyleEven,
+	1:  tableDistStyleKey,
+	8:  tableDistStyleAll,
+	10: tableDistStyleAuto,
+	11: tableDistStyleAuto,
+	12: tableDistStyleAuto,
+}
+
+// columnTypeAliases maps the type names accepted by Redshift to the names reported by format_type().
+var columnTypeAliases = map[string]string{
+	"int":            "integer",
+	"int4":           "integer",
+	"int2":           "smallint",
+	"int8":           "bigint",
+	"decimal":        "numeric",
+	"float4":         "real",
+	"float":          "double precision",
+	"float8":         "double precision",
+	"bool":           "boolean",
+	"char":           "character",
+	"nchar":          "character",
+	"bpchar":         "character",
+	"varchar":        "character varying",
+	"nvarchar":       "character varying",
+	"text":           "character varying",
+	"timestamp":      "timestamp without time zone",
+	"timestamptz":    "timestamp with time zone",
+	"time":           "time without time zone",
+	"timetz":         "time with time zone",
+	"varbinary":      "varbyte",
+	"binary varying": "varbyte",
+}
+
+func redshiftTable() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Manages a table. Columns can be added at the end of the table or dropped, and their encodings, comments and the length of ` + "`VARCHAR`" + ` columns changed in place, as well as the distribution style, distribution key and compound sort key of the table. Other changes, like changing the type or nullability of a column or reordering columns, recreate the table, which drops its data.
+
+Note: Unless ` + "`case_sensitive_identifiers`" + ` is enabled in the provider, Redshift folds the table and column names to lower case, so they should be lower case in the configuration.
+`,
+		Create: RedshiftResourceFunc(RedshiftResourceInDatabase(resourceRedshiftTableCreate)),
+		Read:   RedshiftResourceReadFunc(RedshiftResourceInDatabase(resourceRedshiftTableRead)),
+		Update: RedshiftResourceFunc(RedshiftResourceInDatabase(resourceRedshiftTableUpdate)),
+		Delete: RedshiftResourceFunc(RedshiftResourceInDatabase(resourceRedshiftTableDelete)),
+		Exists: RedshiftResourceExistsFunc(RedshiftResourceExistsInDatabase(resourceRedshiftTableExists)),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: customdiff.All(
+			tableColumnsForceNew,
+			customdiff.ForceNewIfChange(tableSortKeyStyleAttr, func(ctx context.Context, old, new, meta interface{}) bool {
+				return old.(string) != new.(string)
+			}),
+			tableInterleavedSortKeysForceNew,
+			tableDistStyleDiff,
+			validateTableKeys,
+		),
+		Schema: map[string]*schema.Schema{
+			resourceDatabaseAttr: resourceDatabaseSchema("table"),
+			tableNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "The name of the table.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+			},
+			tableSchemaAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "public",
+				ForceNew:     true,
+				Description:  "The schema of the table.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+			},
+			tableOwnerAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the table owner.",
+			},
+			tableCommentAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The comment of the table.",
+			},
+			tableDistStyleAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The distribution style of the table: `AUTO`, `EVEN`, `KEY` or `ALL`. Defaults to `KEY` when `distkey` is set and to `AUTO` otherwise.",
+				ValidateFunc: validation.StringInSlice([]string{
+					tableDistStyleAuto,
+					tableDistStyleEven,
+					tableDistStyleKey,
+					tableDistStyleAll,
+				}, true),
+				StateFunc: func(val interface{}) string {
+					return strings.ToUpper(val.(string))
+				},
+			},
+			tableDistKeyAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The column used as the distribution key of the table.",
+			},
+			tableSortKeyStyleAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     tableSortKeyStyleCompound,
+				Description: "The style of the sort key: `COMPOUND` or `INTERLEAVED`. Changing it, or the columns of an interleaved sort key, recreates the table.",
+				ValidateFunc: validation.StringInSlice([]string{
+					tableSortKeyStyleCompound,
+					tableSortKeyStyleInterleaved,
+				}, true),
+				StateFunc: func(val interface{}) string {
+					return strings.ToUpper(val.(string))
+				},
+			},
+			tableSortKeysAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The columns of the sort key, in order.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIdentifier,
+				},
+			},
+			tableColumnAttr: {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The columns of the table, in order.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						tableColumnNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The name of the column.",
+							ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+						},
+						tableColumnTypeAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The data type of the column, e.g. `integer` or `varchar(256)`. Increasing the length of a `VARCHAR` column is done in place.",
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								return normalizeColumnType(old) == normalizeColumnType(new)
+							},
+						},
+						tableColumnEncodingAttr: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The compression encoding of the column, e.g. `az64`, `lzo`, `zstd` or `raw`. When not set, Redshift chooses the encoding.",
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								return normalizeColumnEncoding(old) == normalizeColumnEncoding(new)
+							},
+						},
+						tableColumnNullableAttr: {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Whether the column can contain `NULL` values. Columns which can't can only be added by recreating the table.",
+						},
+						tableColumnCommentAttr: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The comment of the column.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// tableColumn is a column of a table as configured or read from the catalog.
+type tableColumn struct {
+	name     string
+	typ      string
+	encoding string
+	nullable bool
+	comment  string
+}
+
+func expandTableColumns(raw []interface{}) []tableColumn {
+	columns := make([]tableColumn, 0, len(raw))
+	for _, c := range raw {
+		m := c.(map[string]interface{})
+		columns = append(columns, tableColumn{
+			name:     m[tableColumnNameAttr].(string),
+			typ:      m[tableColumnTypeAttr].(string),
+			encoding: m[tableColumnEncodingAttr].(string),
+			nullable: m[tableColumnNullableAttr].(bool),
+			comment:  m[tableColumnCommentAttr].(string),
+		})
+	}
+	return columns
+}
+
+func (c tableColumn) definition() string {
+	definition := fmt.Sprintf("%s %s", pq.QuoteIdentifier(c.name), c.typ)
+	if !c.nullable {
+		definition += " NOT NULL"
+	}
+	if c.encoding != "" {
+		definition += " ENCODE " + c.encoding
+	}
+	return definition
+}
+
+func resourceRedshiftTableExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
+	var name string
+	err := db.QueryRow("SELECT relname FROM pg_class WHERE oid = $1 AND relkind = 'r'", d.Id()).Scan(&name)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
+
+func resourceRedshiftTableCreate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	schemaName := d.Get(tableSchemaAttr).(string)
+	tableName := d.Get(tableNameAttr).(string)
+	columns := expandTableColumns(d.Get(tableColumnAttr).([]interface{}))
+
+	definitions := []string{}
+	for _, column := range columns {
+		definitions = append(definitions, column.definition())
+	}
+
+	query := fmt.Sprintf("CREATE TABLE %s (%s)", tableIdentifier(schemaName, tableName), strings.Join(definitions, ", "))
+
+	distStyle := strings.ToUpper(d.Get(tableDistStyleAttr).(string))
+	if distKey := d.Get(tableDistKeyAttr).(string); distKey != "" {
+		query += fmt.Sprintf(" DISTSTYLE KEY DISTKEY(%s)", pq.QuoteIdentifier(distKey))
+	} else if distStyle != "" {
+		query += " DISTSTYLE " + distStyle
+	}
+
+	if sortKeys := d.Get(tableSortKeysAttr).([]interface{}); len(sortKeys) > 0 {
+		query += fmt.Sprintf(" %s SORTKEY(%s)", strings.ToUpper(d.Get(tableSortKeyStyleAttr).(string)), strings.Join(tableSortKeysIdentList(sortKeys), ", "))
+	}
+
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Error creating table %s: %w", tableName, err)
+	}
+
+	if owner, ok := d.GetOk(tableOwnerAttr); ok {
+		if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s OWNER TO %s", tableIdentifier(schemaName, tableName), pq.QuoteIdentifier(owner.(string)))); err != nil {
+			return fmt.Errorf("Error updating table OWNER: %w", err)
+		}
+	}
+
+	if comment := d.Get(tableCommentAttr).(string); comment != "" {
+		if err := setTableComment(tx, schemaName, tableName, comment); err != nil {
+			return err
+		}
+	}
+	for _, column := range columns {
+		if column.comment == "" {
+			continue
+		}
+		if err := setTableColumnComment(tx, schemaName, tableName, column.name, column.comment); err != nil {
+			return err
+		}
+	}
+
+	var oid int
+	err = tx.QueryRow(`
+		SELECT c.oid
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind = 'r'`,
+		db.client.normalizeIdentifier(schemaName), db.client.normalizeIdentifier(tableName),
+	).Scan(&oid)
+	if err != nil {
+		return fmt.Errorf("failed to get ID of table %s: %w", tableName, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(strconv.Itoa(oid))
+
+	return resourceRedshiftTableRead(db, d)
+}
+
+func resourceRedshiftTableRead(db *DBConnection, d *schema.ResourceData) error {
+	var tableName, schemaName, owner, comment string
+	var distStyle int
+
+	err := db.QueryRow(`
+		SELECT
+		  c.relname,
+		  n.nspname,
+		  COALESCE(u.usename, ''),
+		  c.reldiststyle,
+		  COALESCE(ds.description, '')
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_user_info u ON u.usesysid = c.relowner
+		LEFT JOIN pg_description ds ON ds.objoid = c.oid AND ds.objsubid = 0
+		WHERE c.oid = $1 AND c.relkind = 'r'`, d.Id()).Scan(&tableName, &schemaName, &owner, &distStyle, &comment)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift Table (%s) not found", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading table: %w", err)
+	}
+
+	rows, err := db.Query(`
+		SELECT
+		  a.attname,
+		  format_type(a.atttypid, a.atttypmod),
+		  format_encoding(a.attencodingtype::integer),
+		  NOT a.attnotnull,
+		  COALESCE(ds.description, ''),
+		  a.attisdistkey,
+		  a.attsortkeyord
+		FROM pg_attribute a
+		LEFT JOIN pg_description ds ON ds.objoid = a.attrelid AND ds.objsubid = a.attnum
+		WHERE a.attrelid = $1 AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`, d.Id())
+	if err != nil {
+		return fmt.Errorf("Error reading table columns: %w", err)
+	}
+	defer rows.Close()
+
+	columns := []map[string]interface{}{}
+	distKey := ""
+	sortKeys := map[int]string{}
+	sortKeyStyle := tableSortKeyStyleCompound
+	for rows.Next() {
+		var column tableColumn
+		var isDistKey bool
+		var sortKeyOrd int
+		if err := rows.Scan(&column.name, &column.typ, &column.encoding, &column.nullable, &column.comment, &isDistKey, &sortKeyOrd); err != nil {
+			return err
+		}
+
+		columns = append(columns, map[string]interface{}{
+			tableColumnNameAttr:     column.name,
+			tableColumnTypeAttr:     column.typ,
+			tableColumnEncodingAttr: normalizeColumnEncoding(column.encoding),
+			tableColumnNullableAttr: column.nullable,
+			tableColumnCommentAttr:  column.comment,
+		})
+		if isDistKey {
+			distKey = column.name
+		}
+		// Columns of interleaved sort keys have negative positions.
+		if sortKeyOrd < 0 {
+			sortKeyStyle = tableSortKeyStyleInterleaved
+			sortKeyOrd = -sortKeyOrd
+		}
+		if sortKeyOrd > 0 {
+			sortKeys[sortKeyOrd] = column.name
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	sortKeysList := []string{}
+	for i := 1; i <= len(sortKeys); i++ {
+		sortKeysList = append(sortKeysList, sortKeys[i])
+	}
+
+	style, ok := tableDistStyles[distStyle]
+	if !ok {
+		return fmt.Errorf("unsupported distribution style %d of table %s", distStyle, tableName)
+	}
+	if style != tableDistStyleKey {
+		distKey = ""
+	}
+
+	d.Set(tableNameAttr, tableName)
+	d.Set(tableSchemaAttr, schemaName)
+	d.Set(tableOwnerAttr, owner)
+	d.Set(tableCommentAttr, comment)
+	d.Set(tableDistStyleAttr, style)
+	d.Set(tableDistKeyAttr, distKey)
+	d.Set(tableSortKeyStyleAttr, sortKeyStyle)
+	d.Set(tableSortKeysAttr, sortKeysList)
+	d.Set(tableColumnAttr, columns)
+
+	return nil
+}
+
+func resourceRedshiftTableUpdate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := setTableName(tx, d); err != nil {
+		return err
+	}
+
+	if err := setTableOwner(tx, d); err != nil {
+		return err
+	}
+
+	oldColumnsRaw, newColumnsRaw := d.GetChange(tableColumnAttr)
+	oldColumns := expandTableColumns(oldColumnsRaw.([]interface{}))
+	newColumns := expandTableColumns(newColumnsRaw.([]interface{}))
+
+	if err := addTableColumns(tx, db, d, oldColumns, newColumns); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	// Redshift does not allow to alter the type or encoding of columns, nor the distribution or sort keys,
+	// within a transaction block, so each of these statements is committed on its own.
+	if err := alterTableColumns(db, d, oldColumns, newColumns); err != nil {
+		return err
+	}
+
+	if err := setTableDistribution(db, d); err != nil {
+		return err
+	}
+
+	if err := setTableSortKeys(db, d); err != nil {
+		return err
+	}
+
+	// Columns are dropped after the keys have been changed, as key columns can't be dropped.
+	tx, err = startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := dropTableColumns(tx, db, d, oldColumns, newColumns); err != nil {
+		return err
+	}
+
+	if err := setTableComments(tx, db, d, oldColumns, newColumns); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourceRedshiftTableRead(db, d)
+}
+
+func resourceRedshiftTableDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	query := fmt.Sprintf("DROP TABLE %s", tableIdentifier(d.Get(tableSchemaAttr).(string), d.Get(tableNameAttr).(string)))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setTableName(tx *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(tableNameAttr) {
+		return nil
+	}
+
+	oldRaw, newRaw := d.GetChange(tableNameAttr)
+	query := fmt.Sprintf("ALTER TABLE %s RENAME TO %s", tableIdentifier(d.Get(tableSchemaAttr).(string), oldRaw.(string)), pq.QuoteIdentifier(newRaw.(string)))
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Error updating table NAME: %w", err)
+	}
+
+	return nil
+}
+
+func setTableOwner(tx *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(tableOwnerAttr) {
+		return nil
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s OWNER TO %s", tableIdentifierFromResource(d), pq.QuoteIdentifier(d.Get(tableOwnerAttr).(string)))
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Error updating table OWNER: %w", err)
+	}
+
+	return nil
+}
+
+func addTableColumns(tx *sql.Tx, db *DBConnection, d *schema.ResourceData, oldColumns, newColumns []tableColumn) error {
+	existing := tableColumnsByName(db, oldColumns)
+	for _, column := range newColumns {
+		if _, ok := existing[db.client.normalizeIdentifier(column.name)]; ok {
+			continue
+		}
+
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", tableIdentifierFromResource(d), column.definition())
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("Error adding column %s: %w", column.name, err)
+		}
+	}
+	return nil
+}
+
+func dropTableColumns(tx *sql.Tx, db *DBConnection, d *schema.ResourceData, oldColumns, newColumns []tableColumn) error {
+	kept := tableColumnsByName(db, newColumns)
+	for _, column := range oldColumns {
+		if _, ok := kept[db.client.normalizeIdentifier(column.name)]; ok {
+			continue
+		}
+
+		query := fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", tableIdentifierFromResource(d), pq.QuoteIdentifier(column.name))
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("Error dropping column %s: %w", column.name, err)
+		}
+	}
+	return nil
+}
+
+// alterTableColumns changes the type and encoding of the columns present before and after the change.
+// Other changes of these columns recreate the table, see tableColumnsForceNew.
+func alterTableColumns(db *DBConnection, d *schema.ResourceData, oldColumns, newColumns []tableColumn) error {
+	existing := tableColumnsByName(db, oldColumns)
+	for _, column := range newColumns {
+		old, ok := existing[db.client.normalizeIdentifier(column.name)]
+		if !ok {
+			continue
+		}
+
+		queries := []string{}
+		if normalizeColumnType(old.typ) != normalizeColumnType(column.typ) {
+			queries = append(queries, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s", tableIdentifierFromResource(d), pq.QuoteIdentifier(column.name), column.typ))
+		}
+		if column.encoding != "" && normalizeColumnEncoding(old.encoding) != normalizeColumnEncoding(column.encoding) {
+			queries = append(queries, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s ENCODE %s", tableIdentifierFromResource(d), pq.QuoteIdentifier(column.name), column.encoding))
+		}
+
+		for _, query := range queries {
+			log.Printf("[DEBUG] %s\n", query)
+			if _, err := db.Exec(query); err != nil {
+				return fmt.Errorf("Error altering column %s: %w", column.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func setTableDistribution(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChanges(tableDistStyleAttr, tableDistKeyAttr) {
+		return nil
+	}
+
+	var query string
+	if distKey := d.Get(tableDistKeyAttr).(string); distKey != "" {
+		query = fmt.Sprintf("ALTER TABLE %s ALTER DISTKEY %s", tableIdentifierFromResource(d), pq.QuoteIdentifier(distKey))
+	} else {
+		distStyle := strings.ToUpper(d.Get(tableDistStyleAttr).(string))
+		query = fmt.Sprintf("ALTER TABLE %s ALTER DISTSTYLE %s", tableIdentifierFromResource(d), distStyle)
+	}
+
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("Error updating table distribution: %w", err)
+	}
+	return nil
+}
+
+func setTableSortKeys(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChange(tableSortKeysAttr) {
+		return nil
+	}
+
+	sortKey := "NONE"
+	if sortKeys := d.Get(tableSortKeysAttr).([]interface{}); len(sortKeys) > 0 {
+		sortKey = fmt.Sprintf("(%s)", strings.Join(tableSortKeysIdentList(sortKeys), ", "))
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s ALTER SORTKEY %s", tableIdentifierFromResource(d), sortKey)
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("Error updating table SORTKEY: %w", err)
+	}
+	return nil
+}
+
+func setTableComments(tx *sql.Tx, db *DBConnection, d *schema.ResourceData, oldColumns, newColumns []tableColumn) error {
+	schemaName := d.Get(tableSchemaAttr).(string)
+	tableName := d.Get(tableNameAttr).(string)
+
+	if d.HasChange(tableCommentAttr) {
+		if err := setTableComment(tx, schemaName, tableName, d.Get(tableCommentAttr).(string)); err != nil {
+			return err
+		}
+	}
+
+	existing := tableColumnsByName(db, oldColumns)
+	for _, column := range newColumns {
+		// Added columns have no comment yet.
+		if old := existing[db.client.normalizeIdentifier(column.name)]; old.comment == column.comment {
+			continue
+		}
+		if err := setTableColumnComment(tx, schemaName, tableName, column.name, column.comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setTableComment(tx *sql.Tx, schemaName, tableName, comment string) error {
+	query := fmt.Sprintf("COMMENT ON TABLE %s IS %s", tableIdentifier(schemaName, tableName), commentLiteral(comment))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Error updating table COMMENT: %w", err)
+	}
+	return nil
+}
+
+func setTableColumnComment(tx *sql.Tx, schemaName, tableName, columnName, comment string) error {
+	query := fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", tableIdentifier(schemaName, tableName), pq.QuoteIdentifier(columnName), commentLiteral(comment))
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Error updating COMMENT of column %s: %w", columnName, err)
+	}
+	return nil
+}
+
+func tableIdentifier(schemaName, tableName string) string {
+	return fmt.Sprintf("%s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(tableName))
+}
+
+func tableIdentifierFromResource(d *schema.ResourceData) string {
+	return tableIdentifier(d.Get(tableSchemaAttr).(string), d.Get(tableNameAttr).(string))
+}
+
+func tableSortKeysIdentList(sortKeys []interface{}) []string {
+	columns := []string{}
+	for _, column := range sortKeys {
+		columns = append(columns, pq.QuoteIdentifier(column.(string)))
+	}
+	return columns
+}
+
+func tableColumnsByName(db *DBConnection, columns []tableColumn) map[string]tableColumn {
+	result := map[string]tableColumn{}
+	for _, column := range columns {
+		result[db.client.normalizeIdentifier(column.name)] = column
+	}
+	return result
+}
+
+// tableColumnsForceNew recreates the table when the columns change in a way which can't be applied in place:
+// existing columns can only be dropped or have the length of VARCHAR columns increased,
+// and new nullable columns can only be added after them.
+func tableColumnsForceNew(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange(tableColumnAttr) {
+		return nil
+	}
+
+	oldRaw, newRaw := d.GetChange(tableColumnAttr)
+	if !tableColumnsChangeableInPlace(expandTableColumns(oldRaw.([]interface{})), expandTableColumns(newRaw.([]interface{}))) {
+		return d.ForceNew(tableColumnAttr)
+	}
+	return nil
+}
+
+func tableColumnsChangeableInPlace(oldColumns, newColumns []tableColumn) bool {
+	existing := map[string]tableColumn{}
+	for _, column := range oldColumns {
+		existing[strings.ToLower(column.name)] = column
+	}
+
+	added := false
+	for _, column := range newColumns {
+		old, ok := existing[strings.ToLower(column.name)]
+		if !ok {
+			// Redshift can't add NOT NULL columns without a default value.
+			if !column.nullable {
+				return false
+			}
+			added = true
+			continue
+		}
+		// Existing columns must keep their order and precede the added ones.
+		if added {
+			return false
+		}
+		if old.nullable != column.nullable {
+			return false
+		}
+		if !columnTypeChangeableInPlace(old.typ, column.typ) {
+			return false
+		}
+	}
+
+	kept := []string{}
+	for _, column := range newColumns {
+		if _, ok := existing[strings.ToLower(column.name)]; ok {
+			kept = append(kept, strings.ToLower(column.name))
+		}
+	}
+	i := 0
+	for _, column := range oldColumns {
+		if i < len(kept) && strings.ToLower(column.name) == kept[i] {
+			i++
+		}
+	}
+	return i == len(kept)
+}
+
+// columnTypeChangeableInPlace reports whether the type of a column can be changed using ALTER COLUMN ... TYPE,
+// which Redshift supports for increasing the length of VARCHAR columns only.
+func columnTypeChangeableInPlace(oldType, newType string) bool {
+	oldType = normalizeColumnType(oldType)
+	newType = normalizeColumnType(newType)
+	if oldType == newType {
+		return true
+	}
+
+	const varcharPrefix = "character varying("
+	if !strings.HasPrefix(oldType, varcharPrefix) || !strings.HasPrefix(newType, varcharPrefix) {
+		return false
+	}
+	oldLength, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(oldType, varcharPrefix), ")"))
+	if err != nil {
+		return false
+	}
+	newLength, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(newType, varcharPrefix), ")"))
+	if err != nil {
+		return false
+	}
+	return newLength > oldLength
+}
+
+func tableInterleavedSortKeysForceNew(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange(tableSortKeysAttr) {
+		return nil
+	}
+	if strings.ToUpper(d.Get(tableSortKeyStyleAttr).(string)) == tableSortKeyStyleInterleaved {
+		return d.ForceNew(tableSortKeysAttr)
+	}
+	return nil
+}
+
+// tableDistStyleDiff plans the distribution style implied by distkey when diststyle is not configured,
+// and rejects the combinations Redshift does not support.
+func tableDistStyleDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown(tableDistKeyAttr) || !d.NewValueKnown(tableDistStyleAttr) {
+		return nil
+	}
+
+	distKey := d.Get(tableDistKeyAttr).(string)
+	configured := false
+	distStyle := ""
+	if config := d.GetRawConfig(); !config.IsNull() {
+		if v := config.GetAttr(tableDistStyleAttr); !v.IsNull() {
+			configured = true
+			distStyle = strings.ToUpper(v.AsString())
+		}
+	}
+
+	switch {
+	case configured && distKey != "" && distStyle != tableDistStyleKey:
+		return fmt.Errorf("%s can only be set with %s %s", tableDistKeyAttr, tableDistStyleAttr, tableDistStyleKey)
+	case configured && distKey == "" && distStyle == tableDistStyleKey:
+		return fmt.Errorf("%s %s requires %s to be set", tableDistStyleAttr, tableDistStyleKey, tableDistKeyAttr)
+	case configured || !d.HasChange(tableDistKeyAttr):
+		return nil
+	case distKey != "":
+		return d.SetNew(tableDistStyleAttr, tableDistStyleKey)
+	case d.Id() != "":
+		return d.SetNew(tableDistStyleAttr, tableDistStyleAuto)
+	}
+	return nil
+}
+
+// validateTableKeys checks that the distribution and sort keys are columns of the table.
+func validateTableKeys(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown(tableColumnAttr) {
+		return nil
+	}
+
+	columns := map[string]bool{}
+	for _, column := range expandTableColumns(d.Get(tableColumnAttr).([]interface{})) {
+		columns[strings.ToLower(column.name)] = true
+	}
+
+	if d.NewValueKnown(tableDistKeyAttr) {
+		if distKey := d.Get(tableDistKeyAttr).(string); distKey != "" && !columns[strings.ToLower(distKey)] {
+			return fmt.Errorf("%s %q is not a column of the table", tableDistKeyAttr, distKey)
+		}
+	}
+
+	if d.NewValueKnown(tableSortKeysAttr) {
+		for _, sortKey := range d.Get(tableSortKeysAttr).([]interface{}) {
+			if !columns[strings.ToLower(sortKey.(string))] {
+				return fmt.Errorf("sort key %q is not a column of the table", sortKey)
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalizeColumnType returns the type as reported by format_type(), so types can be compared regardless of the aliases used.
+func normalizeColumnType(typ string) string {
+	typ = strings.Join(strings.Fields(strings.ToLower(typ)), " ")
+
+	base, args := typ, ""
+	if i := strings.Index(typ, "("); i >= 0 && strings.HasSuffix(typ, ")") {
+		base = strings.TrimSpace(typ[:i])
+		args = strings.ReplaceAll(typ[i+1:len(typ)-1], " ", "")
+	}
+	if alias, ok := columnTypeAliases[base]; ok {
+		base = alias
+	}
+
+	switch base {
+	case "character varying":
+		switch args {
+		case "":
+			args = "256"
+		case "max":
+			args = "65535"
+		}
+	case "character":
+		switch args {
+		case "":
+			args = "1"
+		case "max":
+			args = "4096"
+		}
+	case "numeric":
+		if args == "" {
+			args = "18,0"
+		} else if !strings.Contains(args, ",") {
+			args += ",0"
+		}
+	}
+
+	if args != "" {
+		return fmt.Sprintf("%s(%s)", base, args)
+	}
+	return base
+}
+
+// normalizeColumnEncoding returns the encoding as reported by format_encoding(), which reports RAW as none.
+func normalizeColumnEncoding(encoding string) string {
+	encoding = strings.ToLower(encoding)
+	if encoding == "raw" {
+		return "none"
+	}
+	return encoding
+}
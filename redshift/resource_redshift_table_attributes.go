@@ -0,0 +1,363 @@
+package redshift
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	tableRowLevelSecurityAttr   = "row_level_security"
+	tableRLSConjunctionTypeAttr = "rls_conjunction_type"
+
+	// pqErrorInsufficientPrivilege is the code of the error returned when reading catalog views reserved to security administrators.
+	pqErrorInsufficientPrivilege = "42501"
+)
+
+func redshiftTableAttributes() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Manages the owner, distribution style, distribution key, compound sort key, comment and row-level security of an existing table created outside of Terraform, e.g. by dbt. Only the configured attributes are managed, the others are read from the table. The table itself is neither created nor dropped: destroying the resource leaves the table and its attributes as they are.
+
+The table is looked up by its schema and name, so attributes are applied again when the table is recreated with the same name. Tables with an interleaved sort key can't have their sort key changed.
+`,
+		Create: RedshiftResourceFunc(RedshiftResourceInDatabase(resourceRedshiftTableAttributesCreate)),
+		Read:   RedshiftResourceReadFunc(RedshiftResourceInDatabase(resourceRedshiftTableAttributesRead)),
+		Update: RedshiftResourceFunc(RedshiftResourceInDatabase(resourceRedshiftTableAttributesUpdate)),
+		Delete: RedshiftResourceFunc(resourceRedshiftTableAttributesDelete),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: tableAttributesDistDiff,
+		Schema: map[string]*schema.Schema{
+			resourceDatabaseAttr: resourceDatabaseSchema("table attributes"),
+			tableNameAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The name of the existing table.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+			},
+			tableSchemaAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "public",
+				ForceNew:     true,
+				Description:  "The schema of the table.",
+				ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+			},
+			tableOwnerAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the table owner. Not managed when not set.",
+			},
+			tableCommentAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The comment of the table. Not managed when not set.",
+			},
+			tableDistStyleAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The distribution style of the table: `AUTO`, `EVEN`, `KEY` or `ALL`. Defaults to `KEY` when `distkey` is set. Not managed when neither `diststyle` nor `distkey` are set.",
+				ValidateFunc: validation.StringInSlice([]string{
+					tableDistStyleAuto,
+					tableDistStyleEven,
+					tableDistStyleKey,
+					tableDistStyleAll,
+				}, true),
+				StateFunc: func(val interface{}) string {
+					return strings.ToUpper(val.(string))
+				},
+			},
+			tableDistKeyAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The column used as the distribution key of the table.",
+			},
+			tableSortKeysAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				Description: "The columns of the compound sort key, in order. Not managed when not set.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIdentifier,
+				},
+			},
+			tableRowLevelSecurityAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether row-level security is turned on for the table, so that users only see the rows allowed by the `redshift_rls_policy_attachment` of the table. Not managed when not set. Reading it requires a superuser or the `sys:secadmin` role.",
+			},
+			tableRLSConjunctionTypeAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				RequiredWith: []string{tableRowLevelSecurityAttr},
+				ValidateFunc: validation.StringInSlice([]string{"AND", "OR"}, true),
+				Description:  "How the RLS policies attached to the table for the same user are combined, `AND` or `OR`.",
+				StateFunc: func(val interface{}) string {
+					return strings.ToUpper(val.(string))
+				},
+			},
+		},
+	}
+}
+
+func resourceRedshiftTableAttributesCreate(db *DBConnection, d *schema.ResourceData) error {
+	schemaName := d.Get(tableSchemaAttr).(string)
+	tableName := d.Get(tableNameAttr).(string)
+
+	var exists bool
+	if err := db.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind = 'r')",
+		db.client.normalizeIdentifier(schemaName), db.client.normalizeIdentifier(tableName)).Scan(&exists); err != nil {
+		return fmt.Errorf("Error reading table: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("table %s.%s does not exist, create it before managing its attributes", schemaName, tableName)
+	}
+
+	if err := setTableAttributes(db, d); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", schemaName, tableName))
+
+	return resourceRedshiftTableAttributesRead(db, d)
+}
+
+func resourceRedshiftTableAttributesRead(db *DBConnection, d *schema.ResourceData) error {
+	schemaName, tableName, err := parseTableAttributesID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	var tableID, owner, comment string
+	var distStyle int
+	err = db.QueryRow(`
+		SELECT
+		  c.oid,
+		  COALESCE(u.usename, ''),
+		  c.reldiststyle,
+		  COALESCE(ds.description, '')
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_user_info u ON u.usesysid = c.relowner
+		LEFT JOIN pg_description ds ON ds.objoid = c.oid AND ds.objsubid = 0
+		WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind = 'r'`,
+		db.client.normalizeIdentifier(schemaName), db.client.normalizeIdentifier(tableName)).Scan(&tableID, &owner, &distStyle, &comment)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] Redshift Table %s.%s not found", schemaName, tableName)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("Error reading table: %w", err)
+	}
+
+	rows, err := db.Query(`
+		SELECT a.attname, a.attisdistkey, a.attsortkeyord
+		FROM pg_attribute a
+		WHERE a.attrelid = $1 AND a.attnum > 0 AND NOT a.attisdropped AND (a.attisdistkey OR a.attsortkeyord != 0)`, tableID)
+	if err != nil {
+		return fmt.Errorf("Error reading table keys: %w", err)
+	}
+	defer rows.Close()
+
+	distKey := ""
+	sortKeys := map[int]string{}
+	for rows.Next() {
+		var columnName string
+		var isDistKey bool
+		var sortKeyOrd int
+		if err := rows.Scan(&columnName, &isDistKey, &sortKeyOrd); err != nil {
+			return err
+		}
+		if isDistKey {
+			distKey = columnName
+		}
+		// Columns of interleaved sort keys have negative positions.
+		if sortKeyOrd < 0 {
+			sortKeyOrd = -sortKeyOrd
+		}
+		if sortKeyOrd > 0 {
+			sortKeys[sortKeyOrd] = columnName
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	sortKeysList := []string{}
+	for i := 1; i <= len(sortKeys); i++ {
+		sortKeysList = append(sortKeysList, sortKeys[i])
+	}
+
+	style, ok := tableDistStyles[distStyle]
+	if !ok {
+		return fmt.Errorf("unsupported distribution style %d of table %s.%s", distStyle, schemaName, tableName)
+	}
+	if style != tableDistStyleKey {
+		distKey = ""
+	}
+
+	d.Set(tableSchemaAttr, schemaName)
+	d.Set(tableNameAttr, tableName)
+	d.Set(tableOwnerAttr, owner)
+	d.Set(tableCommentAttr, comment)
+	d.Set(tableDistStyleAttr, style)
+	d.Set(tableDistKeyAttr, distKey)
+	d.Set(tableSortKeysAttr, sortKeysList)
+
+	return readTableRowLevelSecurity(db, d, schemaName, tableName)
+}
+
+// readTableRowLevelSecurity reads whether row-level security is turned on for the table from svv_rls_relation.
+// The view is reserved to security administrators, so the attributes are left as they are for other users.
+func readTableRowLevelSecurity(db *DBConnection, d *schema.ResourceData, schemaName, tableName string) error {
+	var rlsOn bool
+	var conjunctionType string
+	err := db.QueryRow(`
+		SELECT is_rls_on, upper(COALESCE(trim(rls_conjunction_type), ''))
+		FROM svv_rls_relation
+		WHERE datname = current_database() AND relschema = $1 AND relname = $2`,
+		db.client.normalizeIdentifier(schemaName), db.client.normalizeIdentifier(tableName)).Scan(&rlsOn, &conjunctionType)
+	if pqErr, ok := err.(*pq.Error); ok && string(pqErr.Code) == pqErrorInsufficientPrivilege {
+		log.Printf("[WARN] could not read the row-level security of table %s.%s: %v", schemaName, tableName, err)
+		return nil
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("Error reading the row-level security of table: %w", err)
+	}
+
+	d.Set(tableRowLevelSecurityAttr, rlsOn)
+	d.Set(tableRLSConjunctionTypeAttr, conjunctionType)
+	return nil
+}
+
+func resourceRedshiftTableAttributesUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := setTableAttributes(db, d); err != nil {
+		return err
+	}
+
+	return resourceRedshiftTableAttributesRead(db, d)
+}
+
+func resourceRedshiftTableAttributesDelete(db *DBConnection, d *schema.ResourceData) error {
+	log.Printf("[DEBUG] leaving the attributes of table %s as they are", d.Id())
+	return nil
+}
+
+// setTableAttributes applies the changed attributes to the table.
+func setTableAttributes(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := setTableOwner(tx, d); err != nil {
+		return err
+	}
+
+	if d.HasChange(tableCommentAttr) {
+		if err := setTableComment(tx, d.Get(tableSchemaAttr).(string), d.Get(tableNameAttr).(string), d.Get(tableCommentAttr).(string)); err != nil {
+			return err
+		}
+	}
+
+	if err := setTableRowLevelSecurity(tx, d); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	// Redshift does not allow to alter the distribution or sort keys within a transaction block.
+	if err := setTableDistribution(db, d); err != nil {
+		return err
+	}
+
+	return setTableSortKeys(db, d)
+}
+
+// setTableRowLevelSecurity turns row-level security on or off when it changes, or when it is configured for a new resource,
+// as the table may already have row-level security turned on.
+func setTableRowLevelSecurity(tx *sql.Tx, d *schema.ResourceData) error {
+	_, configured := d.GetOkExists(tableRowLevelSecurityAttr)
+	if !d.HasChanges(tableRowLevelSecurityAttr, tableRLSConjunctionTypeAttr) && !(d.IsNewResource() && configured) {
+		return nil
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s ROW LEVEL SECURITY OFF", tableIdentifierFromResource(d))
+	if d.Get(tableRowLevelSecurityAttr).(bool) {
+		query = fmt.Sprintf("ALTER TABLE %s ROW LEVEL SECURITY ON", tableIdentifierFromResource(d))
+		if conjunctionType := d.Get(tableRLSConjunctionTypeAttr).(string); conjunctionType != "" {
+			query = fmt.Sprintf("%s CONJUNCTION TYPE %s", query, strings.ToUpper(conjunctionType))
+		}
+	}
+
+	log.Printf("[DEBUG] %s\n", query)
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("Error updating table ROW LEVEL SECURITY: %w", err)
+	}
+	return nil
+}
+
+// tableAttributesDistDiff plans the distribution key and style implied by the configured one,
+// so that the distribution of the table is only changed when either is configured.
+func tableAttributesDistDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		return nil
+	}
+	distKeyRaw := config.GetAttr(tableDistKeyAttr)
+	distStyleRaw := config.GetAttr(tableDistStyleAttr)
+	if !distKeyRaw.IsKnown() || !distStyleRaw.IsKnown() {
+		return nil
+	}
+	distKeyConfigured := !distKeyRaw.IsNull()
+	distStyle := ""
+	if !distStyleRaw.IsNull() {
+		distStyle = strings.ToUpper(distStyleRaw.AsString())
+	}
+
+	switch {
+	case distKeyConfigured && distStyle != "" && distStyle != tableDistStyleKey:
+		return fmt.Errorf("%s can only be set with %s %s", tableDistKeyAttr, tableDistStyleAttr, tableDistStyleKey)
+	case !distKeyConfigured && distStyle == tableDistStyleKey:
+		return fmt.Errorf("%s %s requires %s to be set", tableDistStyleAttr, tableDistStyleKey, tableDistKeyAttr)
+	case d.Id() == "":
+		return nil
+	case distKeyConfigured && distStyle == "" && d.Get(tableDistStyleAttr).(string) != tableDistStyleKey:
+		return d.SetNew(tableDistStyleAttr, tableDistStyleKey)
+	case !distKeyConfigured && distStyle != "" && d.Get(tableDistKeyAttr).(string) != "":
+		return d.SetNew(tableDistKeyAttr, "")
+	}
+	return nil
+}
+
+// parseTableAttributesID returns the schema and table name of the ID, e.g. "public.events".
+func parseTableAttributesID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid table attributes ID %q, expected <schema>.<table>", id)
+	}
+	return parts[0], parts[1], nil
+}
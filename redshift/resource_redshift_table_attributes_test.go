@@ -0,0 +1,110 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRedshiftTableAttributes_Basic(t *testing.T) {
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	tableName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_table"), "-", "_")
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_owner"), "-", "_")
+	config := func(attributes string) string {
+		return fmt.Sprintf(`
+resource "redshift_schema" "schema" {
+	name = %[1]q
+}
+
+resource "redshift_user" "owner" {
+	name = %[3]q
+}
+
+# Stands for a table created outside of Terraform, its attributes are ignored.
+resource "redshift_table" "table" {
+	name   = %[2]q
+	schema = redshift_schema.schema.name
+
+	column {
+		name = "id"
+		type = "bigint"
+	}
+
+	column {
+		name = "created_at"
+		type = "timestamp"
+	}
+
+	lifecycle {
+		ignore_changes = [owner, comment, diststyle, distkey, sortkeys]
+	}
+}
+
+resource "redshift_table_attributes" "table" {
+	name   = redshift_table.table.name
+	schema = redshift_table.table.schema
+	%[4]s
+}
+`, schemaName, tableName, userName, attributes)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftTableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+	owner    = redshift_user.owner.name
+	comment  = "Events"
+	distkey  = "id"
+	sortkeys = ["created_at"]
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "id", fmt.Sprintf("%s.%s", schemaName, tableName)),
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "owner", userName),
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "comment", "Events"),
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "diststyle", "KEY"),
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "distkey", "id"),
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "sortkeys.#", "1"),
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "sortkeys.0", "created_at"),
+				),
+			},
+			{
+				// Attributes which are no longer configured are left as they are.
+				Config: config(`diststyle = "EVEN"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "owner", userName),
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "comment", "Events"),
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "diststyle", "EVEN"),
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "distkey", ""),
+					resource.TestCheckResourceAttr("redshift_table_attributes.table", "sortkeys.0", "created_at"),
+				),
+			},
+			{
+				ResourceName:      "redshift_table_attributes.table",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestParseTableAttributesID(t *testing.T) {
+	schemaName, tableName, err := parseTableAttributesID("analytics.events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schemaName != "analytics" || tableName != "events" {
+		t.Errorf("expected analytics.events, got %s.%s", schemaName, tableName)
+	}
+
+	for _, id := range []string{"events", ".events", "analytics."} {
+		if _, _, err := parseTableAttributesID(id); err == nil {
+			t.Errorf("expected %q to be an invalid ID", id)
+		}
+	}
+}
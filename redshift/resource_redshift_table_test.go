@@ -0,0 +1,284 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRedshiftTable_Basic(t *testing.T) {
+	schemaName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_schema"), "-", "_")
+	tableName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_table"), "-", "_")
+	config := fmt.Sprintf(`
+resource "redshift_schema" "schema" {
+	name = %[1]q
+}
+
+resource "redshift_table" "table" {
+	name     = %[2]q
+	schema   = redshift_schema.schema.name
+	comment  = "Events"
+	distkey  = "id"
+	sortkeys = ["created_at"]
+
+	column {
+		name     = "id"
+		type     = "bigint"
+		nullable = false
+	}
+
+	column {
+		name     = "name"
+		type     = "varchar(64)"
+		encoding = "zstd"
+		comment  = "Name of the event"
+	}
+
+	column {
+		name = "created_at"
+		type = "timestamp"
+	}
+}
+`, schemaName, tableName)
+
+	configUpdated := fmt.Sprintf(`
+resource "redshift_schema" "schema" {
+	name = %[1]q
+}
+
+resource "redshift_table" "table" {
+	name      = "%[2]s_renamed"
+	schema    = redshift_schema.schema.name
+	diststyle = "EVEN"
+	sortkeys  = ["created_at", "id"]
+
+	column {
+		name     = "id"
+		type     = "int8"
+		nullable = false
+	}
+
+	column {
+		name     = "name"
+		type     = "character varying(128)"
+		encoding = "lzo"
+		comment  = "Name"
+	}
+
+	column {
+		name = "created_at"
+		type = "timestamp without time zone"
+	}
+
+	column {
+		name = "payload"
+		type = "super"
+	}
+}
+`, schemaName, tableName)
+
+	var tableID string
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftTableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_table.table", "name", tableName),
+					resource.TestCheckResourceAttr("redshift_table.table", "schema", schemaName),
+					resource.TestCheckResourceAttr("redshift_table.table", "comment", "Events"),
+					resource.TestCheckResourceAttr("redshift_table.table", "diststyle", "KEY"),
+					resource.TestCheckResourceAttr("redshift_table.table", "distkey", "id"),
+					resource.TestCheckResourceAttr("redshift_table.table", "sortkey_style", "COMPOUND"),
+					resource.TestCheckResourceAttr("redshift_table.table", "sortkeys.#", "1"),
+					resource.TestCheckResourceAttr("redshift_table.table", "sortkeys.0", "created_at"),
+					resource.TestCheckResourceAttr("redshift_table.table", "column.#", "3"),
+					resource.TestCheckResourceAttr("redshift_table.table", "column.0.nullable", "false"),
+					resource.TestCheckResourceAttr("redshift_table.table", "column.1.type", "character varying(64)"),
+					resource.TestCheckResourceAttr("redshift_table.table", "column.1.encoding", "zstd"),
+					resource.TestCheckResourceAttr("redshift_table.table", "column.1.comment", "Name of the event"),
+					func(s *terraform.State) error {
+						tableID = s.RootModule().Resources["redshift_table.table"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config: configUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_table.table", "name", fmt.Sprintf("%s_renamed", tableName)),
+					resource.TestCheckResourceAttr("redshift_table.table", "comment", ""),
+					resource.TestCheckResourceAttr("redshift_table.table", "diststyle", "EVEN"),
+					resource.TestCheckResourceAttr("redshift_table.table", "distkey", ""),
+					resource.TestCheckResourceAttr("redshift_table.table", "sortkeys.#", "2"),
+					resource.TestCheckResourceAttr("redshift_table.table", "column.#", "4"),
+					resource.TestCheckResourceAttr("redshift_table.table", "column.1.type", "character varying(128)"),
+					resource.TestCheckResourceAttr("redshift_table.table", "column.1.encoding", "lzo"),
+					resource.TestCheckResourceAttr("redshift_table.table", "column.1.comment", "Name"),
+					resource.TestCheckResourceAttr("redshift_table.table", "column.3.name", "payload"),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["redshift_table.table"].Primary.ID; id != tableID {
+							return fmt.Errorf("expected the table to be altered in place, but it was recreated")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "redshift_table.table",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckRedshiftTableDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+	db, err := client.Connect()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "redshift_table" {
+			continue
+		}
+
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM pg_class WHERE oid = $1", rs.Primary.ID).Scan(&count); err != nil {
+			return fmt.Errorf("Error checking table %s", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("Table %s still exists after destroy", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func TestNormalizeColumnType(t *testing.T) {
+	cases := map[string]string{
+		"INT":                   "integer",
+		"int8":                  "bigint",
+		"varchar":               "character varying(256)",
+		"VARCHAR( 64 )":         "character varying(64)",
+		"varchar(max)":          "character varying(65535)",
+		"text":                  "character varying(256)",
+		"char":                  "character(1)",
+		"decimal(10)":           "numeric(10,0)",
+		"numeric(10, 2)":        "numeric(10,2)",
+		"timestamptz":           "timestamp with time zone",
+		"double   precision":    "double precision",
+		"character varying(12)": "character varying(12)",
+		"super":                 "super",
+	}
+
+	for typ, expected := range cases {
+		if actual := normalizeColumnType(typ); actual != expected {
+			t.Errorf("expected %q to be normalized to %q, got %q", typ, expected, actual)
+		}
+	}
+}
+
+func TestTableColumnsChangeableInPlace(t *testing.T) {
+	existing := []tableColumn{
+		{name: "id", typ: "bigint", nullable: false},
+		{name: "name", typ: "character varying(64)", nullable: true},
+		{name: "created_at", typ: "timestamp without time zone", nullable: true},
+	}
+
+	cases := map[string]struct {
+		columns  []tableColumn
+		expected bool
+	}{
+		"unchanged with aliases": {
+			columns: []tableColumn{
+				{name: "id", typ: "int8", nullable: false},
+				{name: "name", typ: "varchar(64)", nullable: true},
+				{name: "created_at", typ: "timestamp", nullable: true},
+			},
+			expected: true,
+		},
+		"added and dropped columns": {
+			columns: []tableColumn{
+				{name: "id", typ: "bigint", nullable: false},
+				{name: "created_at", typ: "timestamp", nullable: true},
+				{name: "payload", typ: "super", nullable: true},
+			},
+			expected: true,
+		},
+		"varchar widened": {
+			columns: []tableColumn{
+				{name: "id", typ: "bigint", nullable: false},
+				{name: "name", typ: "varchar(128)", nullable: true},
+				{name: "created_at", typ: "timestamp", nullable: true},
+			},
+			expected: true,
+		},
+		"varchar narrowed": {
+			columns: []tableColumn{
+				{name: "id", typ: "bigint", nullable: false},
+				{name: "name", typ: "varchar(32)", nullable: true},
+				{name: "created_at", typ: "timestamp", nullable: true},
+			},
+			expected: false,
+		},
+		"type changed": {
+			columns: []tableColumn{
+				{name: "id", typ: "integer", nullable: false},
+				{name: "name", typ: "varchar(64)", nullable: true},
+				{name: "created_at", typ: "timestamp", nullable: true},
+			},
+			expected: false,
+		},
+		"nullability changed": {
+			columns: []tableColumn{
+				{name: "id", typ: "bigint", nullable: true},
+				{name: "name", typ: "varchar(64)", nullable: true},
+				{name: "created_at", typ: "timestamp", nullable: true},
+			},
+			expected: false,
+		},
+		"reordered": {
+			columns: []tableColumn{
+				{name: "name", typ: "varchar(64)", nullable: true},
+				{name: "id", typ: "bigint", nullable: false},
+				{name: "created_at", typ: "timestamp", nullable: true},
+			},
+			expected: false,
+		},
+		"added in the middle": {
+			columns: []tableColumn{
+				{name: "id", typ: "bigint", nullable: false},
+				{name: "payload", typ: "super", nullable: true},
+				{name: "name", typ: "varchar(64)", nullable: true},
+				{name: "created_at", typ: "timestamp", nullable: true},
+			},
+			expected: false,
+		},
+		"added not null": {
+			columns: []tableColumn{
+				{name: "id", typ: "bigint", nullable: false},
+				{name: "name", typ: "varchar(64)", nullable: true},
+				{name: "created_at", typ: "timestamp", nullable: true},
+				{name: "payload", typ: "super", nullable: false},
+			},
+			expected: false,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := tableColumnsChangeableInPlace(existing, c.columns); actual != c.expected {
+				t.Errorf("expected %t, got %t", c.expected, actual)
+			}
+		})
+	}
+}
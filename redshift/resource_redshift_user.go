@@ -9,6 +9,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/brainly/terraform-provider-redshift/redshift/internal/catalog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/lib/pq"
@@ -23,17 +25,37 @@ const (
 	userSyslogAccessAttr   = "syslog_access"
 	userSuperuserAttr      = "superuser"
 	userSessionTimeoutAttr = "session_timeout"
+	userExternalIDAttr     = "external_id"
+
+	userRevokeAllOnDestroyScopeAttr = "revoke_all_on_destroy_scope"
+
+	userEffectiveGrantsSummaryAttr = "effective_grants_summary"
 
 	// defaults
 	defaultUserSyslogAccess          = "RESTRICTED"
 	defaultUserSuperuserSyslogAccess = "UNRESTRICTED"
+
+	// scopes of revoke_all_on_destroy_scope
+	userRevokeScopeCurrentDB = "current_db"
+	userRevokeScopeAllDBs    = "all_dbs"
 )
 
 // When authenticating using temporary credentials obtained by GetClusterCredentials,
-// the resulting username is prefixed with either "IAM:"" or "IAMA:"
-// This regexp is designed to match either prefix.
+// the resulting username is prefixed with either "IAM:"" or "IAMA:".
+// Redshift Serverless GetCredentials prefixes it with "IAM:" or "IAMR:".
+// This regexp is designed to match any of the prefixes.
 // See https://docs.aws.amazon.com/redshift/latest/APIReference/API_GetClusterCredentials.html
-var temporaryCredentialsUsernamePrefixRegexp = regexp.MustCompile("^(?:IAMA?:)")
+var temporaryCredentialsUsernamePrefixRegexp = regexp.MustCompile("^(?:IAM[AR]?:)")
+
+// normalizeValidUntil returns the canonical form of a VALID UNTIL value,
+// mapping the aliases of a password without time limit to "infinity".
+func normalizeValidUntil(validUntil string) string {
+	switch strings.ToLower(strings.TrimSpace(validUntil)) {
+	case "", "infinity", "never":
+		return "infinity"
+	}
+	return validUntil
+}
 
 // Resolve the "real" username by stripping the temporary credentials prefix
 func permanentUsername(username string) string {
@@ -46,7 +68,7 @@ func redshiftUser() *schema.Resource {
 Amazon Redshift user accounts can only be created and dropped by a database superuser. Users are authenticated when they login to Amazon Redshift. They can own databases and database objects (for example, tables) and can grant privileges on those objects to users, groups, and schemas to control who has access to which object. Users with CREATE DATABASE rights can create databases and grant privileges to those databases. Superusers have database ownership privileges for all databases.
 `,
 		Create: RedshiftResourceFunc(resourceRedshiftUserCreate),
-		Read:   RedshiftResourceFunc(resourceRedshiftUserRead),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftUserRead),
 		Update: RedshiftResourceFunc(resourceRedshiftUserUpdate),
 		Delete: RedshiftResourceFunc(
 			RedshiftResourceRetryOnPQErrors(resourceRedshiftUserDelete),
@@ -55,33 +77,44 @@ Amazon Redshift user accounts can only be created and dropped by a database supe
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
-		CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, p interface{}) error {
-			isSuperuser := d.Get(userSuperuserAttr).(bool)
-
-			isPasswordKnown := d.NewValueKnown(userPasswordAttr)
-			password, hasPassword := d.GetOk(userPasswordAttr)
-			if isSuperuser && isPasswordKnown && (!hasPassword || password.(string) == "") {
-				return fmt.Errorf("Users that are superusers must define a password.")
-			}
+		CustomizeDiff: customdiff.All(
+			normalizedNameDiff(userNameAttr),
+			func(_ context.Context, d *schema.ResourceDiff, p interface{}) error {
+				isSuperuser := d.Get(userSuperuserAttr).(bool)
+
+				isPasswordKnown := d.NewValueKnown(userPasswordAttr)
+				password, hasPassword := d.GetOk(userPasswordAttr)
+				if isSuperuser && isPasswordKnown && (!hasPassword || password.(string) == "") {
+					return fmt.Errorf("Users that are superusers must define a password.")
+				}
 
-			isSyslogAccessKnown := d.NewValueKnown(userSyslogAccessAttr)
-			syslogAccess, hasSyslogAccess := d.GetOk(userSyslogAccessAttr)
-			if isSuperuser && isSyslogAccessKnown && hasSyslogAccess && syslogAccess != defaultUserSuperuserSyslogAccess {
-				return fmt.Errorf("Superusers must have syslog access set to %s.", defaultUserSuperuserSyslogAccess)
-			}
+				isSyslogAccessKnown := d.NewValueKnown(userSyslogAccessAttr)
+				syslogAccess, hasSyslogAccess := d.GetOk(userSyslogAccessAttr)
+				if isSuperuser && isSyslogAccessKnown && hasSyslogAccess && syslogAccess != defaultUserSuperuserSyslogAccess {
+					return fmt.Errorf("Superusers must have syslog access set to %s.", defaultUserSuperuserSyslogAccess)
+				}
 
-			return nil
-		},
+				return nil
+			},
+			// The external ID can be changed, but not removed from the user.
+			customdiff.ForceNewIfChange(userExternalIDAttr, func(_ context.Context, oldValue, newValue, meta interface{}) bool {
+				return oldValue.(string) != "" && newValue.(string) == ""
+			}),
+		),
 
 		Schema: map[string]*schema.Schema{
 			userNameAttr: {
 				Type:        schema.TypeString,
 				Required:    true,
 				Description: "The name of the user account to create. The user name can't be `PUBLIC`.",
-				ValidateFunc: validation.StringNotInSlice([]string{
-					"public",
-				}, true),
+				ValidateFunc: validation.All(
+					validation.StringNotInSlice([]string{
+						"public",
+					}, true),
+					validateIdentifier,
+				),
 			},
+			normalizedNameAttr: normalizedNameSchema("user"),
 			userPasswordAttr: {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -92,7 +125,13 @@ Amazon Redshift user accounts can only be created and dropped by a database supe
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     "infinity",
-				Description: "Sets a date and time after which the user's password is no longer valid. By default the password has no time limit.",
+				Description: "Sets a date and time after which the user's password is no longer valid. By default the password has no time limit. `never` is accepted as an alias of `infinity`; both are stored as `infinity`.",
+				StateFunc: func(val interface{}) string {
+					return normalizeValidUntil(val.(string))
+				},
+				DiffSuppressFunc: func(k, oldValue, newValue string, d *schema.ResourceData) bool {
+					return normalizeValidUntil(oldValue) == normalizeValidUntil(newValue)
+				},
 			},
 			userCreateDBAttr: {
 				Type:        schema.TypeBool,
@@ -131,10 +170,45 @@ Amazon Redshift user accounts can only be created and dropped by a database supe
 			userSessionTimeoutAttr: {
 				Type:         schema.TypeInt,
 				Optional:     true,
-				Default:      0,
-				Description:  "The maximum time in seconds that a session remains inactive or idle. The range is 60 seconds (one minute) to 1,728,000 seconds (20 days). If no session timeout is set for the user, the cluster setting applies.",
+				Computed:     true,
+				Description:  "The maximum time in seconds that a session remains inactive or idle. The range is 60 seconds (one minute) to 1,728,000 seconds (20 days). If no session timeout is set for the user, the cluster setting applies. When not set, the session timeout is left as is, so it can be managed by `redshift_user_limits` instead. Note that removing the attribute therefore no longer resets the session timeout of the user to the cluster setting: reset it with `ALTER USER ... RESET SESSION TIMEOUT` or manage it with `redshift_user_limits`.",
 				ValidateFunc: validation.All(validation.IntAtLeast(60), validation.IntAtMost(1728000)),
 			},
+			userExternalIDAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "The identifier of the user in the identity provider, for users authenticating through identity federation (e.g. Azure AD). Changing it updates the user, removing it recreates the user.",
+			},
+			userRevokeAllOnDestroyScopeAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  userRevokeScopeCurrentDB,
+				ValidateFunc: validation.StringInSlice([]string{
+					userRevokeScopeCurrentDB,
+					userRevokeScopeAllDBs,
+				}, false),
+				Description: "Where the objects of the user are reassigned to the provider user and the privileges of the user on tables, as well as the default privileges granted to the user by any owner, are revoked before dropping it. `current_db` (default) only cleans up the database the provider connects to. `all_dbs` also cleans up every other local database, each with its own connection and transaction using the provider credentials, so that grants in other databases don't block `DROP USER`.",
+			},
+			userEffectiveGrantsSummaryAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "A summary of the objects the user has any privilege on in the database the provider connects to, either directly, through groups and roles or to PUBLIC. System schemas are not counted. Superusers have privileges on all objects.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"schemas": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of schemas the user has the `usage` or `create` privilege on.",
+						},
+						"tables": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of tables and views the user has the `select`, `insert`, `update`, `delete` or `references` privilege on.",
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -167,6 +241,7 @@ func resourceRedshiftUserCreate(db *DBConnection, d *schema.ResourceData) error
 		{userPasswordAttr, "PASSWORD"},
 		{userValidUntilAttr, "VALID UNTIL"},
 		{userSyslogAccessAttr, "SYSLOG ACCESS"},
+		{userExternalIDAttr, "EXTERNALID"},
 	}
 
 	intOpts := []struct {
@@ -208,15 +283,10 @@ func resourceRedshiftUserCreate(db *DBConnection, d *schema.ResourceData) error
 		val := v.(string)
 		if val != "" {
 			switch {
-			case opt.hclKey == userPasswordAttr:
+			case opt.hclKey == userPasswordAttr, opt.hclKey == userExternalIDAttr:
 				createOpts = append(createOpts, fmt.Sprintf("%s '%s'", opt.sqlKey, pqQuoteLiteral(val)))
 			case opt.hclKey == userValidUntilAttr:
-				switch {
-				case v.(string) == "", strings.ToLower(v.(string)) == "infinity":
-					createOpts = append(createOpts, fmt.Sprintf("%s '%s'", opt.sqlKey, "infinity"))
-				default:
-					createOpts = append(createOpts, fmt.Sprintf("%s '%s'", opt.sqlKey, pqQuoteLiteral(val)))
-				}
+				createOpts = append(createOpts, fmt.Sprintf("%s '%s'", opt.sqlKey, pqQuoteLiteral(normalizeValidUntil(val))))
 			case opt.hclKey == userSyslogAccessAttr:
 				createOpts = append(createOpts, fmt.Sprintf("%s %s", opt.sqlKey, val))
 			default:
@@ -270,7 +340,7 @@ func resourceRedshiftUserRead(db *DBConnection, d *schema.ResourceData) error {
 }
 
 func resourceRedshiftUserReadImpl(db *DBConnection, d *schema.ResourceData) error {
-	var userName, userValidUntil, userConnLimit, userSyslogAccess, userSessionTimeout string
+	var userName, userValidUntil, userConnLimit, userSyslogAccess, userSessionTimeout, userExternalID string
 	var userSuperuser, userCreateDB bool
 
 	columns := []string{
@@ -280,6 +350,7 @@ func resourceRedshiftUserReadImpl(db *DBConnection, d *schema.ResourceData) erro
 		"syslogaccess",
 		`COALESCE(useconnlimit::TEXT, 'UNLIMITED')`,
 		"sessiontimeout",
+		"COALESCE(trim(external_user_id), '')",
 	}
 
 	values := []interface{}{
@@ -289,6 +360,7 @@ func resourceRedshiftUserReadImpl(db *DBConnection, d *schema.ResourceData) erro
 		&userSyslogAccess,
 		&userConnLimit,
 		&userSessionTimeout,
+		&userExternalID,
 	}
 
 	useSysID := d.Id()
@@ -326,12 +398,47 @@ func resourceRedshiftUserReadImpl(db *DBConnection, d *schema.ResourceData) erro
 	}
 
 	d.Set(userNameAttr, userName)
+	d.Set(normalizedNameAttr, normalizeName(db.client, userName))
 	d.Set(userCreateDBAttr, userCreateDB)
 	d.Set(userSuperuserAttr, userSuperuser)
 	d.Set(userSyslogAccessAttr, userSyslogAccess)
 	d.Set(userConnLimitAttr, userConnLimitNumber)
 	d.Set(userValidUntilAttr, userValidUntil)
 	d.Set(userSessionTimeoutAttr, userSessionTimeoutNumber)
+	d.Set(userExternalIDAttr, userExternalID)
+
+	return readUserEffectiveGrantsSummary(db, d, userName)
+}
+
+// readUserEffectiveGrantsSummary counts the schemas and tables the user has any privilege on, in a single query.
+func readUserEffectiveGrantsSummary(db *DBConnection, d *schema.ResourceData, userName string) error {
+	var schemas, tables int
+	err := db.QueryRow(`
+	SELECT
+		COUNT(DISTINCT CASE WHEN has_schema_privilege($1, nsp.nspname, 'USAGE') OR has_schema_privilege($1, nsp.nspname, 'CREATE') THEN nsp.oid END),
+		COUNT(CASE
+			WHEN has_table_privilege($1, quote_ident(nsp.nspname) || '.' || quote_ident(cl.relname), 'SELECT')
+				OR has_table_privilege($1, quote_ident(nsp.nspname) || '.' || quote_ident(cl.relname), 'INSERT')
+				OR has_table_privilege($1, quote_ident(nsp.nspname) || '.' || quote_ident(cl.relname), 'UPDATE')
+				OR has_table_privilege($1, quote_ident(nsp.nspname) || '.' || quote_ident(cl.relname), 'DELETE')
+				OR has_table_privilege($1, quote_ident(nsp.nspname) || '.' || quote_ident(cl.relname), 'REFERENCES')
+			THEN cl.oid END)
+	FROM pg_namespace nsp
+	LEFT JOIN pg_class cl ON cl.relnamespace = nsp.oid AND cl.relkind IN ('r', 'v', 'm')
+	WHERE
+		nsp.nspname NOT IN ('information_schema', 'pg_catalog', 'pg_internal', 'pg_automv', 'catalog_history')
+		AND nsp.nspname NOT LIKE 'pg_temp_%'
+`, userName).Scan(&schemas, &tables)
+	if err != nil {
+		return fmt.Errorf("Error reading user effective grants: %w", err)
+	}
+
+	d.Set(userEffectiveGrantsSummaryAttr, []map[string]interface{}{
+		{
+			"schemas": schemas,
+			"tables":  tables,
+		},
+	})
 
 	return nil
 }
@@ -341,12 +448,79 @@ func resourceRedshiftUserDelete(db *DBConnection, d *schema.ResourceData) error
 	userName := d.Get(userNameAttr).(string)
 	newOwnerName := permanentUsername(db.client.config.Username)
 
+	if d.Get(userRevokeAllOnDestroyScopeAttr).(string) == userRevokeScopeAllDBs {
+		databases, err := otherLocalDatabases(db)
+		if err != nil {
+			return err
+		}
+		for _, database := range databases {
+			log.Printf("[DEBUG] releasing the objects of user %s in database %s\n", userName, database)
+			if err := releaseUserObjectsInDatabase(db.client, database, useSysID, userName, newOwnerName); err != nil {
+				return err
+			}
+		}
+	}
+
 	tx, err := startTransaction(db.client, "")
 	if err != nil {
 		return err
 	}
 	defer deferredRollback(tx)
 
+	if err := releaseUserObjects(tx, useSysID, userName, newOwnerName); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("DROP USER %s", pq.QuoteIdentifier(userName))); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+		//return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// otherLocalDatabases returns the local databases of the cluster other than the one db is connected to.
+func otherLocalDatabases(db *DBConnection) ([]string, error) {
+	query := "SELECT database_name FROM svv_redshift_databases WHERE database_type = 'local' AND database_name NOT IN ($1, 'padb_harvest') ORDER BY 1"
+	log.Printf("[DEBUG] %s\n", query)
+	rows, err := db.Query(query, db.client.databaseName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var databases []string
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return nil, err
+		}
+		databases = append(databases, database)
+	}
+	return databases, rows.Err()
+}
+
+// releaseUserObjectsInDatabase runs releaseUserObjects in its own transaction on database.
+func releaseUserObjectsInDatabase(client *Client, database, useSysID, userName, newOwnerName string) error {
+	tx, err := startTransaction(client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	if err := releaseUserObjects(tx, useSysID, userName, newOwnerName); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// releaseUserObjects reassigns the objects owned by the user to newOwnerName and revokes the privileges
+// of the user on tables, as well as the default privileges granted to the user, in the database of tx, so that the user can be dropped.
+func releaseUserObjects(tx *sql.Tx, useSysID, userName, newOwnerName string) error {
 	// Based on https://github.com/awslabs/amazon-redshift-utils/blob/master/src/AdminViews/v_find_dropuser_objs.sql
 	var reassignOwnerGenerator = `SELECT owner.ddl
 			FROM (
@@ -430,18 +604,78 @@ func resourceRedshiftUserDelete(db *DBConnection, d *schema.ResourceData) error
 
 	}
 
-	if _, err := tx.Exec(fmt.Sprintf("DROP USER %s", pq.QuoteIdentifier(userName))); err != nil {
+	return revokeUserDefaultPrivileges(tx, userName)
+}
+
+// userDefaultACL is a pg_default_acl entry granting default privileges to a user.
+type userDefaultACL struct {
+	owner      string
+	schema     string
+	objectType string
+}
+
+// revokeUserDefaultPrivileges revokes the default privileges granted to the user by any owner,
+// in any schema or globally and for any object type, in the database of tx.
+func revokeUserDefaultPrivileges(tx *sql.Tx, userName string) error {
+	// The LIKE only narrows down the entries, the grantee is matched by parseDefaultACLPrivileges.
+	rows, err := tx.Query(`
+		SELECT u.usename, COALESCE(n.nspname, ''), acl.defaclobjtype, array_to_string(acl.defaclacl, '|')
+		FROM pg_default_acl acl
+		JOIN pg_user u ON u.usesysid = acl.defacluser
+		LEFT JOIN pg_namespace n ON n.oid = acl.defaclnamespace
+		WHERE array_to_string(acl.defaclacl, '|') LIKE '%' || $1 || '%'`, userName)
+	if err != nil {
 		return err
 	}
+	defer rows.Close()
 
-	if err := tx.Commit(); err != nil {
+	var entries []userDefaultACL
+	for rows.Next() {
+		var entry userDefaultACL
+		var acl string
+		if err := rows.Scan(&entry.owner, &entry.schema, &entry.objectType, &acl); err != nil {
+			return err
+		}
+		if len(parseDefaultACLPrivileges(acl, userName, true)) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	if err := rows.Err(); err != nil {
 		return err
-		//return fmt.Errorf("could not commit transaction: %w", err)
 	}
 
+	for _, entry := range entries {
+		query, err := revokeUserDefaultACLQuery(entry, userName)
+		if err != nil {
+			return err
+		}
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// revokeUserDefaultACLQuery returns the statement revoking the default privileges of the entry from the user.
+func revokeUserDefaultACLQuery(entry userDefaultACL, userName string) (string, error) {
+	objectType := ""
+	for name, code := range defaultPrivilegesObjectTypesCodes {
+		if code == entry.objectType {
+			objectType = strings.ToUpper(name) + "S"
+		}
+	}
+	if objectType == "" {
+		return "", fmt.Errorf("unsupported object type %q of the default privileges of user %s granted by %s", entry.objectType, userName, entry.owner)
+	}
+
+	query := fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER %s", pq.QuoteIdentifier(entry.owner))
+	if entry.schema != "" {
+		query = fmt.Sprintf("%s IN SCHEMA %s", query, pq.QuoteIdentifier(entry.schema))
+	}
+	return fmt.Sprintf("%s REVOKE ALL ON %s FROM %s", query, objectType, pq.QuoteIdentifier(userName)), nil
+}
+
 func resourceRedshiftUserUpdate(db *DBConnection, d *schema.ResourceData) error {
 	tx, err := startTransaction(db.client, "")
 	if err != nil {
@@ -449,7 +683,8 @@ func resourceRedshiftUserUpdate(db *DBConnection, d *schema.ResourceData) error
 	}
 	defer deferredRollback(tx)
 
-	if err := setUserName(tx, d); err != nil {
+	resolver := catalog.NewResolver(tx)
+	if err := setUserName(tx, resolver, d); err != nil {
 		return err
 	}
 
@@ -480,6 +715,10 @@ func resourceRedshiftUserUpdate(db *DBConnection, d *schema.ResourceData) error
 		return err
 	}
 
+	if err := setUserExternalID(tx, d); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("could not commit transaction: %w", err)
 	}
@@ -487,7 +726,8 @@ func resourceRedshiftUserUpdate(db *DBConnection, d *schema.ResourceData) error
 	return resourceRedshiftUserReadImpl(db, d)
 }
 
-func setUserName(tx *sql.Tx, d *schema.ResourceData) error {
+// setUserName renames the user and keeps the resolver of the transaction in sync with the new name.
+func setUserName(tx *sql.Tx, resolver *catalog.Resolver, d *schema.ResourceData) error {
 	if !d.HasChange(userNameAttr) {
 		return nil
 	}
@@ -504,6 +744,7 @@ func setUserName(tx *sql.Tx, d *schema.ResourceData) error {
 	if _, err := tx.Exec(sql); err != nil {
 		return fmt.Errorf("Error updating User NAME: %w", err)
 	}
+	resolver.Rename(catalog.User, oldValue, newValue)
 
 	return nil
 }
@@ -563,6 +804,25 @@ func setUserSessionTimeout(tx *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
+func setUserExternalID(tx *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(userExternalIDAttr) {
+		return nil
+	}
+
+	externalID := d.Get(userExternalIDAttr).(string)
+	if externalID == "" {
+		return nil
+	}
+
+	userName := d.Get(userNameAttr).(string)
+	sql := fmt.Sprintf("ALTER USER %s EXTERNALID '%s'", pq.QuoteIdentifier(userName), pqQuoteLiteral(externalID))
+	if _, err := tx.Exec(sql); err != nil {
+		return fmt.Errorf("Error updating user EXTERNALID: %w", err)
+	}
+
+	return nil
+}
+
 func setUserCreateDB(tx *sql.Tx, d *schema.ResourceData) error {
 	if !d.HasChange(userCreateDBAttr) {
 		return nil
@@ -609,9 +869,8 @@ func setUserValidUntil(tx *sql.Tx, d *schema.ResourceData) error {
 	validUntil := d.Get(userValidUntilAttr).(string)
 	if validUntil == "" {
 		return nil
-	} else if strings.ToLower(validUntil) == "infinity" {
-		validUntil = "infinity"
 	}
+	validUntil = normalizeValidUntil(validUntil)
 
 	userName := d.Get(userNameAttr).(string)
 	sql := fmt.Sprintf("ALTER USER %s VALID UNTIL '%s'", pq.QuoteIdentifier(userName), pqQuoteLiteral(validUntil))
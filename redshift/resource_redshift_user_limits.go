@@ -0,0 +1,309 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/brainly/terraform-provider-redshift/redshift/internal/catalog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	userLimitsUsersAttr            = "users"
+	userLimitsStatementTimeoutAttr = "statement_timeout"
+	userLimitsSessionTimeoutAttr   = "session_timeout"
+
+	statementTimeoutConfigPrefix = "statement_timeout="
+)
+
+func redshiftUserLimits() *schema.Resource {
+	return &schema.Resource{
+		Description: `
+Applies the same limits to a list of users, e.g. to enforce a maximum query runtime for all analysts in one place instead of in every ` + "`redshift_user`" + ` resource.
+
+The resource is authoritative for the limits it manages: when any of the users has a different value, e.g. because it was altered outside of Terraform, the next apply sets it back. Limits which are not set are not managed.
+
+Note: Do not set ` + "`session_timeout`" + ` in ` + "`redshift_user`" + ` resources of the same users, as both resources would override each other.
+
+The ID is the comma separated list of the sorted user names. When imported, the limits are read from the first of the users.
+`,
+		Create: RedshiftResourceFunc(resourceRedshiftUserLimitsCreate),
+		Read:   RedshiftResourceReadFunc(resourceRedshiftUserLimitsRead),
+		Update: RedshiftResourceFunc(resourceRedshiftUserLimitsUpdate),
+		Delete: RedshiftResourceFunc(resourceRedshiftUserLimitsDelete),
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			userLimitsUsersAttr: {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "The names of the users the limits are applied to.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.All(validation.StringIsNotWhiteSpace, validateIdentifier),
+				},
+				Set: hashCaseInsensitiveString,
+			},
+			userLimitsStatementTimeoutAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				AtLeastOneOf: []string{userLimitsStatementTimeoutAttr, userLimitsSessionTimeoutAttr},
+				Description:  "The maximum time in milliseconds a statement of the users can run before it is canceled. When removed, the setting of the users is reset to the cluster setting.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			userLimitsSessionTimeoutAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				AtLeastOneOf: []string{userLimitsStatementTimeoutAttr, userLimitsSessionTimeoutAttr},
+				Description:  "The maximum time in seconds that a session of the users remains inactive or idle. The range is 60 seconds (one minute) to 1,728,000 seconds (20 days). When removed, the setting of the users is reset to the cluster setting.",
+				ValidateFunc: validation.All(validation.IntAtLeast(60), validation.IntAtMost(1728000)),
+			},
+		},
+	}
+}
+
+// userLimits are the limits of a single user as observed in the catalog, zero when not set.
+type userLimits struct {
+	statementTimeout int
+	sessionTimeout   int
+}
+
+func resourceRedshiftUserLimitsCreate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	for _, userName := range userLimitsUsers(db, d.Get(userLimitsUsersAttr).(*schema.Set)) {
+		if err := setUserLimits(tx, userName, d.Get(userLimitsStatementTimeoutAttr).(int), d.Get(userLimitsSessionTimeoutAttr).(int)); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateUserLimitsID(db, d))
+
+	return resourceRedshiftUserLimitsRead(db, d)
+}
+
+func resourceRedshiftUserLimitsRead(db *DBConnection, d *schema.ResourceData) error {
+	users := userLimitsUsers(db, d.Get(userLimitsUsersAttr).(*schema.Set))
+	importing := len(users) == 0
+	if importing {
+		users = strings.Split(d.Id(), ",")
+	}
+
+	observed, err := readUserLimits(db, users)
+	if err != nil {
+		return err
+	}
+
+	existing := []string{}
+	for _, userName := range users {
+		if _, ok := observed[userName]; !ok {
+			log.Printf("[WARN] Redshift User (%s) not found", userName)
+			continue
+		}
+		existing = append(existing, userName)
+	}
+	if len(existing) == 0 {
+		log.Printf("[WARN] None of the users of Redshift user limits (%s) exist", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	// Imported limits are the ones of the first user. Limits which are not set are not managed.
+	if importing {
+		d.Set(userLimitsStatementTimeoutAttr, observed[existing[0]].statementTimeout)
+		d.Set(userLimitsSessionTimeoutAttr, observed[existing[0]].sessionTimeout)
+	}
+
+	// A single user with a different value is enough to make the plan apply the limits to all users again.
+	for _, userName := range existing {
+		limits := observed[userName]
+		if statementTimeout := d.Get(userLimitsStatementTimeoutAttr).(int); statementTimeout != 0 && limits.statementTimeout != statementTimeout {
+			log.Printf("[DEBUG] statement_timeout of user %s is %d, expected %d", userName, limits.statementTimeout, statementTimeout)
+			d.Set(userLimitsStatementTimeoutAttr, limits.statementTimeout)
+		}
+		if sessionTimeout := d.Get(userLimitsSessionTimeoutAttr).(int); sessionTimeout != 0 && limits.sessionTimeout != sessionTimeout {
+			log.Printf("[DEBUG] session_timeout of user %s is %d, expected %d", userName, limits.sessionTimeout, sessionTimeout)
+			d.Set(userLimitsSessionTimeoutAttr, limits.sessionTimeout)
+		}
+	}
+
+	d.Set(userLimitsUsersAttr, existing)
+
+	return nil
+}
+
+func resourceRedshiftUserLimitsUpdate(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	oldUsersSet, newUsersSet := d.GetChange(userLimitsUsersAttr)
+	oldStatementTimeout, _ := d.GetChange(userLimitsStatementTimeoutAttr)
+	oldSessionTimeout, _ := d.GetChange(userLimitsSessionTimeoutAttr)
+
+	// Users removed from the list get back the cluster settings for the limits which were managed.
+	for _, userName := range userLimitsUsers(db, oldUsersSet.(*schema.Set).Difference(newUsersSet.(*schema.Set))) {
+		if err := resetUserLimits(tx, userName, oldStatementTimeout.(int) != 0, oldSessionTimeout.(int) != 0); err != nil {
+			return err
+		}
+	}
+
+	statementTimeout := d.Get(userLimitsStatementTimeoutAttr).(int)
+	sessionTimeout := d.Get(userLimitsSessionTimeoutAttr).(int)
+	for _, userName := range userLimitsUsers(db, newUsersSet.(*schema.Set)) {
+		if err := resetUserLimits(tx, userName, statementTimeout == 0 && oldStatementTimeout.(int) != 0, sessionTimeout == 0 && oldSessionTimeout.(int) != 0); err != nil {
+			return err
+		}
+		if err := setUserLimits(tx, userName, statementTimeout, sessionTimeout); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateUserLimitsID(db, d))
+
+	return resourceRedshiftUserLimitsRead(db, d)
+}
+
+func resourceRedshiftUserLimitsDelete(db *DBConnection, d *schema.ResourceData) error {
+	tx, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(tx)
+
+	users := userLimitsUsers(db, d.Get(userLimitsUsersAttr).(*schema.Set))
+	observed, err := readUserLimits(tx, users)
+	if err != nil {
+		return err
+	}
+
+	for _, userName := range users {
+		if _, ok := observed[userName]; !ok {
+			log.Printf("[WARN] Redshift User (%s) not found", userName)
+			continue
+		}
+		if err := resetUserLimits(tx, userName, d.Get(userLimitsStatementTimeoutAttr).(int) != 0, d.Get(userLimitsSessionTimeoutAttr).(int) != 0); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// userLimitsUsers returns the normalized, sorted user names of the set.
+func userLimitsUsers(db *DBConnection, users *schema.Set) []string {
+	result := []string{}
+	for _, userName := range users.List() {
+		result = append(result, db.client.normalizeIdentifier(userName.(string)))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// generateUserLimitsID returns the comma separated list of the normalized, sorted user names.
+func generateUserLimitsID(db *DBConnection, d *schema.ResourceData) string {
+	return strings.Join(userLimitsUsers(db, d.Get(userLimitsUsersAttr).(*schema.Set)), ",")
+}
+
+// readUserLimits returns the limits of the users by name. Users which don't exist are left out.
+func readUserLimits(q catalog.Querier, users []string) (map[string]userLimits, error) {
+	query := `
+SELECT
+  COALESCE(array_to_string(p.useconfig, '|'), ''),
+  s.sessiontimeout
+FROM pg_user p
+JOIN svl_user_info s ON s.usesysid = p.usesysid
+WHERE p.usename = $1
+`
+	result := map[string]userLimits{}
+	for _, userName := range users {
+		var userConfig, sessionTimeout string
+		err := q.QueryRow(query, userName).Scan(&userConfig, &sessionTimeout)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error reading limits of user %s: %w", userName, err)
+		}
+
+		var limits userLimits
+		if limits.statementTimeout, err = parseUserStatementTimeout(userConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse statement_timeout of user %s: %w", userName, err)
+		}
+		if limits.sessionTimeout, err = strconv.Atoi(sessionTimeout); err != nil {
+			return nil, fmt.Errorf("failed to parse session timeout of user %s: %w", userName, err)
+		}
+		result[userName] = limits
+	}
+
+	return result, nil
+}
+
+func setUserLimits(tx *sql.Tx, userName string, statementTimeout, sessionTimeout int) error {
+	queries := []string{}
+	if statementTimeout != 0 {
+		queries = append(queries, fmt.Sprintf("ALTER USER %s SET statement_timeout TO %d", pq.QuoteIdentifier(userName), statementTimeout))
+	}
+	if sessionTimeout != 0 {
+		queries = append(queries, fmt.Sprintf("ALTER USER %s SESSION TIMEOUT %d", pq.QuoteIdentifier(userName), sessionTimeout))
+	}
+
+	for _, query := range queries {
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("Error updating limits of user %s: %w", userName, err)
+		}
+	}
+	return nil
+}
+
+func resetUserLimits(tx *sql.Tx, userName string, statementTimeout, sessionTimeout bool) error {
+	queries := []string{}
+	if statementTimeout {
+		queries = append(queries, fmt.Sprintf("ALTER USER %s RESET statement_timeout", pq.QuoteIdentifier(userName)))
+	}
+	if sessionTimeout {
+		queries = append(queries, fmt.Sprintf("ALTER USER %s RESET SESSION TIMEOUT", pq.QuoteIdentifier(userName)))
+	}
+
+	for _, query := range queries {
+		log.Printf("[DEBUG] %s\n", query)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("Error resetting limits of user %s: %w", userName, err)
+		}
+	}
+	return nil
+}
+
+// parseUserStatementTimeout extracts the statement timeout in milliseconds from the user config,
+// stored as `|` separated "name=value" entries, e.g. `statement_timeout=1800000`. It returns 0 when not set.
+func parseUserStatementTimeout(userConfig string) (int, error) {
+	for _, entry := range strings.Split(userConfig, "|") {
+		if strings.HasPrefix(entry, statementTimeoutConfigPrefix) {
+			return strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(entry, statementTimeoutConfigPrefix)))
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,114 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
+)
+
+func TestAccRedshiftUserLimits_Basic(t *testing.T) {
+	userNames := []string{
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user"), "-", "_"),
+		strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user"), "-", "_"),
+	}
+	config := func(users string, limits string) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "first" {
+	name = %[1]q
+}
+
+resource "redshift_user" "second" {
+	name = %[2]q
+}
+
+resource "redshift_user_limits" "limits" {
+	users = %[3]s
+	%[4]s
+}
+`, userNames[0], userNames[1], users, limits)
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: func(s *terraform.State) error { return nil },
+		Steps: []resource.TestStep{
+			{
+				Config: config(`[redshift_user.first.name, redshift_user.second.name]`, "statement_timeout = 1800000\nsession_timeout = 3600"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_user_limits.limits", "users.#", "2"),
+					resource.TestCheckResourceAttr("redshift_user_limits.limits", "statement_timeout", "1800000"),
+					resource.TestCheckResourceAttr("redshift_user_limits.limits", "session_timeout", "3600"),
+				),
+			},
+			{
+				// Drift of a single user is repaired by the next apply.
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*Client)
+					conn, err := client.Connect()
+					if err != nil {
+						t.Fatalf("couldn't connect to database: %v", err)
+					}
+					if _, err := conn.Exec(fmt.Sprintf("ALTER USER %s SET statement_timeout TO 1000", pq.QuoteIdentifier(userNames[1]))); err != nil {
+						t.Fatalf("couldn't alter user: %v", err)
+					}
+				},
+				Config: config(`[redshift_user.first.name, redshift_user.second.name]`, "statement_timeout = 1800000\nsession_timeout = 3600"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_user_limits.limits", "statement_timeout", "1800000"),
+				),
+			},
+			{
+				Config: config(`[redshift_user.first.name]`, "statement_timeout = 60000"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_user_limits.limits", "users.#", "1"),
+					resource.TestCheckResourceAttr("redshift_user_limits.limits", "statement_timeout", "60000"),
+					resource.TestCheckResourceAttr("redshift_user_limits.limits", "session_timeout", "0"),
+					resource.TestCheckResourceAttr("redshift_user_limits.limits", "id", userNames[0]),
+				),
+			},
+			{
+				ResourceName:      "redshift_user_limits.limits",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestParseUserStatementTimeout(t *testing.T) {
+	cases := map[string]struct {
+		config   string
+		expected int
+	}{
+		"empty": {
+			config:   "",
+			expected: 0,
+		},
+		"statement timeout only": {
+			config:   "statement_timeout=1800000",
+			expected: 1800000,
+		},
+		"other settings": {
+			config:   `search_path="$user", public|statement_timeout=1000`,
+			expected: 1000,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseUserStatementTimeout(c.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != c.expected {
+				t.Errorf("expected %d, got %d", c.expected, actual)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
 func TestAccRedshiftUser_Basic(t *testing.T) {
@@ -43,6 +44,9 @@ func TestAccRedshiftUser_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("redshift_user.user_with_defaults", "valid_until", "infinity"),
 					resource.TestCheckResourceAttr("redshift_user.user_with_defaults", "syslog_access", "RESTRICTED"),
 					resource.TestCheckResourceAttr("redshift_user.user_with_defaults", "session_timeout", "0"),
+					resource.TestCheckResourceAttr("redshift_user.user_with_defaults", "effective_grants_summary.#", "1"),
+					resource.TestCheckResourceAttrSet("redshift_user.user_with_defaults", "effective_grants_summary.0.schemas"),
+					resource.TestCheckResourceAttrSet("redshift_user.user_with_defaults", "effective_grants_summary.0.tables"),
 
 					testAccCheckRedshiftUserExists("user_create_database"),
 					resource.TestCheckResourceAttr("redshift_user.user_with_create_database", "name", "user_create_database"),
@@ -65,6 +69,192 @@ func TestAccRedshiftUser_Basic(t *testing.T) {
 	})
 }
 
+func TestAccRedshiftUser_ExternalID(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_federated_user"), "-", "_")
+	config := func(externalID string) string {
+		return fmt.Sprintf(`
+resource "redshift_user" "federated" {
+  name        = %[1]q
+  external_id = %[2]q
+}
+`, userName, externalID)
+	}
+	var userID string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("ABC123"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftUserExists(userName),
+					resource.TestCheckResourceAttr("redshift_user.federated", "external_id", "ABC123"),
+					func(s *terraform.State) error {
+						userID = s.RootModule().Resources["redshift_user.federated"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				// Changing the external ID updates the user in place.
+				Config: config("DEF456"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redshift_user.federated", "external_id", "DEF456"),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["redshift_user.federated"].Primary.ID; id != userID {
+							return fmt.Errorf("Expected user %s to be updated in place, got new user %s", userID, id)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// The external ID is changed outside of terraform.
+				PreConfig: func() {
+					conn, err := testAccProvider.Meta().(*Client).Connect()
+					if err != nil {
+						t.Fatalf("couldn't start redshift connection: %s", err)
+					}
+					if _, err := conn.Exec(fmt.Sprintf("ALTER USER %s EXTERNALID 'XYZ789'", userName)); err != nil {
+						t.Fatalf("couldn't alter user: %s", err)
+					}
+				},
+				Config:             config("DEF456"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccRedshiftUser_RevokeAllOnDestroyScope(t *testing.T) {
+	userName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_revoke_all_dbs"), "-", "_")
+	dbName := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_revoke_all_dbs"), "-", "_")
+	databaseConfig := fmt.Sprintf(`
+resource "redshift_database" "other" {
+  name = %[1]q
+}
+`, dbName)
+	userConfig := databaseConfig + fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name                        = %[1]q
+  revoke_all_on_destroy_scope = "all_dbs"
+
+  depends_on = [redshift_database.other]
+}
+`, userName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: userConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRedshiftUserExists(userName),
+					resource.TestCheckResourceAttr("redshift_user.user", userRevokeAllOnDestroyScopeAttr, "all_dbs"),
+				),
+			},
+			{
+				// The user is granted privileges in another database outside of terraform, which blocks DROP USER
+				// unless they are revoked in all databases.
+				PreConfig: func() {
+					conn, err := testAccProvider.Meta().(*Client).config.NewClient(dbName).Connect()
+					if err != nil {
+						t.Fatalf("couldn't start redshift connection: %s", err)
+					}
+					statements := []string{
+						fmt.Sprintf("CREATE TABLE public.%s (id INT)", pq.QuoteIdentifier(userName)),
+						fmt.Sprintf("GRANT SELECT ON public.%s TO %s", pq.QuoteIdentifier(userName), pq.QuoteIdentifier(userName)),
+					}
+					for _, statement := range statements {
+						if _, err := conn.Exec(statement); err != nil {
+							t.Fatalf("couldn't run %q: %s", statement, err)
+						}
+					}
+				},
+				Config: databaseConfig,
+				Check: func(s *terraform.State) error {
+					exists, err := checkUserExists(testAccProvider.Meta().(*Client), userName)
+					if err != nil {
+						return err
+					}
+					if exists {
+						return fmt.Errorf("User %s still exists after destroy", userName)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccRedshiftUser_DefaultPrivilegesFromOtherOwners(t *testing.T) {
+	prefix := strings.ReplaceAll(acctest.RandomWithPrefix("tf_acc_user_defaults"), "-", "_")
+	userName := prefix + "_grantee"
+	ownersConfig := fmt.Sprintf(`
+resource "redshift_user" "owner_1" {
+  name = "%[1]s_owner_1"
+}
+
+resource "redshift_user" "owner_2" {
+  name = "%[1]s_owner_2"
+}
+
+resource "redshift_schema" "schema" {
+  name = %[1]q
+}
+`, prefix)
+	userConfig := ownersConfig + fmt.Sprintf(`
+resource "redshift_user" "user" {
+  name = %[1]q
+}
+`, userName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckRedshiftUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: userConfig,
+				Check:  testAccCheckRedshiftUserExists(userName),
+			},
+			{
+				// Default privileges are granted to the user by other owners outside of terraform,
+				// in a schema and globally, which blocks DROP USER unless they are revoked.
+				PreConfig: func() {
+					conn, err := testAccProvider.Meta().(*Client).Connect()
+					if err != nil {
+						t.Fatalf("couldn't start redshift connection: %s", err)
+					}
+					statements := []string{
+						fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER %s IN SCHEMA %s GRANT SELECT ON TABLES TO %s", pq.QuoteIdentifier(prefix+"_owner_1"), pq.QuoteIdentifier(prefix), pq.QuoteIdentifier(userName)),
+						fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER %s GRANT EXECUTE ON FUNCTIONS TO %s", pq.QuoteIdentifier(prefix+"_owner_2"), pq.QuoteIdentifier(userName)),
+						fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR USER %s GRANT INSERT ON TABLES TO %s", pq.QuoteIdentifier(prefix+"_owner_2"), pq.QuoteIdentifier(userName)),
+					}
+					for _, statement := range statements {
+						if _, err := conn.Exec(statement); err != nil {
+							t.Fatalf("couldn't run %q: %s", statement, err)
+						}
+					}
+				},
+				Config: ownersConfig,
+				Check: func(s *terraform.State) error {
+					exists, err := checkUserExists(testAccProvider.Meta().(*Client), userName)
+					if err != nil {
+						return err
+					}
+					if exists {
+						return fmt.Errorf("User %s still exists after destroy", userName)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
 func TestAccRedshiftUser_Update(t *testing.T) {
 
 	var configCreate = `
@@ -80,6 +270,7 @@ resource "redshift_user" "update_user" {
   name = "update_user2"
   connection_limit = 5
   password = "Foobarbaz5"
+  valid_until = "never"
   syslog_access = "UNRESTRICTED"
   create_database = true
 }
@@ -481,6 +672,60 @@ func TestPermanentUsername(t *testing.T) {
 	if result := permanentUsername(fmt.Sprintf("IAMA:%s", expected)); result != expected {
 		t.Fatalf("permanentUsername should strip \"IAMA:\" prefix. Expected %s but was %s", expected, result)
 	}
+	if result := permanentUsername(fmt.Sprintf("IAMR:%s", expected)); result != expected {
+		t.Fatalf("permanentUsername should strip \"IAMR:\" prefix. Expected %s but was %s", expected, result)
+	}
+}
+
+func TestNormalizeValidUntil(t *testing.T) {
+	for input, expected := range map[string]string{
+		"":                    "infinity",
+		"infinity":            "infinity",
+		"Infinity":            "infinity",
+		"never":               "infinity",
+		"NEVER":               "infinity",
+		"2030-01-01 00:00:00": "2030-01-01 00:00:00",
+	} {
+		if result := normalizeValidUntil(input); result != expected {
+			t.Errorf("normalizeValidUntil(%q): expected %q but was %q", input, expected, result)
+		}
+	}
+}
+
+func TestRevokeUserDefaultACLQuery(t *testing.T) {
+	tests := map[string]struct {
+		entry    userDefaultACL
+		expected string
+	}{
+		"tables in schema": {
+			entry:    userDefaultACL{owner: "etl", schema: "analytics", objectType: "r"},
+			expected: `ALTER DEFAULT PRIVILEGES FOR USER "etl" IN SCHEMA "analytics" REVOKE ALL ON TABLES FROM "john"`,
+		},
+		"global functions": {
+			entry:    userDefaultACL{owner: "dbt", objectType: "f"},
+			expected: `ALTER DEFAULT PRIVILEGES FOR USER "dbt" REVOKE ALL ON FUNCTIONS FROM "john"`,
+		},
+		"procedures": {
+			entry:    userDefaultACL{owner: "dbt", schema: "public", objectType: "p"},
+			expected: `ALTER DEFAULT PRIVILEGES FOR USER "dbt" IN SCHEMA "public" REVOKE ALL ON PROCEDURES FROM "john"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			query, err := revokeUserDefaultACLQuery(tt.entry, "john")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, query)
+			}
+		})
+	}
+
+	if _, err := revokeUserDefaultACLQuery(userDefaultACL{owner: "dbt", objectType: "S"}, "john"); err == nil {
+		t.Error("Expected an error for an unsupported object type")
+	}
 }
 
 func testAccCheckRedshiftUserCanLogin(user string, password string) resource.TestCheckFunc {
@@ -0,0 +1,144 @@
+package redshift
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+//go:generate go run ./internal/schemametadata -output schema_metadata.json
+
+// schemaMetadata is the manifest generated by GenerateSchemaMetadata, for policy tooling such as OPA or Sentinel.
+//
+//go:embed schema_metadata.json
+var schemaMetadata []byte
+
+// resourceSQLVerbs lists the SQL statements each resource may run, by their leading verb.
+// Statements nested in other ones, e.g. GRANT in ALTER DEFAULT PRIVILEGES, are reported by the outer verb.
+var resourceSQLVerbs = map[string][]string{
+	"redshift_user":                      {"CREATE", "ALTER", "DROP", "REVOKE"},
+	"redshift_group":                     {"CREATE", "ALTER", "DROP", "REVOKE"},
+	"redshift_role":                      {"CREATE", "ALTER", "DROP", "GRANT", "REVOKE"},
+	"redshift_role_grant":                {"GRANT", "REVOKE"},
+	"redshift_rls_policy":                {"CREATE", "ALTER", "DROP"},
+	"redshift_rls_policy_attachment":     {"ATTACH", "DETACH"},
+	"redshift_masking_policy":            {"CREATE", "ALTER", "DROP"},
+	"redshift_masking_policy_attachment": {"ATTACH", "DETACH"},
+	"redshift_identity_provider":         {"CREATE", "ALTER", "DROP"},
+	"redshift_table_attributes":          {"ALTER"},
+	"redshift_comment":                   {"COMMENT"},
+	"redshift_group_membership":          {"ALTER"},
+	"redshift_schema":                    {"CREATE", "ALTER", "DROP"},
+	"redshift_schema_admin_group":        {"ALTER", "GRANT", "REVOKE"},
+	"redshift_default_privileges":        {"CREATE", "ALTER"},
+	"redshift_grant":                     {"GRANT", "REVOKE"},
+	"redshift_grant_all_schemas":         {"GRANT", "REVOKE"},
+	"redshift_database":                  {"CREATE", "ALTER", "DROP"},
+	"redshift_database_user_mapping":     {"ALTER"},
+	"redshift_user_limits":               {"ALTER"},
+	"redshift_table":                     {"CREATE", "ALTER", "DROP", "COMMENT"},
+	"redshift_procedure":                 {"CREATE", "ALTER", "DROP"},
+	"redshift_datashare":                 {"CREATE", "ALTER", "DROP", "GRANT", "REVOKE"},
+	"redshift_datashare_privilege":       {"GRANT", "REVOKE"},
+	"redshift_datashare_consumer_access": {"GRANT", "REVOKE"},
+	"redshift_prepared_onboarding":       {"CREATE", "ALTER", "DROP", "GRANT", "REVOKE"},
+}
+
+// SchemaMetadata describes the resources and data sources of the provider.
+type SchemaMetadata struct {
+	Resources   map[string]ResourceMetadata `json:"resources"`
+	DataSources map[string]ResourceMetadata `json:"data_sources"`
+}
+
+// ResourceMetadata describes a resource or data source, and the SQL statements it may run, by their leading verb.
+// Data sources only run queries, so they have no SQL verbs.
+type ResourceMetadata struct {
+	SQLVerbs   []string                     `json:"sql_verbs"`
+	Attributes map[string]AttributeMetadata `json:"attributes"`
+}
+
+// AttributeMetadata describes an attribute. Changing a ForceNew attribute drops and recreates the object.
+type AttributeMetadata struct {
+	Type      string                       `json:"type"`
+	Required  bool                         `json:"required,omitempty"`
+	Optional  bool                         `json:"optional,omitempty"`
+	Computed  bool                         `json:"computed,omitempty"`
+	ForceNew  bool                         `json:"force_new,omitempty"`
+	Sensitive bool                         `json:"sensitive,omitempty"`
+	Block     map[string]AttributeMetadata `json:"block,omitempty"`
+}
+
+// ProviderSchemaMetadata returns the JSON manifest of the resources and data sources of the provider, embedded at build time.
+func ProviderSchemaMetadata() []byte {
+	return schemaMetadata
+}
+
+// GenerateSchemaMetadata builds the JSON manifest of the resources and data sources of the provider.
+func GenerateSchemaMetadata() ([]byte, error) {
+	p := Provider()
+	metadata := SchemaMetadata{
+		Resources:   map[string]ResourceMetadata{},
+		DataSources: map[string]ResourceMetadata{},
+	}
+	for name, r := range p.ResourcesMap {
+		verbs := append([]string{}, resourceSQLVerbs[name]...)
+		sort.Strings(verbs)
+		metadata.Resources[name] = ResourceMetadata{
+			SQLVerbs:   verbs,
+			Attributes: attributesMetadata(r.Schema),
+		}
+	}
+	for name, r := range p.DataSourcesMap {
+		metadata.DataSources[name] = ResourceMetadata{
+			SQLVerbs:   []string{},
+			Attributes: attributesMetadata(r.Schema),
+		}
+	}
+
+	raw, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(raw, '\n'), nil
+}
+
+func attributesMetadata(attributes map[string]*schema.Schema) map[string]AttributeMetadata {
+	metadata := map[string]AttributeMetadata{}
+	for name, s := range attributes {
+		attribute := AttributeMetadata{
+			Type:      attributeType(s),
+			Required:  s.Required,
+			Optional:  s.Optional,
+			Computed:  s.Computed,
+			ForceNew:  s.ForceNew,
+			Sensitive: s.Sensitive,
+		}
+		if block, ok := s.Elem.(*schema.Resource); ok {
+			attribute.Block = attributesMetadata(block.Schema)
+		}
+		metadata[name] = attribute
+	}
+	return metadata
+}
+
+// attributeType returns the type of the attribute as in Terraform, e.g. "set of string".
+func attributeType(s *schema.Schema) string {
+	name := map[schema.ValueType]string{
+		schema.TypeBool:   "bool",
+		schema.TypeInt:    "number",
+		schema.TypeFloat:  "number",
+		schema.TypeString: "string",
+		schema.TypeList:   "list",
+		schema.TypeSet:    "set",
+		schema.TypeMap:    "map",
+	}[s.Type]
+	switch elem := s.Elem.(type) {
+	case *schema.Schema:
+		return name + " of " + attributeType(elem)
+	case *schema.Resource:
+		return name + " of object"
+	}
+	return name
+}
@@ -0,0 +1,39 @@
+package redshift
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSchemaMetadataUpToDate(t *testing.T) {
+	generated, err := GenerateSchemaMetadata()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(generated, ProviderSchemaMetadata()) {
+		t.Error("schema_metadata.json is out of date, run `go generate ./redshift`")
+	}
+}
+
+func TestResourceSQLVerbs(t *testing.T) {
+	allowed := map[string]bool{
+		"GRANT": true, "REVOKE": true, "CREATE": true, "DROP": true, "ALTER": true,
+		"ATTACH": true, "DETACH": true, "COMMENT": true,
+	}
+	for name := range Provider().ResourcesMap {
+		verbs, ok := resourceSQLVerbs[name]
+		if !ok || len(verbs) == 0 {
+			t.Errorf("no SQL verbs declared for resource %s", name)
+		}
+		for _, verb := range verbs {
+			if !allowed[verb] {
+				t.Errorf("unexpected SQL verb %q for resource %s", verb, name)
+			}
+		}
+	}
+	for name := range resourceSQLVerbs {
+		if _, ok := Provider().ResourcesMap[name]; !ok {
+			t.Errorf("SQL verbs declared for unknown resource %s", name)
+		}
+	}
+}
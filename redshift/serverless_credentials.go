@@ -0,0 +1,120 @@
+package redshift
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// The APIs of Redshift Serverless are called directly,
+// as the version of the AWS SDK used by the provider doesn't include a Redshift Serverless client.
+// See https://docs.aws.amazon.com/redshift-serverless/latest/APIReference/Welcome.html
+const (
+	serverlessSigningName    = "redshift-serverless"
+	serverlessCredentialsAPI = "RedshiftServerless.GetCredentials"
+	serverlessWorkgroupAPI   = "RedshiftServerless.GetWorkgroup"
+)
+
+type getServerlessCredentialsInput struct {
+	WorkgroupName   string `json:"workgroupName"`
+	DbName          string `json:"dbName,omitempty"`
+	DurationSeconds int32  `json:"durationSeconds,omitempty"`
+}
+
+type getServerlessCredentialsOutput struct {
+	DbUser     string `json:"dbUser"`
+	DbPassword string `json:"dbPassword"`
+}
+
+type serverlessAPIError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// serverlessEndpoint returns the regional endpoint of the Redshift Serverless API.
+func serverlessEndpoint(region string) string {
+	return fmt.Sprintf("https://redshift-serverless.%s.amazonaws.com/", region)
+}
+
+// getServerlessCredentials calls GetCredentials at the endpoint, signing the request with the credentials of cfg.
+func getServerlessCredentials(ctx context.Context, cfg aws.Config, endpoint string, input getServerlessCredentialsInput) (*getServerlessCredentialsOutput, error) {
+	output := &getServerlessCredentialsOutput{}
+	if err := callServerlessAPI(ctx, cfg, endpoint, serverlessCredentialsAPI, input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// callServerlessAPI calls the operation (e.g. RedshiftServerless.GetCredentials) of the Redshift Serverless API at the endpoint,
+// signing the request with the credentials of cfg, and decodes the response into output.
+func callServerlessAPI(ctx context.Context, cfg aws.Config, endpoint, operation string, input, output interface{}) error {
+	name := strings.TrimPrefix(operation, "RedshiftServerless.")
+	if cfg.Region == "" {
+		return fmt.Errorf("the AWS region is required to call the Redshift Serverless %s API", name)
+	}
+	if cfg.Credentials == nil {
+		return fmt.Errorf("no AWS credentials found to call the Redshift Serverless %s API", name)
+	}
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", operation)
+
+	credentials, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("could not retrieve AWS credentials: %w", err)
+	}
+	payloadHash := sha256.Sum256(body)
+	if err := v4.NewSigner().SignHTTP(ctx, credentials, req, hex.EncodeToString(payloadHash[:]), serverlessSigningName, cfg.Region, time.Now()); err != nil {
+		return fmt.Errorf("could not sign %s request: %w", name, err)
+	}
+
+	var httpClient aws.HTTPClient = http.DefaultClient
+	if cfg.HTTPClient != nil {
+		httpClient = cfg.HTTPClient
+	}
+
+	log.Printf("[DEBUG] making Redshift Serverless %s request", name)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := serverlessAPIError{}
+		if err := json.Unmarshal(respBody, &apiErr); err != nil || apiErr.Message == "" {
+			return fmt.Errorf("%s failed with status %d", name, resp.StatusCode)
+		}
+		return fmt.Errorf("%s failed: %s: %s", name, apiErr.Type, apiErr.Message)
+	}
+
+	if err := json.Unmarshal(respBody, output); err != nil {
+		return fmt.Errorf("could not parse %s response: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package redshift
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+func TestGetServerlessCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if target := r.Header.Get("X-Amz-Target"); target != serverlessCredentialsAPI {
+			t.Errorf("unexpected X-Amz-Target %q", target)
+		}
+		if auth := r.Header.Get("Authorization"); !strings.Contains(auth, "/eu-west-1/redshift-serverless/aws4_request") {
+			t.Errorf("request is not signed for redshift-serverless: %q", auth)
+		}
+
+		input := getServerlessCredentialsInput{}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Fatal(err)
+		}
+		if input.WorkgroupName == "missing" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Workgroup missing not found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(getServerlessCredentialsOutput{DbUser: "IAMR:admin", DbPassword: "secret"})
+	}))
+	defer server.Close()
+
+	cfg := aws.Config{
+		Region:      "eu-west-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	}
+
+	output, err := getServerlessCredentials(context.Background(), cfg, server.URL, getServerlessCredentialsInput{WorkgroupName: "default", DbName: "dev"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.DbUser != "IAMR:admin" || output.DbPassword != "secret" {
+		t.Errorf("unexpected credentials %+v", output)
+	}
+
+	_, err = getServerlessCredentials(context.Background(), cfg, server.URL, getServerlessCredentialsInput{WorkgroupName: "missing"})
+	if err == nil || !strings.Contains(err.Error(), "Workgroup missing not found") {
+		t.Errorf("expected the API error, got %v", err)
+	}
+
+	cfg.Region = ""
+	if _, err := getServerlessCredentials(context.Background(), cfg, server.URL, getServerlessCredentialsInput{WorkgroupName: "default"}); err == nil {
+		t.Error("expected an error without region")
+	}
+}
@@ -0,0 +1,139 @@
+package redshift
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// statementStats counts the SQL statements run by a resource operation,
+// including those run in transactions and in other databases.
+type statementStats struct {
+	count int64
+}
+
+func (s *statementStats) add() {
+	if s != nil {
+		atomic.AddInt64(&s.count, 1)
+	}
+}
+
+// Count returns the number of statements run so far.
+func (s *statementStats) Count() int64 {
+	return atomic.LoadInt64(&s.count)
+}
+
+type statementStatsKey struct{}
+
+// withStatementStats returns a context counting the statements run with it in stats.
+func withStatementStats(ctx context.Context, stats *statementStats) context.Context {
+	if stats == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, statementStatsKey{}, stats)
+}
+
+func statementStatsFromContext(ctx context.Context) *statementStats {
+	stats, _ := ctx.Value(statementStatsKey{}).(*statementStats)
+	return stats
+}
+
+// countingConn counts the statements run on a connection in the statementStats of their context.
+// The statements of a transaction are run with a background context by database/sql,
+// so they are counted in the statementStats of the context the transaction was started with.
+type countingConn struct {
+	driver.Conn
+
+	// txStats are the stats of the transaction in progress, nil outside transactions.
+	txStats *statementStats
+}
+
+func (c *countingConn) stats(ctx context.Context) *statementStats {
+	if stats := statementStatsFromContext(ctx); stats != nil {
+		return stats
+	}
+	return c.txStats
+}
+
+func (c *countingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.txStats = statementStatsFromContext(ctx)
+	return &countingTx{tx, c}, nil
+}
+
+func (c *countingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.stats(ctx).add()
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+func (c *countingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.stats(ctx).add()
+	return c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+}
+
+func (c *countingConn) Ping(ctx context.Context) error {
+	return c.Conn.(driver.Pinger).Ping(ctx)
+}
+
+type countingTx struct {
+	driver.Tx
+
+	conn *countingConn
+}
+
+func (tx *countingTx) Commit() error {
+	tx.conn.txStats = nil
+	return tx.Tx.Commit()
+}
+
+func (tx *countingTx) Rollback() error {
+	tx.conn.txStats = nil
+	return tx.Tx.Rollback()
+}
+
+// statementContext returns a context counting the statements in the stats of the client, if any.
+func (db *DBConnection) statementContext(ctx context.Context) context.Context {
+	return withStatementStats(ctx, db.client.config.stats)
+}
+
+func (db *DBConnection) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.DB.ExecContext(db.statementContext(context.Background()), query, args...)
+}
+
+func (db *DBConnection) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.DB.QueryContext(db.statementContext(context.Background()), query, args...)
+}
+
+func (db *DBConnection) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.DB.QueryRowContext(db.statementContext(context.Background()), query, args...)
+}
+
+func (db *DBConnection) Begin() (*sql.Tx, error) {
+	return db.DB.BeginTx(db.statementContext(context.Background()), nil)
+}
+
+func (db *DBConnection) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return db.DB.BeginTx(db.statementContext(ctx), opts)
+}
+
+// withStatementStats returns a copy of the client counting the statements it runs in new stats.
+func (c *Client) withStatementStats() (*Client, *statementStats) {
+	stats := &statementStats{}
+	client := *c
+	client.config.stats = stats
+	return &client, stats
+}
+
+// logStatementStats logs the number of statements run by an operation on the resource and its duration,
+// to help finding the slow resources of large configurations with TF_LOG=DEBUG.
+func logStatementStats(d *schema.ResourceData, stats *statementStats, start time.Time) {
+	log.Printf("[DEBUG] resource %q: %d statements executed in %s", d.Id(), stats.Count(), time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,94 @@
+package redshift
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+)
+
+// fakeConn is a driver connection accepting any statement.
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return fakeTx{}, nil }
+func (fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+func (fakeConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+func (fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return fakeRows{}, nil
+}
+func (fakeConn) Ping(context.Context) error { return nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string              { return []string{} }
+func (fakeRows) Close() error                   { return nil }
+func (fakeRows) Next(dest []driver.Value) error { return io.EOF }
+
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &countingConn{Conn: fakeConn{}}, nil
+}
+func (fakeConnector) Driver() driver.Driver { return proxyDriver{} }
+
+func TestStatementStats(t *testing.T) {
+	sqlDB := sql.OpenDB(fakeConnector{})
+	defer sqlDB.Close()
+	sqlDB.SetMaxOpenConns(1)
+
+	config := &Config{}
+	client, stats := config.NewClient("db").withStatementStats()
+	db := &DBConnection{sqlDB, client}
+	otherDB := &DBConnection{sqlDB, config.NewClient("db")}
+
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows, err := db.Query("SELECT 1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := tx.Exec("SELECT 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Statements of other operations, on the same connection, are not counted.
+	if _, err := otherDB.Exec("SELECT 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tx, err = otherDB.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := tx.Exec("SELECT 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if count := stats.Count(); count != 3 {
+		t.Errorf("expected 3 statements, got %d", count)
+	}
+}
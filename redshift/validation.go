@@ -1,8 +1,13 @@
 package redshift
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
+	"unicode"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
@@ -178,5 +183,69 @@ var dbGroupValidate = validation.All(
 	validation.StringNotInSlice(reservedWords, true),
 )
 
+// maxIdentifierLength is the maximum length of Redshift identifiers, in bytes.
+// See https://docs.aws.amazon.com/redshift/latest/dg/r_names.html
+const maxIdentifierLength = 127
+
+// validateIdentifier rejects names of users, groups, schemas and other objects
+// which Redshift would not accept, before any statement is run: names longer than
+// maxIdentifierLength bytes, and names with control characters. Other characters,
+// including quotes, are valid in quoted identifiers and left to the other validators.
+func validateIdentifier(i interface{}, k string) ([]string, []error) {
+	name, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	if len(name) > maxIdentifierLength {
+		return nil, []error{fmt.Errorf("%s must be at most %d bytes long, got %d bytes", k, maxIdentifierLength, len(name))}
+	}
+	return validateNoControlCharacters(i, k)
+}
+
+// validateNoControlCharacters rejects values with control characters, e.g. newlines or NUL bytes.
+// It is used for values which are not plain identifiers, e.g. function signatures.
+func validateNoControlCharacters(i interface{}, k string) ([]string, []error) {
+	value, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return nil, []error{fmt.Errorf("%s must not contain control characters, got %q", k, value)}
+	}
+	return nil, nil
+}
+
 var awsAccountIdRegexp = regexp.MustCompile(`^\d{12}$`)
 var uuidRegex = regexp.MustCompile("^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}$")
+
+// Privileges which are still accepted by Redshift for compatibility with PostgreSQL,
+// but have no effect. See https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html
+var noOpPrivileges = map[string]string{
+	"rule":    "The RULE privilege is accepted by Redshift for compatibility reasons only and has no effect.",
+	"trigger": "The TRIGGER privilege is accepted by Redshift for compatibility reasons only and has no effect.",
+}
+
+// privilegeNoOpWarning emits a warning diagnostic when a privilege without any effect
+// in Redshift is used. It never fails the validation.
+func privilegeNoOpWarning(i interface{}, path cty.Path) diag.Diagnostics {
+	privilege, ok := i.(string)
+	if !ok {
+		return diag.Errorf("expected type of privilege to be string")
+	}
+
+	detail, isNoOp := noOpPrivileges[strings.ToLower(privilege)]
+	if !isNoOp {
+		return nil
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity:      diag.Warning,
+			Summary:       fmt.Sprintf("Privilege %q has no effect", privilege),
+			Detail:        fmt.Sprintf("%s Consider removing it from the configuration.", detail),
+			AttributePath: path,
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package redshift
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestPrivilegeNoOpWarning(t *testing.T) {
+	tests := map[string]struct {
+		privilege string
+		warning   bool
+	}{
+		"select":          {privilege: "select", warning: false},
+		"rule":            {privilege: "rule", warning: true},
+		"trigger":         {privilege: "trigger", warning: true},
+		"uppercase rule":  {privilege: "RULE", warning: true},
+		"mixed case drop": {privilege: "Drop", warning: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			diags := privilegeNoOpWarning(tt.privilege, cty.Path{})
+
+			if diags.HasError() {
+				t.Fatalf("Expected no errors but got %v", diags)
+			}
+			if tt.warning && (len(diags) != 1 || diags[0].Severity != diag.Warning) {
+				t.Errorf("Expected a single warning for %q but got %v", tt.privilege, diags)
+			}
+			if !tt.warning && len(diags) != 0 {
+				t.Errorf("Expected no diagnostics for %q but got %v", tt.privilege, diags)
+			}
+		})
+	}
+}
+
+func TestValidateIdentifier(t *testing.T) {
+	tests := map[string]struct {
+		name  string
+		valid bool
+	}{
+		"plain":                {name: "analysts", valid: true},
+		"empty":                {name: "", valid: true},
+		"quotes":               {name: `a"b'c`, valid: true},
+		"injection attempt":    {name: `x"; DROP TABLE users; --`, valid: true},
+		"email":                {name: "john.doe@example.com", valid: true},
+		"max length":           {name: strings.Repeat("a", 127), valid: true},
+		"too long":             {name: strings.Repeat("a", 128), valid: false},
+		"multibyte max length": {name: strings.Repeat("ą", 63) + "a", valid: true},
+		"multibyte too long":   {name: strings.Repeat("ą", 64), valid: false},
+		"newline":              {name: "a\nb", valid: false},
+		"injection on newline": {name: "x\"\nDROP TABLE users; --", valid: false},
+		"carriage return":      {name: "a\rb", valid: false},
+		"tab":                  {name: "a\tb", valid: false},
+		"nul byte":             {name: "a\x00b", valid: false},
+		"escape":               {name: "a\x1bb", valid: false},
+		"delete":               {name: "a\x7fb", valid: false},
+		"c1 control":           {name: "a\u0085b", valid: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, errs := validateIdentifier(tt.name, "name")
+			if tt.valid && len(errs) > 0 {
+				t.Errorf("Expected %q to be valid, got %v", tt.name, errs)
+			}
+			if !tt.valid && len(errs) == 0 {
+				t.Errorf("Expected %q to be invalid", tt.name)
+			}
+		})
+	}
+
+	if _, errs := validateIdentifier(1, "name"); len(errs) == 0 {
+		t.Errorf("Expected a non-string value to be invalid")
+	}
+}
+
+func TestValidateNoControlCharacters(t *testing.T) {
+	if _, errs := validateNoControlCharacters("myschema.myfunction("+strings.Repeat("integer, ", 20)+"integer)", "objects"); len(errs) > 0 {
+		t.Errorf("Expected long function signatures to be valid, got %v", errs)
+	}
+	if _, errs := validateNoControlCharacters("mytable\n; DROP TABLE users", "objects"); len(errs) == 0 {
+		t.Errorf("Expected object names with newlines to be invalid")
+	}
+}
+
+// TestResourceIdentifierAttrsValidated checks that the name attributes of all resources reject malformed identifiers.
+func TestResourceIdentifierAttrsValidated(t *testing.T) {
+	for resourceName, r := range Provider().ResourcesMap {
+		for _, attr := range identifierAttrs {
+			s, ok := r.Schema[attr]
+			if !ok || s.Type != schema.TypeString || (s.Computed && !s.Optional) {
+				continue
+			}
+			if s.ValidateFunc == nil {
+				t.Errorf("%s.%s has no validation", resourceName, attr)
+				continue
+			}
+			if _, errs := s.ValidateFunc(strings.Repeat("a", maxIdentifierLength+1), attr); len(errs) == 0 {
+				t.Errorf("%s.%s accepts names longer than %d bytes", resourceName, attr, maxIdentifierLength)
+			}
+			if _, errs := s.ValidateFunc("a\nb", attr); len(errs) == 0 {
+				t.Errorf("%s.%s accepts names with control characters", resourceName, attr)
+			}
+		}
+	}
+}